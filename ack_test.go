@@ -0,0 +1,107 @@
+package fmesh
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFMesh_AtLeastOnce(t *testing.T) {
+	// newMesh builds producer -> consumer, the consumer fails once on signal 2 after processing signal 1
+	newMesh := func(strategy ErrorHandlingStrategy, processed *[]any) *FMesh {
+		failed := false
+		producer := component.New("producer").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			this.OutputByName("out").PutSignals(signal.NewGroup(1, 2, 3).SignalsOrNil()...)
+			return nil
+		})
+		consumer := component.New("consumer").
+			WithAtLeastOnce().
+			WithInputs("in").
+			WithActivationFunc(func(this *component.Component) error {
+				for _, sig := range this.InputByName("in").AllSignalsOrNil() {
+					if sig.PayloadOrNil() == 2 && !failed {
+						failed = true
+						return errors.New("transient failure")
+					}
+					*processed = append(*processed, sig.PayloadOrNil())
+					this.Ack(sig)
+				}
+				return nil
+			})
+		producer.OutputByName("out").PipeTo(consumer.InputByName("in"))
+
+		fm := NewWithConfig("fm", &Config{
+			ErrorHandlingStrategy: strategy,
+			CyclesLimit:           10,
+		}).WithComponents(producer, consumer)
+		producer.InputByName("in").PutSignals(signal.New("start"))
+		return fm
+	}
+
+	t.Run("unacknowledged signals are retried", func(t *testing.T) {
+		var processed []any
+		fm := newMesh(IgnoreAll, &processed)
+
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, 2, 3}, processed)
+		assert.False(t, fm.ComponentByName("consumer").InputByName("in").HasSignals())
+	})
+
+	t.Run("unacknowledged signals stay when the run stops on error", func(t *testing.T) {
+		var processed []any
+		fm := newMesh(StopOnFirstErrorOrPanic, &processed)
+
+		_, err := fm.Run()
+		assert.ErrorIs(t, err, ErrHitAnErrorOrPanic)
+		assert.Equal(t, []any{1}, processed)
+
+		payloads, err := fm.ComponentByName("consumer").InputByName("in").AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{2, 3}, payloads)
+	})
+}
+
+func TestFMesh_ExactlyOnce(t *testing.T) {
+	// The producer emits keyed signals and fails once, so the same signals are emitted again on retry
+	attempts := 0
+	producer := component.New("producer").
+		WithAtLeastOnce().
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			attempts++
+			for _, key := range []string{"a", "b"} {
+				this.OutputByName("out").PutSignals(signal.New(key).WithIdempotencyKey(key))
+			}
+			if attempts == 1 {
+				return errors.New("failed after emitting")
+			}
+			this.Ack(this.InputByName("in").AllSignalsOrNil()...)
+			return nil
+		})
+
+	var processed []any
+	consumer := component.New("consumer").
+		WithInputs("in").
+		WithExactlyOnce(100).
+		WithActivationFunc(func(this *component.Component) error {
+			payloads, err := this.InputByName("in").AllSignalsPayloads()
+			processed = append(processed, payloads...)
+			return err
+		})
+	producer.OutputByName("out").PipeTo(consumer.InputByName("in"))
+
+	fm := NewWithConfig("fm", &Config{
+		ErrorHandlingStrategy: IgnoreAll,
+		CyclesLimit:           10,
+	}).WithComponents(producer, consumer)
+	producer.InputByName("in").PutSignals(signal.New("start"))
+
+	_, err := fm.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, []any{"a", "b"}, processed)
+}
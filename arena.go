@@ -0,0 +1,46 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/cycle"
+)
+
+// cycleArena holds the transient per-cycle bookkeeping of the scheduler,
+// buffers are allocated once per run and reset at the start of each cycle,
+// so long runs do not produce garbage proportional to the mesh size on every cycle
+type cycleArena struct {
+	// activationResults of the current cycle indexed by component ID
+	activationResults []*component.ActivationResult
+	// transfers of the current cycle indexed by source component ID
+	transfers [][]cycle.Transfer
+	// ready holds IDs of components to be activated (or drained) in the current phase of the cycle
+	ready []int
+	// parallel and serial split ready components pinned to serial execution from the others
+	parallel []int
+	serial   []int
+	// nextScheduled is the schedule being built for the next cycle (swapped with the current one)
+	nextScheduled []bool
+	// cycleNumber is the number of the cycle whose activation results are collected (0 when not collected yet)
+	cycleNumber int
+}
+
+// newCycleArena creates an arena for the given number of components
+func newCycleArena(componentsCount int) *cycleArena {
+	return &cycleArena{
+		activationResults: make([]*component.ActivationResult, componentsCount),
+		transfers:         make([][]cycle.Transfer, componentsCount),
+		ready:             make([]int, 0, componentsCount),
+		parallel:          make([]int, 0, componentsCount+1),
+		serial:            make([]int, 0, componentsCount),
+		nextScheduled:     make([]bool, componentsCount),
+	}
+}
+
+// reset prepares the arena for a new cycle, keeping the allocated capacity
+func (arena *cycleArena) reset() {
+	clear(arena.activationResults)
+	for id := range arena.transfers {
+		arena.transfers[id] = arena.transfers[id][:0]
+	}
+	arena.cycleNumber = 0
+}
@@ -0,0 +1,46 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/cycle"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_cycleArena_reset(t *testing.T) {
+	arena := newCycleArena(2)
+	arena.activationResults[0] = component.NewActivationResult("c1")
+	arena.transfers[1] = append(arena.transfers[1], cycle.Transfer{SourceComponent: "c2"}, cycle.Transfer{SourceComponent: "c2"})
+	arena.cycleNumber = 3
+
+	arena.reset()
+	assert.Equal(t, []*component.ActivationResult{nil, nil}, arena.activationResults)
+	assert.Empty(t, arena.transfers[1])
+	assert.Equal(t, 2, cap(arena.transfers[1]), "capacity must be kept for the next cycle")
+	assert.Zero(t, arena.cycleNumber)
+}
+
+func TestFMesh_ArenaIsReusedAcrossCycles(t *testing.T) {
+	c1 := component.New("c1").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+		this.OutputByName("out").PutSignals(signal.New(1))
+		return nil
+	})
+	c2 := component.New("c2").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+		return nil
+	})
+	c1.OutputByName("out").PipeTo(c2.InputByName("in"))
+	fm := New("fm").WithComponents(c1, c2)
+	c1.InputByName("in").PutSignals(signal.New("start"))
+
+	cycles, err := fm.Run()
+	assert.NoError(t, err)
+	assert.Len(t, cycles, 3)
+
+	// Bookkeeping buffers are reused, while the transfers recorded in cycles are not affected
+	assert.Len(t, cycles[0].Transfers(), 1)
+	assert.Empty(t, cycles[1].Transfers())
+	arena := fm.compiledTopology().arena
+	assert.Equal(t, 3, arena.cycleNumber)
+	assert.Same(t, cycles[2].ActivationResults().ByComponentName("c1"), fm.compiledTopology().activationResults(cycles[2])[0])
+}
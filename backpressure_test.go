@@ -0,0 +1,57 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFMesh_Backpressure(t *testing.T) {
+	for _, engine := range []Engine{CycleEngine, EventEngine} {
+		consumer := component.New("consumer").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+			if this.InputByName("in").Buffer().Len() < 3 {
+				return component.NewErrWaitForInputs(true)
+			}
+			return nil
+		})
+		consumer.InputByName("in").WithHighWatermark(3)
+
+		// The producer emits 2 signals per activation and feeds itself until 12 signals are emitted
+		emitted := 0
+		producer := component.New("producer").WithInputs("in").WithOutputs("out", "next").WithActivationFunc(func(this *component.Component) error {
+			this.OutputByName("out").PutSignals(signal.New(emitted), signal.New(emitted+1))
+			emitted += 2
+			if emitted < 12 {
+				this.OutputByName("next").PutSignals(signal.New("next"))
+			}
+			return nil
+		})
+		producer.OutputByName("out").PipeTo(consumer.InputByName("in"))
+		producer.OutputByName("next").PipeTo(producer.InputByName("in"))
+
+		var batchSizes []int
+		fm := NewWithConfig("fm", &Config{CyclesLimit: 20, Engine: engine}).
+			WithComponents(producer, consumer).
+			OnComponentActivated(func(fm *FMesh, activationResult *component.ActivationResult) {
+				if activationResult.ComponentName() == "consumer" && !component.IsWaitingForInput(activationResult) {
+					batchSizes = append(batchSizes, activationResult.SignalsConsumed())
+				}
+			})
+		producer.InputByName("in").PutSignals(signal.New("start"))
+
+		cycles, err := fm.Run()
+		require.NoError(t, err)
+		for _, c := range cycles {
+			// The consumer processes signals only when it is over the watermark, so the producer must be pushed back
+			consumed := c.ActivationResults().ByComponentName("consumer")
+			if consumed.Activated() && !component.IsWaitingForInput(consumed) {
+				assert.False(t, c.ActivationResults().ByComponentName("producer").Activated(), "cycle %d", c.Number())
+			}
+		}
+		assert.Equal(t, 12, emitted)
+		assert.Equal(t, []int{4, 4, 4}, batchSizes)
+		assert.False(t, producer.Backpressure())
+	}
+}
@@ -0,0 +1,44 @@
+package benchmarks
+
+import (
+	"github.com/hovsep/fmesh"
+	"testing"
+)
+
+// benchmarkRun runs the mesh built by newMesh, building time is excluded
+func benchmarkRun(b *testing.B, newMesh func() *fmesh.FMesh) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		fm := newMesh()
+		b.StartTimer()
+
+		if _, err := fm.Run(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDeepChain(b *testing.B) {
+	benchmarkRun(b, func() *fmesh.FMesh {
+		return DeepChain(1000)
+	})
+}
+
+func BenchmarkWideFanOut(b *testing.B) {
+	benchmarkRun(b, func() *fmesh.FMesh {
+		return WideFanOut(1000, 100)
+	})
+}
+
+func BenchmarkFeedbackLoop(b *testing.B) {
+	benchmarkRun(b, func() *fmesh.FMesh {
+		return FeedbackLoop(1000)
+	})
+}
+
+func BenchmarkBigPayloads(b *testing.B) {
+	benchmarkRun(b, func() *fmesh.FMesh {
+		return BigPayloads(10, 100, 1<<20)
+	})
+}
@@ -0,0 +1,47 @@
+package benchmarks
+
+import (
+	"github.com/hovsep/fmesh"
+	"runtime"
+	"testing"
+)
+
+// allocsBudget is the max number of allocations per run of each topology (measured number plus ~2% headroom),
+// a change exceeding the budget must either be optimized or come with updated baseline numbers
+var allocsBudget = map[string]struct {
+	newMesh func() *fmesh.FMesh
+	allocs  uint64
+}{
+	"deep chain":    {newMesh: func() *fmesh.FMesh { return DeepChain(1000) }, allocs: 61_000},
+	"wide fan-out":  {newMesh: func() *fmesh.FMesh { return WideFanOut(1000, 100) }, allocs: 19_100},
+	"feedback loop": {newMesh: func() *fmesh.FMesh { return FeedbackLoop(1000) }, allocs: 63_700},
+	"big payloads":  {newMesh: func() *fmesh.FMesh { return BigPayloads(10, 100, 1<<20) }, allocs: 670},
+}
+
+func TestAllocsBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("budget checks are skipped in short mode")
+	}
+	if raceEnabled {
+		t.Skip("budget checks are skipped with the race detector")
+	}
+
+	for name, budget := range allocsBudget {
+		t.Run(name, func(t *testing.T) {
+			fm := budget.newMesh()
+
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			if _, err := fm.Run(); err != nil {
+				t.Fatal(err)
+			}
+			runtime.ReadMemStats(&after)
+
+			allocs := after.Mallocs - before.Mallocs
+			if allocs > budget.allocs {
+				t.Errorf("run made %d allocations, budget is %d", allocs, budget.allocs)
+			}
+			t.Logf("run made %d allocations, budget is %d", allocs, budget.allocs)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !race
+
+package benchmarks
+
+// raceEnabled is set when tests run with the race detector, which makes its own allocations
+const raceEnabled = false
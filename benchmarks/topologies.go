@@ -0,0 +1,124 @@
+// Package benchmarks contains representative mesh topologies used to track the performance of the scheduler,
+// see README.md for baseline numbers and budgets
+package benchmarks
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+)
+
+// config is shared by all topologies, cycles are not limited as some topologies run for many cycles
+func config() *fmesh.Config {
+	return &fmesh.Config{
+		ErrorHandlingStrategy: fmesh.StopOnFirstErrorOrPanic,
+		CyclesLimit:           fmesh.UnlimitedCycles,
+	}
+}
+
+// forward is the activation function of components which simply pass signals through
+func forward(this *component.Component) error {
+	return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+}
+
+// newForwarder creates a component passing signals from "in" to "out"
+func newForwarder(name string) *component.Component {
+	return component.New(name).
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(forward)
+}
+
+// DeepChain returns a mesh of given number of components piped one after another,
+// a single signal travels through the whole chain (one cycle per component)
+func DeepChain(length int) *fmesh.FMesh {
+	fm := fmesh.NewWithConfig("deep chain", config())
+
+	var prev *component.Component
+	for i := 0; i < length; i++ {
+		c := newForwarder(fmt.Sprintf("c-%d", i))
+		if prev != nil {
+			prev.OutputByName("out").PipeTo(c.InputByName("in"))
+		}
+		fm.WithComponents(c)
+		prev = c
+	}
+
+	fm.ComponentByName("c-0").InputByName("in").PutSignals(signal.New(0))
+	return fm
+}
+
+// WideFanOut returns a mesh where one producer is piped to given number of consumers,
+// the producer emits signalsCount signals which are delivered to every consumer
+func WideFanOut(width int, signalsCount int) *fmesh.FMesh {
+	fm := fmesh.NewWithConfig("wide fan-out", config())
+
+	producer := component.New("producer").
+		WithInputs("start").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			for i := 0; i < signalsCount; i++ {
+				this.OutputByName("out").PutSignals(signal.New(i))
+			}
+			return nil
+		})
+	fm.WithComponents(producer)
+
+	for i := 0; i < width; i++ {
+		consumer := newForwarder(fmt.Sprintf("consumer-%d", i))
+		producer.OutputByName("out").PipeTo(consumer.InputByName("in"))
+		fm.WithComponents(consumer)
+	}
+
+	producer.InputByName("start").PutSignals(signal.New("start"))
+	return fm
+}
+
+// FeedbackLoop returns a mesh of two components piped to each other in a loop,
+// the counter passes the incremented number to the checker, which sends it back until given number of iterations is reached
+func FeedbackLoop(iterations int) *fmesh.FMesh {
+	counter := component.New("counter").
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("in").AllSignalsOrNil() {
+				this.OutputByName("out").PutSignals(signal.New(sig.PayloadOrNil().(int) + 1))
+			}
+			return nil
+		})
+
+	checker := component.New("checker").
+		WithInputs("in").
+		WithOutputs("loop", "done").
+		WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("in").AllSignalsOrNil() {
+				if sig.PayloadOrNil().(int) < iterations {
+					this.OutputByName("loop").PutSignals(sig)
+					continue
+				}
+				this.OutputByName("done").PutSignals(sig)
+			}
+			return nil
+		})
+
+	counter.OutputByName("out").PipeTo(checker.InputByName("in"))
+	checker.OutputByName("loop").PipeTo(counter.InputByName("in"))
+
+	fm := fmesh.NewWithConfig("feedback loop", config()).WithComponents(counter, checker)
+	counter.InputByName("in").PutSignals(signal.New(0))
+	return fm
+}
+
+// BigPayloads returns a mesh of a short chain of given length,
+// signalsCount signals carrying payloads of payloadSize bytes travel through the chain
+func BigPayloads(length int, signalsCount int, payloadSize int) *fmesh.FMesh {
+	fm := DeepChain(length)
+	first := fm.ComponentByName("c-0").InputByName("in")
+	first.Clear()
+	for i := 0; i < signalsCount; i++ {
+		first.PutSignals(signal.New(make([]byte, payloadSize)))
+	}
+	return fm
+}
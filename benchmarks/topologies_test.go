@@ -0,0 +1,45 @@
+package benchmarks
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDeepChain(t *testing.T) {
+	fm := DeepChain(10)
+	cycles, err := fm.Run()
+	assert.NoError(t, err)
+	assert.Len(t, cycles, 11)
+	assert.Equal(t, 1, fm.ComponentByName("c-9").OutputByName("out").Buffer().Len())
+}
+
+func TestWideFanOut(t *testing.T) {
+	fm := WideFanOut(10, 5)
+	cycles, err := fm.Run()
+	assert.NoError(t, err)
+	assert.Len(t, cycles, 3)
+	for _, c := range fm.Components().ComponentsOrNil() {
+		if c.Name() == "producer" {
+			continue
+		}
+		assert.Equal(t, 5, c.OutputByName("out").Buffer().Len())
+	}
+}
+
+func TestFeedbackLoop(t *testing.T) {
+	fm := FeedbackLoop(10)
+	cycles, err := fm.Run()
+	assert.NoError(t, err)
+	assert.Len(t, cycles, 21)
+	assert.Equal(t, 10, fm.ComponentByName("checker").OutputByName("done").FirstSignalPayloadOrNil())
+}
+
+func TestBigPayloads(t *testing.T) {
+	fm := BigPayloads(3, 2, 1024)
+	_, err := fm.Run()
+	assert.NoError(t, err)
+	payloads, err := fm.ComponentByName("c-2").OutputByName("out").AllSignalsPayloads()
+	assert.NoError(t, err)
+	assert.Len(t, payloads, 2)
+	assert.Len(t, payloads[0], 1024)
+}
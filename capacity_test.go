@@ -0,0 +1,61 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFMesh_Capacity(t *testing.T) {
+	newMesh := func(engine Engine, policy port.OverflowPolicy, batchSizes *[]int) (*FMesh, *component.Component) {
+		producer := component.New("producer").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			for i := 0; i < 10; i++ {
+				this.OutputByName("out").PutSignals(signal.New(i))
+			}
+			return nil
+		})
+		consumer := component.New("consumer").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+			*batchSizes = append(*batchSizes, this.InputByName("in").Buffer().Len())
+			return nil
+		})
+		consumer.InputByName("in").WithCapacity(4, policy)
+		producer.OutputByName("out").PipeTo(consumer.InputByName("in"))
+
+		fm := NewWithConfig("fm", &Config{CyclesLimit: 10, Engine: engine}).WithComponents(producer, consumer)
+		producer.InputByName("in").PutSignals(signal.New("start"))
+		return fm, consumer
+	}
+
+	for _, engine := range []Engine{CycleEngine, EventEngine} {
+		t.Run("blocked signals are delivered as the consumer frees room", func(t *testing.T) {
+			var batchSizes []int
+			fm, _ := newMesh(engine, port.OverflowBlock, &batchSizes)
+
+			_, err := fm.Run()
+			assert.NoError(t, err)
+			assert.Equal(t, []int{4, 4, 2}, batchSizes)
+			assert.False(t, fm.ComponentByName("producer").OutputByName("out").HasHeldSignals())
+		})
+	}
+
+	t.Run("dropped signals are counted", func(t *testing.T) {
+		var batchSizes []int
+		fm, consumer := newMesh(CycleEngine, port.OverflowDropOldest, &batchSizes)
+
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{4}, batchSizes)
+		assert.Equal(t, 6, consumer.InputByName("in").Dropped())
+	})
+
+	t.Run("overflow errors stop the run", func(t *testing.T) {
+		var batchSizes []int
+		fm, _ := newMesh(CycleEngine, port.OverflowError, &batchSizes)
+
+		_, err := fm.Run()
+		assert.ErrorIs(t, err, port.ErrPortOverflow)
+		assert.Empty(t, batchSizes)
+	})
+}
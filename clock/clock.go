@@ -0,0 +1,43 @@
+package clock
+
+import "time"
+
+// Clock is the source of time used by the mesh and its components
+type Clock interface {
+	// Now returns current time
+	Now() time.Time
+	// Since returns the time elapsed since t
+	Since(t time.Time) time.Duration
+	// After waits for the duration to elapse and then sends the current time on the returned channel
+	After(d time.Duration) <-chan time.Time
+	// Sleep pauses the current goroutine for at least the duration d
+	Sleep(d time.Duration)
+}
+
+// realClock is the wall clock
+type realClock struct{}
+
+// Real returns the wall clock
+func Real() Clock {
+	return realClock{}
+}
+
+// Now returns current wall time
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// Since returns the wall time elapsed since t
+func (realClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// After is the same as time.After
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Sleep is the same as time.Sleep
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
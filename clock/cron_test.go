@@ -0,0 +1,119 @@
+package clock
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{name: "every minute", expr: "* * * * *"},
+		{name: "lists, ranges and steps", expr: "0,30 9-17/2 1-15 */3 1-5"},
+		{name: "start with step", expr: "5/15 * * * *"},
+		{name: "sunday as 7", expr: "0 0 * * 7"},
+		{name: "macro", expr: "@daily"},
+		{name: "too few fields", expr: "* * * *", wantErr: true},
+		{name: "out of range", expr: "60 * * * *", wantErr: true},
+		{name: "reversed range", expr: "* 10-5 * * *", wantErr: true},
+		{name: "invalid step", expr: "*/0 * * * *", wantErr: true},
+		{name: "not a number", expr: "a * * * *", wantErr: true},
+		{name: "unknown macro", expr: "@sometimes", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCronExpression)
+				assert.Nil(t, schedule)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, schedule)
+			}
+		})
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	// Wednesday
+	from := time.Date(2024, time.January, 10, 10, 17, 42, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "every minute",
+			expr: "* * * * *",
+			from: from,
+			want: time.Date(2024, time.January, 10, 10, 18, 0, 0, time.UTC),
+		},
+		{
+			name: "strictly after",
+			expr: "18 10 * * *",
+			from: time.Date(2024, time.January, 10, 10, 18, 0, 0, time.UTC),
+			want: time.Date(2024, time.January, 11, 10, 18, 0, 0, time.UTC),
+		},
+		{
+			name: "every 15 minutes",
+			expr: "*/15 * * * *",
+			from: from,
+			want: time.Date(2024, time.January, 10, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "nightly",
+			expr: "@midnight",
+			from: from,
+			want: time.Date(2024, time.January, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "next month",
+			expr: "0 3 1 * *",
+			from: from,
+			want: time.Date(2024, time.February, 1, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekdays only",
+			expr: "0 9 * * 1-5",
+			from: time.Date(2024, time.January, 12, 10, 0, 0, 0, time.UTC),
+			want: time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "sunday as 7",
+			expr: "0 0 * * 7",
+			from: from,
+			want: time.Date(2024, time.January, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month or day of week",
+			expr: "0 0 13 * 5",
+			from: from,
+			want: time.Date(2024, time.January, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "leap day",
+			expr: "0 0 29 2 *",
+			from: from,
+			want: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "never",
+			expr: "0 0 30 2 *",
+			from: from,
+			want: time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(tt.from))
+		})
+	}
+}
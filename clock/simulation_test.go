@@ -0,0 +1,57 @@
+package clock
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSimulation(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		tick     time.Duration
+		wantTick time.Duration
+		wantNow  time.Time
+	}{
+		{
+			name:     "advances by tick",
+			tick:     time.Second,
+			wantTick: time.Second,
+			wantNow:  start.Add(3 * time.Second),
+		},
+		{
+			name:     "zero tick does not advance",
+			tick:     0,
+			wantTick: 0,
+			wantNow:  start,
+		},
+		{
+			name:     "negative tick is zero",
+			tick:     -time.Second,
+			wantTick: 0,
+			wantNow:  start,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := NewSimulation(start, tt.tick)
+			for i := 0; i < 3; i++ {
+				sim.Tick()
+			}
+			assert.Equal(t, tt.wantTick, sim.TickDuration())
+			assert.Equal(t, tt.wantNow, sim.Now())
+			assert.Equal(t, int64(3), sim.Ticks())
+		})
+	}
+
+	t.Run("tick fires due waiters", func(t *testing.T) {
+		sim := NewSimulation(start, time.Minute)
+		ch := sim.After(90 * time.Second)
+		sim.Tick()
+		assert.Equal(t, 1, sim.Pending())
+		sim.Tick()
+		assert.Equal(t, start.Add(2*time.Minute), <-ch)
+	})
+}
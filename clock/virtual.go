@@ -0,0 +1,151 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// waiter is a pending After call
+type waiter struct {
+	deadline time.Time
+	ch       chan time.Time
+	// seq is the registration number of the waiter
+	seq uint64
+}
+
+// Virtual is a clock which advances only when explicitly told to,
+// it allows to fast-forward hours of simulated time in milliseconds
+type Virtual struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*waiter
+	// registered counts waiters ever registered, changed is closed (and replaced) when a new one is registered
+	registered uint64
+	changed    chan struct{}
+}
+
+// NewVirtual creates a virtual clock starting at given time
+func NewVirtual(start time.Time) *Virtual {
+	return &Virtual{
+		now:     start,
+		changed: make(chan struct{}),
+	}
+}
+
+// Now returns current virtual time
+func (v *Virtual) Now() time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.now
+}
+
+// Since returns the virtual time elapsed since t
+func (v *Virtual) Since(t time.Time) time.Duration {
+	return v.Now().Sub(t)
+}
+
+// After returns a channel which receives the virtual time once the clock is advanced by at least d
+func (v *Virtual) After(d time.Duration) <-chan time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- v.now
+		return ch
+	}
+
+	v.registered++
+	v.waiters = append(v.waiters, &waiter{
+		deadline: v.now.Add(d),
+		ch:       ch,
+		seq:      v.registered,
+	})
+	close(v.changed)
+	v.changed = make(chan struct{})
+	return ch
+}
+
+// Sleep blocks until the clock is advanced by at least d
+func (v *Virtual) Sleep(d time.Duration) {
+	<-v.After(d)
+}
+
+// Advance moves the clock forward and fires all due waiters
+func (v *Virtual) Advance(d time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.advanceTo(v.now.Add(d))
+}
+
+// advanceTo sets the time and fires all due waiters, it must be called with the lock held
+func (v *Virtual) advanceTo(now time.Time) {
+	v.now = now
+
+	pending := v.waiters[:0]
+	for _, w := range v.waiters {
+		if w.deadline.After(v.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- v.now
+	}
+	v.waiters = pending
+}
+
+// Pending returns the number of waiters not fired yet
+func (v *Virtual) Pending() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.waiters)
+}
+
+// Mark returns the number of waiters registered so far, waiters registered later are counted from the mark (see WaitingSince)
+func (v *Virtual) Mark() uint64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.registered
+}
+
+// WaitingSince returns the number of waiters registered after the mark and not fired yet
+func (v *Virtual) WaitingSince(mark uint64) int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	count := 0
+	for _, w := range v.waiters {
+		if w.seq > mark {
+			count++
+		}
+	}
+	return count
+}
+
+// Changed returns a channel closed when the next waiter is registered
+func (v *Virtual) Changed() <-chan struct{} {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.changed
+}
+
+// AdvanceToNext moves the clock to the nearest deadline of waiters registered after the mark and fires all due waiters,
+// returns false when there are no such waiters.
+// Waiters registered before the mark may be left by goroutines which stopped waiting, so they are ignored
+func (v *Virtual) AdvanceToNext(mark uint64) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	var next time.Time
+	for _, w := range v.waiters {
+		if w.seq > mark && (next.IsZero() || w.deadline.Before(next)) {
+			next = w.deadline
+		}
+	}
+	if next.IsZero() {
+		return false
+	}
+
+	v.advanceTo(next)
+	return true
+}
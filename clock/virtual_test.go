@@ -0,0 +1,89 @@
+package clock
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestReal(t *testing.T) {
+	c := Real()
+	start := c.Now()
+	c.Sleep(time.Millisecond)
+	assert.GreaterOrEqual(t, c.Since(start), time.Millisecond)
+	<-c.After(time.Millisecond)
+}
+
+func TestVirtual(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("time does not flow by itself", func(t *testing.T) {
+		v := NewVirtual(start)
+		time.Sleep(time.Millisecond)
+		assert.Equal(t, start, v.Now())
+		assert.Zero(t, v.Since(start))
+	})
+
+	t.Run("advance", func(t *testing.T) {
+		v := NewVirtual(start)
+		v.Advance(10 * time.Hour)
+		assert.Equal(t, start.Add(10*time.Hour), v.Now())
+		assert.Equal(t, 10*time.Hour, v.Since(start))
+	})
+
+	t.Run("after fires only when due", func(t *testing.T) {
+		v := NewVirtual(start)
+		ch := v.After(time.Hour)
+		immediate := v.After(0)
+		assert.Equal(t, start, <-immediate)
+		assert.Equal(t, 1, v.Pending())
+
+		v.Advance(59 * time.Minute)
+		select {
+		case <-ch:
+			t.Fatal("fired too early")
+		default:
+		}
+
+		v.Advance(time.Minute)
+		assert.Equal(t, start.Add(time.Hour), <-ch)
+		assert.Zero(t, v.Pending())
+	})
+
+	t.Run("sleep is released by advance", func(t *testing.T) {
+		v := NewVirtual(start)
+		done := make(chan struct{})
+		go func() {
+			v.Sleep(24 * time.Hour)
+			close(done)
+		}()
+
+		for v.Pending() == 0 {
+			time.Sleep(time.Microsecond)
+		}
+		v.Advance(24 * time.Hour)
+		<-done
+	})
+	t.Run("advance to the nearest waiter registered after the mark", func(t *testing.T) {
+		v := NewVirtual(start)
+		stale := v.After(time.Minute)
+		mark := v.Mark()
+		changed := v.Changed()
+		assert.False(t, v.AdvanceToNext(mark))
+
+		later := v.After(2 * time.Hour)
+		nearest := v.After(time.Hour)
+		<-changed
+		assert.Equal(t, 2, v.WaitingSince(mark))
+
+		assert.True(t, v.AdvanceToNext(mark))
+		assert.Equal(t, start.Add(time.Hour), v.Now())
+		assert.Equal(t, start.Add(time.Hour), <-nearest)
+		assert.Equal(t, start.Add(time.Hour), <-stale)
+		assert.Equal(t, 1, v.WaitingSince(mark))
+
+		assert.True(t, v.AdvanceToNext(mark))
+		assert.Equal(t, start.Add(2*time.Hour), <-later)
+		assert.Zero(t, v.WaitingSince(mark))
+	})
+}
@@ -0,0 +1,257 @@
+// Portgen generates port name constants and accessors for components declared in a package, so ports are referenced
+// by identifiers checked by the compiler instead of strings prone to typos.
+//
+// It scans declarations like component.New("power_plant").WithInputs("power_demand").WithOutputs("supply")
+// (component and port names must be string literals) and emits:
+//
+//	const (
+//		PowerPlantInPowerDemand = "power_demand"
+//		PowerPlantOutSupply     = "supply"
+//	)
+//
+//	func PowerPlantInputPowerDemand(c *component.Component) *port.Port
+//	func PowerPlantOutputSupply(c *component.Component) *port.Port
+//
+// Usage (in any file of the package):
+//
+//	//go:generate go run github.com/hovsep/fmesh/cmd/portgen
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const componentPackage = "github.com/hovsep/fmesh/component"
+
+// componentPorts holds port names of a component
+type componentPorts struct {
+	name    string
+	inputs  []string
+	outputs []string
+}
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the package to scan")
+	output := flag.String("output", "fmesh_ports.go", "name of the generated file (written to the package directory)")
+	flag.Parse()
+
+	if err := run(*dir, *output); err != nil {
+		log.Fatalf("portgen: %v", err)
+	}
+}
+
+// run scans the package in dir and writes the generated file
+func run(dir string, output string) error {
+	pkg, components, err := scan(dir, output)
+	if err != nil {
+		return err
+	}
+
+	src, err := render(pkg, components)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, output), src, 0o644)
+}
+
+// scan parses non-test files of the package (except the generated one) and returns the package name and declared components
+func scan(dir string, output string) (string, []*componentPorts, error) {
+	fset := token.NewFileSet()
+	filter := func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != output
+	}
+	pkgs, err := parser.ParseDir(fset, dir, filter, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(pkgs) != 1 {
+		return "", nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	var (
+		pkgName string
+		files   []*ast.File
+	)
+	for name, pkg := range pkgs {
+		pkgName = name
+		for _, file := range pkg.Files {
+			files = append(files, file)
+		}
+	}
+	return pkgName, collect(files), nil
+}
+
+// collect returns components declared in the files ordered by name
+func collect(files []*ast.File) []*componentPorts {
+	byName := make(map[string]*componentPorts)
+	for _, file := range files {
+		componentIdent := importName(file, componentPackage)
+		if componentIdent == "" {
+			continue
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			selector, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || (selector.Sel.Name != "WithInputs" && selector.Sel.Name != "WithOutputs") {
+				return true
+			}
+
+			name, ok := componentName(selector.X, componentIdent)
+			if !ok {
+				return true
+			}
+			c, ok := byName[name]
+			if !ok {
+				c = &componentPorts{name: name}
+				byName[name] = c
+			}
+			for _, arg := range call.Args {
+				portName, ok := stringLiteral(arg)
+				if !ok {
+					continue
+				}
+				if selector.Sel.Name == "WithInputs" {
+					c.inputs = appendUnique(c.inputs, portName)
+				} else {
+					c.outputs = appendUnique(c.outputs, portName)
+				}
+			}
+			return true
+		})
+	}
+
+	components := make([]*componentPorts, 0, len(byName))
+	for _, c := range byName {
+		slices.Sort(c.inputs)
+		slices.Sort(c.outputs)
+		components = append(components, c)
+	}
+	slices.SortFunc(components, func(a, b *componentPorts) int {
+		return strings.Compare(a.name, b.name)
+	})
+	return components
+}
+
+// importName returns the name the package is imported with in the file (empty when it is not imported)
+func importName(file *ast.File, path string) string {
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || importPath != path {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name
+		}
+		return filepath.Base(path)
+	}
+	return ""
+}
+
+// componentName follows the chain of calls down to component.New and returns the name of the component
+func componentName(expr ast.Expr, componentIdent string) (string, bool) {
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return "", false
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return "", false
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok && ident.Name == componentIdent && selector.Sel.Name == "New" {
+			if len(call.Args) != 1 {
+				return "", false
+			}
+			return stringLiteral(call.Args[0])
+		}
+		expr = selector.X
+	}
+}
+
+// stringLiteral returns the value of the string literal
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// appendUnique appends the value unless it is already there
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+// render returns the formatted source of the generated file
+func render(pkg string, components []*componentPorts) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by portgen. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	if len(components) == 0 {
+		return format.Source(buf.Bytes())
+	}
+
+	fmt.Fprintf(&buf, "import (\n%q\n%q\n)\n\n", componentPackage, "github.com/hovsep/fmesh/port")
+	for _, c := range components {
+		prefix := identifier(c.name)
+		fmt.Fprintf(&buf, "// Ports of component %q\nconst (\n", c.name)
+		for _, name := range c.inputs {
+			fmt.Fprintf(&buf, "%sIn%s = %q\n", prefix, identifier(name), name)
+		}
+		for _, name := range c.outputs {
+			fmt.Fprintf(&buf, "%sOut%s = %q\n", prefix, identifier(name), name)
+		}
+		buf.WriteString(")\n\n")
+
+		for _, name := range c.inputs {
+			fmt.Fprintf(&buf, "// %sInput%s returns input port %q of component %q\n", prefix, identifier(name), name, c.name)
+			fmt.Fprintf(&buf, "func %sInput%s(c *component.Component) *port.Port {\nreturn c.InputByName(%sIn%s)\n}\n\n", prefix, identifier(name), prefix, identifier(name))
+		}
+		for _, name := range c.outputs {
+			fmt.Fprintf(&buf, "// %sOutput%s returns output port %q of component %q\n", prefix, identifier(name), name, c.name)
+			fmt.Fprintf(&buf, "func %sOutput%s(c *component.Component) *port.Port {\nreturn c.OutputByName(%sOut%s)\n}\n\n", prefix, identifier(name), prefix, identifier(name))
+		}
+	}
+	return format.Source(buf.Bytes())
+}
+
+// identifier converts the name to an exported Go identifier: "power_demand" becomes "PowerDemand"
+func identifier(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 || unicode.IsDigit(rune(b.String()[0])) {
+		return "X" + b.String()
+	}
+	return b.String()
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name: "components with ports",
+			source: `package plant
+
+import (
+	fmc "github.com/hovsep/fmesh/component"
+)
+
+func build(workers int) []*fmc.Component {
+	plant := fmc.New("power_plant").
+		WithInputs("power_demand", "ctl").
+		WithOutputs("supply").
+		WithActivationFunc(nil)
+	// Only chains starting at New are scanned, components and ports with non-literal names are skipped
+	plant.WithInputs("ctl", "1st_shift")
+	dynamic := fmc.New(fmt.Sprintf("w%d", workers)).WithInputs("in")
+	return []*fmc.Component{plant, fmc.New("lb").WithOutputsIndexed("out", 1, workers).WithOutputs("log"), dynamic}
+}
+`,
+			want: `// Code generated by portgen. DO NOT EDIT.
+
+package plant
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+)
+
+// Ports of component "lb"
+const (
+	LbOutLog = "log"
+)
+
+// LbOutputLog returns output port "log" of component "lb"
+func LbOutputLog(c *component.Component) *port.Port {
+	return c.OutputByName(LbOutLog)
+}
+
+// Ports of component "power_plant"
+const (
+	PowerPlantInCtl         = "ctl"
+	PowerPlantInPowerDemand = "power_demand"
+	PowerPlantOutSupply     = "supply"
+)
+
+// PowerPlantInputCtl returns input port "ctl" of component "power_plant"
+func PowerPlantInputCtl(c *component.Component) *port.Port {
+	return c.InputByName(PowerPlantInCtl)
+}
+
+// PowerPlantInputPowerDemand returns input port "power_demand" of component "power_plant"
+func PowerPlantInputPowerDemand(c *component.Component) *port.Port {
+	return c.InputByName(PowerPlantInPowerDemand)
+}
+
+// PowerPlantOutputSupply returns output port "supply" of component "power_plant"
+func PowerPlantOutputSupply(c *component.Component) *port.Port {
+	return c.OutputByName(PowerPlantOutSupply)
+}
+`,
+		},
+		{
+			name: "no components",
+			source: `package plant
+
+func build() {}
+`,
+			want: `// Code generated by portgen. DO NOT EDIT.
+
+package plant
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "plant.go"), []byte(tt.source), 0o644))
+			// Previously generated file is not scanned
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "fmesh_ports.go"), []byte("package plant\n"), 0o644))
+
+			require.NoError(t, run(dir, "fmesh_ports.go"))
+			got, err := os.ReadFile(filepath.Join(dir, "fmesh_ports.go"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "power_demand", want: "PowerDemand"},
+		{name: "in-1", want: "In1"},
+		{name: "alreadyCamel", want: "AlreadyCamel"},
+		{name: "1st", want: "X1st"},
+		{name: "", want: "X"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, identifier(tt.name))
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// RegexpPatternPrefix marks label patterns which are regular expressions, other patterns are globs
+const RegexpPatternPrefix = "re:"
+
+var (
+	ErrInvalidLabelPattern = errors.New("invalid label pattern")
+	ErrInvalidLabelQuery   = errors.New("invalid label query")
+)
+
+// LabelMatcher matches labels by patterns of their keys and values.
+// A pattern is a glob (see path.Match, so "region/eu/*" matches "region/eu/west" but not "region/eu/west/1")
+// or a regular expression when prefixed with RegexpPatternPrefix (e.g. "re:^eu-(west|north)$")
+type LabelMatcher struct {
+	key   func(string) bool
+	value func(string) bool
+}
+
+// NewLabelMatcher compiles key and value patterns
+func NewLabelMatcher(keyPattern string, valuePattern string) (*LabelMatcher, error) {
+	key, err := compileLabelPattern(keyPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := compileLabelPattern(valuePattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LabelMatcher{
+		key:   key,
+		value: value,
+	}, nil
+}
+
+// Matches returns true when at least one label matches both key and value patterns
+func (m *LabelMatcher) Matches(labels LabelsCollection) bool {
+	for label, value := range labels {
+		if m.key(label) && m.value(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileLabelPattern compiles a glob or regular expression pattern into a match function
+func compileLabelPattern(pattern string) (func(string) bool, error) {
+	if expr, ok := strings.CutPrefix(pattern, RegexpPatternPrefix); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidLabelPattern, pattern, err)
+		}
+		return re.MatchString, nil
+	}
+
+	// Validate the glob once, so matching can not fail later
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("%w: %q: %w", ErrInvalidLabelPattern, pattern, err)
+	}
+	return func(s string) bool {
+		matched, _ := path.Match(pattern, s)
+		return matched
+	}, nil
+}
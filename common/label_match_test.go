@@ -0,0 +1,109 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLabelMatcher_Matches(t *testing.T) {
+	labels := LabelsCollection{
+		"region": "region/eu/west",
+		"tier":   "backend",
+	}
+
+	type args struct {
+		keyPattern   string
+		valuePattern string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "exact match",
+			args: args{
+				keyPattern:   "tier",
+				valuePattern: "backend",
+			},
+			want:    true,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "glob value",
+			args: args{
+				keyPattern:   "region",
+				valuePattern: "region/eu/*",
+			},
+			want:    true,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "glob does not cross separators",
+			args: args{
+				keyPattern:   "region",
+				valuePattern: "region/*",
+			},
+			want:    false,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "glob key",
+			args: args{
+				keyPattern:   "t*",
+				valuePattern: "*",
+			},
+			want:    true,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "regexp value",
+			args: args{
+				keyPattern:   "region",
+				valuePattern: "re:^region/(eu|us)/",
+			},
+			want:    true,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "key and value must match the same label",
+			args: args{
+				keyPattern:   "tier",
+				valuePattern: "region/*/*",
+			},
+			want:    false,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "invalid glob",
+			args: args{
+				keyPattern:   "[",
+				valuePattern: "*",
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrInvalidLabelPattern)
+			},
+		},
+		{
+			name: "invalid regexp",
+			args: args{
+				keyPattern:   "region",
+				valuePattern: "re:(",
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrInvalidLabelPattern)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher, err := NewLabelMatcher(tt.args.keyPattern, tt.args.valuePattern)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, matcher.Matches(labels))
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrReservedLabel          = errors.New("label is in the namespace reserved for f-mesh")
+	ErrLabelNamespaceConflict = errors.New("label namespace conflict")
+)
+
+// ValidateLabels returns an error when labels contain system labels, which are managed by f-mesh only
+func ValidateLabels(labels LabelsCollection) error {
+	var reserved []string
+	for label := range labels {
+		if IsSystemLabel(label) {
+			reserved = append(reserved, label)
+		}
+	}
+	if len(reserved) == 0 {
+		return nil
+	}
+
+	sort.Strings(reserved)
+	return fmt.Errorf("%w: %s", ErrReservedLabel, strings.Join(reserved, ", "))
+}
+
+// LabelNamespacesOverlap returns true when labels of one namespace (prefix) may belong to the other one
+func LabelNamespacesOverlap(a string, b string) bool {
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
@@ -0,0 +1,51 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidateLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  LabelsCollection
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "no labels",
+			labels:  nil,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "user labels",
+			labels: LabelsCollection{
+				"env":           "staging",
+				"autopipe:role": "log",
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "system labels",
+			labels: LabelsCollection{
+				"env":                   "staging",
+				SystemLabelPrefix + "b": "1",
+				SystemLabelPrefix + "a": "1",
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrReservedLabel) && assert.ErrorContains(t, err, "fmesh:a, fmesh:b")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.wantErr(t, ValidateLabels(tt.labels))
+		})
+	}
+}
+
+func TestLabelNamespacesOverlap(t *testing.T) {
+	assert.True(t, LabelNamespacesOverlap("autopipe:", "autopipe:"))
+	assert.True(t, LabelNamespacesOverlap("auto", "autopipe:"))
+	assert.True(t, LabelNamespacesOverlap("autopipe:group:", "autopipe:"))
+	assert.False(t, LabelNamespacesOverlap("autopipe:", "router:"))
+}
@@ -0,0 +1,118 @@
+package common
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// labelQueryOperators are checked in order, so two-char operators go first
+var labelQueryOperators = []string{"==", "!=", ">=", "<=", "=", ">", "<"}
+
+// LabelQuery compares the value of a label with a constant, e.g. "stage > 3" or "year >= 2020".
+// Numbers are compared numerically, times (RFC 3339 or 2006-01-02 dates) chronologically and other values as strings.
+// Entities without the label or with a value of another type never match
+type LabelQuery struct {
+	label    string
+	operator string
+	value    string
+	compare  func(value string) (int, bool)
+}
+
+// ParseLabelQuery parses a query of the form "<label> <operator> <value>", supported operators are =, ==, !=, <, <=, >, >=
+func ParseLabelQuery(query string) (*LabelQuery, error) {
+	for _, operator := range labelQueryOperators {
+		label, value, found := strings.Cut(query, operator)
+		if !found {
+			continue
+		}
+
+		label, value = strings.TrimSpace(label), strings.TrimSpace(value)
+		if label == "" || value == "" {
+			break
+		}
+
+		if operator == "==" {
+			operator = "="
+		}
+		return &LabelQuery{
+			label:    label,
+			operator: operator,
+			value:    value,
+			compare:  labelComparator(value),
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrInvalidLabelQuery, query)
+}
+
+// Matches returns true when the labels satisfy the query
+func (q *LabelQuery) Matches(labels LabelsCollection) bool {
+	value, ok := labels[q.label]
+	if !ok {
+		return false
+	}
+
+	result, ok := q.compare(value)
+	if !ok {
+		return false
+	}
+
+	switch q.operator {
+	case "=":
+		return result == 0
+	case "!=":
+		return result != 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	default:
+		return result >= 0
+	}
+}
+
+// String returns the normalized query
+func (q *LabelQuery) String() string {
+	return q.label + " " + q.operator + " " + q.value
+}
+
+// labelComparator returns a function comparing a label value with the query value according to the type of the latter
+func labelComparator(queryValue string) func(value string) (int, bool) {
+	if number, err := strconv.ParseFloat(queryValue, 64); err == nil {
+		return func(value string) (int, bool) {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return 0, false
+			}
+			return cmp.Compare(v, number), true
+		}
+	}
+
+	if t, ok := parseLabelTime(queryValue); ok {
+		return func(value string) (int, bool) {
+			v, ok := parseLabelTime(value)
+			if !ok {
+				return 0, false
+			}
+			return v.Compare(t), true
+		}
+	}
+
+	return func(value string) (int, bool) {
+		return strings.Compare(value, queryValue), true
+	}
+}
+
+// parseLabelTime parses RFC 3339 times and dates
+func parseLabelTime(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
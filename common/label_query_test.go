@@ -0,0 +1,88 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLabelQuery_Matches(t *testing.T) {
+	labels := LabelsCollection{
+		"stage":    "4",
+		"year":     "2019",
+		"score":    "0.75",
+		"released": "2021-06-01",
+		"env":      "staging",
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{name: "greater", query: "stage > 3", want: true},
+		{name: "numbers are not compared as strings", query: "stage < 10", want: true},
+		{name: "greater or equal", query: "year >= 2020", want: false},
+		{name: "float", query: "score <= 0.8", want: true},
+		{name: "equal", query: "stage == 4.0", want: true},
+		{name: "single equal sign", query: "env=staging", want: true},
+		{name: "not equal", query: "env != prod", want: true},
+		{name: "date", query: "released > 2021-01-01", want: true},
+		{name: "RFC 3339 time", query: "released < 2021-05-31T23:59:59Z", want: false},
+		{name: "string", query: "env < test", want: true},
+		{name: "type mismatch never matches", query: "env > 3", want: false},
+		{name: "missing label never matches", query: "priority != 1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseLabelQuery(tt.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, q.Matches(labels))
+		})
+	}
+}
+
+func TestParseLabelQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "normalized",
+			query: " stage>=3 ",
+			want:  "stage >= 3",
+		},
+		{
+			name:  "double equal sign",
+			query: "env == prod",
+			want:  "env = prod",
+		},
+		{
+			name:    "no operator",
+			query:   "stage 3",
+			wantErr: ErrInvalidLabelQuery,
+		},
+		{
+			name:    "no value",
+			query:   "stage >",
+			wantErr: ErrInvalidLabelQuery,
+		},
+		{
+			name:    "no label",
+			query:   "< 3",
+			wantErr: ErrInvalidLabelQuery,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseLabelQuery(tt.query)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, q.String())
+		})
+	}
+}
@@ -3,14 +3,39 @@ package common
 import (
 	"errors"
 	"fmt"
+	"iter"
+	"maps"
+	"sort"
+	"strings"
 )
 
+// SystemLabelPrefix is the prefix of labels managed by f-mesh itself
+const SystemLabelPrefix = "fmesh:"
+
 type LabelsCollection map[string]string
 
 type LabeledEntity struct {
 	labels LabelsCollection
+	// sharedLabels is set when labels collection is shared with other entities, so it must be copied before modification
+	sharedLabels bool
+	// labelChangeHandlers are called on each change of labels
+	labelChangeHandlers []LabelChangeHandler
+}
+
+// LabelChange describes a change of a single label
+type LabelChange struct {
+	Label string
+	// OldValue is empty when the label is added
+	OldValue string
+	// NewValue is empty when the label is deleted
+	NewValue string
+	Added    bool
+	Deleted  bool
 }
 
+// LabelChangeHandler is called synchronously by the goroutine changing the labels
+type LabelChangeHandler func(change LabelChange)
+
 var (
 	ErrLabelNotFound = errors.New("label not found")
 )
@@ -20,11 +45,40 @@ func NewLabeledEntity(labels LabelsCollection) LabeledEntity {
 	return LabeledEntity{labels: labels}
 }
 
-// Labels getter
+// LabelsMatcher matches labels collections (see LabelMatcher and LabelQuery)
+type LabelsMatcher interface {
+	Matches(labels LabelsCollection) bool
+}
+
+// Labels getter, labels shared with other entities are returned as a copy, so modifying it does not affect them
 func (e *LabeledEntity) Labels() LabelsCollection {
+	if e.sharedLabels {
+		return maps.Clone(e.labels)
+	}
 	return e.labels
 }
 
+// AllLabels iterates over labels in place, unlike Labels it never copies shared labels, so it suits hot paths
+func (e *LabeledEntity) AllLabels() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for label, value := range e.labels {
+			if !yield(label, value) {
+				return
+			}
+		}
+	}
+}
+
+// LabelsCount returns the number of labels
+func (e *LabeledEntity) LabelsCount() int {
+	return len(e.labels)
+}
+
+// MatchesLabels says whether the labels satisfy the matcher, shared labels are matched in place (without copying them)
+func (e *LabeledEntity) MatchesLabels(matcher LabelsMatcher) bool {
+	return matcher.Matches(e.labels)
+}
+
 // Label returns the value of single label or nil if it is not found
 func (e *LabeledEntity) Label(label string) (string, error) {
 	value, ok := e.labels[label]
@@ -47,15 +101,81 @@ func (e *LabeledEntity) LabelOrDefault(label string, defaultValue string) string
 
 // SetLabels overwrites labels collection
 func (e *LabeledEntity) SetLabels(labels LabelsCollection) {
+	old := e.labels
 	e.labels = labels
+	e.sharedLabels = false
+
+	if len(e.labelChangeHandlers) == 0 {
+		return
+	}
+	for label, oldValue := range old {
+		if _, ok := labels[label]; !ok {
+			e.notifyLabelChange(LabelChange{Label: label, OldValue: oldValue, Deleted: true})
+		}
+	}
+	for label, value := range labels {
+		oldValue, ok := old[label]
+		if !ok || oldValue != value {
+			e.notifyLabelChange(LabelChange{Label: label, OldValue: oldValue, NewValue: value, Added: !ok})
+		}
+	}
 }
 
-// AddLabel adds or updates(if label already exists) single label
-func (e *LabeledEntity) AddLabel(label string, value string) {
+// OnLabelChange registers a handler called on each change of labels (adding, updating or deleting a label)
+func (e *LabeledEntity) OnLabelChange(handler LabelChangeHandler) {
+	e.labelChangeHandlers = append(e.labelChangeHandlers, handler)
+}
+
+// notifyLabelChange calls all label change handlers
+func (e *LabeledEntity) notifyLabelChange(change LabelChange) {
+	for _, handler := range e.labelChangeHandlers {
+		handler(change)
+	}
+}
+
+// ShareLabels sets labels collection without copying it,
+// the collection is copied on first modification, so many entities can share the same read-only labels
+func (e *LabeledEntity) ShareLabels(labels LabelsCollection) {
+	e.labels = labels
+	e.sharedLabels = true
+}
+
+// ShareLabelsOf shares labels of the source entity (see ShareLabels), unlike sharing Labels() it does not copy labels the source shares itself
+func (e *LabeledEntity) ShareLabelsOf(source *LabeledEntity) {
+	e.ShareLabels(source.labels)
+}
+
+// ensureOwnLabels makes sure the labels collection can be modified
+func (e *LabeledEntity) ensureOwnLabels() {
 	if e.labels == nil {
 		e.labels = make(LabelsCollection)
+		e.sharedLabels = false
+		return
 	}
+
+	if e.sharedLabels {
+		own := make(LabelsCollection, len(e.labels)+1)
+		for label, value := range e.labels {
+			own[label] = value
+		}
+		e.labels = own
+		e.sharedLabels = false
+	}
+}
+
+// AddLabel adds or updates(if label already exists) single label
+func (e *LabeledEntity) AddLabel(label string, value string) {
+	oldValue, existed := e.labels[label]
+	if existed && oldValue == value {
+		// Nothing changes, so shared labels stay shared
+		return
+	}
+	e.ensureOwnLabels()
 	e.labels[label] = value
+
+	if len(e.labelChangeHandlers) > 0 {
+		e.notifyLabelChange(LabelChange{Label: label, OldValue: oldValue, NewValue: value, Added: !existed})
+	}
 }
 
 // AddLabels adds or updates(if label already exists) multiple labels
@@ -65,9 +185,36 @@ func (e *LabeledEntity) AddLabels(labels LabelsCollection) {
 	}
 }
 
+// InheritLabels adds the labels of a parent entity which the entity does not have yet,
+// so own labels override inherited ones. System labels are never inherited
+func (e *LabeledEntity) InheritLabels(parent LabelsCollection) {
+	for label, value := range parent {
+		if e.HasLabel(label) || IsSystemLabel(label) {
+			continue
+		}
+		e.AddLabel(label, value)
+	}
+}
+
+// InheritLabelsOf is InheritLabels taking the labels of the parent entity in place (without copying shared ones)
+func (e *LabeledEntity) InheritLabelsOf(parent *LabeledEntity) {
+	e.InheritLabels(parent.labels)
+}
+
+// IsSystemLabel returns true when the label is managed by f-mesh itself
+func IsSystemLabel(label string) bool {
+	return strings.HasPrefix(label, SystemLabelPrefix)
+}
+
 // DeleteLabel deletes given label
 func (e *LabeledEntity) DeleteLabel(label string) {
+	if !e.HasLabel(label) {
+		return
+	}
+	oldValue := e.labels[label]
+	e.ensureOwnLabels()
 	delete(e.labels, label)
+	e.notifyLabelChange(LabelChange{Label: label, OldValue: oldValue, Deleted: true})
 }
 
 // HasLabel returns true when entity has given label or false otherwise
@@ -95,3 +242,18 @@ func (e *LabeledEntity) HasAnyLabel(label ...string) bool {
 	}
 	return false
 }
+
+// String returns labels as "key=value" pairs sorted by key
+func (l LabelsCollection) String() string {
+	keys := make([]string, 0, len(l))
+	for key := range l {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + l[key]
+	}
+	return strings.Join(pairs, ", ")
+}
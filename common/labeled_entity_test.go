@@ -2,6 +2,7 @@ package common
 
 import (
 	"github.com/stretchr/testify/assert"
+	"maps"
 	"testing"
 )
 
@@ -74,6 +75,35 @@ func TestLabeledEntity_Labels(t *testing.T) {
 	}
 }
 
+func TestLabeledEntity_AllLabels(t *testing.T) {
+	shared := LabelsCollection{
+		"l1": "v1",
+		"l2": "v2",
+	}
+	var e LabeledEntity
+	e.ShareLabels(shared)
+
+	t.Run("all labels", func(t *testing.T) {
+		assert.Equal(t, shared, LabelsCollection(maps.Collect(e.AllLabels())))
+	})
+
+	t.Run("iteration stops", func(t *testing.T) {
+		count := 0
+		for range e.AllLabels() {
+			count++
+			break
+		}
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("shared labels are not copied", func(t *testing.T) {
+		assert.Zero(t, testing.AllocsPerRun(10, func() {
+			for range e.AllLabels() {
+			}
+		}))
+	})
+}
+
 func TestLabeledEntity_SetLabels(t *testing.T) {
 	type args struct {
 		labels LabelsCollection
@@ -153,6 +183,67 @@ func TestLabeledEntity_SetLabels(t *testing.T) {
 	}
 }
 
+func TestLabeledEntity_ShareLabels(t *testing.T) {
+	shared := LabelsCollection{
+		"l1": "v1",
+	}
+
+	t.Run("entities share the collection until modified", func(t *testing.T) {
+		e1, e2 := NewLabeledEntity(nil), NewLabeledEntity(nil)
+		e1.ShareLabels(shared)
+		e2.ShareLabels(shared)
+		assert.Equal(t, shared, e1.Labels())
+
+		e1.AddLabel("l2", "v2")
+		e2.DeleteLabel("l1")
+
+		assert.Equal(t, LabelsCollection{"l1": "v1", "l2": "v2"}, e1.Labels())
+		assert.Empty(t, e2.Labels())
+		assert.Equal(t, LabelsCollection{"l1": "v1"}, shared, "shared collection must not be modified")
+	})
+
+	t.Run("set labels are owned", func(t *testing.T) {
+		own := LabelsCollection{"l1": "v1"}
+		e := NewLabeledEntity(nil)
+		e.ShareLabels(shared)
+		e.SetLabels(own)
+		e.AddLabel("l2", "v2")
+		assert.Equal(t, LabelsCollection{"l1": "v1", "l2": "v2"}, own)
+	})
+
+	t.Run("returned labels are a copy", func(t *testing.T) {
+		e1, e2 := NewLabeledEntity(nil), NewLabeledEntity(nil)
+		e1.ShareLabels(shared)
+		e2.ShareLabelsOf(&e1)
+
+		e1.Labels()["l2"] = "v2"
+		assert.False(t, e1.HasLabel("l2"))
+		assert.False(t, e2.HasLabel("l2"))
+		assert.Equal(t, LabelsCollection{"l1": "v1"}, shared, "shared collection must not be modified")
+	})
+
+	t.Run("adding existing label keeps the collection shared", func(t *testing.T) {
+		e := NewLabeledEntity(nil)
+		e.ShareLabels(shared)
+		e.AddLabel("l1", "v1")
+		e.AddLabels(LabelsCollection{"l1": "v1"})
+		assert.True(t, e.sharedLabels)
+
+		e.AddLabel("l1", "v2")
+		assert.False(t, e.sharedLabels)
+		assert.Equal(t, LabelsCollection{"l1": "v1"}, shared, "shared collection must not be modified")
+	})
+
+	t.Run("shared labels are matched in place", func(t *testing.T) {
+		e := NewLabeledEntity(nil)
+		e.ShareLabels(shared)
+		matcher, err := NewLabelMatcher("l1", "v*")
+		assert.NoError(t, err)
+		assert.True(t, e.MatchesLabels(matcher))
+		assert.Equal(t, 1, e.LabelsCount())
+	})
+}
+
 func TestLabeledEntity_AddLabel(t *testing.T) {
 	type args struct {
 		label string
@@ -266,6 +357,76 @@ func TestLabeledEntity_AddLabels(t *testing.T) {
 	}
 }
 
+func TestLabeledEntity_InheritLabels(t *testing.T) {
+	type args struct {
+		parent LabelsCollection
+	}
+	tests := []struct {
+		name          string
+		labeledEntity LabeledEntity
+		args          args
+		assertions    func(t *testing.T, labeledEntity LabeledEntity)
+	}{
+		{
+			name:          "inherit into empty labels collection",
+			labeledEntity: NewLabeledEntity(nil),
+			args: args{
+				parent: LabelsCollection{
+					"env": "staging",
+				},
+			},
+			assertions: func(t *testing.T, labeledEntity LabeledEntity) {
+				assert.Equal(t, LabelsCollection{
+					"env": "staging",
+				}, labeledEntity.Labels())
+			},
+		},
+		{
+			name: "own labels override inherited ones",
+			labeledEntity: NewLabeledEntity(LabelsCollection{
+				"env":  "prod",
+				"team": "core",
+			}),
+			args: args{
+				parent: LabelsCollection{
+					"env":    "staging",
+					"region": "eu",
+				},
+			},
+			assertions: func(t *testing.T, labeledEntity LabeledEntity) {
+				assert.Equal(t, LabelsCollection{
+					"env":    "prod",
+					"team":   "core",
+					"region": "eu",
+				}, labeledEntity.Labels())
+			},
+		},
+		{
+			name:          "system labels are not inherited",
+			labeledEntity: NewLabeledEntity(nil),
+			args: args{
+				parent: LabelsCollection{
+					SystemLabelPrefix + "port:direction": "in",
+					"env":                                "staging",
+				},
+			},
+			assertions: func(t *testing.T, labeledEntity LabeledEntity) {
+				assert.Equal(t, LabelsCollection{
+					"env": "staging",
+				}, labeledEntity.Labels())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.labeledEntity.InheritLabels(tt.args.parent)
+			if tt.assertions != nil {
+				tt.assertions(t, tt.labeledEntity)
+			}
+		})
+	}
+}
+
 func TestLabeledEntity_DeleteLabel(t *testing.T) {
 	type args struct {
 		label string
@@ -479,3 +640,32 @@ func TestLabeledEntity_Label(t *testing.T) {
 		})
 	}
 }
+
+func TestLabeledEntity_OnLabelChange(t *testing.T) {
+	labeledEntity := NewLabeledEntity(LabelsCollection{
+		"l1": "v1",
+		"l2": "v2",
+	})
+	var changes []LabelChange
+	labeledEntity.OnLabelChange(func(change LabelChange) {
+		changes = append(changes, change)
+	})
+
+	labeledEntity.AddLabel("l1", "v1")
+	labeledEntity.AddLabel("l1", "v100")
+	labeledEntity.AddLabel("l3", "v3")
+	labeledEntity.DeleteLabel("l2")
+	labeledEntity.DeleteLabel("l4")
+	labeledEntity.SetLabels(LabelsCollection{
+		"l1": "v100",
+		"l5": "v5",
+	})
+
+	assert.Equal(t, []LabelChange{
+		{Label: "l1", OldValue: "v1", NewValue: "v100"},
+		{Label: "l3", NewValue: "v3", Added: true},
+		{Label: "l2", OldValue: "v2", Deleted: true},
+		{Label: "l3", OldValue: "v3", Deleted: true},
+		{Label: "l5", NewValue: "v5", Added: true},
+	}, changes)
+}
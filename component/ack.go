@@ -0,0 +1,99 @@
+package component
+
+import (
+	"github.com/hovsep/fmesh/signal"
+	"sync"
+)
+
+// acks is the set of input signals acknowledged during the current activation
+type acks struct {
+	mu      sync.Mutex
+	signals map[*signal.Signal]struct{}
+}
+
+// WithAtLeastOnce enables at-least-once delivery: the activation function acknowledges (see Ack) the input signals
+// it has fully processed, and when the activation fails (returns an error or panics) the unacknowledged signals
+// stay on input ports for the next attempt instead of being cleared. Successful activations consume all input signals
+func (c *Component) WithAtLeastOnce() *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.acks = &acks{}
+	return c
+}
+
+// IsAtLeastOnce returns true when the component acknowledges input signals
+func (c *Component) IsAtLeastOnce() bool {
+	return c.acks != nil
+}
+
+// Ack acknowledges input signals as fully processed, it is safe to call from helper goroutines (see Go)
+// and it is a no-op unless at-least-once delivery is enabled
+func (c *Component) Ack(signals ...*signal.Signal) {
+	if c.acks == nil {
+		return
+	}
+
+	c.acks.mu.Lock()
+	defer c.acks.mu.Unlock()
+	if c.acks.signals == nil {
+		c.acks.signals = make(map[*signal.Signal]struct{}, len(signals))
+	}
+	for _, sig := range signals {
+		c.acks.signals[sig] = struct{}{}
+	}
+}
+
+// IsAcked returns true when the signal was acknowledged during the current activation
+func (c *Component) IsAcked(sig *signal.Signal) bool {
+	if c.acks == nil {
+		return false
+	}
+
+	c.acks.mu.Lock()
+	defer c.acks.mu.Unlock()
+	_, ok := c.acks.signals[sig]
+	return ok
+}
+
+// resetAcks forgets acknowledgements of the previous activation
+func (c *Component) resetAcks() {
+	if c.acks == nil {
+		return
+	}
+
+	c.acks.mu.Lock()
+	defer c.acks.mu.Unlock()
+	c.acks.signals = nil
+}
+
+// ClearAckedInputs removes acknowledged signals from input ports, keeping the rest for the next activation
+func (c *Component) ClearAckedInputs() *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	for _, p := range c.Inputs().PortsOrNil() {
+		if p.HasSignals() {
+			p.RetainSignals(func(sig *signal.Signal) bool {
+				return !c.IsAcked(sig)
+			})
+		}
+	}
+	c.resetAcks()
+	return c
+}
+
+// WithExactlyOnce enables at-least-once delivery (see WithAtLeastOnce) and drops duplicate signals on all input ports
+// (see port.WithDedupWindow), so signals redelivered after retries are processed once. Input ports must be added before
+func (c *Component) WithExactlyOnce(dedupWindow int) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	for _, p := range c.Inputs().PortsOrNil() {
+		p.WithDedupWindow(dedupWindow)
+	}
+	return c.WithAtLeastOnce()
+}
@@ -0,0 +1,50 @@
+package component
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestComponent_Ack(t *testing.T) {
+	t.Run("acks are ignored unless at-least-once is enabled", func(t *testing.T) {
+		sig := signal.New(1)
+		c := New("c")
+		c.Ack(sig)
+		assert.False(t, c.IsAtLeastOnce())
+		assert.False(t, c.IsAcked(sig))
+	})
+
+	t.Run("acked signals are cleared, the rest is kept", func(t *testing.T) {
+		c := New("c").WithAtLeastOnce().WithInputs("in").WithActivationFunc(func(this *Component) error {
+			for _, sig := range this.InputByName("in").AllSignalsOrNil() {
+				if sig.PayloadOrNil() == 2 {
+					return errors.New("transient failure")
+				}
+				this.Ack(sig)
+			}
+			return nil
+		})
+		c.InputByName("in").PutSignals(signal.NewGroup(1, 2, 3).SignalsOrNil()...)
+
+		result := c.MaybeActivate()
+		assert.True(t, result.IsError())
+
+		c.ClearAckedInputs()
+		payloads, err := c.InputByName("in").AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{2, 3}, payloads)
+	})
+
+	t.Run("acks are reset on each activation", func(t *testing.T) {
+		sig := signal.New(1)
+		c := New("c").WithAtLeastOnce().WithInputs("in").WithActivationFunc(func(this *Component) error {
+			return nil
+		})
+		c.Ack(sig)
+		c.InputByName("in").PutSignals(sig)
+		c.MaybeActivate()
+		assert.False(t, c.IsAcked(sig))
+	})
+}
@@ -2,11 +2,21 @@ package component
 
 import (
 	"errors"
-	"fmt"
+	"github.com/hovsep/fmesh/port"
+	"time"
 )
 
 type ActivationFunc func(this *Component) error
 
+// ReadinessFunc tells whether the component is ready to activate even without input signals,
+// it is used by source components which get data from outside the mesh (e.g. channels or readers)
+type ReadinessFunc func(this *Component) bool
+
+// IdleWaitFunc blocks until the source may become ready, it is called by the mesh when nothing else is left to do,
+// so a source emitting on schedule keeps the mesh running between emissions.
+// It must return promptly with false when cancel is closed, true means the source is worth evaluating again
+type IdleWaitFunc func(this *Component, cancel <-chan struct{}) bool
+
 // WithActivationFunc sets activation function
 func (c *Component) WithActivationFunc(f ActivationFunc) *Component {
 	if c.HasErr() {
@@ -17,6 +27,50 @@ func (c *Component) WithActivationFunc(f ActivationFunc) *Component {
 	return c
 }
 
+// HasActivationFunc says whether the activation function is set
+func (c *Component) HasActivationFunc() bool {
+	return c.f != nil
+}
+
+// WithReadinessFunc sets the readiness function, which makes the component a source:
+// it is evaluated in every cycle and activates when it has input signals or the readiness function returns true
+func (c *Component) WithReadinessFunc(f ReadinessFunc) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.ready = f
+	return c
+}
+
+// WithIdleWaitFunc sets the function the mesh uses to wait for the source when it is idle
+func (c *Component) WithIdleWaitFunc(f IdleWaitFunc) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.idleWait = f
+	return c
+}
+
+// HasIdleWait says whether the mesh should wait for the component instead of stopping when it is idle
+func (c *Component) HasIdleWait() bool {
+	return c.idleWait != nil
+}
+
+// IdleWait blocks until the source may become ready or cancel is closed
+func (c *Component) IdleWait(cancel <-chan struct{}) bool {
+	if c.idleWait == nil {
+		return false
+	}
+	return c.idleWait(c, cancel)
+}
+
+// IsSource says whether the component can activate without input signals
+func (c *Component) IsSource() bool {
+	return c.ready != nil
+}
+
 // hasActivationFunction checks when activation function is set
 func (c *Component) hasActivationFunction() bool {
 	if c.HasErr() {
@@ -26,6 +80,15 @@ func (c *Component) hasActivationFunction() bool {
 	return c.f != nil
 }
 
+// QuietActivationResult returns the result MaybeActivate yields when the component has no input signals,
+// so the mesh can skip components which received nothing new without evaluating their readiness
+func (c *Component) QuietActivationResult() *ActivationResult {
+	if !c.hasActivationFunction() {
+		return c.newActivationResultNoFunction()
+	}
+	return c.newActivationResultNoInput()
+}
+
 // MaybeActivate tries to run the activation function if all required conditions are met
 func (c *Component) MaybeActivate() (activationResult *ActivationResult) {
 	c.propagateChainErrors()
@@ -35,9 +98,31 @@ func (c *Component) MaybeActivate() (activationResult *ActivationResult) {
 		return
 	}
 
+	var (
+		checkpoint    State
+		startedAt     time.Time
+		consumed      int
+		outputsBefore int
+		retries       int
+	)
+	defer func() {
+		// Registered first, so it runs after the panic is recovered
+		if startedAt.IsZero() {
+			return
+		}
+		if WantsToKeepInputs(activationResult) {
+			consumed = 0
+		}
+		activationResult.
+			WithStats(c.Clock().Since(startedAt), consumed, countSignals(c.Outputs())-outputsBefore).
+			WithRetries(retries)
+	}()
 	defer func() {
 		if r := recover(); r != nil {
-			activationResult = c.newActivationResultPanicked(fmt.Errorf("panicked with: %v", r))
+			// Helpers must not outlive the activation
+			c.abortHelpers()
+			c.rollbackState(checkpoint)
+			activationResult = c.newActivationResultPanicked(newPanicError(r))
 		}
 	}()
 
@@ -47,25 +132,81 @@ func (c *Component) MaybeActivate() (activationResult *ActivationResult) {
 		return
 	}
 
-	if !c.Inputs().AnyHasSignals() {
-		//No inputs set, stop here
+	if !c.Inputs().AnyHasSignals() && !(c.IsSource() && c.ready(c)) {
+		//No inputs set (and source is not ready), stop here
 		activationResult = c.newActivationResultNoInput()
 		return
 	}
 
-	//Invoke the activation func
-	err := c.f(c)
+	checkpoint = c.checkpointState()
+	c.rememberStateBeforeActivation()
+	consumed = countSignals(c.Inputs())
+	outputsBefore = countSignals(c.Outputs())
+	startedAt = c.Clock().Now()
+	inputs, outputs := c.snapshotPorts(c.Inputs()), c.snapshotPorts(c.Outputs())
+
+	for attempt := 1; ; attempt++ {
+		c.resetAcks()
+
+		//Invoke the activation func
+		panicked, err := c.invoke()
+
+		if !panicked && errors.Is(err, errWaitingForInputs) {
+			activationResult = c.newActivationResultWaitingForInputs(err)
+			return
+		}
+
+		if err == nil {
+			err = c.validateState()
+		}
+
+		if err == nil {
+			activationResult = c.newActivationResultOK()
+			return
+		}
+
+		c.rollbackState(checkpoint)
+		if c.waitRetry(attempt) {
+			retries++
+			inputs.restore()
+			outputs.restore()
+			continue
+		}
 
-	if errors.Is(err, errWaitingForInputs) {
-		activationResult = c.newActivationResultWaitingForInputs(err)
+		if panicked {
+			activationResult = c.newActivationResultPanicked(err)
+		} else {
+			activationResult = c.newActivationResultReturnedError(err)
+		}
+		c.restart(activationResult)
 		return
 	}
+}
 
-	if err != nil {
-		activationResult = c.newActivationResultReturnedError(err)
-		return
+// invoke runs the activation function once, a panic is recovered and returned as the error
+func (c *Component) invoke() (panicked bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			// Helpers must not outlive the activation
+			c.abortHelpers()
+			panicked, err = true, newPanicError(r)
+		}
+	}()
+
+	err = c.f(c)
+
+	// Activation is finished only when all helper goroutines are done
+	if helpersErr := c.WaitHelpers(); err == nil {
+		err = helpersErr
 	}
+	return false, err
+}
 
-	activationResult = c.newActivationResultOK()
-	return
+// countSignals returns the number of signals buffered on the ports
+func countSignals(ports *port.Collection) int {
+	count := 0
+	for _, p := range ports.PortsOrNil() {
+		count += p.Buffer().Len()
+	}
+	return count
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/hovsep/fmesh/common"
+	"time"
 )
 
 // ActivationResult defines the result (possibly an error) of the activation of given component in given cycle
@@ -13,6 +14,11 @@ type ActivationResult struct {
 	activated       bool
 	code            ActivationResultCode
 	activationError error //Error returned from component activation function
+	duration        time.Duration
+	signalsConsumed int
+	signalsProduced int
+	retries         int
+	restarted       bool
 }
 
 // ActivationResultCode denotes a specific info about how a component been activated or why not activated at all
@@ -96,6 +102,57 @@ func (ar *ActivationResult) Code() ActivationResultCode {
 	return ar.code
 }
 
+// Duration returns how long the activation function ran (zero when the component did not activate)
+func (ar *ActivationResult) Duration() time.Duration {
+	return ar.duration
+}
+
+// SignalsConsumed returns the number of input signals the activation got (zero when the component kept its inputs)
+func (ar *ActivationResult) SignalsConsumed() int {
+	return ar.signalsConsumed
+}
+
+// SignalsProduced returns the number of signals the activation put on output ports
+func (ar *ActivationResult) SignalsProduced() int {
+	return ar.signalsProduced
+}
+
+// WithStats sets the activation stats
+func (ar *ActivationResult) WithStats(duration time.Duration, consumed int, produced int) *ActivationResult {
+	ar.duration = duration
+	ar.signalsConsumed = consumed
+	ar.signalsProduced = produced
+	return ar
+}
+
+// Retries returns the number of times the failed activation was retried (see WithRetryPolicy)
+func (ar *ActivationResult) Retries() int {
+	return ar.retries
+}
+
+// WithRetries sets the number of retries
+func (ar *ActivationResult) WithRetries(retries int) *ActivationResult {
+	ar.retries = retries
+	return ar
+}
+
+// Stack returns the stack trace of the panic the activation failed with (nil when it did not panic, see PanicError)
+func (ar *ActivationResult) Stack() []byte {
+	return PanicStack(ar.activationError)
+}
+
+// Restarted says whether the failed activation was handled by restarting the component (see WithRestartStrategy),
+// so the failure is not escalated to the mesh
+func (ar *ActivationResult) Restarted() bool {
+	return ar.restarted
+}
+
+// SetRestarted setter
+func (ar *ActivationResult) SetRestarted(restarted bool) *ActivationResult {
+	ar.restarted = restarted
+	return ar
+}
+
 // IsError returns true when activation result has an error
 func (ar *ActivationResult) IsError() bool {
 	return ar.code == ActivationCodeReturnedError && ar.ActivationError() != nil
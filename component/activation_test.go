@@ -257,7 +257,7 @@ func TestComponent_MaybeActivate(t *testing.T) {
 				c := New("c1").
 					WithInputs("i1").
 					WithActivationFunc(func(this *Component) error {
-						this.logger.Println("This line must be logged")
+						this.Logger().Println("This line must be logged")
 						return errors.New("test error")
 					})
 				//Only one input set
@@ -297,3 +297,49 @@ func TestComponent_MaybeActivate(t *testing.T) {
 		})
 	}
 }
+
+func TestComponent_ActivationStats(t *testing.T) {
+	t.Run("signals consumed and produced", func(t *testing.T) {
+		c := New("c1").WithInputs("i1").WithOutputs("o1").WithActivationFunc(func(this *Component) error {
+			this.OutputByName("o1").PutSignals(signal.New(1))
+			return nil
+		})
+		c.InputByName("i1").PutSignals(signal.New(1), signal.New(2))
+		// Signals left on outputs are not counted
+		c.OutputByName("o1").PutSignals(signal.New(0))
+
+		ar := c.MaybeActivate()
+		assert.Equal(t, 2, ar.SignalsConsumed())
+		assert.Equal(t, 1, ar.SignalsProduced())
+	})
+
+	t.Run("panicked activation", func(t *testing.T) {
+		c := New("c1").WithInputs("i1").WithActivationFunc(func(this *Component) error {
+			panic("boom")
+		})
+		c.InputByName("i1").PutSignals(signal.New(1))
+
+		ar := c.MaybeActivate()
+		assert.True(t, ar.IsPanic())
+		assert.Equal(t, 1, ar.SignalsConsumed())
+	})
+
+	t.Run("kept inputs are not consumed", func(t *testing.T) {
+		c := New("c1").WithInputs("i1", "i2").WithActivationFunc(func(this *Component) error {
+			return NewErrWaitForInputs(true)
+		})
+		c.InputByName("i1").PutSignals(signal.New(1))
+
+		ar := c.MaybeActivate()
+		assert.True(t, WantsToKeepInputs(ar))
+		assert.Zero(t, ar.SignalsConsumed())
+	})
+
+	t.Run("no stats without activation", func(t *testing.T) {
+		ar := New("c1").WithInputs("i1").WithActivationFunc(func(this *Component) error {
+			return nil
+		}).MaybeActivate()
+		assert.Zero(t, ar.Duration())
+		assert.Zero(t, ar.SignalsConsumed())
+	})
+}
@@ -0,0 +1,27 @@
+package component
+
+import "github.com/hovsep/fmesh/port"
+
+// Backpressure says whether ports the component pipes to are over their high watermarks (see port.WithHighWatermark),
+// so the component should slow down. The mesh does not schedule such components until the ports catch up,
+// sources may additionally check it to stop pulling data from outside the mesh. Pipes leading back to the component are ignored
+func (c *Component) Backpressure() bool {
+	for _, out := range c.Outputs().PortsOrNil() {
+		for _, dest := range out.Pipes().PortsOrNil() {
+			if dest.IsOverHighWatermark() && !c.ownsInput(dest) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ownsInput says whether the port is an input port of the component
+func (c *Component) ownsInput(p *port.Port) bool {
+	for _, in := range c.Inputs().PortsOrNil() {
+		if in == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package component
+
+import (
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestComponent_Backpressure(t *testing.T) {
+	c := New("c").WithInputs("in").WithOutputs("out", "loop")
+	sink := New("sink").WithInputs("in")
+	sink.InputByName("in").WithHighWatermark(1)
+	c.InputByName("in").WithHighWatermark(1)
+	c.OutputByName("out").PipeTo(sink.InputByName("in"))
+	c.OutputByName("loop").PipeTo(c.InputByName("in"))
+
+	// Own input ports do not push back
+	c.InputByName("in").PutSignals(signal.New(1))
+	assert.False(t, c.Backpressure())
+
+	sink.InputByName("in").PutSignals(signal.New(1))
+	assert.True(t, c.Backpressure())
+
+	sink.ClearInputs()
+	assert.False(t, c.Backpressure())
+}
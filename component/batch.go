@@ -0,0 +1,143 @@
+package component
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/signal"
+	"io"
+)
+
+const (
+	// BatchSourceOutput is the output port of batch sources, each record is emitted as a separate signal
+	BatchSourceOutput = "out"
+	// BatchSourceAck is the input port of batch sources receiving the numbers of records written by the sink
+	BatchSourceAck = "ack"
+	// BatchSinkInput is the input port of batch sinks
+	BatchSinkInput = "in"
+	// BatchSinkAck is the output port of batch sinks emitting the number of records written in each activation
+	BatchSinkAck = "ack"
+)
+
+// BatchReader reads at most max next records of a dataset (e.g. from a file or a database cursor),
+// it returns io.EOF (possibly together with the last records) once the dataset is exhausted
+type BatchReader[T any] func(max int) ([]T, error)
+
+// BatchWriter writes records (e.g. to a file or a database)
+type BatchWriter[T any] func(records []T) error
+
+// BatchSourceConfig configures batch sources
+type BatchSourceConfig struct {
+	// BatchSize is the maximum number of records emitted per activation cycle
+	BatchSize int
+	// MaxInFlight bounds the number of records emitted but not acknowledged by the sink yet (0 means no bound),
+	// so the records held by the mesh never exceed it, however large the dataset is
+	MaxInFlight int
+}
+
+// NewBatchSource creates a source component streaming a dataset larger than memory in bounded batches:
+// each activation reads at most BatchSize records and emits them on BatchSourceOutput port.
+// With MaxInFlight set the source pauses when that many records are in flight and resumes as the sink acknowledges them,
+// so BatchSinkAck port of the sink must be piped to BatchSourceAck port and every record must reach the sink
+// (components in between must emit exactly one result per record)
+func NewBatchSource[T any](name string, read BatchReader[T], config BatchSourceConfig) *Component {
+	if config.BatchSize <= 0 || config.MaxInFlight < 0 {
+		return New(name).WithErr(fmt.Errorf("%w, batch size: %d, max in flight: %d", ErrInvalidBatchConfig, config.BatchSize, config.MaxInFlight))
+	}
+
+	var (
+		inFlight int
+		done     bool
+	)
+	return New(name).
+		WithDescription("streams a dataset in batches").
+		WithInputs(BatchSourceAck).
+		WithOutputs(BatchSourceOutput).
+		WithReadinessFunc(func(this *Component) bool {
+			return !done && (config.MaxInFlight == 0 || inFlight < config.MaxInFlight)
+		}).
+		WithActivationFunc(func(this *Component) error {
+			for _, sig := range this.InputByName(BatchSourceAck).AllSignalsOrNil() {
+				acked, ok := sig.PayloadOrNil().(int)
+				if !ok {
+					return fmt.Errorf("%w: ack must be int, got %T", signal.ErrUnexpectedPayloadType, sig.PayloadOrNil())
+				}
+				inFlight = max(inFlight-acked, 0)
+			}
+
+			size := config.BatchSize
+			if config.MaxInFlight > 0 {
+				size = min(size, config.MaxInFlight-inFlight)
+			}
+			if done || size <= 0 {
+				return nil
+			}
+
+			records, err := read(size)
+			if errors.Is(err, io.EOF) {
+				done = true
+			} else if err != nil {
+				return fmt.Errorf("failed to read batch: %w", err)
+			}
+
+			out := this.OutputByName(BatchSourceOutput)
+			for _, record := range records {
+				out.PutSignals(signal.New(record))
+			}
+			inFlight += len(records)
+			return out.Err()
+		})
+}
+
+// NewBatchSink creates a component writing records received on BatchSinkInput port in chunks of at most batchSize records,
+// records are written in each activation, so results leave the mesh incrementally. The number of written records
+// is emitted on BatchSinkAck port (pipe it to the source to let it go on, see NewBatchSource)
+func NewBatchSink[T any](name string, write BatchWriter[T], batchSize int) *Component {
+	if batchSize <= 0 {
+		return New(name).WithErr(fmt.Errorf("%w, batch size: %d", ErrInvalidBatchConfig, batchSize))
+	}
+
+	return New(name).
+		WithDescription("writes records in batches").
+		WithInputs(BatchSinkInput).
+		WithOutputs(BatchSinkAck).
+		WithActivationFunc(func(this *Component) error {
+			signals := this.InputByName(BatchSinkInput).AllSignalsOrNil()
+			batch := make([]T, 0, min(batchSize, len(signals)))
+			written := 0
+			flush := func() error {
+				if len(batch) == 0 {
+					return nil
+				}
+				if err := write(batch); err != nil {
+					return fmt.Errorf("failed to write batch: %w", err)
+				}
+				written += len(batch)
+				batch = batch[:0]
+				return nil
+			}
+
+			var err error
+			for _, sig := range signals {
+				record, ok := sig.PayloadOrNil().(T)
+				if !ok {
+					err = fmt.Errorf("%w: expected %T, got %T", signal.ErrUnexpectedPayloadType, record, sig.PayloadOrNil())
+					break
+				}
+				batch = append(batch, record)
+				if len(batch) == batchSize {
+					if err = flush(); err != nil {
+						break
+					}
+				}
+			}
+			if err == nil {
+				err = flush()
+			}
+
+			// Records written before a failure are acknowledged anyway
+			if written > 0 {
+				this.OutputByName(BatchSinkAck).PutSignals(signal.New(written))
+			}
+			return err
+		})
+}
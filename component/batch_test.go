@@ -0,0 +1,100 @@
+package component
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+// sliceReader returns a batch reader over the records
+func sliceReader(records []int) BatchReader[int] {
+	return func(max int) ([]int, error) {
+		n := min(max, len(records))
+		batch := records[:n]
+		records = records[n:]
+		if len(records) == 0 {
+			return batch, io.EOF
+		}
+		return batch, nil
+	}
+}
+
+func TestNewBatchSource(t *testing.T) {
+	t.Run("emits bounded batches", func(t *testing.T) {
+		source := NewBatchSource("source", sliceReader([]int{1, 2, 3, 4, 5}), BatchSourceConfig{BatchSize: 2})
+		assert.True(t, source.IsSource())
+
+		var batches [][]any
+		for source.MaybeActivate().Code() == ActivationCodeOK {
+			payloads, err := source.OutputByName(BatchSourceOutput).AllSignalsPayloads()
+			assert.NoError(t, err)
+			batches = append(batches, payloads)
+			source.OutputByName(BatchSourceOutput).Clear()
+		}
+		assert.Equal(t, [][]any{{1, 2}, {3, 4}, {5}}, batches)
+	})
+
+	t.Run("pauses until records are acknowledged", func(t *testing.T) {
+		source := NewBatchSource("source", sliceReader([]int{1, 2, 3, 4, 5}), BatchSourceConfig{BatchSize: 2, MaxInFlight: 3})
+
+		assert.Equal(t, ActivationCodeOK, source.MaybeActivate().Code())
+		assert.Equal(t, ActivationCodeOK, source.MaybeActivate().Code())
+		assert.Equal(t, 3, source.OutputByName(BatchSourceOutput).Buffer().Len(), "second batch is cut to the free capacity")
+		assert.Equal(t, ActivationCodeNoInput, source.MaybeActivate().Code())
+
+		source.InputByName(BatchSourceAck).PutSignals(signal.New(2))
+		assert.Equal(t, ActivationCodeOK, source.MaybeActivate().Code())
+		assert.Equal(t, 5, source.OutputByName(BatchSourceOutput).Buffer().Len())
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		source := NewBatchSource("source", func(max int) ([]int, error) {
+			return nil, errors.New("cursor closed")
+		}, BatchSourceConfig{BatchSize: 2})
+		assert.ErrorContains(t, source.MaybeActivate().ActivationError(), "failed to read batch: cursor closed")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		assert.ErrorIs(t, NewBatchSource("source", sliceReader(nil), BatchSourceConfig{}).Err(), ErrInvalidBatchConfig)
+	})
+}
+
+func TestNewBatchSink(t *testing.T) {
+	t.Run("writes in chunks and acknowledges", func(t *testing.T) {
+		var written [][]int
+		sink := NewBatchSink("sink", func(records []int) error {
+			written = append(written, append([]int(nil), records...))
+			return nil
+		}, 2)
+		sink.InputByName(BatchSinkInput).PutSignals(signal.New(1), signal.New(2), signal.New(3))
+
+		assert.Equal(t, ActivationCodeOK, sink.MaybeActivate().Code())
+		assert.Equal(t, [][]int{{1, 2}, {3}}, written)
+		assert.Equal(t, 3, sink.OutputByName(BatchSinkAck).FirstSignalPayloadOrNil())
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		calls := 0
+		sink := NewBatchSink("sink", func(records []int) error {
+			calls++
+			if calls == 2 {
+				return errors.New("disk full")
+			}
+			return nil
+		}, 1)
+		sink.InputByName(BatchSinkInput).PutSignals(signal.New(1), signal.New(2), signal.New(3))
+
+		assert.ErrorContains(t, sink.MaybeActivate().ActivationError(), "failed to write batch: disk full")
+		assert.Equal(t, 1, sink.OutputByName(BatchSinkAck).FirstSignalPayloadOrNil(), "written records are acknowledged")
+	})
+
+	t.Run("unexpected payload", func(t *testing.T) {
+		sink := NewBatchSink("sink", func(records []int) error {
+			return nil
+		}, 1)
+		sink.InputByName(BatchSinkInput).PutSignals(signal.New("one"))
+		assert.ErrorIs(t, sink.MaybeActivate().ActivationError(), signal.ErrUnexpectedPayloadType)
+	})
+}
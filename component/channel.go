@@ -0,0 +1,60 @@
+package component
+
+import (
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+)
+
+const (
+	// ChannelSourceOutput is the output port of channel source
+	ChannelSourceOutput = "out"
+
+	// ChannelSinkInput is the input port of channel sink
+	ChannelSinkInput = "in"
+)
+
+// NewChannelSource creates a source component emitting values received from the channel as signals on ChannelSourceOutput port,
+// the component activates whenever the channel has values and never blocks, so the mesh stops once the channel is drained
+func NewChannelSource[T any](name string, ch <-chan T) *Component {
+	// Value received while checking readiness, it is emitted first on activation
+	var pending *signal.Signal
+
+	return New(name).
+		WithDescription("emits values received from a channel").
+		WithOutputs(ChannelSourceOutput).
+		WithReadinessFunc(func(this *Component) bool {
+			if pending != nil {
+				return true
+			}
+			select {
+			case value, ok := <-ch:
+				if !ok {
+					return false
+				}
+				pending = signal.New(value)
+				return true
+			default:
+				return false
+			}
+		}).
+		WithActivationFunc(func(this *Component) error {
+			out := this.OutputByName(ChannelSourceOutput)
+			if pending != nil {
+				out.PutSignals(pending)
+				pending = nil
+			}
+			out.WithSignalGroups(port.FromChannel(ch))
+			return out.Err()
+		})
+}
+
+// NewChannelSink creates a component sending payloads of signals received on ChannelSinkInput port to the channel,
+// sends are blocking, so a slow reader of the channel slows down the whole mesh
+func NewChannelSink[T any](name string, ch chan<- T) *Component {
+	return New(name).
+		WithDescription("sends payloads to a channel").
+		WithInputs(ChannelSinkInput).
+		WithActivationFunc(func(this *Component) error {
+			return port.ToChannel(this.InputByName(ChannelSinkInput), ch)
+		})
+}
@@ -0,0 +1,83 @@
+package component
+
+import (
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewChannelSource(t *testing.T) {
+	t.Run("activates only when channel has values", func(t *testing.T) {
+		ch := make(chan int, 3)
+		source := NewChannelSource("source", ch)
+		assert.True(t, source.IsSource())
+
+		assert.Equal(t, ActivationCodeNoInput, source.MaybeActivate().Code())
+
+		ch <- 1
+		ch <- 2
+		assert.Equal(t, ActivationCodeOK, source.MaybeActivate().Code())
+		payloads, err := source.OutputByName(ChannelSourceOutput).AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, 2}, payloads)
+	})
+
+	t.Run("unbuffered channel", func(t *testing.T) {
+		ch := make(chan string)
+		source := NewChannelSource("source", ch)
+
+		go func() {
+			ch <- "hello"
+		}()
+
+		// The sender may not be ready yet, so retry until the value is received
+		assert.Eventually(t, func() bool {
+			return source.MaybeActivate().Code() == ActivationCodeOK
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, "hello", source.OutputByName(ChannelSourceOutput).FirstSignalPayloadOrNil())
+	})
+
+	t.Run("closed channel", func(t *testing.T) {
+		ch := make(chan int)
+		close(ch)
+		assert.Equal(t, ActivationCodeNoInput, NewChannelSource("source", ch).MaybeActivate().Code())
+	})
+}
+
+func TestNewChannelSink(t *testing.T) {
+	ch := make(chan int, 2)
+	sink := NewChannelSink("sink", ch)
+	sink.InputByName(ChannelSinkInput).PutSignals(signal.New(1), signal.New(2))
+
+	assert.Equal(t, ActivationCodeOK, sink.MaybeActivate().Code())
+	assert.Equal(t, 1, <-ch)
+	assert.Equal(t, 2, <-ch)
+
+	sink.ClearInputs()
+	sink.InputByName(ChannelSinkInput).PutSignals(signal.New("wrong type"))
+	assert.Equal(t, ActivationCodeReturnedError, sink.MaybeActivate().Code())
+}
+
+func TestComponent_WithReadinessFunc(t *testing.T) {
+	ready := false
+	c := New("c").
+		WithInputs("in").
+		WithReadinessFunc(func(this *Component) bool {
+			return ready
+		}).
+		WithActivationFunc(func(this *Component) error {
+			return nil
+		})
+
+	assert.Equal(t, ActivationCodeNoInput, c.MaybeActivate().Code())
+
+	ready = true
+	assert.Equal(t, ActivationCodeOK, c.MaybeActivate().Code())
+
+	// Input signals activate the source regardless of readiness
+	ready = false
+	c.InputByName("in").PutSignals(signal.New(1))
+	assert.Equal(t, ActivationCodeOK, c.MaybeActivate().Code())
+	assert.False(t, New("c").IsSource())
+}
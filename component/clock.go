@@ -0,0 +1,21 @@
+package component
+
+import "github.com/hovsep/fmesh/clock"
+
+// WithClock sets the clock used by the component (useful to control time in tests and simulations)
+func (c *Component) WithClock(clk clock.Clock) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.clock = clk
+	return c
+}
+
+// Clock returns the clock of the component (wall clock when it is not set explicitly or by the mesh)
+func (c *Component) Clock() clock.Clock {
+	if c.clock == nil {
+		return clock.Real()
+	}
+	return c.clock
+}
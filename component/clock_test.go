@@ -0,0 +1,40 @@
+package component
+
+import (
+	"github.com/hovsep/fmesh/clock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestComponent_Clock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		component  *Component
+		assertions func(t *testing.T, component *Component)
+	}{
+		{
+			name:      "wall clock by default",
+			component: New("c1"),
+			assertions: func(t *testing.T, component *Component) {
+				assert.Equal(t, clock.Real(), component.Clock())
+			},
+		},
+		{
+			name:      "virtual clock",
+			component: New("c1").WithClock(clock.NewVirtual(start)),
+			assertions: func(t *testing.T, component *Component) {
+				assert.Equal(t, start, component.Clock().Now())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.assertions != nil {
+				tt.assertions(t, tt.component)
+			}
+		})
+	}
+}
@@ -31,13 +31,83 @@ func (c *Collection) ByName(name string) *Component {
 	component, ok := c.components[name]
 
 	if !ok {
-		c.SetErr(fmt.Errorf("%w, component name: %s", errNotFound, name))
+		c.SetErr(fmt.Errorf("%w, component name: %s", ErrNotFound, name))
 		return New("").WithErr(c.Err())
 	}
 
 	return component
 }
 
+// ByNameE returns the component by name, unlike ByName it returns the error (wrapping ErrNotFound) instead of setting it on the collection
+func (c *Collection) ByNameE(name string) (*Component, error) {
+	if c.HasErr() {
+		return nil, c.Err()
+	}
+
+	component, ok := c.components[name]
+	if !ok {
+		return nil, fmt.Errorf("%w, component name: %s", ErrNotFound, name)
+	}
+	return component, nil
+}
+
+// ByLabel returns components having the label with the given value
+func (c *Collection) ByLabel(label string, value string) *Collection {
+	if c.HasErr() {
+		return NewCollection().WithErr(c.Err())
+	}
+
+	selected := NewCollection()
+	for _, component := range c.components {
+		if component.HasLabel(label) && component.LabelOrDefault(label, "") == value {
+			selected.With(component)
+		}
+	}
+	return selected
+}
+
+// ByLabelMatch returns components having a label which key and value match the patterns (see common.LabelMatcher)
+func (c *Collection) ByLabelMatch(keyPattern string, valuePattern string) *Collection {
+	if c.HasErr() {
+		return NewCollection().WithErr(c.Err())
+	}
+
+	matcher, err := common.NewLabelMatcher(keyPattern, valuePattern)
+	if err != nil {
+		c.SetErr(err)
+		return NewCollection().WithErr(c.Err())
+	}
+
+	selected := NewCollection()
+	for _, component := range c.components {
+		if component.MatchesLabels(matcher) {
+			selected.With(component)
+		}
+	}
+	return selected
+}
+
+// ByLabelQuery returns components satisfying the query, e.g. "stage > 3" (see common.LabelQuery)
+func (c *Collection) ByLabelQuery(query string) *Collection {
+	if c.HasErr() {
+		return NewCollection().WithErr(c.Err())
+	}
+
+	q, err := common.ParseLabelQuery(query)
+	if err != nil {
+		c.SetErr(err)
+		return NewCollection().WithErr(c.Err())
+	}
+
+	selected := NewCollection()
+	for _, component := range c.components {
+		if component.MatchesLabels(q) {
+			selected.With(component)
+		}
+	}
+	return selected
+}
+
 // With adds components and returns the collection
 func (c *Collection) With(components ...*Component) *Collection {
 	if c.HasErr() {
@@ -73,3 +143,17 @@ func (c *Collection) Components() (ComponentsMap, error) {
 	}
 	return c.components, nil
 }
+
+// ComponentsOrNil returns components or nil in case of any error
+func (c *Collection) ComponentsOrNil() ComponentsMap {
+	return c.ComponentsOrDefault(nil)
+}
+
+// ComponentsOrDefault returns components or default in case of any error
+func (c *Collection) ComponentsOrDefault(defaultComponents ComponentsMap) ComponentsMap {
+	components, err := c.Components()
+	if err != nil {
+		return defaultComponents
+	}
+	return components
+}
@@ -2,6 +2,7 @@ package component
 
 import (
 	"fmt"
+	"github.com/hovsep/fmesh/common"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -30,7 +31,7 @@ func TestCollection_ByName(t *testing.T) {
 			args: args{
 				name: "c3",
 			},
-			want: New("").WithErr(fmt.Errorf("%w, component name: %s", errNotFound, "c3")),
+			want: New("").WithErr(fmt.Errorf("%w, component name: %s", ErrNotFound, "c3")),
 		},
 	}
 	for _, tt := range tests {
@@ -96,3 +97,87 @@ func TestCollection_With(t *testing.T) {
 		})
 	}
 }
+
+func TestCollection_ByLabelMatch(t *testing.T) {
+	components := NewCollection().With(
+		New("c1").WithLabels(common.LabelsCollection{"subsystem": "billing/invoices"}),
+		New("c2").WithLabels(common.LabelsCollection{"subsystem": "billing/payments"}),
+		New("c3").WithLabels(common.LabelsCollection{"subsystem": "shipping"}),
+	)
+
+	type args struct {
+		keyPattern   string
+		valuePattern string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "glob",
+			args: args{
+				keyPattern:   "subsystem",
+				valuePattern: "billing/*",
+			},
+			want: []string{"c1", "c2"},
+		},
+		{
+			name: "regexp",
+			args: args{
+				keyPattern:   "re:^sub",
+				valuePattern: "re:ping$",
+			},
+			want: []string{"c3"},
+		},
+		{
+			name: "nothing matched",
+			args: args{
+				keyPattern:   "team",
+				valuePattern: "*",
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected := components.ByLabelMatch(tt.args.keyPattern, tt.args.valuePattern)
+			assert.NoError(t, selected.Err())
+			var names []string
+			for name := range selected.ComponentsOrNil() {
+				names = append(names, name)
+			}
+			assert.ElementsMatch(t, tt.want, names)
+		})
+	}
+}
+
+func TestCollection_ByLabel(t *testing.T) {
+	components := NewCollection().With(
+		New("c1").WithLabels(common.LabelsCollection{"metrics": "true"}),
+		New("c2").WithLabels(common.LabelsCollection{"metrics": "false"}),
+		New("c3").WithLabels(common.LabelsCollection{"metrics": "true*"}),
+		New("c4"),
+	)
+
+	selected := components.ByLabel("metrics", "true")
+	assert.NoError(t, selected.Err())
+	assert.Equal(t, 1, selected.Len())
+	assert.NotNil(t, selected.ByName("c1"))
+}
+
+func TestCollection_ByLabelQuery(t *testing.T) {
+	components := NewCollection().With(
+		New("stage-1").WithLabels(common.LabelsCollection{"stage": "1"}),
+		New("stage-4").WithLabels(common.LabelsCollection{"stage": "4"}),
+		New("stage-12").WithLabels(common.LabelsCollection{"stage": "12"}),
+	)
+
+	selected := components.ByLabelQuery("stage > 3")
+	assert.NoError(t, selected.Err())
+	var names []string
+	for name := range selected.ComponentsOrNil() {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"stage-4", "stage-12"}, names)
+}
@@ -1,10 +1,25 @@
 package component
 
 import (
+	"context"
 	"fmt"
+	"github.com/hovsep/fmesh/clock"
 	"github.com/hovsep/fmesh/common"
 	"github.com/hovsep/fmesh/port"
 	"log"
+	"log/slog"
+	"math/rand"
+	"sync"
+)
+
+// Default labels of input and output ports, shared by all components (ports copy them on write)
+var (
+	inputPortLabels = common.LabelsCollection{
+		port.DirectionLabel: port.DirectionIn,
+	}
+	outputPortLabels = common.LabelsCollection{
+		port.DirectionLabel: port.DirectionOut,
+	}
 )
 
 // Component defines a main building block of FMesh
@@ -16,8 +31,54 @@ type Component struct {
 	inputs  *port.Collection
 	outputs *port.Collection
 	f       ActivationFunc
-	logger  *log.Logger
-	state   State
+	// ready tells whether the component can activate without input signals (set for sources)
+	ready ReadinessFunc
+	// idleWait keeps the mesh waiting for the source when nothing else is left to do
+	idleWait IdleWaitFunc
+	// lazyMu guards logger, slog and rand created on first use, as helper goroutines (see Go) may ask for them concurrently
+	lazyMu sync.Mutex
+	// parentLogger is the logger given by the mesh, the prefixed component logger is created from it on first use
+	parentLogger *log.Logger
+	logger       *log.Logger
+	// parentSlog is the structured logger given by the mesh, the component logger is created from it on first use
+	parentSlog *slog.Logger
+	slog       *slog.Logger
+	state      State
+	// stateMeta holds watchers, the schema and modification tracking of the state (nil when none of them is used)
+	stateMeta *stateMeta
+	// stateRollback is set when the state is rolled back on failed activations
+	stateRollback bool
+	// stateVersion is the version of the state layout, stateMigrations map versions to migrations to the next version
+	stateVersion    int
+	stateMigrations map[int]StateMigration
+	rand            *rand.Rand
+	// randSeed is used to create rand on first use (when hasRandSeed is set)
+	randSeed    int64
+	hasRandSeed bool
+	clock       clock.Clock
+	// ctx is the context of the current run
+	ctx context.Context
+	// config is the configuration object of the instance
+	config any
+	// goLimit bounds the number of helper goroutines, helpers are the ones of the current activation
+	goLimit int
+	helpers *helpers
+	// acks is set when at-least-once delivery is enabled
+	acks *acks
+	// retry is set when failed activations are retried
+	retry *retryPolicy
+	// restartStrategy defines how failed activations are supervised, initState are functions setting the initial state
+	restartStrategy RestartStrategy
+	initState       []func(state State)
+	// fallback is the name of the component taking over inputs when this one is degraded
+	fallback string
+	// autoPorts is set when ports looked up by undeclared names are created instead of failing
+	autoPorts bool
+	// priority orders ready components within a cycle, exclusive components preempt non-exclusive ones
+	priority  int
+	exclusive bool
+	// serial is set when the component is pinned to serial execution
+	serial bool
 }
 
 // New creates initialized component
@@ -27,13 +88,9 @@ func New(name string) *Component {
 		DescribedEntity: common.NewDescribedEntity(""),
 		LabeledEntity:   common.NewLabeledEntity(nil),
 		Chainable:       common.NewChainable(),
-		inputs: port.NewCollection().WithDefaultLabels(common.LabelsCollection{
-			port.DirectionLabel: port.DirectionIn,
-		}),
-		outputs: port.NewCollection().WithDefaultLabels(common.LabelsCollection{
-			port.DirectionLabel: port.DirectionOut,
-		}),
-		state: NewState(),
+		inputs:          port.NewCollection().WithDefaultLabels(inputPortLabels),
+		outputs:         port.NewCollection().WithDefaultLabels(outputPortLabels),
+		state:           NewState(),
 	}
 }
 
@@ -52,6 +109,10 @@ func (c *Component) WithLabels(labels common.LabelsCollection) *Component {
 	if c.HasErr() {
 		return c
 	}
+	if err := common.ValidateLabels(labels); err != nil {
+		return c.WithErr(err)
+	}
+
 	c.LabeledEntity.SetLabels(labels)
 	return c
 }
@@ -89,7 +150,7 @@ func (c *Component) WithErr(err error) *Component {
 	return c
 }
 
-// WithLogger creates a new logger prefixed with component name
+// WithLogger sets the logger, the component logger (prefixed with component name) is created from it on first use
 func (c *Component) WithLogger(logger *log.Logger) *Component {
 	if c.HasErr() {
 		return c
@@ -99,12 +160,19 @@ func (c *Component) WithLogger(logger *log.Logger) *Component {
 		return c
 	}
 
-	prefix := fmt.Sprintf("%s : ", c.Name())
-	c.logger = log.New(logger.Writer(), prefix, logger.Flags())
+	c.parentLogger = logger
+	c.logger = nil
 	return c
 }
 
 // Logger returns component logger
 func (c *Component) Logger() *log.Logger {
+	c.lazyMu.Lock()
+	defer c.lazyMu.Unlock()
+
+	if c.logger == nil && c.parentLogger != nil {
+		prefix := fmt.Sprintf("%s : ", c.Name())
+		c.logger = log.New(c.parentLogger.Writer(), prefix, c.parentLogger.Flags())
+	}
 	return c.logger
 }
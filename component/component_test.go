@@ -1,9 +1,11 @@
 package component
 
 import (
+	"bytes"
 	"github.com/hovsep/fmesh/common"
 	"github.com/hovsep/fmesh/port"
 	"github.com/stretchr/testify/assert"
+	"log"
 	"testing"
 )
 
@@ -101,6 +103,19 @@ func TestComponent_WithLabels(t *testing.T) {
 				assert.True(t, component.HasAllLabels("l1", "l2"))
 			},
 		},
+		{
+			name:      "system labels are rejected",
+			component: New("c1"),
+			args: args{
+				labels: common.LabelsCollection{
+					common.SystemLabelPrefix + "l1": "v1",
+				},
+			},
+			assertions: func(t *testing.T, component *Component) {
+				assert.ErrorIs(t, component.Err(), common.ErrReservedLabel)
+				assert.Empty(t, component.Labels())
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -111,3 +126,19 @@ func TestComponent_WithLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestComponent_WithLogger(t *testing.T) {
+	t.Run("no logger", func(t *testing.T) {
+		assert.Nil(t, New("c1").Logger())
+	})
+
+	t.Run("prefixed logger is created on first use", func(t *testing.T) {
+		var output bytes.Buffer
+		c := New("c1").WithLogger(log.New(&output, "", 0))
+		assert.Nil(t, c.logger)
+
+		c.Logger().Println("hello")
+		assert.Same(t, c.Logger(), c.Logger())
+		assert.Equal(t, "c1 : hello\n", output.String())
+	})
+}
@@ -0,0 +1,40 @@
+package component
+
+import (
+	"fmt"
+)
+
+// WithConfig sets the configuration object of the component instance (window size, URL, thresholds, etc.),
+// so parameterized components do not have to capture their parameters in closures
+func (c *Component) WithConfig(config any) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.config = config
+	return c
+}
+
+// Config returns the configuration object of the component (nil when not set)
+func (c *Component) Config() any {
+	return c.config
+}
+
+// HasConfig says whether the configuration object is set
+func (c *Component) HasConfig() bool {
+	return c.config != nil
+}
+
+// ConfigAs returns the configuration object of the component as T (a config set as *T is dereferenced)
+func ConfigAs[T any](c *Component) (T, error) {
+	var zero T
+	switch config := c.config.(type) {
+	case T:
+		return config, nil
+	case *T:
+		if config != nil {
+			return *config, nil
+		}
+	}
+	return zero, fmt.Errorf("%w: want %T, got %T", ErrUnexpectedConfigType, zero, c.config)
+}
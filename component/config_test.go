@@ -0,0 +1,56 @@
+package component
+
+import (
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type windowConfig struct {
+	Size      int
+	Threshold float64
+}
+
+func TestComponent_WithConfig(t *testing.T) {
+	t.Run("no config", func(t *testing.T) {
+		c := New("c")
+		assert.False(t, c.HasConfig())
+		assert.Nil(t, c.Config())
+		_, err := ConfigAs[windowConfig](c)
+		assert.ErrorIs(t, err, ErrUnexpectedConfigType)
+	})
+
+	t.Run("config is available in activation function", func(t *testing.T) {
+		var size int
+		c := New("window").
+			WithConfig(windowConfig{Size: 3}).
+			WithInputs("in").
+			WithActivationFunc(func(this *Component) error {
+				config, err := ConfigAs[windowConfig](this)
+				size = config.Size
+				return err
+			})
+		assert.True(t, c.HasConfig())
+		assert.Equal(t, windowConfig{Size: 3}, c.Config())
+
+		c.InputByName("in").PutSignals(signal.New(1))
+		assert.Equal(t, ActivationCodeOK, c.MaybeActivate().Code())
+		assert.Equal(t, 3, size)
+	})
+
+	t.Run("pointer config", func(t *testing.T) {
+		c := New("c").WithConfig(&windowConfig{Threshold: 0.5})
+		config, err := ConfigAs[windowConfig](c)
+		assert.NoError(t, err)
+		assert.Equal(t, 0.5, config.Threshold)
+
+		pointer, err := ConfigAs[*windowConfig](c)
+		assert.NoError(t, err)
+		assert.Equal(t, 0.5, pointer.Threshold)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		_, err := ConfigAs[string](New("c").WithConfig(42))
+		assert.ErrorIs(t, err, ErrUnexpectedConfigType)
+	})
+}
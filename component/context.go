@@ -0,0 +1,22 @@
+package component
+
+import "context"
+
+// WithContext sets the context of the run the component takes part in (the mesh sets it when a run starts)
+func (c *Component) WithContext(ctx context.Context) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.ctx = ctx
+	return c
+}
+
+// Context returns the context of the current run, activation functions can use it to get run-scoped values
+// (tenant ID, request metadata, etc.) or to stop long operations when the run is cancelled
+func (c *Component) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
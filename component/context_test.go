@@ -0,0 +1,32 @@
+package component
+
+import (
+	"context"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type contextKey string
+
+func TestComponent_Context(t *testing.T) {
+	t.Run("background by default", func(t *testing.T) {
+		assert.Equal(t, context.Background(), New("c").Context())
+	})
+
+	t.Run("values are available in activation function", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), contextKey("tenant"), "acme")
+		var tenant any
+		c := New("c").
+			WithInputs("in").
+			WithActivationFunc(func(this *Component) error {
+				tenant = this.Context().Value(contextKey("tenant"))
+				return nil
+			}).
+			WithContext(ctx)
+		c.InputByName("in").PutSignals(signal.New(1))
+
+		assert.Equal(t, ActivationCodeOK, c.MaybeActivate().Code())
+		assert.Equal(t, "acme", tenant)
+	})
+}
@@ -0,0 +1,9 @@
+package component
+
+import "github.com/hovsep/fmesh/signal"
+
+// NewCorrelatedSignal creates a signal correlated with the parent signal (see signal.NewCorrelated),
+// so replies and scatter-gather results can be matched with SignalsByCorrelation of the port receiving them
+func (c *Component) NewCorrelatedSignal(parent *signal.Signal, payload any) *signal.Signal {
+	return signal.NewCorrelated(parent, payload)
+}
@@ -0,0 +1,28 @@
+package component
+
+import (
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestComponent_NewCorrelatedSignal(t *testing.T) {
+	// Scatter-gather: each request is fanned out into two parts, the gatherer matches parts by correlation ID
+	scatter := New("scatter").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *Component) error {
+		for _, request := range this.InputByName("in").AllSignalsOrNil() {
+			this.OutputByName("out").PutSignals(
+				this.NewCorrelatedSignal(request, "part-1"),
+				this.NewCorrelatedSignal(request, "part-2"),
+			)
+		}
+		return nil
+	})
+	r1, r2 := signal.New("r1").WithCorrelationID("r1"), signal.New("r2")
+	scatter.InputByName("in").PutSignals(r1, r2)
+
+	assert.NoError(t, scatter.MaybeActivate().ActivationError())
+	out := scatter.OutputByName("out")
+	assert.Len(t, out.SignalsByCorrelation("r1"), 2)
+	assert.Len(t, out.SignalsByCorrelation(r2.CorrelationID()), 2)
+	assert.Empty(t, out.SignalsByCorrelation("r3"))
+}
@@ -0,0 +1,58 @@
+package component
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/clock"
+	"github.com/hovsep/fmesh/signal"
+	"time"
+)
+
+// CronSourceOutput is the output port of cron sources
+const CronSourceOutput = "out"
+
+// NewCronSource creates a source component emitting a trigger signal on CronSourceOutput port each time the cron expression fires,
+// the payload is the scheduled time (time.Time). Firings missed while the mesh was busy are emitted one per cycle.
+// When nothing else is left to do the mesh waits for the next firing instead of stopping, so a mesh with a cron source
+// runs continuously (it should be configured with fmesh.UnlimitedCycles)
+func NewCronSource(name string, expr string) *Component {
+	schedule, err := clock.ParseCron(expr)
+	if err != nil {
+		return New(name).WithErr(err)
+	}
+
+	// next is the upcoming firing, it is computed on first use as the clock is set by the mesh
+	var next time.Time
+
+	nextFiring := func(this *Component) time.Time {
+		if next.IsZero() {
+			next = schedule.Next(this.Clock().Now())
+		}
+		return next
+	}
+
+	return New(name).
+		WithDescription(fmt.Sprintf("emits triggers on schedule %q", expr)).
+		WithOutputs(CronSourceOutput).
+		WithReadinessFunc(func(this *Component) bool {
+			firing := nextFiring(this)
+			return !firing.IsZero() && !this.Clock().Now().Before(firing)
+		}).
+		WithIdleWaitFunc(func(this *Component, cancel <-chan struct{}) bool {
+			firing := nextFiring(this)
+			if firing.IsZero() {
+				// Schedule never fires
+				return false
+			}
+			select {
+			case <-this.Clock().After(firing.Sub(this.Clock().Now())):
+				return true
+			case <-cancel:
+				return false
+			}
+		}).
+		WithActivationFunc(func(this *Component) error {
+			this.OutputByName(CronSourceOutput).PutSignals(signal.New(next))
+			next = schedule.Next(next)
+			return nil
+		})
+}
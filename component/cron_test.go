@@ -0,0 +1,65 @@
+package component
+
+import (
+	"github.com/hovsep/fmesh/clock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewCronSource(t *testing.T) {
+	t.Run("invalid expression", func(t *testing.T) {
+		source := NewCronSource("cron", "* * *")
+		assert.ErrorIs(t, source.Err(), clock.ErrInvalidCronExpression)
+	})
+
+	t.Run("fires on schedule", func(t *testing.T) {
+		clk := clock.NewVirtual(time.Date(2024, time.January, 1, 23, 30, 0, 0, time.UTC))
+		source := NewCronSource("nightly", "@midnight").WithClock(clk)
+		assert.True(t, source.IsSource())
+		assert.True(t, source.HasIdleWait())
+
+		assert.Equal(t, ActivationCodeNoInput, source.MaybeActivate().Code())
+
+		clk.Advance(30 * time.Minute)
+		assert.Equal(t, ActivationCodeOK, source.MaybeActivate().Code())
+		assert.Equal(t, time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC), source.OutputByName(CronSourceOutput).FirstSignalPayloadOrNil())
+		assert.Equal(t, ActivationCodeNoInput, source.MaybeActivate().Code())
+	})
+
+	t.Run("missed firings are emitted one by one", func(t *testing.T) {
+		clk := clock.NewVirtual(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+		source := NewCronSource("hourly", "@hourly").WithClock(clk)
+		assert.Equal(t, ActivationCodeNoInput, source.MaybeActivate().Code())
+
+		clk.Advance(2 * time.Hour)
+		assert.Equal(t, ActivationCodeOK, source.MaybeActivate().Code())
+		assert.Equal(t, ActivationCodeOK, source.MaybeActivate().Code())
+		assert.Equal(t, ActivationCodeNoInput, source.MaybeActivate().Code())
+		payloads, err := source.OutputByName(CronSourceOutput).AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{
+			time.Date(2024, time.January, 1, 1, 0, 0, 0, time.UTC),
+			time.Date(2024, time.January, 1, 2, 0, 0, 0, time.UTC),
+		}, payloads)
+	})
+
+	t.Run("idle wait", func(t *testing.T) {
+		clk := clock.NewVirtual(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+		source := NewCronSource("hourly", "@hourly").WithClock(clk)
+
+		woken := make(chan bool)
+		go func() {
+			woken <- source.IdleWait(make(chan struct{}))
+		}()
+		assert.Eventually(t, func() bool {
+			return clk.Pending() == 1
+		}, time.Second, time.Millisecond)
+		clk.Advance(time.Hour)
+		assert.True(t, <-woken)
+
+		cancel := make(chan struct{})
+		close(cancel)
+		assert.False(t, NewCronSource("hourly", "@hourly").WithClock(clk).IdleWait(cancel))
+	})
+}
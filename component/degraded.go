@@ -0,0 +1,27 @@
+package component
+
+import "github.com/hovsep/fmesh/common"
+
+// DegradedLabel marks components degraded by the mesh (see fmesh.DegradeFailing), the value is "true"
+const DegradedLabel = common.SystemLabelPrefix + "component:degraded"
+
+// WithFallback declares the component taking over the inputs of this one once it is degraded,
+// the fallback must have input ports with the same names
+func (c *Component) WithFallback(name string) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.fallback = name
+	return c
+}
+
+// Fallback returns the name of the fallback component (empty when there is none)
+func (c *Component) Fallback() string {
+	return c.fallback
+}
+
+// IsDegraded says whether the component is degraded
+func (c *Component) IsDegraded() bool {
+	return c.LabelOrDefault(DegradedLabel, "") == "true"
+}
@@ -0,0 +1,72 @@
+package component
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/port"
+	"sort"
+	"strings"
+)
+
+// String returns a short readable representation of the component, e.g. "component adder (inputs: 2, outputs: 1)"
+func (c *Component) String() string {
+	if c.HasErr() {
+		return fmt.Sprintf("component %s (error: %v)", c.Name(), c.Err())
+	}
+	return fmt.Sprintf("component %s (inputs: %d, outputs: %d)", c.Name(), c.Inputs().Len(), c.Outputs().Len())
+}
+
+// Describe returns a readable multi-line summary of the component: description, labels, ports with their buffers and pipes
+// and the keys of the state
+func (c *Component) Describe() string {
+	var sb strings.Builder
+	sb.WriteString(c.String())
+	if c.HasErr() {
+		return sb.String()
+	}
+
+	if c.Description() != "" {
+		sb.WriteString("\n  description: " + c.Description())
+	}
+	if len(c.Labels()) > 0 {
+		sb.WriteString("\n  labels: " + c.Labels().String())
+	}
+	if c.IsSource() {
+		sb.WriteString("\n  source")
+	}
+	if c.IsDegraded() {
+		sb.WriteString("\n  degraded")
+	}
+	if c.fallback != "" {
+		sb.WriteString("\n  fallback: " + c.fallback)
+	}
+	writePorts(&sb, "inputs", c.Inputs().PortsOrNil())
+	writePorts(&sb, "outputs", c.Outputs().PortsOrNil())
+
+	if len(c.state) > 0 {
+		keys := make([]string, 0, len(c.state))
+		for key := range c.state {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		sb.WriteString("\n  state: " + strings.Join(keys, ", "))
+	}
+	return sb.String()
+}
+
+// writePorts writes descriptions of the ports sorted by name
+func writePorts(sb *strings.Builder, title string, ports port.PortMap) {
+	if len(ports) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(ports))
+	for name := range ports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sb.WriteString("\n  " + title + ":")
+	for _, name := range names {
+		sb.WriteString("\n    " + strings.ReplaceAll(ports[name].Describe(), "\n", "\n    "))
+	}
+}
@@ -6,9 +6,17 @@ import (
 )
 
 var (
-	errNotFound             = errors.New("component not found")
-	errWaitingForInputs     = errors.New("component is waiting for some inputs")
-	errWaitingForInputsKeep = fmt.Errorf("%w: do not clear input ports", errWaitingForInputs)
+	ErrNotFound                  = errors.New("component not found")
+	errWaitingForInputs          = errors.New("component is waiting for some inputs")
+	errWaitingForInputsKeep      = fmt.Errorf("%w: do not clear input ports", errWaitingForInputs)
+	ErrInvalidChunkSize          = errors.New("chunk size must be positive")
+	ErrUnexpectedConfigType      = errors.New("unexpected config type")
+	ErrStateSchemaViolation      = errors.New("state schema violation")
+	ErrFailedToMigrateState      = errors.New("failed to migrate state")
+	ErrMissingActivationFunc     = errors.New("activation function is not set")
+	ErrInvalidBatchConfig        = errors.New("invalid batch config")
+	ErrInvalidLoadBalancerConfig = errors.New("invalid load balancer config")
+	ErrForeignPortHandle         = errors.New("port handle belongs to another component")
 )
 
 // NewErrWaitForInputs returns respective error
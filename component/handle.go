@@ -0,0 +1,43 @@
+package component
+
+import (
+	"github.com/hovsep/fmesh/port"
+)
+
+// PortHandle is a port of the component resolved by name once (typically when the component is built),
+// so activation functions reach the port without looking it up by name in every activation.
+// Declared ports are never replaced, hence a handle stays valid for the lifetime of the component
+type PortHandle struct {
+	owner *Component
+	port  *port.Port
+}
+
+// InputHandle resolves the input port by name (see InputByName) and returns its handle
+func (c *Component) InputHandle(name string) PortHandle {
+	return PortHandle{
+		owner: c,
+		port:  c.InputByName(name),
+	}
+}
+
+// OutputHandle resolves the output port by name (see OutputByName) and returns its handle
+func (c *Component) OutputHandle(name string) PortHandle {
+	return PortHandle{
+		owner: c,
+		port:  c.OutputByName(name),
+	}
+}
+
+// Port returns the port of the handle, the handle must be resolved by the same component
+// (a handle of another component, e.g. captured by an activation function shared by many components, gives a port with error)
+func (c *Component) Port(handle PortHandle) *port.Port {
+	if c.HasErr() {
+		return port.New("").WithErr(c.Err())
+	}
+
+	if handle.owner != c {
+		c.SetErr(ErrForeignPortHandle)
+		return port.New("").WithErr(c.Err())
+	}
+	return handle.port
+}
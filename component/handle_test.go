@@ -0,0 +1,52 @@
+package component
+
+import (
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestComponent_PortHandles(t *testing.T) {
+	t.Run("handles resolve ports once", func(t *testing.T) {
+		c := New("doubler").WithInputs("num").WithOutputs("res")
+		num, res := c.InputHandle("num"), c.OutputHandle("res")
+		c.WithActivationFunc(func(this *Component) error {
+			for _, sig := range this.Port(num).AllSignalsOrNil() {
+				this.Port(res).PutSignals(signal.New(sig.PayloadOrNil().(int) * 2))
+			}
+			return nil
+		})
+
+		assert.Same(t, c.InputByName("num"), c.Port(num))
+		assert.Same(t, c.OutputByName("res"), c.Port(res))
+
+		c.InputByName("num").PutSignals(signal.New(21))
+		assert.Equal(t, ActivationCodeOK, c.MaybeActivate().Code())
+		assert.Equal(t, 42, c.OutputByName("res").FirstSignalPayloadOrNil())
+	})
+
+	t.Run("unknown port", func(t *testing.T) {
+		c := New("c").WithInputs("in")
+		handle := c.InputHandle("typo")
+		assert.True(t, handle.port.HasErr())
+		assert.ErrorIs(t, c.Port(handle).Err(), port.ErrPortNotFoundInCollection)
+	})
+
+	t.Run("handle of another component", func(t *testing.T) {
+		c1, c2 := New("c1").WithInputs("in"), New("c2").WithInputs("in")
+		handle := c1.InputHandle("in")
+		assert.ErrorIs(t, c2.Port(handle).Err(), ErrForeignPortHandle)
+		assert.True(t, c2.HasErr())
+	})
+}
+
+func BenchmarkComponent_PortHandle(b *testing.B) {
+	c := New("c").WithInputs("num", "factor", "offset").WithOutputs("res")
+	factor, res := c.InputHandle("factor"), c.OutputHandle("res")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.Port(factor)
+		c.Port(res)
+	}
+}
@@ -0,0 +1,101 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// helpers are goroutines started by the activation function via Go
+type helpers struct {
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+	// sem bounds the number of running helpers (nil means no limit)
+	sem     chan struct{}
+	errOnce sync.Once
+	err     error
+}
+
+// WithGoLimit bounds the number of helper goroutines (started via Go) running at the same time, 0 means no limit
+func (c *Component) WithGoLimit(limit int) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.goLimit = limit
+	return c
+}
+
+// Go runs f in a helper goroutine, the activation is considered finished only when all helpers return.
+// The first helper error cancels the context given to other helpers and is returned as the activation error
+// (unless the activation function itself returns an error). When the limit is reached Go blocks until a helper returns.
+// Ports are not guarded against concurrent access, so helpers should hand their results back to the activation function
+// (or put signals on distinct ports)
+func (c *Component) Go(f func(ctx context.Context) error) {
+	h := c.currentHelpers()
+	if h.sem != nil {
+		h.sem <- struct{}{}
+	}
+
+	h.wg.Add(1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				h.fail(fmt.Errorf("helper goroutine %w", newPanicError(r)))
+			}
+			if h.sem != nil {
+				<-h.sem
+			}
+			h.wg.Done()
+		}()
+
+		if err := f(h.ctx); err != nil {
+			h.fail(err)
+		}
+	}()
+}
+
+// currentHelpers returns the helpers of the current activation (created on first use)
+func (c *Component) currentHelpers() *helpers {
+	if c.helpers == nil {
+		h := &helpers{}
+		h.ctx, h.cancel = context.WithCancel(c.Context())
+		if c.goLimit > 0 {
+			h.sem = make(chan struct{}, c.goLimit)
+		}
+		c.helpers = h
+	}
+	return c.helpers
+}
+
+// fail records the first error and cancels other helpers
+func (h *helpers) fail(err error) {
+	h.errOnce.Do(func() {
+		h.err = err
+		h.cancel()
+	})
+}
+
+// WaitHelpers waits for all helpers started so far and returns the first error,
+// activation functions use it to collect results of helpers, the runtime calls it when the activation function returns
+func (c *Component) WaitHelpers() error {
+	h := c.helpers
+	if h == nil {
+		return nil
+	}
+
+	h.wg.Wait()
+	h.cancel()
+	c.helpers = nil
+	return h.err
+}
+
+// abortHelpers cancels the context given to helpers and waits for them to return,
+// it is used when the activation function panics, so helpers waiting for the context do not block the activation forever
+func (c *Component) abortHelpers() {
+	if c.helpers != nil {
+		c.helpers.cancel()
+	}
+	_ = c.WaitHelpers()
+}
@@ -0,0 +1,234 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestComponent_Go(t *testing.T) {
+	tests := []struct {
+		name       string
+		component  func() *Component
+		assertions func(t *testing.T, c *Component, activationResult *ActivationResult)
+	}{
+		{
+			name: "activation function collects results of helpers",
+			component: func() *Component {
+				return New("c").
+					WithInputs("in").
+					WithOutputs("out").
+					WithActivationFunc(func(this *Component) error {
+						payloads, err := this.InputByName("in").AllSignalsPayloads()
+						if err != nil {
+							return err
+						}
+
+						squares := make([]int, len(payloads))
+						for i, payload := range payloads {
+							this.Go(func(ctx context.Context) error {
+								squares[i] = payload.(int) * payload.(int)
+								return nil
+							})
+						}
+						if err := this.WaitHelpers(); err != nil {
+							return err
+						}
+
+						for _, square := range squares {
+							this.OutputByName("out").PutSignals(signal.New(square))
+						}
+						return nil
+					})
+			},
+			assertions: func(t *testing.T, c *Component, activationResult *ActivationResult) {
+				assert.Equal(t, ActivationCodeOK, activationResult.Code())
+				payloads, err := c.OutputByName("out").AllSignalsPayloads()
+				assert.NoError(t, err)
+				assert.Equal(t, []any{1, 4, 9}, payloads)
+			},
+		},
+		{
+			name: "activation is finished when helpers are done",
+			component: func() *Component {
+				return New("c").
+					WithInputs("in").
+					WithActivationFunc(func(this *Component) error {
+						this.Go(func(ctx context.Context) error {
+							time.Sleep(10 * time.Millisecond)
+							this.State().Set("done", true)
+							return nil
+						})
+						return nil
+					})
+			},
+			assertions: func(t *testing.T, c *Component, activationResult *ActivationResult) {
+				assert.Equal(t, ActivationCodeOK, activationResult.Code())
+				assert.Equal(t, true, c.State().Get("done"))
+			},
+		},
+		{
+			name: "first error cancels other helpers",
+			component: func() *Component {
+				return New("c").
+					WithInputs("in").
+					WithActivationFunc(func(this *Component) error {
+						this.Go(func(ctx context.Context) error {
+							<-ctx.Done()
+							return ctx.Err()
+						})
+						this.Go(func(ctx context.Context) error {
+							return errors.New("boom")
+						})
+						return nil
+					})
+			},
+			assertions: func(t *testing.T, c *Component, activationResult *ActivationResult) {
+				assert.Equal(t, ActivationCodeReturnedError, activationResult.Code())
+				assert.ErrorContains(t, activationResult.ActivationError(), "boom")
+			},
+		},
+		{
+			name: "error of activation function takes precedence",
+			component: func() *Component {
+				return New("c").
+					WithInputs("in").
+					WithActivationFunc(func(this *Component) error {
+						this.Go(func(ctx context.Context) error {
+							return errors.New("helper failed")
+						})
+						return errors.New("activation failed")
+					})
+			},
+			assertions: func(t *testing.T, c *Component, activationResult *ActivationResult) {
+				assert.ErrorContains(t, activationResult.ActivationError(), "activation failed")
+			},
+		},
+		{
+			name: "helper panic",
+			component: func() *Component {
+				return New("c").
+					WithInputs("in").
+					WithActivationFunc(func(this *Component) error {
+						this.Go(func(ctx context.Context) error {
+							panic("oops")
+						})
+						return nil
+					})
+			},
+			assertions: func(t *testing.T, c *Component, activationResult *ActivationResult) {
+				assert.Equal(t, ActivationCodeReturnedError, activationResult.Code())
+				assert.ErrorContains(t, activationResult.ActivationError(), "helper goroutine panicked with: oops")
+			},
+		},
+		{
+			name: "limit",
+			component: func() *Component {
+				return New("c").
+					WithInputs("in").
+					WithGoLimit(2).
+					WithActivationFunc(func(this *Component) error {
+						var running, maxRunning atomic.Int32
+						for i := 0; i < 10; i++ {
+							this.Go(func(ctx context.Context) error {
+								n := running.Add(1)
+								defer running.Add(-1)
+								for {
+									current := maxRunning.Load()
+									if n <= current || maxRunning.CompareAndSwap(current, n) {
+										break
+									}
+								}
+								time.Sleep(time.Millisecond)
+								return nil
+							})
+						}
+						if err := this.WaitHelpers(); err != nil {
+							return err
+						}
+						this.State().Set("max running", int(maxRunning.Load()))
+						return nil
+					})
+			},
+			assertions: func(t *testing.T, c *Component, activationResult *ActivationResult) {
+				assert.Equal(t, ActivationCodeOK, activationResult.Code())
+				assert.LessOrEqual(t, c.State().Get("max running"), 2)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.component()
+			c.InputByName("in").PutSignals(signal.New(1), signal.New(2), signal.New(3))
+			tt.assertions(t, c, c.MaybeActivate())
+		})
+	}
+
+	t.Run("helpers are awaited when activation function panics", func(t *testing.T) {
+		finished := false
+		c := New("c").
+			WithInputs("in").
+			WithActivationFunc(func(this *Component) error {
+				this.Go(func(ctx context.Context) error {
+					time.Sleep(5 * time.Millisecond)
+					finished = true
+					return nil
+				})
+				panic("oops")
+			})
+		c.InputByName("in").PutSignals(signal.New(1))
+		assert.Equal(t, ActivationCodePanicked, c.MaybeActivate().Code())
+		assert.True(t, finished)
+	})
+	t.Run("helpers are cancelled when activation function panics", func(t *testing.T) {
+		cancelled := false
+		c := New("c").
+			WithInputs("in").
+			WithActivationFunc(func(this *Component) error {
+				this.Go(func(ctx context.Context) error {
+					<-ctx.Done()
+					cancelled = true
+					return ctx.Err()
+				})
+				panic("oops")
+			})
+		c.InputByName("in").PutSignals(signal.New(1))
+
+		done := make(chan *ActivationResult)
+		go func() {
+			done <- c.MaybeActivate()
+		}()
+		select {
+		case activationResult := <-done:
+			assert.Equal(t, ActivationCodePanicked, activationResult.Code())
+			assert.True(t, cancelled)
+		case <-time.After(time.Second):
+			t.Fatal("activation is blocked by the helper")
+		}
+	})
+	t.Run("helpers get lazily created members concurrently", func(t *testing.T) {
+		c := New("c").
+			WithInputs("in").
+			WithLogger(log.New(io.Discard, "", 0)).
+			WithRandSeed(42).
+			WithActivationFunc(func(this *Component) error {
+				for i := 0; i < 4; i++ {
+					this.Go(func(ctx context.Context) error {
+						this.Logger().Print("helper")
+						this.Slog().Debug("helper")
+						_ = this.Rand()
+						return nil
+					})
+				}
+				return nil
+			})
+		c.InputByName("in").PutSignals(signal.New(1))
+		assert.Equal(t, ActivationCodeOK, c.MaybeActivate().Code())
+	})
+}
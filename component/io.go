@@ -1,6 +1,9 @@
 package component
 
-import "github.com/hovsep/fmesh/port"
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/port"
+)
 
 // withInputPorts sets input ports collection
 func (c *Component) withInputPorts(collection *port.Collection) *Component {
@@ -33,9 +36,9 @@ func (c *Component) WithInputs(portNames ...string) *Component {
 		return c
 	}
 
-	ports, err := port.NewGroup(portNames...).Ports()
+	ports, err := declaredPorts(c.inputs, port.NewGroup(portNames...))
 	if err != nil {
-		c.SetErr(err)
+		c.SetErr(fmt.Errorf("invalid input ports: %w", err))
 		return New("").WithErr(c.Err())
 	}
 
@@ -48,9 +51,9 @@ func (c *Component) WithOutputs(portNames ...string) *Component {
 		return c
 	}
 
-	ports, err := port.NewGroup(portNames...).Ports()
+	ports, err := declaredPorts(c.outputs, port.NewGroup(portNames...))
 	if err != nil {
-		c.SetErr(err)
+		c.SetErr(fmt.Errorf("invalid output ports: %w", err))
 		return New("").WithErr(c.Err())
 	}
 	return c.withOutputPorts(c.Outputs().With(ports...))
@@ -62,7 +65,11 @@ func (c *Component) WithInputsIndexed(prefix string, startIndex int, endIndex in
 		return c
 	}
 
-	return c.withInputPorts(c.Inputs().WithIndexed(prefix, startIndex, endIndex))
+	ports, err := declaredPorts(c.inputs, port.NewIndexedGroup(prefix, startIndex, endIndex))
+	if err != nil {
+		return c.WithErr(fmt.Errorf("invalid input ports: %w", err))
+	}
+	return c.withInputPorts(c.Inputs().With(ports...))
 }
 
 // WithOutputsIndexed creates multiple prefixed ports
@@ -71,7 +78,36 @@ func (c *Component) WithOutputsIndexed(prefix string, startIndex int, endIndex i
 		return c
 	}
 
-	return c.withOutputPorts(c.Outputs().WithIndexed(prefix, startIndex, endIndex))
+	ports, err := declaredPorts(c.outputs, port.NewIndexedGroup(prefix, startIndex, endIndex))
+	if err != nil {
+		return c.WithErr(fmt.Errorf("invalid output ports: %w", err))
+	}
+	return c.withOutputPorts(c.Outputs().With(ports...))
+}
+
+// declaredPorts returns ports of the group being declared, names must not be empty and must not repeat
+// (neither within the group, nor with ports declared before), as ports with the same name would silently collapse into one
+func declaredPorts(declared *port.Collection, group *port.Group) (port.Ports, error) {
+	ports, err := group.Ports()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(ports))
+	for _, p := range ports {
+		name := p.Name()
+		if name == "" {
+			return nil, port.ErrEmptyPortName
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w, port name: %s (declared twice)", port.ErrDuplicatePortName, name)
+		}
+		if _, ok := declared.PortsOrNil()[name]; ok {
+			return nil, fmt.Errorf("%w, port name: %s (already declared)", port.ErrDuplicatePortName, name)
+		}
+		seen[name] = true
+	}
+	return ports, nil
 }
 
 // Inputs getter
@@ -97,6 +133,7 @@ func (c *Component) OutputByName(name string) *port.Port {
 	if c.HasErr() {
 		return port.New("").WithErr(c.Err())
 	}
+	c.maybeCreatePort(c.outputs, name)
 	outputPort := c.Outputs().ByName(name)
 	if outputPort.HasErr() {
 		c.SetErr(outputPort.Err())
@@ -110,6 +147,7 @@ func (c *Component) InputByName(name string) *port.Port {
 	if c.HasErr() {
 		return port.New("").WithErr(c.Err())
 	}
+	c.maybeCreatePort(c.inputs, name)
 	inputPort := c.Inputs().ByName(name)
 	if inputPort.HasErr() {
 		c.SetErr(inputPort.Err())
@@ -146,3 +184,81 @@ func (c *Component) ClearInputs() *Component {
 	c.Inputs().Clear()
 	return c
 }
+
+// InputByNameE returns the input port by name, unlike InputByName it returns the error instead of setting it on the component
+func (c *Component) InputByNameE(name string) (*port.Port, error) {
+	if c.HasErr() {
+		return nil, c.Err()
+	}
+
+	c.maybeCreatePort(c.inputs, name)
+	inputPort, err := c.inputs.ByNameE(name)
+	if err != nil {
+		return nil, fmt.Errorf("component %s: %w", c.Name(), err)
+	}
+	return inputPort, nil
+}
+
+// OutputByNameE returns the output port by name, unlike OutputByName it returns the error instead of setting it on the component
+func (c *Component) OutputByNameE(name string) (*port.Port, error) {
+	if c.HasErr() {
+		return nil, c.Err()
+	}
+
+	c.maybeCreatePort(c.outputs, name)
+	outputPort, err := c.outputs.ByNameE(name)
+	if err != nil {
+		return nil, fmt.Errorf("component %s: %w", c.Name(), err)
+	}
+	return outputPort, nil
+}
+
+// MustInputByName returns the input port by name and panics when there is none,
+// it is meant for wiring code where a missing port is a programming error
+func (c *Component) MustInputByName(name string) *port.Port {
+	inputPort, err := c.InputByNameE(name)
+	if err != nil {
+		panic(fmt.Sprintf("input port lookup failed: %v", err))
+	}
+	return inputPort
+}
+
+// MustOutputByName returns the output port by name and panics when there is none,
+// it is meant for wiring code where a missing port is a programming error
+func (c *Component) MustOutputByName(name string) *port.Port {
+	outputPort, err := c.OutputByNameE(name)
+	if err != nil {
+		panic(fmt.Sprintf("output port lookup failed: %v", err))
+	}
+	return outputPort
+}
+
+// WithAutoPorts makes InputByName and OutputByName create ports with undeclared names (each creation is logged)
+// instead of failing, which speeds up prototyping. Typos go unnoticed this way, so it is not meant for production
+func (c *Component) WithAutoPorts() *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.autoPorts = true
+	return c
+}
+
+// maybeCreatePort creates the port in the collection when it is missing and auto ports are enabled
+func (c *Component) maybeCreatePort(collection *port.Collection, name string) {
+	if !c.autoPorts || collection.HasErr() {
+		return
+	}
+	if _, ok := collection.PortsOrNil()[name]; ok {
+		return
+	}
+
+	direction := port.DirectionIn
+	if collection == c.outputs {
+		direction = port.DirectionOut
+	}
+	collection.With(port.New(name))
+	if logger := c.Logger(); logger != nil {
+		logger.Printf("auto-created %s port %s", direction, name)
+	}
+}
@@ -1,11 +1,13 @@
 package component
 
 import (
+	"bytes"
 	"errors"
 	"github.com/hovsep/fmesh/common"
 	"github.com/hovsep/fmesh/port"
 	"github.com/hovsep/fmesh/signal"
 	"github.com/stretchr/testify/assert"
+	"log"
 	"testing"
 )
 
@@ -178,6 +180,73 @@ func TestComponent_WithInputsIndexed(t *testing.T) {
 	}
 }
 
+func TestComponent_PortDeclarationValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		component func() *Component
+		wantErr   error
+		wantMsg   string
+	}{
+		{
+			name: "duplicate within declaration",
+			component: func() *Component {
+				return New("c").WithInputs("a", "b", "a")
+			},
+			wantErr: port.ErrDuplicatePortName,
+			wantMsg: "invalid input ports: duplicate port name, port name: a (declared twice)",
+		},
+		{
+			name: "duplicate of declared port",
+			component: func() *Component {
+				return New("c").WithOutputs("a").WithOutputs("b", "a")
+			},
+			wantErr: port.ErrDuplicatePortName,
+			wantMsg: "invalid output ports: duplicate port name, port name: a (already declared)",
+		},
+		{
+			name: "empty name",
+			component: func() *Component {
+				return New("c").WithInputs("a", "")
+			},
+			wantErr: port.ErrEmptyPortName,
+			wantMsg: "invalid input ports: port name is empty",
+		},
+		{
+			name: "collision with indexed range",
+			component: func() *Component {
+				return New("c").WithOutputsIndexed("out", 1, 3).WithOutputs("out2")
+			},
+			wantErr: port.ErrDuplicatePortName,
+			wantMsg: "invalid output ports: duplicate port name, port name: out2 (already declared)",
+		},
+		{
+			name: "indexed range colliding with declared port",
+			component: func() *Component {
+				return New("c").WithInputs("in2").WithInputsIndexed("in", 1, 3)
+			},
+			wantErr: port.ErrDuplicatePortName,
+			wantMsg: "invalid input ports: duplicate port name, port name: in2 (already declared)",
+		},
+		{
+			name: "same name on input and output is fine",
+			component: func() *Component {
+				return New("c").WithInputs("data").WithOutputs("data")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.component()
+			if tt.wantErr == nil {
+				assert.NoError(t, c.Err())
+				return
+			}
+			assert.ErrorIs(t, c.Err(), tt.wantErr)
+			assert.EqualError(t, c.Err(), tt.wantMsg)
+		})
+	}
+}
+
 func TestComponent_WithOutputsIndexed(t *testing.T) {
 	type args struct {
 		prefix     string
@@ -286,16 +355,82 @@ func TestComponent_Outputs(t *testing.T) {
 func TestComponent_ShortcutMethods(t *testing.T) {
 	t.Run("InputByName", func(t *testing.T) {
 		c := New("c").WithInputs("a", "b", "c")
-		assert.Equal(t, port.New("b").WithLabels(common.LabelsCollection{
+		got := c.InputByName("b")
+		assert.Equal(t, "b", got.Name())
+		assert.Equal(t, common.LabelsCollection{
 			port.DirectionLabel: port.DirectionIn,
-		}), c.InputByName("b"))
+		}, got.Labels())
 	})
 
 	t.Run("OutputByName", func(t *testing.T) {
 		c := New("c").WithOutputs("a", "b", "c")
-		assert.Equal(t, port.New("b").WithLabels(common.LabelsCollection{
+		got := c.OutputByName("b")
+		assert.Equal(t, "b", got.Name())
+		assert.Equal(t, common.LabelsCollection{
 			port.DirectionLabel: port.DirectionOut,
-		}), c.OutputByName("b"))
+		}, got.Labels())
+	})
+}
+
+func TestComponent_ErrorReturningLookups(t *testing.T) {
+	c := New("c").WithInputs("in").WithOutputs("out")
+
+	t.Run("found", func(t *testing.T) {
+		in, err := c.InputByNameE("in")
+		assert.NoError(t, err)
+		assert.Equal(t, "in", in.Name())
+		out, err := c.OutputByNameE("out")
+		assert.NoError(t, err)
+		assert.Equal(t, "out", out.Name())
+		assert.Same(t, in, c.MustInputByName("in"))
+		assert.Same(t, out, c.MustOutputByName("out"))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := c.InputByNameE("typo")
+		assert.ErrorIs(t, err, port.ErrPortNotFoundInCollection)
+		assert.EqualError(t, err, "component c: port not found, port name: typo")
+		_, err = c.OutputByNameE("typo")
+		assert.ErrorIs(t, err, port.ErrPortNotFoundInCollection)
+		assert.False(t, c.HasErr(), "chain error is not set")
+		assert.False(t, c.Inputs().HasErr(), "chain error is not set")
+
+		assert.PanicsWithValue(t, "input port lookup failed: component c: port not found, port name: typo", func() {
+			c.MustInputByName("typo")
+		})
+		assert.PanicsWithValue(t, "output port lookup failed: component c: port not found, port name: typo", func() {
+			c.MustOutputByName("typo")
+		})
+	})
+
+	t.Run("component with chain error", func(t *testing.T) {
+		_, err := New("c").WithErr(errors.New("some error")).InputByNameE("in")
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestComponent_WithAutoPorts(t *testing.T) {
+	t.Run("undeclared ports are created", func(t *testing.T) {
+		var logs bytes.Buffer
+		c := New("c").WithInputs("a").WithAutoPorts().WithLogger(log.New(&logs, "", 0))
+
+		in := c.InputByName("b")
+		out := c.OutputByName("res")
+		assert.False(t, c.HasErr())
+		assert.Equal(t, "b", in.Name())
+		assert.Equal(t, port.DirectionIn, in.LabelOrDefault(port.DirectionLabel, ""))
+		assert.Equal(t, port.DirectionOut, out.LabelOrDefault(port.DirectionLabel, ""))
+		assert.Same(t, in, c.InputByName("b"), "port is created once")
+		assert.Equal(t, 2, c.Inputs().Len())
+		assert.Equal(t, "c : auto-created in port b\nc : auto-created out port res\n", logs.String())
+
+		assert.NoError(t, out.PipeTo(New("d").WithAutoPorts().InputByName("in")).Err())
+	})
+
+	t.Run("strict by default", func(t *testing.T) {
+		c := New("c").WithInputs("a")
+		c.InputByName("b")
+		assert.ErrorIs(t, c.Err(), port.ErrPortNotFoundInCollection)
 	})
 }
 
@@ -413,3 +548,12 @@ func TestComponent_FlushOutputs(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkComponent_InputByName(b *testing.B) {
+	c := New("c").WithInputs("num", "factor", "offset").WithOutputs("res")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.InputByName("factor")
+		c.OutputByName("res")
+	}
+}
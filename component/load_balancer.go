@@ -0,0 +1,128 @@
+package component
+
+import (
+	"fmt"
+)
+
+const (
+	// LoadBalancerInput is the input port of load balancers receiving signals to be balanced
+	LoadBalancerInput = "in"
+
+	// LoadBalancerOutput is the output port of load balancers emitting results of all workers
+	LoadBalancerOutput = "out"
+
+	// LoadBalancerWorkerPrefix is the prefix of indexed output ports of load balancers, each one is piped to a worker
+	LoadBalancerWorkerPrefix = "worker_"
+
+	// LoadBalancerResultPrefix is the prefix of indexed input ports of load balancers, each one receives results of a worker
+	LoadBalancerResultPrefix = "result_"
+
+	// WorkerInput and WorkerOutput are the ports workers of load balancers must have
+	WorkerInput  = "in"
+	WorkerOutput = "out"
+)
+
+// LoadBalancingStrategy defines how load balancers pick workers
+type LoadBalancingStrategy int
+
+const (
+	// BalanceRoundRobin hands signals to workers in turn
+	BalanceRoundRobin LoadBalancingStrategy = iota
+
+	// BalanceRandom hands each signal to a random worker (drawn from the random generator of the balancer, see Rand)
+	BalanceRandom
+
+	// BalanceLeastLoaded hands each signal to the worker with the fewest signals in progress (the first one on ties),
+	// a worker is loaded with signals it got until it emits as many results
+	BalanceLeastLoaded
+)
+
+// String returns the name of the strategy
+func (strategy LoadBalancingStrategy) String() string {
+	switch strategy {
+	case BalanceRoundRobin:
+		return "RoundRobin"
+	case BalanceRandom:
+		return "Random"
+	case BalanceLeastLoaded:
+		return "LeastLoaded"
+	default:
+		return "Unsupported load balancing strategy"
+	}
+}
+
+// NewLoadBalancer creates the load balancer with n workers made by the factory (indexes start from 1, worker names must be unique),
+// workers must have WorkerInput and WorkerOutput ports. The balancer hands signals received on LoadBalancerInput port
+// to workers according to the strategy and emits their results on LoadBalancerOutput port, so the group is wired
+// to the rest of the mesh through the balancer only. The balancer goes first in the returned components, all of them are to be added to the mesh:
+//
+//	fm.WithComponents(component.NewLoadBalancer("lb", newWorker, 4, component.BalanceLeastLoaded)...)
+func NewLoadBalancer(name string, workerFactory func(index int) *Component, n int, strategy LoadBalancingStrategy) []*Component {
+	if n < 1 || workerFactory == nil || strategy < BalanceRoundRobin || strategy > BalanceLeastLoaded {
+		return []*Component{New(name).WithErr(fmt.Errorf("%w: %d workers, strategy %s", ErrInvalidLoadBalancerConfig, n, strategy))}
+	}
+
+	balancer := New(name).
+		WithDescription(fmt.Sprintf("balances signals over %d workers (%s)", n, strategy)).
+		WithInputs(LoadBalancerInput).
+		WithInputsIndexed(LoadBalancerResultPrefix, 1, n).
+		WithOutputs(LoadBalancerOutput).
+		WithOutputsIndexed(LoadBalancerWorkerPrefix, 1, n).
+		WithActivationFunc(func(this *Component) error {
+			for i := 1; i <= n; i++ {
+				results := this.InputByName(fmt.Sprintf("%s%d", LoadBalancerResultPrefix, i)).AllSignalsOrNil()
+				if len(results) == 0 {
+					continue
+				}
+				this.OutputByName(LoadBalancerOutput).PutSignals(results...)
+				setWorkerLoad(this, i, max(WorkerLoad(this, i)-len(results), 0))
+			}
+
+			for _, sig := range this.InputByName(LoadBalancerInput).AllSignalsOrNil() {
+				i := pickWorker(this, n, strategy)
+				this.OutputByName(fmt.Sprintf("%s%d", LoadBalancerWorkerPrefix, i)).PutSignals(sig)
+				setWorkerLoad(this, i, WorkerLoad(this, i)+1)
+			}
+			return nil
+		})
+
+	components := []*Component{balancer}
+	for i := 1; i <= n; i++ {
+		worker := workerFactory(i)
+		balancer.OutputByName(fmt.Sprintf("%s%d", LoadBalancerWorkerPrefix, i)).PipeTo(worker.InputByName(WorkerInput))
+		worker.OutputByName(WorkerOutput).PipeTo(balancer.InputByName(fmt.Sprintf("%s%d", LoadBalancerResultPrefix, i)))
+		components = append(components, worker)
+	}
+	return components
+}
+
+// WorkerLoad returns the number of signals the worker with the given index has in progress according to the load balancer
+func WorkerLoad(balancer *Component, index int) int {
+	load, _ := balancer.State().GetOrDefault(fmt.Sprintf("load_%d", index), 0).(int)
+	return load
+}
+
+// setWorkerLoad records the number of signals the worker has in progress
+func setWorkerLoad(balancer *Component, index int, load int) {
+	balancer.State().Set(fmt.Sprintf("load_%d", index), load)
+}
+
+// pickWorker returns the index of the worker the next signal is handed to
+func pickWorker(balancer *Component, n int, strategy LoadBalancingStrategy) int {
+	switch strategy {
+	case BalanceRandom:
+		return balancer.Rand().Intn(n) + 1
+	case BalanceLeastLoaded:
+		picked := 1
+		for i := 2; i <= n; i++ {
+			if WorkerLoad(balancer, i) < WorkerLoad(balancer, picked) {
+				picked = i
+			}
+		}
+		return picked
+	default:
+		next, _ := balancer.State().GetOrDefault("next", 0).(int)
+		balancer.State().Set("next", (next+1)%n)
+		return next + 1
+	}
+}
@@ -0,0 +1,75 @@
+package component
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNewLoadBalancer(t *testing.T) {
+	newWorker := func(index int) *Component {
+		return New(fmt.Sprintf("worker-%d", index)).WithInputs(WorkerInput).WithOutputs(WorkerOutput).WithActivationFunc(func(this *Component) error {
+			return nil
+		})
+	}
+	// dispatch hands signals to the balancer and returns the number of signals each worker got
+	dispatch := func(balancer *Component, n int, signals int) []int {
+		balancer.InputByName(LoadBalancerInput).PutSignals(signal.NewGroup(make([]any, signals)...).SignalsOrNil()...)
+		require.NoError(t, balancer.MaybeActivate().ActivationError())
+		balancer.ClearInputs()
+
+		counts := make([]int, n)
+		for i := 1; i <= n; i++ {
+			out := balancer.OutputByName(fmt.Sprintf("%s%d", LoadBalancerWorkerPrefix, i))
+			counts[i-1] = out.Buffer().Len()
+			out.Clear()
+		}
+		return counts
+	}
+
+	t.Run("group is wired", func(t *testing.T) {
+		components := NewLoadBalancer("lb", newWorker, 3, BalanceRoundRobin)
+		require.Len(t, components, 4)
+		balancer := components[0]
+		assert.Equal(t, "lb", balancer.Name())
+		for i, worker := range components[1:] {
+			assert.Equal(t, fmt.Sprintf("worker-%d", i+1), worker.Name())
+			assert.Equal(t, 1, balancer.OutputByName(fmt.Sprintf("%s%d", LoadBalancerWorkerPrefix, i+1)).Pipes().Len())
+			assert.Equal(t, 1, worker.OutputByName(WorkerOutput).Pipes().Len())
+		}
+	})
+
+	t.Run("round robin", func(t *testing.T) {
+		balancer := NewLoadBalancer("lb", newWorker, 3, BalanceRoundRobin)[0]
+		assert.Equal(t, []int{2, 1, 1}, dispatch(balancer, 3, 4))
+		assert.Equal(t, []int{0, 1, 1}, dispatch(balancer, 3, 2))
+	})
+
+	t.Run("random", func(t *testing.T) {
+		balancer := NewLoadBalancer("lb", newWorker, 3, BalanceRandom)[0].WithRandSeed(42)
+		counts := dispatch(balancer, 3, 30)
+		assert.Equal(t, 30, counts[0]+counts[1]+counts[2])
+	})
+
+	t.Run("least loaded", func(t *testing.T) {
+		balancer := NewLoadBalancer("lb", newWorker, 3, BalanceLeastLoaded)[0]
+		assert.Equal(t, []int{2, 1, 1}, dispatch(balancer, 3, 4))
+
+		// The second worker is done, so it is the least loaded one
+		balancer.InputByName(LoadBalancerResultPrefix + "2").PutSignals(signal.New("result"))
+		assert.Equal(t, []int{0, 1, 0}, dispatch(balancer, 3, 1))
+		assert.Equal(t, 1, balancer.OutputByName(LoadBalancerOutput).Buffer().Len())
+		assert.Equal(t, []int{2, 1, 1}, []int{WorkerLoad(balancer, 1), WorkerLoad(balancer, 2), WorkerLoad(balancer, 3)})
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		components := NewLoadBalancer("lb", newWorker, 0, BalanceRoundRobin)
+		require.Len(t, components, 1)
+		assert.ErrorIs(t, components[0].Err(), ErrInvalidLoadBalancerConfig)
+
+		components = NewLoadBalancer("lb", newWorker, 2, LoadBalancingStrategy(42))
+		assert.ErrorIs(t, components[0].Err(), ErrInvalidLoadBalancerConfig)
+	})
+}
@@ -0,0 +1,88 @@
+package component
+
+import "github.com/hovsep/fmesh/common"
+
+// Option configures a component created with NewWithOptions, any builder method can be turned into an option:
+//
+//	func(c *Component) *Component { return c.WithGoLimit(4) }
+type Option func(c *Component) *Component
+
+// NewWithOptions creates a component and applies the options in order, it is an alternative to the builder chain
+// for cases where options are assembled programmatically (e.g. from a config file)
+func NewWithOptions(name string, options ...Option) *Component {
+	c := New(name)
+	for _, option := range options {
+		c = option(c)
+	}
+	return c
+}
+
+// WithInputs is the option adding input ports (see Component.WithInputs)
+func WithInputs(portNames ...string) Option {
+	return func(c *Component) *Component {
+		return c.WithInputs(portNames...)
+	}
+}
+
+// WithOutputs is the option adding output ports (see Component.WithOutputs)
+func WithOutputs(portNames ...string) Option {
+	return func(c *Component) *Component {
+		return c.WithOutputs(portNames...)
+	}
+}
+
+// WithInputsIndexed is the option adding indexed input ports (see Component.WithInputsIndexed)
+func WithInputsIndexed(prefix string, startIndex int, endIndex int) Option {
+	return func(c *Component) *Component {
+		return c.WithInputsIndexed(prefix, startIndex, endIndex)
+	}
+}
+
+// WithOutputsIndexed is the option adding indexed output ports (see Component.WithOutputsIndexed)
+func WithOutputsIndexed(prefix string, startIndex int, endIndex int) Option {
+	return func(c *Component) *Component {
+		return c.WithOutputsIndexed(prefix, startIndex, endIndex)
+	}
+}
+
+// WithDescription is the option setting the description (see Component.WithDescription)
+func WithDescription(description string) Option {
+	return func(c *Component) *Component {
+		return c.WithDescription(description)
+	}
+}
+
+// WithLabels is the option adding labels (see Component.WithLabels)
+func WithLabels(labels common.LabelsCollection) Option {
+	return func(c *Component) *Component {
+		return c.WithLabels(labels)
+	}
+}
+
+// WithActivationFunc is the option setting the activation function (see Component.WithActivationFunc)
+func WithActivationFunc(f ActivationFunc) Option {
+	return func(c *Component) *Component {
+		return c.WithActivationFunc(f)
+	}
+}
+
+// WithReadinessFunc is the option setting the readiness function (see Component.WithReadinessFunc)
+func WithReadinessFunc(f ReadinessFunc) Option {
+	return func(c *Component) *Component {
+		return c.WithReadinessFunc(f)
+	}
+}
+
+// WithInitialState is the option initializing the state (see Component.WithInitialState)
+func WithInitialState(init func(state State)) Option {
+	return func(c *Component) *Component {
+		return c.WithInitialState(init)
+	}
+}
+
+// WithConfig is the option setting the configuration object (see Component.WithConfig)
+func WithConfig(config any) Option {
+	return func(c *Component) *Component {
+		return c.WithConfig(config)
+	}
+}
@@ -0,0 +1,45 @@
+package component
+
+import (
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/port"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	t.Run("options are applied in order", func(t *testing.T) {
+		options := []Option{
+			WithDescription("adder"),
+			WithInputs("a", "b"),
+			WithOutputsIndexed("out", 1, 2),
+			WithLabels(common.LabelsCollection{"role": "math"}),
+			WithInitialState(func(state State) {
+				state.Set("count", 0)
+			}),
+			WithConfig("cfg"),
+			func(c *Component) *Component {
+				return c.WithGoLimit(4)
+			},
+			WithActivationFunc(func(this *Component) error {
+				return nil
+			}),
+		}
+		c := NewWithOptions("c", options...)
+
+		assert.False(t, c.HasErr())
+		assert.Equal(t, "c", c.Name())
+		assert.Equal(t, "adder", c.Description())
+		assert.Equal(t, 2, c.Inputs().Len())
+		assert.Equal(t, 2, c.Outputs().Len())
+		assert.Equal(t, "math", c.LabelOrDefault("role", ""))
+		assert.Equal(t, 0, c.State().Get("count"))
+		assert.Equal(t, "cfg", c.Config())
+		assert.True(t, c.HasActivationFunc())
+	})
+
+	t.Run("chain error stops options", func(t *testing.T) {
+		c := NewWithOptions("c", WithInputsIndexed("in", 2, 1), WithOutputs("out"))
+		assert.ErrorIs(t, c.Err(), port.ErrInvalidRangeForIndexedGroup)
+	})
+}
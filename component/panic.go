@@ -0,0 +1,36 @@
+package component
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error of a panicked activation (or helper goroutine), it keeps the stack trace of the panic
+type PanicError struct {
+	// Value is the value the activation panicked with
+	Value any
+	Stack []byte
+}
+
+// newPanicError captures the stack trace, it must be called by the deferred function recovering the panic
+func newPanicError(value any) *PanicError {
+	return &PanicError{
+		Value: value,
+		Stack: debug.Stack(),
+	}
+}
+
+// Error returns the error message
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panicked with: %v", e.Value)
+}
+
+// PanicStack returns the stack trace of the panic the error is caused by (nil when the error is not caused by a panic)
+func PanicStack(err error) []byte {
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		return panicErr.Stack
+	}
+	return nil
+}
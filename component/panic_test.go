@@ -0,0 +1,54 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestComponent_PanicStack(t *testing.T) {
+	tests := []struct {
+		name     string
+		activate func(this *Component) error
+	}{
+		{
+			name: "activation function panics",
+			activate: func(this *Component) error {
+				panic("boom")
+			},
+		},
+		{
+			name: "helper goroutine panics",
+			activate: func(this *Component) error {
+				this.Go(func(ctx context.Context) error {
+					panic("boom")
+				})
+				return nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New("c1").WithInputs("in").WithActivationFunc(tt.activate)
+			c.InputByName("in").PutSignals(signal.New(1))
+
+			ar := c.MaybeActivate()
+			assert.ErrorContains(t, ar.ActivationError(), "panicked with: boom")
+			assert.Contains(t, string(ar.Stack()), "TestComponent_PanicStack")
+
+			var panicErr *PanicError
+			if assert.ErrorAs(t, ar.ActivationError(), &panicErr) {
+				assert.Equal(t, "boom", panicErr.Value)
+			}
+		})
+	}
+
+	t.Run("errors have no stack", func(t *testing.T) {
+		assert.Nil(t, PanicStack(errors.New("boom")))
+		assert.Nil(t, PanicStack(nil))
+		assert.NotNil(t, PanicStack(fmt.Errorf("wrapped: %w", newPanicError("boom"))))
+	})
+}
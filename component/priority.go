@@ -0,0 +1,34 @@
+package component
+
+// WithPriority sets the priority of the component: within an activation cycle ready components with higher priority
+// are started first, components of the same priority are ordered by the scheduling policy of the mesh. The default priority is 0
+func (c *Component) WithPriority(priority int) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.priority = priority
+	return c
+}
+
+// Priority returns the priority of the component
+func (c *Component) Priority() int {
+	return c.priority
+}
+
+// WithExclusive makes the component preempt non-exclusive ones (e.g. control-plane components preempting data-plane ones):
+// with CycleEngine a cycle having ready exclusive components activates only them and other ready components wait for the next cycle,
+// with EventEngine activations of exclusive components do not run alongside any other activation
+func (c *Component) WithExclusive() *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.exclusive = true
+	return c
+}
+
+// IsExclusive says whether the component preempts non-exclusive ones
+func (c *Component) IsExclusive() bool {
+	return c.exclusive
+}
@@ -0,0 +1,16 @@
+package component
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestComponent_WithPriority(t *testing.T) {
+	c := New("c")
+	assert.Zero(t, c.Priority())
+	assert.False(t, c.IsExclusive())
+
+	c.WithPriority(7).WithExclusive()
+	assert.Equal(t, 7, c.Priority())
+	assert.True(t, c.IsExclusive())
+}
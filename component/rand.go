@@ -0,0 +1,51 @@
+package component
+
+import (
+	"math/rand"
+	"time"
+)
+
+// WithRand sets the random generator used by the component (useful to make activation functions reproducible)
+func (c *Component) WithRand(r *rand.Rand) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.rand = r
+	return c
+}
+
+// WithRandSeed sets the seed of the random generator, the generator itself is created on first use
+// (a generator holds several kilobytes of state, so large meshes should not create them upfront)
+func (c *Component) WithRandSeed(seed int64) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.rand = nil
+	c.randSeed = seed
+	c.hasRandSeed = true
+	return c
+}
+
+// HasRand says whether the random generator or its seed is set (explicitly or by the mesh)
+func (c *Component) HasRand() bool {
+	return c.rand != nil || c.hasRandSeed
+}
+
+// Rand returns the random generator of the component,
+// when neither generator nor seed is set explicitly (or by the mesh) a time-seeded one is created.
+// Like any rand.Rand the generator itself is not safe for concurrent use
+func (c *Component) Rand() *rand.Rand {
+	c.lazyMu.Lock()
+	defer c.lazyMu.Unlock()
+
+	if c.rand == nil {
+		seed := c.randSeed
+		if !c.hasRandSeed {
+			seed = time.Now().UnixNano()
+		}
+		c.rand = rand.New(rand.NewSource(seed))
+	}
+	return c.rand
+}
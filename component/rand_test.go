@@ -0,0 +1,59 @@
+package component
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestComponent_Rand(t *testing.T) {
+	tests := []struct {
+		name       string
+		component  *Component
+		assertions func(t *testing.T, component *Component)
+	}{
+		{
+			name:      "default rand is created lazily",
+			component: New("c1"),
+			assertions: func(t *testing.T, component *Component) {
+				assert.NotNil(t, component.Rand())
+				assert.Same(t, component.Rand(), component.Rand())
+			},
+		},
+		{
+			name:      "seeded rand is reproducible",
+			component: New("c1").WithRand(rand.New(rand.NewSource(42))),
+			assertions: func(t *testing.T, component *Component) {
+				expected := rand.New(rand.NewSource(42))
+				for i := 0; i < 10; i++ {
+					assert.Equal(t, expected.Intn(100), component.Rand().Intn(100))
+				}
+			},
+		},
+		{
+			name:      "seeded rand is created lazily",
+			component: New("c1").WithRandSeed(42),
+			assertions: func(t *testing.T, component *Component) {
+				assert.Nil(t, component.rand)
+				expected := rand.New(rand.NewSource(42))
+				for i := 0; i < 10; i++ {
+					assert.Equal(t, expected.Intn(100), component.Rand().Intn(100))
+				}
+			},
+		},
+		{
+			name:      "chain error is propagated",
+			component: New("c1").WithErr(ErrNotFound).WithRand(rand.New(rand.NewSource(42))),
+			assertions: func(t *testing.T, component *Component) {
+				assert.ErrorIs(t, component.Err(), ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.assertions != nil {
+				tt.assertions(t, tt.component)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package component
+
+import (
+	"github.com/hovsep/fmesh/clock"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"time"
+)
+
+// Backoff returns the delay before the given retry (1 is the first retry)
+type Backoff func(retry int) time.Duration
+
+// ConstantBackoff waits the same delay before each retry
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(retry int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff doubles the delay before each retry starting from initial, the delay never exceeds limit (0 means no limit)
+func ExponentialBackoff(initial time.Duration, limit time.Duration) Backoff {
+	return func(retry int) time.Duration {
+		delay := initial
+		for i := 1; i < retry; i++ {
+			delay *= 2
+			if limit > 0 && delay >= limit {
+				return limit
+			}
+		}
+		if limit > 0 && delay > limit {
+			return limit
+		}
+		return delay
+	}
+}
+
+// retryPolicy defines how failed activations are retried
+type retryPolicy struct {
+	maxAttempts int
+	backoff     Backoff
+}
+
+// WithRetryPolicy makes a failed activation (returned error or panic) retried within the same cycle up to maxAttempts attempts in total,
+// waiting for the backoff (nil means no delay) before each retry. Each attempt gets the input signals the activation started with,
+// output signals and state changes of failed attempts are discarded (the state is checkpointed even without WithStateRollback).
+// The mesh error handling strategy applies only when the last attempt fails. Retries are reported by the activation result (see Retries)
+// and they stop early when the run context is cancelled. maxAttempts below 2 disables retries.
+// The backoff is waited within the cycle, so it holds up the cycle. With clock.Simulation retries do not wait at all,
+// as the simulated time moves only between cycles
+func (c *Component) WithRetryPolicy(maxAttempts int, backoff Backoff) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	if maxAttempts < 2 {
+		c.retry = nil
+		return c
+	}
+	c.retry = &retryPolicy{
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+	}
+	return c
+}
+
+// waitRetry waits for the backoff before the retry following the failed attempt,
+// returns false when attempts are exhausted or the run context is cancelled
+func (c *Component) waitRetry(attempt int) bool {
+	if c.retry == nil || attempt >= c.retry.maxAttempts || c.Context().Err() != nil {
+		return false
+	}
+	if c.retry.backoff == nil {
+		return true
+	}
+
+	delay := c.retry.backoff(attempt)
+	if delay <= 0 {
+		return true
+	}
+	if _, simulated := c.Clock().(*clock.Simulation); simulated {
+		// The simulation clock does not move during the cycle, so waiting for it would never end
+		return true
+	}
+	select {
+	case <-c.Clock().After(delay):
+		return true
+	case <-c.Context().Done():
+		return false
+	}
+}
+
+// portsSnapshot holds signals of ports, so they can be restored before a retry
+type portsSnapshot map[*port.Port]signal.Signals
+
+// snapshotPorts returns signals of the ports (nil when retries are disabled, as nothing is restored)
+func (c *Component) snapshotPorts(ports *port.Collection) portsSnapshot {
+	if c.retry == nil {
+		return nil
+	}
+
+	snapshot := make(portsSnapshot)
+	for _, p := range ports.PortsOrNil() {
+		snapshot[p] = p.AllSignalsOrNil()
+	}
+	return snapshot
+}
+
+// restore puts the signals back on the ports
+func (snapshot portsSnapshot) restore() {
+	for p, signals := range snapshot {
+		p.Clear().PutSignals(signals...)
+	}
+}
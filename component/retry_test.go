@@ -0,0 +1,127 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"github.com/hovsep/fmesh/clock"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestComponent_WithRetryPolicy(t *testing.T) {
+	// newFlaky returns the component failing the given number of attempts, each attempt consumes inputs and puts outputs
+	newFlaky := func(failures int, panics bool) (*Component, *[]int) {
+		var seen []int
+		attempts := 0
+		c := New("flaky").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *Component) error {
+			attempts++
+			seen = append(seen, this.InputByName("in").Buffer().Len())
+			this.State().Set("attempts", this.State().GetOrDefault("attempts", 0).(int)+1)
+			this.OutputByName("out").PutSignals(signal.New(attempts))
+			this.InputByName("in").Clear()
+			if attempts <= failures {
+				if panics {
+					panic("boom")
+				}
+				return errors.New("boom")
+			}
+			return nil
+		})
+		c.InputByName("in").PutSignals(signal.New(1), signal.New(2))
+		return c, &seen
+	}
+
+	t.Run("succeeds on retry", func(t *testing.T) {
+		c, seen := newFlaky(2, false)
+		ar := c.WithRetryPolicy(3, nil).MaybeActivate()
+
+		assert.Equal(t, ActivationCodeOK, ar.Code())
+		assert.Equal(t, 2, ar.Retries())
+		// Each attempt gets the original inputs, outputs and state of failed attempts are discarded
+		assert.Equal(t, []int{2, 2, 2}, *seen)
+		payloads, err := c.OutputByName("out").AllSignalsPayloads()
+		require.NoError(t, err)
+		assert.Equal(t, []any{3}, payloads)
+		assert.Equal(t, 1, c.State().GetOrDefault("attempts", 0))
+		assert.Equal(t, 2, ar.SignalsConsumed())
+		assert.Equal(t, 1, ar.SignalsProduced())
+	})
+
+	t.Run("attempts are exhausted", func(t *testing.T) {
+		c, seen := newFlaky(5, false)
+		ar := c.WithRetryPolicy(3, ConstantBackoff(time.Millisecond)).MaybeActivate()
+
+		assert.True(t, ar.IsError())
+		assert.Equal(t, 2, ar.Retries())
+		assert.Len(t, *seen, 3)
+	})
+
+	t.Run("panics are retried", func(t *testing.T) {
+		c, _ := newFlaky(1, true)
+		ar := c.WithRetryPolicy(2, nil).MaybeActivate()
+		assert.Equal(t, ActivationCodeOK, ar.Code())
+		assert.Equal(t, 1, ar.Retries())
+
+		c, _ = newFlaky(2, true)
+		ar = c.WithRetryPolicy(2, nil).MaybeActivate()
+		assert.True(t, ar.IsPanic())
+		assert.Equal(t, 1, ar.Retries())
+	})
+
+	t.Run("waiting for inputs is not retried", func(t *testing.T) {
+		attempts := 0
+		c := New("c").WithInputs("in").WithRetryPolicy(3, nil).WithActivationFunc(func(this *Component) error {
+			attempts++
+			return NewErrWaitForInputs(false)
+		})
+		c.InputByName("in").PutSignals(signal.New(1))
+
+		ar := c.MaybeActivate()
+		assert.True(t, IsWaitingForInput(ar))
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("cancelled context stops retries", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		c, seen := newFlaky(5, false)
+		ar := c.WithContext(ctx).WithRetryPolicy(3, ConstantBackoff(time.Hour)).MaybeActivate()
+
+		assert.True(t, ar.IsError())
+		assert.Zero(t, ar.Retries())
+		assert.Len(t, *seen, 1)
+	})
+
+	t.Run("simulation clock does not block backoff", func(t *testing.T) {
+		c, seen := newFlaky(2, false)
+		clk := clock.NewSimulation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Second)
+		ar := c.WithClock(clk).WithRetryPolicy(3, ConstantBackoff(time.Hour)).MaybeActivate()
+
+		assert.Equal(t, ActivationCodeOK, ar.Code())
+		assert.Equal(t, 2, ar.Retries())
+		assert.Len(t, *seen, 3)
+	})
+
+	t.Run("retries are disabled", func(t *testing.T) {
+		c, seen := newFlaky(1, false)
+		ar := c.WithRetryPolicy(3, nil).WithRetryPolicy(1, nil).MaybeActivate()
+
+		assert.True(t, ar.IsError())
+		assert.Zero(t, ar.Retries())
+		assert.Len(t, *seen, 1)
+	})
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	var delays []time.Duration
+	for retry := 1; retry <= 4; retry++ {
+		delays = append(delays, backoff(retry))
+	}
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond}, delays)
+
+	require.Equal(t, 80*time.Millisecond, ExponentialBackoff(10*time.Millisecond, 0)(4))
+}
@@ -0,0 +1,36 @@
+package component
+
+import (
+	"fmt"
+)
+
+const (
+	// LabelRouterInput is the input port of label routers
+	LabelRouterInput = "in"
+
+	// LabelRouterDefaultOutput is the output port of label routers receiving signals which can not be routed
+	LabelRouterDefaultOutput = "default"
+)
+
+// NewLabelRouter creates a component forwarding each signal received on LabelRouterInput port to the output port
+// named after the value of its routeLabelKey label, signals without the label or with a value having no matching port
+// go to LabelRouterDefaultOutput port. Route ports are added with WithOutputs as usual:
+//
+//	NewLabelRouter("by-region", "region").WithOutputs("eu", "us")
+func NewLabelRouter(name string, routeLabelKey string) *Component {
+	return New(name).
+		WithDescription(fmt.Sprintf("routes signals by label %q", routeLabelKey)).
+		WithInputs(LabelRouterInput).
+		WithOutputs(LabelRouterDefaultOutput).
+		WithActivationFunc(func(this *Component) error {
+			routes := this.Outputs().PortsOrNil()
+			for _, sig := range this.InputByName(LabelRouterInput).AllSignalsOrNil() {
+				route, ok := routes[sig.LabelOrDefault(routeLabelKey, LabelRouterDefaultOutput)]
+				if !ok {
+					route = routes[LabelRouterDefaultOutput]
+				}
+				route.PutSignals(sig)
+			}
+			return nil
+		})
+}
@@ -0,0 +1,59 @@
+package component
+
+import (
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewLabelRouter(t *testing.T) {
+	eu := signal.New(1).WithLabels(common.LabelsCollection{"region": "eu"})
+	us := signal.New(2).WithLabels(common.LabelsCollection{"region": "us"})
+	asia := signal.New(3).WithLabels(common.LabelsCollection{"region": "asia"})
+	unlabeled := signal.New(4)
+
+	tests := []struct {
+		name    string
+		routes  []string
+		signals []*signal.Signal
+		want    map[string][]any
+	}{
+		{
+			name:    "signals are routed by label value",
+			routes:  []string{"eu", "us"},
+			signals: []*signal.Signal{eu, us, eu},
+			want: map[string][]any{
+				"eu":                     {1, 1},
+				"us":                     {2},
+				LabelRouterDefaultOutput: {},
+			},
+		},
+		{
+			name:    "unknown values and unlabeled signals go to default port",
+			routes:  []string{"eu"},
+			signals: []*signal.Signal{asia, eu, unlabeled},
+			want: map[string][]any{
+				"eu":                     {1},
+				LabelRouterDefaultOutput: {3, 4},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewLabelRouter("router", "region").WithOutputs(tt.routes...)
+			router.InputByName(LabelRouterInput).PutSignals(tt.signals...)
+
+			result := router.MaybeActivate()
+			assert.NoError(t, result.ActivationError())
+
+			got := make(map[string][]any)
+			for name, p := range router.Outputs().PortsOrNil() {
+				payloads, err := p.AllSignalsPayloads()
+				assert.NoError(t, err)
+				got[name] = payloads
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
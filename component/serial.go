@@ -0,0 +1,17 @@
+package component
+
+// WithSerialExecution pins the component to serial execution: activations of all pinned components never run concurrently with each other
+// (e.g. when they share a resource which is not safe for concurrent use), they still run alongside other components
+func (c *Component) WithSerialExecution() *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.serial = true
+	return c
+}
+
+// IsSerial says whether the component is pinned to serial execution
+func (c *Component) IsSerial() bool {
+	return c.serial
+}
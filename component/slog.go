@@ -0,0 +1,34 @@
+package component
+
+import "log/slog"
+
+// WithSlog sets the structured logger, the component logger (with the "component" attribute) is created from it on first use
+func (c *Component) WithSlog(logger *slog.Logger) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	if logger == nil {
+		return c
+	}
+
+	c.parentSlog = logger
+	c.slog = nil
+	return c
+}
+
+// Slog returns the structured component logger, it is created from slog.Default() when no logger is set
+// (components added to a mesh get the logger of the mesh, see fmesh.Config.Slog)
+func (c *Component) Slog() *slog.Logger {
+	c.lazyMu.Lock()
+	defer c.lazyMu.Unlock()
+
+	if c.slog == nil {
+		parent := c.parentSlog
+		if parent == nil {
+			parent = slog.Default()
+		}
+		c.slog = parent.With("component", c.Name())
+	}
+	return c.slog
+}
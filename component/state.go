@@ -1,5 +1,7 @@
 package component
 
+import "maps"
+
 // State is a key-value storage that persists between activation cycles of a component.
 // It allows storing and retrieving arbitrary data using string keys.
 //
@@ -12,9 +14,14 @@ func NewState() State {
 	return make(State)
 }
 
-// WithInitialState sets initial state (optional)
+// WithInitialState sets initial state (optional), it is validated when the component has a state schema
 func (c *Component) WithInitialState(init func(state State)) *Component {
 	init(c.state)
+	// Kept to restore the initial state on restarts (see RestartAndClearState)
+	c.initState = append(c.initState, init)
+	if err := c.validateState(); err != nil {
+		return c.WithErr(err)
+	}
 	return c
 }
 
@@ -57,3 +64,47 @@ func (s State) Set(key string, value any) {
 func (s State) Delete(key string) {
 	delete(s, key)
 }
+
+// Snapshot returns a copy of the state, values are copied shallowly,
+// so mutable values (slices, maps, pointers) must be replaced rather than modified in place to be restorable
+func (s State) Snapshot() State {
+	snapshot := make(State, len(s))
+	maps.Copy(snapshot, s)
+	return snapshot
+}
+
+// Restore replaces the content of the state with the snapshot
+func (s State) Restore(snapshot State) {
+	clear(s)
+	maps.Copy(s, snapshot)
+}
+
+// WithStateRollback makes the component checkpoint its state at the start of each activation
+// and roll it back when the activation fails (returns an error or panics), so state changes of an activation
+// are transactional: a failed attempt leaves no partial changes (e.g. before the retry of unacknowledged signals, see WithAtLeastOnce).
+// Waiting for inputs is not a failure, so changes made by such activation are kept
+func (c *Component) WithStateRollback() *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.stateRollback = true
+	return c
+}
+
+// checkpointState returns the snapshot of the state to roll back to
+// (nil when rollback is disabled, activations are not retried and the state has no schema)
+func (c *Component) checkpointState() State {
+	if !c.stateRollback && c.retry == nil && !c.hasStateSchema() {
+		return nil
+	}
+	return c.state.Snapshot()
+}
+
+// rollbackState restores the state from the checkpoint (if any)
+func (c *Component) rollbackState(checkpoint State) {
+	if checkpoint == nil {
+		return
+	}
+	c.state.Restore(checkpoint)
+}
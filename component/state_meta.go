@@ -0,0 +1,76 @@
+package component
+
+import (
+	"maps"
+	"reflect"
+	"sync"
+)
+
+// stateMeta holds what is attached to the state of a component: watchers (with values of watched keys seen at the previous check),
+// the schema and, when modifications are tracked, the state as it was before the latest activation
+type stateMeta struct {
+	mu       sync.Mutex
+	watchers map[string][]StateWatcher
+	seen     map[string]any
+	present  map[string]bool
+	schema   StateSchema
+	tracked  bool
+	before   State
+}
+
+// ensureStateMeta returns the state meta of the component, creating it when needed
+func (c *Component) ensureStateMeta() *stateMeta {
+	if c.stateMeta == nil {
+		c.stateMeta = &stateMeta{}
+	}
+	return c.stateMeta
+}
+
+// TrackStateModifications makes the component remember whether its state was modified by an activation,
+// see TakeStateModified
+func (c *Component) TrackStateModifications() {
+	meta := c.ensureStateMeta()
+	meta.mu.Lock()
+	defer meta.mu.Unlock()
+	meta.tracked = true
+}
+
+// TakeStateModified says whether the state was modified by the latest activation (the mesh uses it for state stats),
+// modifications are only tracked after TrackStateModifications
+// @TODO: hide this method from user
+func (c *Component) TakeStateModified() bool {
+	meta := c.stateMeta
+	if meta == nil {
+		return false
+	}
+
+	meta.mu.Lock()
+	defer meta.mu.Unlock()
+	if meta.before == nil {
+		return false
+	}
+	modified := !statesEqual(meta.before, c.state)
+	meta.before = nil
+	return modified
+}
+
+// rememberStateBeforeActivation keeps a copy of the state, so the modification can be detected after the activation
+func (c *Component) rememberStateBeforeActivation() {
+	meta := c.stateMeta
+	if meta == nil {
+		return
+	}
+
+	meta.mu.Lock()
+	defer meta.mu.Unlock()
+	if meta.tracked {
+		meta.before = c.state.Snapshot()
+	}
+}
+
+// statesEqual says whether both states have the same keys with the same values
+func statesEqual(a State, b State) bool {
+	return maps.EqualFunc(a, b, func(x, y any) bool {
+		return reflect.DeepEqual(x, y)
+	})
+}
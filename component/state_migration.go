@@ -0,0 +1,84 @@
+package component
+
+import "fmt"
+
+// StateMigration converts the state of one version to the state of the next version
+type StateMigration func(old State) (State, error)
+
+// WithStateVersion sets the version of the state layout (0 by default), bump it when keys or their meaning change
+func (c *Component) WithStateVersion(version int) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.stateVersion = version
+	return c
+}
+
+// StateVersion returns the version of the state layout
+func (c *Component) StateVersion() int {
+	return c.stateVersion
+}
+
+// WithStateMigration registers the migration of the state from the given version to the next one,
+// so state saved by older versions of the component can be restored (see RestoreState and AdoptState)
+func (c *Component) WithStateMigration(fromVersion int, migrate StateMigration) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	if c.stateMigrations == nil {
+		c.stateMigrations = make(map[int]StateMigration)
+	}
+	c.stateMigrations[fromVersion] = migrate
+	return c
+}
+
+// RestoreState replaces the state with the one saved at the given version (e.g. a checkpoint),
+// the saved state is migrated step by step up to the current version and validated against the schema (if any),
+// the state is not changed when anything fails
+func (c *Component) RestoreState(saved State, version int) error {
+	if c.HasErr() {
+		return c.Err()
+	}
+
+	migrated, err := c.migrateState(saved.Snapshot(), version)
+	if err != nil {
+		return err
+	}
+
+	checkpoint := c.state.Snapshot()
+	c.state.Restore(migrated)
+	if err := c.validateState(); err != nil {
+		c.state.Restore(checkpoint)
+		return fmt.Errorf("%w: %w", ErrFailedToMigrateState, err)
+	}
+	return nil
+}
+
+// AdoptState takes over the state of another instance of the component (e.g. the one it replaces in a running system),
+// the state is migrated from the version of the old instance
+func (c *Component) AdoptState(old *Component) error {
+	return c.RestoreState(old.State(), old.StateVersion())
+}
+
+// migrateState applies migrations from the given version up to the current one
+func (c *Component) migrateState(state State, version int) (State, error) {
+	if version > c.stateVersion {
+		return nil, fmt.Errorf("%w: state version %d is newer than component state version %d", ErrFailedToMigrateState, version, c.stateVersion)
+	}
+
+	for ; version < c.stateVersion; version++ {
+		migrate, ok := c.stateMigrations[version]
+		if !ok {
+			return nil, fmt.Errorf("%w: no migration from version %d", ErrFailedToMigrateState, version)
+		}
+
+		migrated, err := migrate(state)
+		if err != nil {
+			return nil, fmt.Errorf("%w: migration from version %d: %w", ErrFailedToMigrateState, version, err)
+		}
+		state = migrated
+	}
+	return state, nil
+}
@@ -0,0 +1,103 @@
+package component
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestComponent_RestoreState(t *testing.T) {
+	// v0 kept "level" in percents, v1 renamed it to "fill", v2 keeps it as a fraction
+	newTank := func() *Component {
+		return New("tank").
+			WithStateVersion(2).
+			WithStateSchema(StateSchema{"fill": 0.0}).
+			WithStateMigration(0, func(old State) (State, error) {
+				return State{"fill": old.Get("level")}, nil
+			}).
+			WithStateMigration(1, func(old State) (State, error) {
+				percents, ok := old.Get("fill").(float64)
+				if !ok {
+					return nil, errors.New("fill is not a number")
+				}
+				return State{"fill": percents / 100}, nil
+			}).
+			WithInitialState(func(state State) {
+				state.Set("fill", 0.0)
+			})
+	}
+
+	tests := []struct {
+		name       string
+		saved      State
+		version    int
+		wantState  State
+		wantErrMsg string
+	}{
+		{
+			name:      "current version",
+			saved:     State{"fill": 0.3},
+			version:   2,
+			wantState: State{"fill": 0.3},
+		},
+		{
+			name:      "migrated from the oldest version",
+			saved:     State{"level": 50.0},
+			version:   0,
+			wantState: State{"fill": 0.5},
+		},
+		{
+			name:       "failed migration",
+			saved:      State{"level": "half"},
+			version:    0,
+			wantState:  State{"fill": 0.0},
+			wantErrMsg: "failed to migrate state: migration from version 1: fill is not a number",
+		},
+		{
+			name:       "migrated state violates the schema",
+			saved:      State{"fill": 0.3, "extra": true},
+			version:    2,
+			wantState:  State{"fill": 0.0},
+			wantErrMsg: `failed to migrate state: state schema violation: key "extra" is not declared`,
+		},
+		{
+			name:       "newer version",
+			saved:      State{"fill": 0.3},
+			version:    3,
+			wantState:  State{"fill": 0.0},
+			wantErrMsg: "failed to migrate state: state version 3 is newer than component state version 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tank := newTank()
+			err := tank.RestoreState(tt.saved, tt.version)
+			if tt.wantErrMsg != "" {
+				assert.EqualError(t, err, tt.wantErrMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantState, tank.State())
+		})
+	}
+
+	t.Run("missing migration", func(t *testing.T) {
+		c := New("c").WithStateVersion(1)
+		assert.ErrorIs(t, c.RestoreState(State{}, 0), ErrFailedToMigrateState)
+	})
+}
+
+func TestComponent_AdoptState(t *testing.T) {
+	old := New("counter").WithInitialState(func(state State) {
+		state.Set("count", 5)
+	})
+	replacement := New("counter").
+		WithStateVersion(1).
+		WithStateMigration(0, func(old State) (State, error) {
+			return State{"count": old.Get("count"), "resets": 0}, nil
+		})
+
+	assert.NoError(t, replacement.AdoptState(old))
+	assert.Equal(t, State{"count": 5, "resets": 0}, replacement.State())
+	assert.Equal(t, State{"count": 5}, old.State(), "state of the old instance is not changed")
+}
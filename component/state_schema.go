@@ -0,0 +1,103 @@
+package component
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// StateSchema declares state keys and their types by example values, e.g. StateSchema{"level": 0, "name": ""}.
+// A nil example allows values of any type, a reflect.Type example allows values assignable to it (e.g. an interface type)
+type StateSchema map[string]any
+
+// WithStateSchema declares the state keys of the component, so unknown keys (typos) and values of wrong types are caught:
+// the current state is validated at once, WithInitialState validates the initial state and the state is validated after each activation,
+// an activation leaving the state invalid fails and its state changes are rolled back
+func (c *Component) WithStateSchema(schema StateSchema) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	meta := c.ensureStateMeta()
+	meta.mu.Lock()
+	meta.schema = schema
+	meta.mu.Unlock()
+
+	if err := c.validateState(); err != nil {
+		return c.WithErr(err)
+	}
+	return c
+}
+
+// Validate checks the state against the schema
+func (schema StateSchema) Validate(state State) error {
+	keys := make([]string, 0, len(state))
+	for key := range state {
+		keys = append(keys, key)
+	}
+	// Sorted, so the reported violation does not depend on map order
+	slices.Sort(keys)
+	for _, key := range keys {
+		if err := schema.check(key, state[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasStateSchema says whether the state of the component has a schema
+func (c *Component) hasStateSchema() bool {
+	return c.stateSchema() != nil
+}
+
+// stateSchema returns the schema of the state (nil when there is none)
+func (c *Component) stateSchema() StateSchema {
+	meta := c.stateMeta
+	if meta == nil {
+		return nil
+	}
+
+	meta.mu.Lock()
+	defer meta.mu.Unlock()
+	return meta.schema
+}
+
+// validateState checks the state against its schema (if any)
+func (c *Component) validateState() error {
+	schema := c.stateSchema()
+	if schema == nil {
+		return nil
+	}
+	return schema.Validate(c.state)
+}
+
+// check validates the value of the key
+func (schema StateSchema) check(key string, value any) error {
+	example, declared := schema[key]
+	if !declared {
+		return fmt.Errorf("%w: key %q is not declared", ErrStateSchemaViolation, key)
+	}
+
+	if example == nil {
+		return nil
+	}
+	want, ok := example.(reflect.Type)
+	if !ok {
+		want = reflect.TypeOf(example)
+	}
+
+	got := reflect.TypeOf(value)
+	if got == nil {
+		// nil is allowed for types having it
+		switch want.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return nil
+		default:
+			return fmt.Errorf("%w: key %q: want %s, got nil", ErrStateSchemaViolation, key, want)
+		}
+	}
+	if !got.AssignableTo(want) {
+		return fmt.Errorf("%w: key %q: want %s, got %s", ErrStateSchemaViolation, key, want, got)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package component
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestComponent_WithStateSchema(t *testing.T) {
+	schema := StateSchema{
+		"level": 0,
+		"name":  "",
+		"any":   nil,
+		"err":   reflect.TypeFor[error](),
+	}
+
+	tests := []struct {
+		name       string
+		component  func() *Component
+		wantErrMsg string
+	}{
+		{
+			name: "valid initial state",
+			component: func() *Component {
+				return New("c").WithStateSchema(schema).WithInitialState(func(state State) {
+					state.Set("level", 1)
+					state.Set("any", []int{1})
+					state.Set("err", errors.New("boom"))
+					state.Set("err", nil)
+				})
+			},
+		},
+		{
+			name: "typo in initial state",
+			component: func() *Component {
+				return New("c").WithStateSchema(schema).WithInitialState(func(state State) {
+					state.Set("lavel", 1)
+				})
+			},
+			wantErrMsg: `state schema violation: key "lavel" is not declared`,
+		},
+		{
+			name: "wrong type in initial state",
+			component: func() *Component {
+				return New("c").WithStateSchema(schema).WithInitialState(func(state State) {
+					state.Set("level", "high")
+				})
+			},
+			wantErrMsg: `state schema violation: key "level": want int, got string`,
+		},
+		{
+			name: "existing state is validated",
+			component: func() *Component {
+				return New("c").WithInitialState(func(state State) {
+					state.Set("name", 1)
+				}).WithStateSchema(schema)
+			},
+			wantErrMsg: `state schema violation: key "name": want string, got int`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.component()
+			if tt.wantErrMsg != "" {
+				assert.EqualError(t, c.Err(), tt.wantErrMsg)
+				assert.ErrorIs(t, c.Err(), ErrStateSchemaViolation)
+				return
+			}
+			assert.NoError(t, c.Err())
+		})
+	}
+}
+
+func TestComponent_StateSchemaOnActivation(t *testing.T) {
+	tests := []struct {
+		name       string
+		f          ActivationFunc
+		wantErrMsg string
+		wantState  State
+	}{
+		{
+			name: "valid writes",
+			f: func(this *Component) error {
+				this.State().Set("level", 2)
+				return nil
+			},
+			wantState: State{"level": 2},
+		},
+		{
+			name: "invalid write fails the activation",
+			f: func(this *Component) error {
+				this.State().Set("lavel", 2)
+				return nil
+			},
+			wantErrMsg: `component returned an error: state schema violation: key "lavel" is not declared`,
+			wantState:  State{"level": 1},
+		},
+		{
+			name: "direct write is caught after the activation",
+			f: func(this *Component) error {
+				this.State()["level"] = 2.5
+				return nil
+			},
+			wantErrMsg: `component returned an error: state schema violation: key "level": want int, got float64`,
+			wantState:  State{"level": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New("c").
+				WithInputs("in").
+				WithStateSchema(StateSchema{"level": 0}).
+				WithInitialState(func(state State) {
+					state.Set("level", 1)
+				}).
+				WithActivationFunc(tt.f)
+			c.InputByName("in").PutSignals(signal.New(1))
+
+			activationResult := c.MaybeActivate()
+			if tt.wantErrMsg != "" {
+				assert.True(t, activationResult.IsError())
+				assert.EqualError(t, activationResult.ActivationError(), tt.wantErrMsg)
+			} else {
+				assert.False(t, activationResult.IsError())
+			}
+			assert.Equal(t, tt.wantState, c.State())
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package component
 
 import (
+	"errors"
+	"github.com/hovsep/fmesh/signal"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -88,3 +90,78 @@ func Test_StateManipulations(t *testing.T) {
 		assert.Len(t, c.State(), 0)
 	})
 }
+
+func TestState_SnapshotRestore(t *testing.T) {
+	state := State{"level": 1, "name": "tank"}
+	snapshot := state.Snapshot()
+
+	state.Set("level", 2)
+	state.Set("leak", true)
+	state.Delete("name")
+	assert.Equal(t, State{"level": 1, "name": "tank"}, snapshot, "snapshot is not affected by changes")
+
+	state.Restore(snapshot)
+	assert.Equal(t, State{"level": 1, "name": "tank"}, state)
+}
+
+func TestComponent_WithStateRollback(t *testing.T) {
+	tests := []struct {
+		name      string
+		rollback  bool
+		f         ActivationFunc
+		wantLevel int
+	}{
+		{
+			name:     "successful activation keeps changes",
+			rollback: true,
+			f: func(this *Component) error {
+				this.State().Set("level", 2)
+				return nil
+			},
+			wantLevel: 2,
+		},
+		{
+			name:     "failed activation is rolled back",
+			rollback: true,
+			f: func(this *Component) error {
+				this.State().Set("level", 2)
+				return errors.New("failed midway")
+			},
+			wantLevel: 1,
+		},
+		{
+			name:     "panicked activation is rolled back",
+			rollback: true,
+			f: func(this *Component) error {
+				this.State().Set("level", 2)
+				panic("failed midway")
+			},
+			wantLevel: 1,
+		},
+		{
+			name: "changes stay without rollback",
+			f: func(this *Component) error {
+				this.State().Set("level", 2)
+				return errors.New("failed midway")
+			},
+			wantLevel: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New("c").
+				WithInputs("in").
+				WithInitialState(func(state State) {
+					state.Set("level", 1)
+				}).
+				WithActivationFunc(tt.f)
+			if tt.rollback {
+				c.WithStateRollback()
+			}
+			c.InputByName("in").PutSignals(signal.New(1))
+
+			c.MaybeActivate()
+			assert.Equal(t, tt.wantLevel, c.State().Get("level"))
+		})
+	}
+}
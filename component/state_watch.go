@@ -0,0 +1,92 @@
+package component
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// StateChange describes a change of a watched state key
+type StateChange struct {
+	Key      string
+	OldValue any
+	NewValue any
+	// Deleted is set when the key was removed (NewValue is nil then)
+	Deleted bool
+}
+
+// StateWatcher is called when the watched state key changes
+type StateWatcher func(change StateChange)
+
+// WatchState subscribes to changes of the state key. Changes are detected by comparing the value with the one seen at the previous check,
+// so writes done through Set and directly to the map are both noticed, while values mutated in place (slices, maps, pointers) are not.
+// A mesh checks watched keys of activated components after each activation cycle and calls watchers sequentially,
+// so watchers do not need to be safe for concurrent use. Watchers survive state resets
+func (c *Component) WatchState(key string, watcher StateWatcher) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	meta := c.ensureStateMeta()
+	meta.mu.Lock()
+	defer meta.mu.Unlock()
+	if meta.watchers == nil {
+		meta.watchers = make(map[string][]StateWatcher)
+		meta.seen = make(map[string]any)
+		meta.present = make(map[string]bool)
+	}
+	if _, watched := meta.watchers[key]; !watched {
+		meta.seen[key], meta.present[key] = c.state[key]
+	}
+	meta.watchers[key] = append(meta.watchers[key], watcher)
+	return c
+}
+
+// NotifyStateWatchers calls watchers of state keys changed since the previous check (the mesh does it after each activation cycle)
+// @TODO: hide this method from user
+func (c *Component) NotifyStateWatchers() {
+	meta := c.stateMeta
+	if meta == nil {
+		return
+	}
+
+	meta.mu.Lock()
+	changes := meta.detect(c.state)
+	watchers := make([][]StateWatcher, len(changes))
+	for i, change := range changes {
+		watchers[i] = meta.watchers[change.Key]
+	}
+	meta.mu.Unlock()
+
+	for i, change := range changes {
+		for _, watcher := range watchers[i] {
+			watcher(change)
+		}
+	}
+}
+
+// detect returns changes of watched keys and remembers the current values
+func (w *stateMeta) detect(s State) []StateChange {
+	var changes []StateChange
+	for key := range w.watchers {
+		value, present := s[key]
+		wasPresent := w.present[key]
+		if present == wasPresent && reflect.DeepEqual(value, w.seen[key]) {
+			continue
+		}
+
+		changes = append(changes, StateChange{
+			Key:      key,
+			OldValue: w.seen[key],
+			NewValue: value,
+			Deleted:  wasPresent && !present,
+		})
+		w.seen[key], w.present[key] = value, present
+	}
+
+	// Keys are delivered in a stable order
+	slices.SortFunc(changes, func(a, b StateChange) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return changes
+}
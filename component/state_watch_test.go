@@ -0,0 +1,47 @@
+package component
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestComponent_WatchState(t *testing.T) {
+	c := New("tank").WithInitialState(func(state State) {
+		state.Set("level", 1)
+		state.Set("valve", "closed")
+	})
+
+	var changes []StateChange
+	c.WatchState("level", func(change StateChange) {
+		changes = append(changes, change)
+	}).WatchState("valve", func(change StateChange) {
+		changes = append(changes, change)
+	})
+
+	c.NotifyStateWatchers()
+	assert.Empty(t, changes, "nothing changed yet")
+
+	c.State().Set("level", 2)
+	c.State()["valve"] = "open"
+	c.State().Set("unwatched", true)
+	c.NotifyStateWatchers()
+	assert.Equal(t, []StateChange{
+		{Key: "level", OldValue: 1, NewValue: 2},
+		{Key: "valve", OldValue: "closed", NewValue: "open"},
+	}, changes)
+
+	changes = nil
+	c.State().Set("level", 2)
+	c.State().Delete("valve")
+	c.NotifyStateWatchers()
+	assert.Equal(t, []StateChange{
+		{Key: "valve", OldValue: "open", Deleted: true},
+	}, changes)
+
+	changes = nil
+	c.ResetState()
+	c.NotifyStateWatchers()
+	assert.Equal(t, []StateChange{
+		{Key: "level", OldValue: 2, Deleted: true},
+	}, changes, "watchers survive reset")
+}
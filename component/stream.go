@@ -0,0 +1,139 @@
+package component
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/signal"
+	"io"
+)
+
+const (
+	// ReaderSourceOutput is the output port of reader sources
+	ReaderSourceOutput = "out"
+
+	// WriterSinkInput is the input port of writer sinks
+	WriterSinkInput = "in"
+)
+
+// NewLineReaderSource creates a source component emitting each line read from the reader as a string signal (without the line break),
+// one line is emitted per activation cycle, the source stops activating at the end of the stream.
+// Reads are blocking, so the mesh waits for slow streams (e.g. stdin or network connections)
+func NewLineReaderSource(name string, r io.Reader) *Component {
+	scanner := bufio.NewScanner(r)
+	return newReaderSource(name, "emits lines read from a stream", func() (any, error) {
+		if scanner.Scan() {
+			return scanner.Text(), nil
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	})
+}
+
+// NewChunkReaderSource creates a source component emitting chunks ([]byte) of at most chunkSize bytes read from the reader,
+// one chunk is emitted per activation cycle, the source stops activating at the end of the stream
+func NewChunkReaderSource(name string, r io.Reader, chunkSize int) *Component {
+	if chunkSize <= 0 {
+		return New(name).WithErr(fmt.Errorf("%w, got: %d", ErrInvalidChunkSize, chunkSize))
+	}
+
+	return newReaderSource(name, "emits chunks read from a stream", func() (any, error) {
+		chunk := make([]byte, chunkSize)
+		n, err := io.ReadFull(r, chunk)
+		if n > 0 {
+			// Short chunk at the end of the stream, the next read reports the end
+			return chunk[:n], nil
+		}
+		return nil, err
+	})
+}
+
+// newReaderSource creates a source component emitting values returned by next until it returns io.EOF,
+// other errors are returned from the activation function and stop the source
+func newReaderSource(name string, description string, next func() (any, error)) *Component {
+	var (
+		pending    *signal.Signal
+		pendingErr error
+		done       bool
+	)
+
+	return New(name).
+		WithDescription(description).
+		WithOutputs(ReaderSourceOutput).
+		WithReadinessFunc(func(this *Component) bool {
+			if done {
+				return false
+			}
+			if pending != nil || pendingErr != nil {
+				return true
+			}
+
+			value, err := next()
+			if errors.Is(err, io.EOF) {
+				done = true
+				return false
+			}
+			if err != nil {
+				pendingErr = err
+				return true
+			}
+			pending = signal.New(value)
+			return true
+		}).
+		WithActivationFunc(func(this *Component) error {
+			if pendingErr != nil {
+				done = true
+				return fmt.Errorf("failed to read: %w", pendingErr)
+			}
+
+			if pending != nil {
+				this.OutputByName(ReaderSourceOutput).PutSignals(pending)
+				pending = nil
+			}
+			return nil
+		})
+}
+
+// NewWriterSink creates a component writing payloads of signals received on WriterSinkInput port to the writer,
+// []byte and string payloads are written as is, other payloads are formatted with fmt.Fprint
+func NewWriterSink(name string, w io.Writer) *Component {
+	return newWriterSink(name, w, "")
+}
+
+// NewLineWriterSink creates a component writing payloads like NewWriterSink does, but each payload is followed by a line break
+func NewLineWriterSink(name string, w io.Writer) *Component {
+	return newWriterSink(name, w, "\n")
+}
+
+// newWriterSink creates a writer sink appending the suffix to each payload
+func newWriterSink(name string, w io.Writer, suffix string) *Component {
+	return New(name).
+		WithDescription("writes payloads to a stream").
+		WithInputs(WriterSinkInput).
+		WithActivationFunc(func(this *Component) error {
+			payloads, err := this.InputByName(WriterSinkInput).AllSignalsPayloads()
+			if err != nil {
+				return err
+			}
+
+			for _, payload := range payloads {
+				switch p := payload.(type) {
+				case []byte:
+					_, err = w.Write(p)
+				case string:
+					_, err = io.WriteString(w, p)
+				default:
+					_, err = fmt.Fprint(w, p)
+				}
+				if err == nil && suffix != "" {
+					_, err = io.WriteString(w, suffix)
+				}
+				if err != nil {
+					return fmt.Errorf("failed to write: %w", err)
+				}
+			}
+			return nil
+		})
+}
@@ -0,0 +1,90 @@
+package component
+
+import (
+	"bytes"
+	"errors"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestNewLineReaderSource(t *testing.T) {
+	t.Run("one line per activation", func(t *testing.T) {
+		source := NewLineReaderSource("lines", strings.NewReader("first\nsecond\n"))
+		assert.True(t, source.IsSource())
+
+		for _, want := range []string{"first", "second"} {
+			assert.Equal(t, ActivationCodeOK, source.MaybeActivate().Code())
+			assert.Equal(t, want, source.OutputByName(ReaderSourceOutput).FirstSignalPayloadOrNil())
+			source.OutputByName(ReaderSourceOutput).Clear()
+		}
+
+		assert.Equal(t, ActivationCodeNoInput, source.MaybeActivate().Code())
+		assert.Equal(t, ActivationCodeNoInput, source.MaybeActivate().Code())
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		source := NewLineReaderSource("lines", iotest.ErrReader(errors.New("connection reset")))
+
+		activationResult := source.MaybeActivate()
+		assert.Equal(t, ActivationCodeReturnedError, activationResult.Code())
+		assert.ErrorContains(t, activationResult.ActivationError(), "connection reset")
+		assert.Equal(t, ActivationCodeNoInput, source.MaybeActivate().Code(), "source must stop after error")
+	})
+}
+
+func TestNewChunkReaderSource(t *testing.T) {
+	t.Run("chunks with short tail", func(t *testing.T) {
+		source := NewChunkReaderSource("chunks", strings.NewReader("abcdefg"), 3)
+
+		var chunks []any
+		for source.MaybeActivate().Code() == ActivationCodeOK {
+			chunks = append(chunks, source.OutputByName(ReaderSourceOutput).FirstSignalPayloadOrNil())
+			source.OutputByName(ReaderSourceOutput).Clear()
+		}
+		assert.Equal(t, []any{[]byte("abc"), []byte("def"), []byte("g")}, chunks)
+	})
+
+	t.Run("invalid chunk size", func(t *testing.T) {
+		assert.ErrorIs(t, NewChunkReaderSource("chunks", strings.NewReader(""), 0).Err(), ErrInvalidChunkSize)
+	})
+}
+
+func TestNewWriterSink(t *testing.T) {
+	t.Run("raw payloads", func(t *testing.T) {
+		var buf bytes.Buffer
+		sink := NewWriterSink("sink", &buf)
+		sink.InputByName(WriterSinkInput).PutSignals(signal.New("a"), signal.New([]byte("b")), signal.New(42))
+
+		assert.Equal(t, ActivationCodeOK, sink.MaybeActivate().Code())
+		assert.Equal(t, "ab42", buf.String())
+	})
+
+	t.Run("lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		sink := NewLineWriterSink("sink", &buf)
+		sink.InputByName(WriterSinkInput).PutSignals(signal.New("a"), signal.New("b"))
+
+		assert.Equal(t, ActivationCodeOK, sink.MaybeActivate().Code())
+		assert.Equal(t, "a\nb\n", buf.String())
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		sink := NewWriterSink("sink", failingWriter{})
+		sink.InputByName(WriterSinkInput).PutSignals(signal.New("a"))
+
+		activationResult := sink.MaybeActivate()
+		assert.Equal(t, ActivationCodeReturnedError, activationResult.Code())
+		assert.ErrorIs(t, activationResult.ActivationError(), io.ErrShortWrite)
+	})
+}
+
+// failingWriter fails on every write
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, io.ErrShortWrite
+}
@@ -0,0 +1,67 @@
+package component
+
+// RestartStrategy defines how the component is supervised when its activation fails (after retries, see WithRetryPolicy)
+type RestartStrategy int
+
+const (
+	// Escalate leaves the failure to the mesh error handling strategy (default)
+	Escalate RestartStrategy = iota
+
+	// RestartOnError handles the failure within the component: the component is restarted with its state kept
+	// and the mesh keeps running regardless of its error handling strategy
+	RestartOnError
+
+	// RestartAndClearState handles the failure like RestartOnError, but the state is reset to the initial one
+	// (the one set by WithInitialState), so a component with corrupted state starts afresh
+	RestartAndClearState
+)
+
+// String returns the name of the strategy
+func (s RestartStrategy) String() string {
+	switch s {
+	case Escalate:
+		return "Escalate"
+	case RestartOnError:
+		return "RestartOnError"
+	case RestartAndClearState:
+		return "RestartAndClearState"
+	default:
+		return "Unsupported restart strategy"
+	}
+}
+
+// WithRestartStrategy sets how failed activations of the component are supervised.
+// Restarted activations keep their error code and error (see ActivationResult.Restarted),
+// inputs of restarted activations are cleared like inputs of other failed activations
+func (c *Component) WithRestartStrategy(strategy RestartStrategy) *Component {
+	if c.HasErr() {
+		return c
+	}
+
+	c.restartStrategy = strategy
+	return c
+}
+
+// RestartStrategy returns the restart strategy of the component
+func (c *Component) RestartStrategy() RestartStrategy {
+	return c.restartStrategy
+}
+
+// restart handles the failed activation according to the restart strategy
+func (c *Component) restart(activationResult *ActivationResult) {
+	switch c.restartStrategy {
+	case RestartOnError:
+	case RestartAndClearState:
+		c.ResetState()
+		for _, init := range c.initState {
+			init(c.state)
+		}
+	default:
+		return
+	}
+
+	if logger := c.Logger(); logger != nil {
+		logger.Printf("restarted (%s) after failed activation: %v", c.restartStrategy, activationResult.ActivationError())
+	}
+	activationResult.SetRestarted(true)
+}
@@ -0,0 +1,73 @@
+package component
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestComponent_WithRestartStrategy(t *testing.T) {
+	newBattery := func(strategy RestartStrategy) *Component {
+		return New("battery").
+			WithInputs("drain").
+			WithRestartStrategy(strategy).
+			WithInitialState(func(state State) {
+				state.Set("level", 100)
+			}).
+			WithActivationFunc(func(this *Component) error {
+				level := this.State().Get("level").(int) - this.InputByName("drain").FirstSignalPayloadOrDefault(0).(int)
+				this.State().Set("level", level)
+				if level < 0 {
+					return errors.New("battery is over-drained")
+				}
+				return nil
+			})
+	}
+
+	tests := []struct {
+		name          string
+		strategy      RestartStrategy
+		wantRestarted bool
+		wantLevel     int
+	}{
+		{
+			name:      "escalate by default",
+			strategy:  Escalate,
+			wantLevel: -20,
+		},
+		{
+			name:          "restart keeping state",
+			strategy:      RestartOnError,
+			wantRestarted: true,
+			wantLevel:     -20,
+		},
+		{
+			name:          "restart with initial state",
+			strategy:      RestartAndClearState,
+			wantRestarted: true,
+			wantLevel:     100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newBattery(tt.strategy)
+			c.InputByName("drain").PutSignals(signal.New(120))
+
+			ar := c.MaybeActivate()
+			assert.True(t, ar.IsError())
+			assert.Equal(t, tt.wantRestarted, ar.Restarted())
+			assert.Equal(t, tt.wantLevel, c.State().Get("level"))
+			assert.Equal(t, tt.strategy, c.RestartStrategy())
+		})
+	}
+
+	t.Run("successful activations are not restarted", func(t *testing.T) {
+		c := newBattery(RestartAndClearState)
+		c.InputByName("drain").PutSignals(signal.New(30))
+
+		ar := c.MaybeActivate()
+		assert.False(t, ar.Restarted())
+		assert.Equal(t, 70, c.State().Get("level"))
+	})
+}
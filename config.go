@@ -1,17 +1,69 @@
 package fmesh
 
-import "log"
+import (
+	"github.com/hovsep/fmesh/clock"
+	"log"
+	"log/slog"
+	"math/rand"
+	"time"
+)
 
 const UnlimitedCycles = 0
 
 type Config struct {
 	// ErrorHandlingStrategy defines how f-mesh will handle errors and panics
 	ErrorHandlingStrategy ErrorHandlingStrategy
-	// CyclesLimit defines max number of activation cycles, 0 means no limit
+	// CyclesLimit defines max number of activation cycles, 0 means no limit. The run stops with ErrReachedMaxAllowedCycles when it is exceeded,
+	// so meshes with accidental infinite loops (e.g. looped pipes) terminate deterministically
 	CyclesLimit int
+	// MaxDuration limits the duration of a run measured by the mesh clock (so it is deterministic with clock.Simulation), 0 means no limit.
+	// The run stops with ErrReachedMaxAllowedDuration when it is exceeded, the limit is checked between activation cycles (EventEngine measures wall time)
+	MaxDuration time.Duration
 	// Debug flag enabled debug mode, when additional information will be logged
 	Debug  bool
 	Logger *log.Logger
+	// Slog is the root structured logger, the mesh and its components log with loggers derived from it
+	// (with mesh, component and cycle attributes, see FMesh.Slog), nil means slog.Default()
+	Slog *slog.Logger
+	// RandSource is used to seed random generators of all components (see component.Rand), nil means time-based seed
+	RandSource rand.Source
+	// Clock is the source of time for the mesh and all components, nil means wall clock.
+	// With clock.Simulation the time is logical: it advances by a tick after each cycle and skips idle periods
+	Clock clock.Clock
+	// Engine defines how activations are driven: in lock-step cycles (default) or as soon as signals arrive
+	Engine Engine
+	// ExecutionStrategy defines how activations and flushes are spread over goroutines
+	ExecutionStrategy ExecutionStrategy
+	// Workers is the number of workers used by WorkerPool strategy, 0 means GOMAXPROCS
+	Workers int
+	// MaxConcurrentActivations bounds the number of activations (and flushes) running at once, 0 means no limit.
+	// With CycleEngine it makes the mesh run them on a worker pool of that size (whatever the ExecutionStrategy is),
+	// so large meshes on small machines do not start a goroutine per component in each cycle (with EventEngine it bounds activations only).
+	// Components pinned to serial execution (see component.WithSerialExecution) take a single slot together
+	MaxConcurrentActivations int
+	// CyclePeriod paces the run: each cycle (including draining) takes at least the period, so the mesh advances at a predictable rate
+	// (e.g. when controlling devices), cycles taking longer are reported as overruns (see OverrunListener), 0 means as fast as possible
+	CyclePeriod time.Duration
+	// SchedulingPolicy defines the order ready components are dispatched in within a cycle
+	SchedulingPolicy SchedulingPolicy
+	// InheritLabels enables passing labels down the hierarchy: mesh labels to components, component labels to ports
+	// and output port labels to emitted signals, labels set on the entity itself always win
+	InheritLabels bool
+	// PrioritizeSignals makes input ports hold high-priority signals (see signal.Priority) before normal ones after each drain,
+	// so control-plane signals are seen first by activation functions
+	PrioritizeSignals bool
+	// TransactionalState makes state changes of an activation take effect only when it succeeds, a failed activation
+	// (e.g. under IgnoreAll strategy) leaves the state as it was (enables component.WithStateRollback on all components)
+	TransactionalState bool
+	// AutoPorts makes InputByName and OutputByName create ports with undeclared names instead of failing (enables component.WithAutoPorts
+	// on all components added to the mesh), so prototypes need no port declarations. Keep it disabled in production to catch typos
+	AutoPorts bool
+	// TraceSignals records the route of signals: each time a signal is piped, the component and the output port it is emitted from
+	// are added to its trace (see signal.Trace), so after a run it is known where a signal came from.
+	// Signals created by activation functions start new traces
+	TraceSignals bool
+	// StateStats enables tracking of state modifications, so the run report (RuntimeInfo.States) includes per-component state stats
+	StateStats bool
 }
 
 var defaultConfig = &Config{
@@ -28,6 +80,7 @@ func (fm *FMesh) withConfig(config *Config) *FMesh {
 	}
 
 	fm.config = config
+	fm.slog = nil
 
 	if fm.Logger() == nil {
 		fm.config.Logger = getDefaultLogger()
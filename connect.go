@@ -0,0 +1,89 @@
+package fmesh
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/port"
+	"sort"
+)
+
+// LabelSelector selects ports having a label which key and value match the patterns (see common.LabelMatcher)
+type LabelSelector struct {
+	Key   string
+	Value string
+}
+
+// ConnectByLabel pipes every output port matching the outputs selector to every input port matching the inputs selector
+// (across all components of the mesh), so wiring conventions like "role=log-out goes to the logger" take one call.
+// Components must be added to the mesh before, selecting no ports is not an error
+func (fm *FMesh) ConnectByLabel(outputs LabelSelector, inputs LabelSelector) *FMesh {
+	if fm.HasErr() {
+		return fm
+	}
+
+	sources, err := fm.selectPorts(outputs, port.DirectionOut)
+	if err != nil {
+		return fm.WithErr(errors.Join(ErrFailedToConnect, err))
+	}
+
+	dests, err := fm.selectPorts(inputs, port.DirectionIn)
+	if err != nil {
+		return fm.WithErr(errors.Join(ErrFailedToConnect, err))
+	}
+
+	if len(dests) == 0 {
+		return fm
+	}
+
+	for _, source := range sources {
+		if source.PipeTo(dests...).HasErr() {
+			return fm.WithErr(errors.Join(ErrFailedToConnect, source.Err()))
+		}
+	}
+
+	// Topology must be recompiled as pipes changed
+	fm.topology = nil
+	return fm
+}
+
+// selectPorts returns ports of given direction matching the selector, ordered by component and port names
+func (fm *FMesh) selectPorts(selector LabelSelector, direction string) (port.Ports, error) {
+	// Invalid patterns would leave the error on port collections of the components, so they are checked upfront
+	if _, err := common.NewLabelMatcher(selector.Key, selector.Value); err != nil {
+		return nil, err
+	}
+
+	components, err := fm.Components().Components()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var selected port.Ports
+	for _, name := range names {
+		collection := components[name].Inputs()
+		if direction == port.DirectionOut {
+			collection = components[name].Outputs()
+		}
+
+		matched, err := collection.ByLabelMatch(selector.Key, selector.Value).Ports()
+		if err != nil {
+			return nil, err
+		}
+
+		portNames := make([]string, 0, len(matched))
+		for portName := range matched {
+			portNames = append(portNames, portName)
+		}
+		sort.Strings(portNames)
+		for _, portName := range portNames {
+			selected = append(selected, matched[portName])
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,73 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFMesh_ConnectByLabel(t *testing.T) {
+	newWorker := func(name string) *component.Component {
+		c := component.New(name).
+			WithInputs("in").
+			WithOutputs("result", "log").
+			WithActivationFunc(func(this *component.Component) error {
+				this.OutputByName("log").PutSignals(signal.New(this.Name() + " activated"))
+				return nil
+			})
+		c.OutputByName("log").AddLabel("role", "log-out")
+		c.InputByName("in").PutSignals(signal.New("start"))
+		return c
+	}
+
+	t.Run("matching ports are piped", func(t *testing.T) {
+		var logged []any
+		logger := component.New("logger").
+			WithInputs("in").
+			WithActivationFunc(func(this *component.Component) error {
+				payloads, err := this.InputByName("in").AllSignalsPayloads()
+				logged = append(logged, payloads...)
+				return err
+			})
+		logger.InputByName("in").AddLabel("role", "log-in")
+
+		fm := New("fm").
+			WithComponents(newWorker("w1"), newWorker("w2"), logger).
+			ConnectByLabel(
+				LabelSelector{Key: "role", Value: "log-out"},
+				LabelSelector{Key: "role", Value: "log-*"},
+			)
+		assert.NoError(t, fm.Err())
+
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []any{"w1 activated", "w2 activated"}, logged)
+		// Result ports are not labeled, so they are not piped
+		assert.False(t, fm.ComponentByName("w1").OutputByName("result").HasPipes())
+	})
+
+	t.Run("nothing selected", func(t *testing.T) {
+		fm := New("fm").
+			WithComponents(newWorker("w1")).
+			ConnectByLabel(
+				LabelSelector{Key: "role", Value: "log-out"},
+				LabelSelector{Key: "role", Value: "log-in"},
+			)
+		assert.NoError(t, fm.Err())
+		assert.False(t, fm.ComponentByName("w1").OutputByName("log").HasPipes())
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		fm := New("fm").
+			WithComponents(newWorker("w1")).
+			ConnectByLabel(
+				LabelSelector{Key: "role", Value: "re:("},
+				LabelSelector{Key: "role", Value: "log-in"},
+			)
+		assert.ErrorIs(t, fm.Err(), ErrFailedToConnect)
+		assert.ErrorIs(t, fm.Err(), common.ErrInvalidLabelPattern)
+		assert.NoError(t, fm.components.ByName("w1").Outputs().Err())
+	})
+}
@@ -0,0 +1,116 @@
+package fmesh
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"sort"
+	"strings"
+)
+
+// ConstructionError is an error made while building the mesh (e.g. a lookup of a port by wrong name),
+// it identifies the component and the port (when known) the error was made on
+type ConstructionError struct {
+	Component string
+	Port      string
+	Err       error
+}
+
+// Error returns the error prefixed with the component and port names
+func (e *ConstructionError) Error() string {
+	var location []string
+	if e.Component != "" {
+		location = append(location, "component "+e.Component)
+	}
+	if e.Port != "" {
+		location = append(location, "port "+e.Port)
+	}
+	if len(location) == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", strings.Join(location, ", "), e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ConstructionError) Unwrap() error {
+	return e.Err
+}
+
+// constructionErrors returns all errors left on components, their port collections and ports (ordered by names),
+// so every mistake is reported at once instead of the first one
+func (fm *FMesh) constructionErrors() error {
+	if fm.components.HasErr() {
+		// Components can not be listed, so the error of the collection is all there is
+		return &ConstructionError{Err: fm.components.Err()}
+	}
+
+	components := fm.components.ComponentsOrNil()
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		errs = append(errs, componentConstructionErrors(components[name])...)
+	}
+	for _, name := range fm.duplicateNames {
+		errs = append(errs, &ConstructionError{Component: name, Err: ErrDuplicateComponentName})
+	}
+	return errors.Join(errs...)
+}
+
+// componentConstructionErrors returns errors left on the component, its port collections and ports
+func componentConstructionErrors(c *component.Component) []error {
+	if c.HasErr() {
+		return []error{&ConstructionError{Component: c.Name(), Err: c.Err()}}
+	}
+
+	var errs []error
+	if !c.HasActivationFunc() {
+		errs = append(errs, &ConstructionError{Component: c.Name(), Err: component.ErrMissingActivationFunc})
+	}
+	for _, ports := range []*port.Collection{c.Inputs(), c.Outputs()} {
+		if ports.HasErr() {
+			errs = append(errs, &ConstructionError{Component: c.Name(), Err: ports.Err()})
+			continue
+		}
+
+		portMap := ports.PortsOrNil()
+		portNames := make([]string, 0, len(portMap))
+		for portName := range portMap {
+			portNames = append(portNames, portName)
+		}
+		sort.Strings(portNames)
+		for _, portName := range portNames {
+			if p := portMap[portName]; p.HasErr() {
+				errs = append(errs, &ConstructionError{Component: c.Name(), Port: portName, Err: p.Err()})
+			}
+		}
+	}
+	return errs
+}
+
+// Validate checks the mesh is ready to run, all construction errors (see ConstructionError) are returned at once.
+// Run validates the mesh itself, so calling it upfront is only needed to report problems early.
+// Optional checks of the topology (e.g. TopologyChecks) report problems which do not prevent the run, but are likely mistakes,
+// so they serve as a dry run: problems found by them are returned (all at once) without putting the mesh into error state
+func (fm *FMesh) Validate(checks ...ValidationCheck) error {
+	if fm.HasErr() {
+		return fm.Err()
+	}
+
+	if err := fm.constructionErrors(); err != nil {
+		fm.SetErr(err)
+		return err
+	}
+	if err := fm.validateLabels(); err != nil {
+		return err
+	}
+	if err := fm.validatePlugins(); err != nil {
+		return err
+	}
+	return fm.validateTopology(checks)
+}
@@ -0,0 +1,85 @@
+package fmesh
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFMesh_Validate(t *testing.T) {
+	newComponent := func(name string) *component.Component {
+		return component.New(name).WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			return nil
+		})
+	}
+
+	tests := []struct {
+		name      string
+		fm        func() *FMesh
+		wantErrs  []string
+		wantError error
+	}{
+		{
+			name: "valid mesh",
+			fm: func() *FMesh {
+				return New("fm").WithComponents(newComponent("c1"), newComponent("c2"))
+			},
+		},
+		{
+			name: "all broken components are reported",
+			fm: func() *FMesh {
+				return New("fm").WithComponents(
+					newComponent("c1").WithErr(errors.New("c1 is broken")),
+					newComponent("c2"),
+					newComponent("c3").WithErr(errors.New("c3 is broken")),
+				)
+			},
+			wantErrs: []string{
+				"component c1: c1 is broken",
+				"component c3: c3 is broken",
+			},
+		},
+		{
+			name: "errors made after adding components are reported",
+			fm: func() *FMesh {
+				c1, c2, c3 := newComponent("c1"), newComponent("c2"), component.New("c3").WithInputs("in")
+				fm := New("fm").WithComponents(c1, c2, c3)
+				c1.InputByName("typo")
+				c2.OutputByName("out").PipeTo(c2.OutputByName("out"))
+				return fm
+			},
+			wantErrs: []string{
+				"component c1: port not found, port name: typo",
+				"component c2, port out: pipe validation failed",
+				"component c3: activation function is not set",
+			},
+			wantError: port.ErrInvalidPipeDirection,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm := tt.fm()
+			err := fm.Validate()
+			if len(tt.wantErrs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			for _, wantErr := range tt.wantErrs {
+				assert.ErrorContains(t, err, wantErr)
+			}
+			if tt.wantError != nil {
+				assert.ErrorIs(t, err, tt.wantError)
+			}
+			var constructionErr *ConstructionError
+			assert.ErrorAs(t, err, &constructionErr)
+
+			_, runErr := fm.Run()
+			assert.Equal(t, err, runErr, "run reports the same errors")
+		})
+	}
+}
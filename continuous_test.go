@@ -0,0 +1,115 @@
+package fmesh
+
+import (
+	"context"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestFMesh_RunContinuously(t *testing.T) {
+	// newMesh returns a mesh doubling numbers injected into "double" and sending results to the channel
+	newMesh := func(engine Engine, results chan<- int) *FMesh {
+		double := component.New("double").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("in").AllSignalsOrNil() {
+				this.OutputByName("out").PutSignals(signal.New(sig.PayloadOrNil().(int) * 2))
+			}
+			return nil
+		})
+		sink := component.NewChannelSink("results", results)
+		double.OutputByName("out").PipeTo(sink.InputByName(component.ChannelSinkInput))
+		return NewWithConfig("fm", &Config{
+			Engine:      engine,
+			CyclesLimit: UnlimitedCycles,
+		}).WithComponents(double, sink)
+	}
+
+	engines := map[string]Engine{
+		"cycle engine": CycleEngine,
+		"event engine": EventEngine,
+	}
+	for name, engine := range engines {
+		t.Run(name+": stop", func(t *testing.T) {
+			results := make(chan int, 10)
+			fm := newMesh(engine, results)
+
+			done := make(chan error)
+			go func() {
+				_, err := fm.RunContinuously(context.Background())
+				done <- err
+			}()
+
+			for i := 1; i <= 3; i++ {
+				require.NoError(t, fm.Inject("double", "in", signal.New(i)))
+				select {
+				case result := <-results:
+					assert.Equal(t, i*2, result)
+				case <-time.After(5 * time.Second):
+					t.Fatal("injected signal is not processed")
+				}
+			}
+
+			// The mesh is idle, but the run goes on
+			select {
+			case err := <-done:
+				t.Fatalf("run ended: %v", err)
+			case <-time.After(20 * time.Millisecond):
+			}
+
+			require.NoError(t, fm.Inject("double", "in", signal.New(4)))
+			fm.Stop()
+			assert.NoError(t, <-done)
+		})
+
+		t.Run(name+": cancel", func(t *testing.T) {
+			fm := newMesh(engine, make(chan int, 10))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() {
+				_, err := fm.RunContinuously(ctx)
+				done <- err
+			}()
+			cancel()
+			assert.ErrorIs(t, <-done, context.Canceled)
+		})
+	}
+
+	t.Run("sources keep working", func(t *testing.T) {
+		results := make(chan int, 10)
+		ticks := make(chan int)
+		source := component.NewChannelSource("ticks", ticks).WithIdleWaitFunc(func(this *component.Component, cancel <-chan struct{}) bool {
+			select {
+			case <-cancel:
+				return false
+			case <-time.After(time.Millisecond):
+				return true
+			}
+		})
+		forward := component.New("forward").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+		})
+		sink := component.NewChannelSink("results", results)
+		source.OutputByName(component.ChannelSourceOutput).PipeTo(forward.InputByName("in"))
+		forward.OutputByName("out").PipeTo(sink.InputByName(component.ChannelSinkInput))
+		fm := NewWithConfig("fm", &Config{CyclesLimit: UnlimitedCycles}).WithComponents(source, forward, sink)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			_, err := fm.RunContinuously(ctx)
+			done <- err
+		}()
+
+		ticks <- 1
+		assert.Equal(t, 1, <-results)
+		require.NoError(t, fm.Inject("forward", "in", signal.New(2)))
+		assert.Equal(t, 2, <-results)
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+	})
+}
@@ -13,6 +13,7 @@ type Cycle struct {
 	*common.Chainable
 	number            int
 	activationResults component.ActivationResultCollection
+	transfers         []Transfer
 }
 
 // New creates a new cycle
@@ -216,3 +216,13 @@ func TestCycle_WithActivationResults(t *testing.T) {
 		})
 	}
 }
+
+func TestCycle_WithTransfers(t *testing.T) {
+	t.Run("transfers are accumulated", func(t *testing.T) {
+		cycle := New().
+			WithTransfers(Transfer{SourceComponent: "c1", SourcePort: "o1", DestComponent: "c2", DestPort: "i1", SignalsCount: 2}).
+			WithTransfers(Transfer{SourceComponent: "c2", SourcePort: "o1", DestComponent: "c3", DestPort: "i1", SignalsCount: 1})
+		assert.Len(t, cycle.Transfers(), 2)
+		assert.Equal(t, "c3", cycle.Transfers()[1].DestComponent)
+	})
+}
@@ -0,0 +1,21 @@
+package cycle
+
+// Transfer describes signals moved through one pipe while draining the cycle
+type Transfer struct {
+	SourceComponent string `json:"sourceComponent"`
+	SourcePort      string `json:"sourcePort"`
+	DestComponent   string `json:"destComponent"`
+	DestPort        string `json:"destPort"`
+	SignalsCount    int    `json:"signalsCount"`
+}
+
+// Transfers returns all transfers happened after the cycle
+func (cycle *Cycle) Transfers() []Transfer {
+	return cycle.transfers
+}
+
+// WithTransfers adds transfers to the cycle
+func (cycle *Cycle) WithTransfers(transfers ...Transfer) *Cycle {
+	cycle.transfers = append(cycle.transfers, transfers...)
+	return cycle
+}
@@ -0,0 +1,82 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"sync"
+)
+
+// DeadLetter is an input signal lost by a failed activation: the component returned an error or panicked,
+// so its inputs were cleared without being processed
+type DeadLetter struct {
+	Component string
+	Port      string
+	// Cycle is the number of the cycle the activation failed in (0 with EventEngine, which has no cycles)
+	Cycle int
+	// Err is the activation error, Stack is the stack trace of the panic (nil when the activation did not panic)
+	Err    error
+	Stack  []byte
+	Signal *signal.Signal
+}
+
+// DeadLetters collects signals which could not be processed. Signals are not lost (hence not collected) when they stay on ports:
+// inputs of at-least-once components, inputs rerouted to fallbacks and inputs of a run stopped on the error.
+// It is safe for concurrent use
+type DeadLetters struct {
+	mu      sync.Mutex
+	letters []DeadLetter
+}
+
+// DeadLetters returns signals lost by failed activations of all runs (until Reset)
+func (fm *FMesh) DeadLetters() *DeadLetters {
+	return &fm.deadLetters
+}
+
+// All returns collected dead letters in the order they were collected
+func (dl *DeadLetters) All() []DeadLetter {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+	return append([]DeadLetter(nil), dl.letters...)
+}
+
+// Len returns the number of collected dead letters
+func (dl *DeadLetters) Len() int {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+	return len(dl.letters)
+}
+
+// Drain returns collected dead letters and removes them, so they can be handled (e.g. logged or replayed via Inject) once
+func (dl *DeadLetters) Drain() []DeadLetter {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+	letters := dl.letters
+	dl.letters = nil
+	return letters
+}
+
+// collect adds input signals of the failed component (ordered by port names)
+func (dl *DeadLetters) collect(c *component.Component, cycleNumber int, err error) {
+	inputs := c.Inputs().PortsOrNil()
+	stack := component.PanicStack(err)
+	var letters []DeadLetter
+	for _, name := range sortedPortNames(inputs) {
+		for _, sig := range inputs[name].AllSignalsOrNil() {
+			letters = append(letters, DeadLetter{
+				Component: c.Name(),
+				Port:      name,
+				Cycle:     cycleNumber,
+				Err:       err,
+				Stack:     stack,
+				Signal:    sig,
+			})
+		}
+	}
+	if len(letters) == 0 {
+		return
+	}
+
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+	dl.letters = append(dl.letters, letters...)
+}
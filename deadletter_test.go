@@ -0,0 +1,93 @@
+package fmesh
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFMesh_DeadLetters(t *testing.T) {
+	newFailing := func() *component.Component {
+		return component.New("failing").
+			WithInputs("a", "b").
+			WithActivationFunc(func(this *component.Component) error {
+				if this.InputByName("b").HasSignals() {
+					panic("boom")
+				}
+				return errors.New("bad input")
+			})
+	}
+
+	for _, engine := range []Engine{CycleEngine, EventEngine} {
+		c := newFailing()
+		c.InputByName("a").PutSignals(signal.New(1), signal.New(2))
+		fm := NewWithConfig("fm", &Config{ErrorHandlingStrategy: IgnoreAll, Engine: engine}).WithComponents(c)
+
+		_, err := fm.Run()
+		require.NoError(t, err)
+
+		letters := fm.DeadLetters().All()
+		require.Len(t, letters, 2)
+		for i, letter := range letters {
+			assert.Equal(t, "failing", letter.Component)
+			assert.Equal(t, "a", letter.Port)
+			assert.ErrorContains(t, letter.Err, "bad input")
+			assert.Equal(t, i+1, letter.Signal.PayloadOrNil())
+			if engine == CycleEngine {
+				assert.Equal(t, 1, letter.Cycle)
+			} else {
+				assert.Zero(t, letter.Cycle)
+			}
+		}
+	}
+
+	t.Run("panics", func(t *testing.T) {
+		c := newFailing()
+		c.InputByName("a").PutSignals(signal.New(1))
+		c.InputByName("b").PutSignals(signal.New(2))
+		fm := NewWithConfig("fm", &Config{ErrorHandlingStrategy: IgnoreAll}).WithComponents(c)
+
+		_, err := fm.Run()
+		require.NoError(t, err)
+
+		letters := fm.DeadLetters().Drain()
+		require.Len(t, letters, 2)
+		assert.Equal(t, []string{"a", "b"}, []string{letters[0].Port, letters[1].Port})
+		assert.ErrorContains(t, letters[1].Err, "boom")
+		assert.Zero(t, fm.DeadLetters().Len())
+	})
+
+	t.Run("signals left on ports are not dead letters", func(t *testing.T) {
+		// The run stops on the error, so inputs are kept
+		c := newFailing()
+		c.InputByName("a").PutSignals(signal.New(1))
+		fm := New("fm").WithComponents(c)
+		_, err := fm.Run()
+		require.Error(t, err)
+		assert.Zero(t, fm.DeadLetters().Len())
+		assert.True(t, c.InputByName("a").HasSignals())
+
+		// Unacknowledged signals of at-least-once components stay for the next attempt
+		c = newFailing().WithAtLeastOnce()
+		c.InputByName("a").PutSignals(signal.New(1))
+		fm = NewWithConfig("fm", &Config{ErrorHandlingStrategy: IgnoreAll, CyclesLimit: 3}).WithComponents(c)
+		_, err = fm.Run()
+		require.ErrorIs(t, err, ErrReachedMaxAllowedCycles)
+		assert.Zero(t, fm.DeadLetters().Len())
+	})
+
+	t.Run("reset drops dead letters", func(t *testing.T) {
+		c := newFailing()
+		c.InputByName("a").PutSignals(signal.New(1))
+		fm := NewWithConfig("fm", &Config{ErrorHandlingStrategy: IgnoreAll}).WithComponents(c)
+		_, err := fm.Run()
+		require.NoError(t, err)
+		require.Equal(t, 1, fm.DeadLetters().Len())
+
+		fm.Reset()
+		assert.Zero(t, fm.DeadLetters().Len())
+	})
+}
@@ -0,0 +1,90 @@
+package fmesh
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/cycle"
+	"sort"
+	"strings"
+)
+
+// DeadlockError reports components which wait for inputs that can not arrive: all components activated in the cycle
+// were waiting for inputs, so nothing was flushed and nothing else can change the inputs
+type DeadlockError struct {
+	Cycle   int
+	Blocked []BlockedComponent
+}
+
+// BlockedComponent is a component waiting for inputs
+type BlockedComponent struct {
+	Component string
+	// Holding are input ports having signals
+	Holding []string
+	// WaitingFor are input ports having no signals
+	WaitingFor []string
+	// KeepsInputs says whether the component keeps its input signals while waiting (otherwise they are cleared and lost)
+	KeepsInputs bool
+}
+
+// Error returns the error listing blocked components
+func (e *DeadlockError) Error() string {
+	blocked := make([]string, len(e.Blocked))
+	for i, b := range e.Blocked {
+		blocked[i] = fmt.Sprintf("%s (holding: [%s], waiting for: [%s])", b.Component, strings.Join(b.Holding, ", "), strings.Join(b.WaitingFor, ", "))
+	}
+	return fmt.Sprintf("%v, cycle # %d, blocked components: %s", ErrDeadlock, e.Cycle, strings.Join(blocked, ", "))
+}
+
+// Unwrap returns ErrDeadlock
+func (e *DeadlockError) Unwrap() error {
+	return ErrDeadlock
+}
+
+// detectDeadlock returns DeadlockError when all components activated in the cycle were waiting for inputs and nothing
+// can bring new signals: no injections are pending, the run is not continuous and the mesh has no sources
+func (fm *FMesh) detectDeadlock(lastCycle *cycle.Cycle) error {
+	if !lastCycle.HasActivatedComponents() || fm.continuous || fm.injections.hasPending() {
+		return nil
+	}
+
+	var blocked []BlockedComponent
+	for name, activationResult := range lastCycle.ActivationResults() {
+		if !activationResult.Activated() {
+			continue
+		}
+		if !component.IsWaitingForInput(activationResult) {
+			return nil
+		}
+
+		c := fm.Components().ByName(name)
+		b := BlockedComponent{
+			Component:   name,
+			KeepsInputs: component.WantsToKeepInputs(activationResult),
+		}
+		for portName, p := range c.Inputs().PortsOrNil() {
+			if p.HasSignals() {
+				b.Holding = append(b.Holding, portName)
+			} else {
+				b.WaitingFor = append(b.WaitingFor, portName)
+			}
+		}
+		sort.Strings(b.Holding)
+		sort.Strings(b.WaitingFor)
+		blocked = append(blocked, b)
+	}
+
+	for _, c := range fm.Components().ComponentsOrNil() {
+		if c.IsSource() {
+			// Sources may become ready later
+			return nil
+		}
+	}
+
+	sort.Slice(blocked, func(i, j int) bool {
+		return blocked[i].Component < blocked[j].Component
+	})
+	return &DeadlockError{
+		Cycle:   lastCycle.Number(),
+		Blocked: blocked,
+	}
+}
@@ -0,0 +1,123 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFMesh_DeadlockDetection(t *testing.T) {
+	// joiner waits until both inputs have signals
+	newJoiner := func(keepInputs bool) *component.Component {
+		return component.New("joiner").
+			WithInputs("a", "b").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				if !this.Inputs().AllHaveSignals() {
+					return component.NewErrWaitForInputs(keepInputs)
+				}
+				this.OutputByName("out").PutSignals(signal.New("joined"))
+				return nil
+			})
+	}
+	newForwarder := func(name string) *component.Component {
+		return component.New(name).
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+				return nil
+			})
+	}
+
+	tests := []struct {
+		name       string
+		getFM      func() *FMesh
+		assertions func(t *testing.T, cycles int, err error)
+	}{
+		{
+			name: "waiting with kept inputs does not loop until cycles limit",
+			getFM: func() *FMesh {
+				joiner := newJoiner(true)
+				joiner.InputByName("a").PutSignals(signal.New(1))
+				return New("fm").WithComponents(joiner)
+			},
+			assertions: func(t *testing.T, cycles int, err error) {
+				var deadlock *DeadlockError
+				require.ErrorAs(t, err, &deadlock)
+				require.ErrorIs(t, err, ErrDeadlock)
+				assert.Equal(t, 1, deadlock.Cycle)
+				assert.Equal(t, []BlockedComponent{
+					{Component: "joiner", Holding: []string{"a"}, WaitingFor: []string{"b"}, KeepsInputs: true},
+				}, deadlock.Blocked)
+				assert.Equal(t, "deadlock: components wait for inputs which can not arrive, cycle # 1, blocked components: joiner (holding: [a], waiting for: [b])", err.Error())
+			},
+		},
+		{
+			name: "waiting with cleared inputs does not stop silently",
+			getFM: func() *FMesh {
+				fwd := newForwarder("fwd")
+				joiner := newJoiner(false)
+				fwd.OutputByName("out").PipeTo(joiner.InputByName("b"))
+				fwd.InputByName("in").PutSignals(signal.New(1))
+				return New("fm").WithComponents(fwd, joiner)
+			},
+			assertions: func(t *testing.T, cycles int, err error) {
+				var deadlock *DeadlockError
+				require.ErrorAs(t, err, &deadlock)
+				assert.Equal(t, 2, deadlock.Cycle)
+				assert.Equal(t, []BlockedComponent{
+					{Component: "joiner", Holding: []string{"b"}, WaitingFor: []string{"a"}},
+				}, deadlock.Blocked)
+			},
+		},
+		{
+			name: "waiting while other components progress is not a deadlock",
+			getFM: func() *FMesh {
+				fwd1, fwd2 := newForwarder("fwd1"), newForwarder("fwd2")
+				joiner := newJoiner(true)
+				fwd1.OutputByName("out").PipeTo(fwd2.InputByName("in"))
+				fwd2.OutputByName("out").PipeTo(joiner.InputByName("b"))
+				fwd1.InputByName("in").PutSignals(signal.New(1))
+				joiner.InputByName("a").PutSignals(signal.New(1))
+				return New("fm").WithComponents(fwd1, fwd2, joiner)
+			},
+			assertions: func(t *testing.T, cycles int, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 4, cycles)
+			},
+		},
+		{
+			name: "sources may unblock the mesh later",
+			getFM: func() *FMesh {
+				ticks := 0
+				source := component.New("source").
+					WithOutputs("out").
+					WithReadinessFunc(func(this *component.Component) bool {
+						ticks++
+						return ticks == 3
+					}).
+					WithActivationFunc(func(this *component.Component) error {
+						this.OutputByName("out").PutSignals(signal.New(1))
+						return nil
+					})
+				joiner := newJoiner(true)
+				source.OutputByName("out").PipeTo(joiner.InputByName("b"))
+				joiner.InputByName("a").PutSignals(signal.New(1))
+				return New("fm").WithComponents(source, joiner)
+			},
+			assertions: func(t *testing.T, cycles int, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cycles, err := tt.getFM().Run()
+			tt.assertions(t, len(cycles), err)
+		})
+	}
+}
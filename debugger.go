@@ -0,0 +1,146 @@
+package fmesh
+
+import (
+	"context"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// Debugger pauses the mesh before components hitting breakpoints activate, so their inputs and state can be inspected.
+// The paused mesh is continued with Resume or Step (see Pause). Breakpoints apply to CycleEngine only
+type Debugger struct {
+	fm          *FMesh
+	mu          sync.Mutex
+	breakpoints []breakpoint
+	// breaks holds the latest break nobody waited for yet
+	breaks chan *Break
+}
+
+// breakpoint matches components by name or by label
+type breakpoint struct {
+	component string
+	label     string
+	value     string
+}
+
+// matches says whether the component hits the breakpoint
+func (b breakpoint) matches(c *component.Component) bool {
+	if b.component != "" {
+		return c.Name() == b.component
+	}
+	return c.HasLabel(b.label) && c.LabelOrDefault(b.label, "") == b.value
+}
+
+// Break describes the paused mesh: the cycle which is about to run and the components which hit breakpoints
+type Break struct {
+	// Cycle is the number of the cycle which runs when the mesh is continued
+	Cycle      int
+	Components []BreakComponent
+}
+
+// BreakComponent is a snapshot of a component which hit a breakpoint, taken before its activation
+type BreakComponent struct {
+	Name string
+	// Inputs holds signals buffered on input ports by port names
+	Inputs map[string]signal.Signals
+	State  component.State
+}
+
+// Debugger returns the debugger of the mesh
+func (fm *FMesh) Debugger() *Debugger {
+	fm.debuggerOnce.Do(func() {
+		fm.debugger = &Debugger{
+			fm:     fm,
+			breaks: make(chan *Break, 1),
+		}
+	})
+	return fm.debugger
+}
+
+// BreakOn sets a breakpoint on the component with the given name
+func (d *Debugger) BreakOn(componentName string) *Debugger {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.breakpoints = append(d.breakpoints, breakpoint{component: componentName})
+	return d
+}
+
+// BreakOnLabel sets a breakpoint on components having the label with the given value
+func (d *Debugger) BreakOnLabel(label string, value string) *Debugger {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.breakpoints = append(d.breakpoints, breakpoint{label: label, value: value})
+	return d
+}
+
+// ClearBreakpoints removes all breakpoints
+func (d *Debugger) ClearBreakpoints() *Debugger {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.breakpoints = nil
+	return d
+}
+
+// Wait blocks until the mesh hits a breakpoint (or returns the break which was hit while nobody waited)
+func (d *Debugger) Wait(ctx context.Context) (*Break, error) {
+	select {
+	case b := <-d.breaks:
+		return b, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// check pauses the mesh when components which are about to activate hit breakpoints
+func (d *Debugger) check() {
+	d.mu.Lock()
+	breakpoints := slices.Clone(d.breakpoints)
+	d.mu.Unlock()
+	if len(breakpoints) == 0 {
+		return
+	}
+
+	t := d.fm.compiledTopology()
+	injected := d.fm.injections.targets()
+	b := &Break{
+		Cycle: d.fm.cycles.Len() + 1,
+	}
+	for id, c := range t.components {
+		if !t.scheduled[id] || !(c.Inputs().AnyHasSignals() || injected[c.Name()]) {
+			// The component does not activate in the next cycle
+			continue
+		}
+		if !slices.ContainsFunc(breakpoints, func(bp breakpoint) bool {
+			return bp.matches(c)
+		}) {
+			continue
+		}
+
+		inputs := make(map[string]signal.Signals)
+		for name, p := range c.Inputs().PortsOrNil() {
+			inputs[name] = slices.Clone(p.AllSignalsOrNil())
+		}
+		b.Components = append(b.Components, BreakComponent{
+			Name:   c.Name(),
+			Inputs: inputs,
+			State:  c.State().Snapshot(),
+		})
+	}
+	if len(b.Components) == 0 {
+		return
+	}
+
+	sort.Slice(b.Components, func(i, j int) bool {
+		return b.Components[i].Name < b.Components[j].Name
+	})
+	d.fm.Pause()
+	// Only the latest break is kept for Wait
+	select {
+	case <-d.breaks:
+	default:
+	}
+	d.breaks <- b
+}
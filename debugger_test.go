@@ -0,0 +1,100 @@
+package fmesh
+
+import (
+	"context"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestDebugger(t *testing.T) {
+	// a forwards signals to b, b counts them
+	getFM := func() *FMesh {
+		a := component.New("a").
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+				return nil
+			})
+		b := component.New("b").
+			WithLabels(common.LabelsCollection{"role": "counter"}).
+			WithInputs("in").
+			WithInitialState(func(state component.State) {
+				state.Set("count", 0)
+			}).
+			WithActivationFunc(func(this *component.Component) error {
+				this.State().Set("count", this.State().Get("count").(int)+len(this.InputByName("in").AllSignalsOrNil()))
+				return nil
+			})
+		a.OutputByName("out").PipeTo(b.InputByName("in"))
+		a.InputByName("in").PutSignals(signal.New(1), signal.New(2))
+		return New("fm").WithComponents(a, b)
+	}
+
+	runAsync := func(fm *FMesh) <-chan error {
+		done := make(chan error, 1)
+		go func() {
+			_, err := fm.Run()
+			done <- err
+		}()
+		return done
+	}
+
+	tests := []struct {
+		name       string
+		breakpoint func(d *Debugger)
+	}{
+		{
+			name: "break on component",
+			breakpoint: func(d *Debugger) {
+				d.BreakOn("b")
+			},
+		},
+		{
+			name: "break on label",
+			breakpoint: func(d *Debugger) {
+				d.BreakOnLabel("role", "counter")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm := getFM()
+			tt.breakpoint(fm.Debugger())
+			done := runAsync(fm)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			b, err := fm.Debugger().Wait(ctx)
+			require.NoError(t, err)
+
+			assert.True(t, fm.IsPaused())
+			assert.Equal(t, 2, b.Cycle)
+			require.Len(t, b.Components, 1)
+			assert.Equal(t, "b", b.Components[0].Name)
+			assert.Len(t, b.Components[0].Inputs["in"], 2)
+			assert.Equal(t, 0, b.Components[0].State.Get("count"), "state is captured before the activation")
+
+			c, err := fm.Step()
+			require.NoError(t, err)
+			assert.Equal(t, 2, c.Number())
+			assert.Equal(t, 2, fm.ComponentByName("b").State().Get("count"))
+
+			fm.Resume()
+			require.NoError(t, <-done)
+		})
+	}
+
+	t.Run("cleared breakpoints", func(t *testing.T) {
+		fm := getFM()
+		fm.Debugger().BreakOn("b").ClearBreakpoints()
+		require.NoError(t, <-runAsync(fm))
+		assert.False(t, fm.IsPaused())
+	})
+}
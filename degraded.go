@@ -0,0 +1,103 @@
+package fmesh
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+)
+
+// reroute is a batch of signals to be put on the input port of a fallback component
+type reroute struct {
+	fallbackID int
+	port       *port.Port
+	signals    signal.Signals
+}
+
+// degradeFailed marks components failed in the latest cycle as degraded (under DegradeFailing strategy) and returns
+// their inputs to be rerouted to fallbacks, inputs are taken before they are cleared, so the failed work is retried by the fallback
+func (fm *FMesh) degradeFailed(activationResults []*component.ActivationResult) []reroute {
+	if fm.config.ErrorHandlingStrategy != DegradeFailing {
+		return nil
+	}
+
+	var reroutes []reroute
+	t := fm.compiledTopology()
+	for id, activationResult := range activationResults {
+		if (!activationResult.IsError() && !activationResult.IsPanic()) || activationResult.Restarted() {
+			continue
+		}
+
+		c := t.components[id]
+		if !c.IsDegraded() {
+			fm.LogDebug(fmt.Sprintf("component %s is degraded: %v", c.Name(), activationResult.ActivationError()))
+			c.AddLabel(component.DegradedLabel, "true")
+		}
+		componentReroutes, err := fm.takeInputsForFallback(c)
+		if err != nil {
+			fm.SetErr(err)
+			return nil
+		}
+		reroutes = append(reroutes, componentReroutes...)
+	}
+	return reroutes
+}
+
+// rerouteDegraded puts the given signals and signals delivered to degraded components in the latest drain on their fallbacks,
+// fallbacks are scheduled, so they activate in the next cycle
+func (fm *FMesh) rerouteDegraded(reroutes []reroute) {
+	if fm.config.ErrorHandlingStrategy != DegradeFailing {
+		return
+	}
+
+	t := fm.compiledTopology()
+	for _, c := range t.components {
+		if !c.IsDegraded() {
+			continue
+		}
+		componentReroutes, err := fm.takeInputsForFallback(c)
+		if err != nil {
+			fm.SetErr(err)
+			return
+		}
+		reroutes = append(reroutes, componentReroutes...)
+	}
+
+	for _, r := range reroutes {
+		r.port.PutSignals(r.signals...)
+		t.scheduled[r.fallbackID] = true
+	}
+}
+
+// takeInputsForFallback removes signals from inputs of the degraded component and returns them as reroutes to its fallback
+// (nothing is taken when the component has no fallback)
+func (fm *FMesh) takeInputsForFallback(c *component.Component) ([]reroute, error) {
+	if c.Fallback() == "" {
+		return nil, nil
+	}
+
+	t := fm.compiledTopology()
+	fallbackID, ok := t.ids[c.Fallback()]
+	if !ok {
+		return nil, fmt.Errorf("%w: fallback %s of component %s is not in the mesh", ErrInvalidFallback, c.Fallback(), c.Name())
+	}
+	fallback := t.components[fallbackID]
+
+	var reroutes []reroute
+	for name, p := range c.Inputs().PortsOrNil() {
+		if !p.HasSignals() {
+			continue
+		}
+		dest, ok := fallback.Inputs().PortsOrNil()[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: fallback %s of component %s has no input port %s", ErrInvalidFallback, fallback.Name(), c.Name(), name)
+		}
+		reroutes = append(reroutes, reroute{
+			fallbackID: fallbackID,
+			port:       dest,
+			signals:    p.AllSignalsOrNil(),
+		})
+		p.Clear()
+	}
+	return reroutes, nil
+}
@@ -0,0 +1,93 @@
+package fmesh
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFMesh_DegradeFailing(t *testing.T) {
+	// newMesh returns a mesh where a feeder forwards jobs to a primary, which fails on "bad" jobs,
+	// the backup is the fallback of the primary, both report processed jobs on ports without pipes
+	newMesh := func(fallback string) *FMesh {
+		feeder := component.New("feeder").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+		})
+		primary := component.New("primary").WithInputs("jobs").WithOutputs("done").WithFallback(fallback).WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("jobs").AllSignalsOrNil() {
+				if sig.PayloadOrNil() == "bad" {
+					return errors.New("primary is broken")
+				}
+			}
+			return port.ForwardSignals(this.InputByName("jobs"), this.OutputByName("done"))
+		})
+		backup := component.New("backup").WithInputs("jobs").WithOutputs("done").WithActivationFunc(func(this *component.Component) error {
+			return port.ForwardSignals(this.InputByName("jobs"), this.OutputByName("done"))
+		})
+		feeder.OutputByName("out").PipeTo(primary.InputByName("jobs"))
+		return NewWithConfig("fm", &Config{
+			ErrorHandlingStrategy: DegradeFailing,
+			CyclesLimit:           100,
+		}).WithComponents(feeder, primary, backup)
+	}
+
+	t.Run("inputs are rerouted to fallback", func(t *testing.T) {
+		fm := newMesh("backup")
+		primary, backup := fm.ComponentByName("primary"), fm.ComponentByName("backup")
+
+		fm.ComponentByName("feeder").InputByName("in").PutSignals(signal.New("ok-1"))
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.False(t, primary.IsDegraded())
+
+		fm.ComponentByName("feeder").InputByName("in").PutSignals(signal.New("bad"))
+		_, err = fm.Run()
+		assert.NoError(t, err, "the run continues")
+		assert.True(t, primary.IsDegraded())
+		assert.Equal(t, "true", primary.LabelOrDefault(component.DegradedLabel, ""))
+		payloads, err := backup.OutputByName("done").AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"bad"}, payloads, "inputs of the failed activation are taken over")
+
+		fm.ComponentByName("feeder").InputByName("in").PutSignals(signal.New("ok-2"))
+		_, err = fm.Run()
+		assert.NoError(t, err)
+		payloads, err = backup.OutputByName("done").AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"bad", "ok-2"}, payloads, "degraded component does not get signals anymore")
+		payloads, err = primary.OutputByName("done").AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"ok-1"}, payloads)
+
+		fm.Reset()
+		assert.False(t, primary.IsDegraded())
+	})
+
+	t.Run("degraded component without fallback keeps getting signals", func(t *testing.T) {
+		fm := newMesh("")
+		primary := fm.ComponentByName("primary")
+
+		fm.ComponentByName("feeder").InputByName("in").PutSignals(signal.New("bad"))
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.True(t, primary.IsDegraded())
+
+		fm.ComponentByName("feeder").InputByName("in").PutSignals(signal.New("ok"))
+		_, err = fm.Run()
+		assert.NoError(t, err)
+		payloads, err := primary.OutputByName("done").AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"ok"}, payloads)
+	})
+
+	t.Run("fallback must be in the mesh", func(t *testing.T) {
+		fm := newMesh("missing")
+
+		fm.ComponentByName("feeder").InputByName("in").PutSignals(signal.New("bad"))
+		_, err := fm.Run()
+		assert.ErrorIs(t, err, ErrInvalidFallback)
+	})
+}
@@ -0,0 +1,69 @@
+package fmesh
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/port"
+	"sort"
+	"strings"
+)
+
+// String returns a short readable representation of the mesh, e.g. "f-mesh demo (3 components)"
+func (fm *FMesh) String() string {
+	if fm.HasErr() {
+		return fmt.Sprintf("f-mesh %s (error: %v)", fm.Name(), fm.Err())
+	}
+	return fmt.Sprintf("f-mesh %s (%d components)", fm.Name(), fm.Components().Len())
+}
+
+// Describe returns a readable multi-line summary of the mesh: description, labels, components (see component.Describe)
+// and pipes between them, it is meant for quick inspection in logs and debuggers
+func (fm *FMesh) Describe() string {
+	var sb strings.Builder
+	sb.WriteString(fm.String())
+	if fm.HasErr() {
+		return sb.String()
+	}
+
+	if fm.Description() != "" {
+		sb.WriteString("\n  description: " + fm.Description())
+	}
+	if len(fm.Labels()) > 0 {
+		sb.WriteString("\n  labels: " + fm.Labels().String())
+	}
+
+	components := fm.Components().ComponentsOrNil()
+	names := make([]string, 0, len(components))
+	owners := make(map[*port.Port]string)
+	for name, c := range components {
+		names = append(names, name)
+		for _, p := range c.Inputs().PortsOrNil() {
+			owners[p] = name
+		}
+	}
+	sort.Strings(names)
+
+	var pipes []string
+	for _, name := range names {
+		c := components[name]
+		sb.WriteString("\n  " + strings.ReplaceAll(c.Describe(), "\n", "\n  "))
+
+		for _, out := range c.Outputs().PortsOrNil() {
+			for _, dest := range out.Pipes().PortsOrNil() {
+				owner, ok := owners[dest]
+				if !ok {
+					owner = "?"
+				}
+				pipes = append(pipes, fmt.Sprintf("%s.%s -> %s.%s", name, out.Name(), owner, dest.Name()))
+			}
+		}
+	}
+
+	if len(pipes) > 0 {
+		sort.Strings(pipes)
+		sb.WriteString("\n  pipes:")
+		for _, pipe := range pipes {
+			sb.WriteString("\n    " + pipe)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,53 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFMesh_Describe(t *testing.T) {
+	adder := component.New("adder").
+		WithDescription("adds numbers").
+		WithLabels(common.LabelsCollection{"role": "math", "tier": "1"}).
+		WithInputs("a", "b").
+		WithOutputs("sum").
+		WithInitialState(func(state component.State) {
+			state.Set("total", 0)
+		}).
+		WithActivationFunc(func(this *component.Component) error {
+			return nil
+		})
+	printer := component.New("printer").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+		return nil
+	})
+	adder.InputByName("a").PutSignals(signal.New(1), signal.New(2))
+	adder.OutputByName("sum").AddLabel("unit", "kg")
+	adder.OutputByName("sum").PipeTo(printer.InputByName("in"))
+
+	fm := New("calc").WithDescription("calculator").WithComponents(adder, printer)
+
+	assert.Equal(t, "f-mesh calc (2 components)", fm.String())
+	assert.Equal(t, "component adder (inputs: 2, outputs: 1)", adder.String())
+	assert.Equal(t, "in port a (2 signals)", adder.InputByName("a").String())
+	assert.Equal(t, `f-mesh calc (2 components)
+  description: calculator
+  component adder (inputs: 2, outputs: 1)
+    description: adds numbers
+    labels: role=math, tier=1
+    inputs:
+      in port a (2 signals)
+      in port b (0 signals)
+    outputs:
+      out port sum (0 signals, 1 pipe)
+        labels: unit=kg
+        pipes to: in
+    state: total
+  component printer (inputs: 1, outputs: 0)
+    inputs:
+      in port in (0 signals)
+  pipes:
+    adder.sum -> printer.in`, fm.Describe())
+}
@@ -0,0 +1,22 @@
+package distributed
+
+// Endpoint is a port of a component in a given partition
+type Endpoint struct {
+	Partition string
+	Component string
+	Port      string
+}
+
+// Boundary is a pipe crossing partitions: from an output port in one partition to an input port in another
+type Boundary struct {
+	From Endpoint
+	To   Endpoint
+}
+
+// ref returns the port reference within the partition
+func (e Endpoint) ref() PortRef {
+	return PortRef{
+		Component: e.Component,
+		Port:      e.Port,
+	}
+}
@@ -0,0 +1,150 @@
+package distributed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Coordinator advances all partitions in lock-step and routes signals crossing boundaries:
+// in each round every partition runs exactly one activation cycle, signals collected from boundary sources
+// are delivered to their destinations at the start of the next round, like a local pipe delivers them in the next cycle.
+// The run stops when no partition activated and no signals are in flight
+type Coordinator struct {
+	partitions map[string]Node
+	boundaries []Boundary
+}
+
+// NewCoordinator creates a coordinator without partitions
+func NewCoordinator() *Coordinator {
+	return &Coordinator{
+		partitions: make(map[string]Node),
+	}
+}
+
+// WithPartition adds a partition reachable through the given node
+func (c *Coordinator) WithPartition(name string, node Node) *Coordinator {
+	c.partitions[name] = node
+	return c
+}
+
+// WithBoundaries adds pipes crossing partitions
+func (c *Coordinator) WithBoundaries(boundaries ...Boundary) *Coordinator {
+	c.boundaries = append(c.boundaries, boundaries...)
+	return c
+}
+
+// Run advances partitions until the distributed mesh stops, returns the number of rounds (activation cycles) run
+func (c *Coordinator) Run(ctx context.Context) (int, error) {
+	if err := c.validate(); err != nil {
+		return 0, err
+	}
+
+	collect := c.collectRefs()
+	deliveries := make(map[string][]PortSignals)
+	for round := 1; ; round++ {
+		if err := ctx.Err(); err != nil {
+			return round - 1, err
+		}
+
+		responses, err := c.step(ctx, deliveries, collect)
+		if err != nil {
+			return round, err
+		}
+
+		activated := false
+		for _, response := range responses {
+			activated = activated || response.Activated
+		}
+		deliveries = c.route(responses)
+
+		if !activated && len(deliveries) == 0 {
+			return round, nil
+		}
+	}
+}
+
+// validate checks that all boundaries connect known partitions
+func (c *Coordinator) validate() error {
+	if len(c.partitions) == 0 {
+		return ErrNoPartitions
+	}
+	for _, boundary := range c.boundaries {
+		for _, partition := range []string{boundary.From.Partition, boundary.To.Partition} {
+			if _, ok := c.partitions[partition]; !ok {
+				return fmt.Errorf("%w: %s", ErrUnknownPartition, partition)
+			}
+		}
+	}
+	return nil
+}
+
+// collectRefs returns boundary source ports grouped by partition
+func (c *Coordinator) collectRefs() map[string][]PortRef {
+	refs := make(map[string][]PortRef)
+	seen := make(map[Endpoint]bool)
+	for _, boundary := range c.boundaries {
+		if seen[boundary.From] {
+			continue
+		}
+		seen[boundary.From] = true
+		refs[boundary.From.Partition] = append(refs[boundary.From.Partition], boundary.From.ref())
+	}
+	return refs
+}
+
+// step runs one cycle in all partitions concurrently
+func (c *Coordinator) step(ctx context.Context, deliveries map[string][]PortSignals, collect map[string][]PortRef) (map[string]*StepResponse, error) {
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		responses = make(map[string]*StepResponse, len(c.partitions))
+		errs      []error
+	)
+	for name, node := range c.partitions {
+		wg.Add(1)
+		go func(name string, node Node) {
+			defer wg.Done()
+			response, err := node.Step(ctx, &StepRequest{
+				Deliveries: deliveries[name],
+				Collect:    collect[name],
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w %s: %w", ErrPartitionStepFailed, name, err))
+				return
+			}
+			responses[name] = response
+		}(name, node)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return responses, nil
+}
+
+// route maps signals collected from boundary sources to deliveries for destination partitions
+func (c *Coordinator) route(responses map[string]*StepResponse) map[string][]PortSignals {
+	deliveries := make(map[string][]PortSignals)
+	for _, boundary := range c.boundaries {
+		response, ok := responses[boundary.From.Partition]
+		if !ok {
+			continue
+		}
+		for _, collected := range response.Collected {
+			if collected.PortRef != boundary.From.ref() {
+				continue
+			}
+			deliveries[boundary.To.Partition] = append(deliveries[boundary.To.Partition], PortSignals{
+				PortRef: boundary.To.ref(),
+				Signals: collected.Signals,
+			})
+		}
+	}
+	return deliveries
+}
@@ -0,0 +1,158 @@
+package distributed
+
+import (
+	"context"
+	"errors"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+// newDecrementer creates a component forwarding a decremented number while it is positive
+func newDecrementer(name string) *component.Component {
+	return component.New(name).
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			var num int
+			switch payload := this.InputByName("in").FirstSignalPayloadOrNil().(type) {
+			case int:
+				num = payload
+			case float64:
+				// Payloads crossing partitions are JSON encoded
+				num = int(payload)
+			}
+			if num > 0 {
+				this.OutputByName("out").PutSignals(signal.New(num - 1))
+			}
+			return nil
+		})
+}
+
+func TestCoordinator_Run(t *testing.T) {
+	t.Run("ping-pong keeps cycle semantics of a single mesh", func(t *testing.T) {
+		// Reference: both components in one mesh
+		ping, pong := newDecrementer("ping"), newDecrementer("pong")
+		ping.OutputByName("out").PipeTo(pong.InputByName("in"))
+		pong.OutputByName("out").PipeTo(ping.InputByName("in"))
+		single := fmesh.New("single").WithComponents(ping, pong)
+		ping.InputByName("in").PutSignals(signal.New(5))
+		cycles, err := single.Run()
+		assert.NoError(t, err)
+
+		// Same topology split into two partitions
+		left := fmesh.New("left").WithComponents(newDecrementer("ping"))
+		right := fmesh.New("right").WithComponents(newDecrementer("pong"))
+		left.ComponentByName("ping").InputByName("in").PutSignals(signal.New(5))
+
+		rounds, err := NewCoordinator().
+			WithPartition("left", NewLocalNode(left)).
+			WithPartition("right", NewLocalNode(right)).
+			WithBoundaries(
+				Boundary{
+					From: Endpoint{Partition: "left", Component: "ping", Port: "out"},
+					To:   Endpoint{Partition: "right", Component: "pong", Port: "in"},
+				},
+				Boundary{
+					From: Endpoint{Partition: "right", Component: "pong", Port: "out"},
+					To:   Endpoint{Partition: "left", Component: "ping", Port: "in"},
+				},
+			).
+			Run(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, len(cycles), rounds)
+	})
+
+	t.Run("fan-out boundary", func(t *testing.T) {
+		source := fmesh.New("source").WithComponents(newDecrementer("gen"))
+		source.ComponentByName("gen").InputByName("in").PutSignals(signal.New(1))
+		var (
+			mu       sync.Mutex
+			received []any
+		)
+		newSink := func() *component.Component {
+			return component.New("sink").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+				mu.Lock()
+				defer mu.Unlock()
+				received = append(received, this.InputByName("in").FirstSignalPayloadOrNil())
+				return nil
+			})
+		}
+
+		from := Endpoint{Partition: "source", Component: "gen", Port: "out"}
+		rounds, err := NewCoordinator().
+			WithPartition("source", NewLocalNode(source)).
+			WithPartition("a", NewLocalNode(fmesh.New("a").WithComponents(newSink()))).
+			WithPartition("b", NewLocalNode(fmesh.New("b").WithComponents(newSink()))).
+			WithBoundaries(
+				Boundary{From: from, To: Endpoint{Partition: "a", Component: "sink", Port: "in"}},
+				Boundary{From: from, To: Endpoint{Partition: "b", Component: "sink", Port: "in"}},
+			).
+			Run(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 3, rounds)
+		assert.Equal(t, []any{float64(0), float64(0)}, received)
+	})
+
+	t.Run("no partitions", func(t *testing.T) {
+		_, err := NewCoordinator().Run(context.Background())
+		assert.ErrorIs(t, err, ErrNoPartitions)
+	})
+
+	t.Run("unknown partition", func(t *testing.T) {
+		_, err := NewCoordinator().
+			WithPartition("left", NewLocalNode(fmesh.New("left"))).
+			WithBoundaries(Boundary{
+				From: Endpoint{Partition: "left", Component: "c", Port: "out"},
+				To:   Endpoint{Partition: "right", Component: "c", Port: "in"},
+			}).
+			Run(context.Background())
+		assert.ErrorIs(t, err, ErrUnknownPartition)
+	})
+
+	t.Run("boundary source piped locally", func(t *testing.T) {
+		c1, c2 := newDecrementer("c1"), newDecrementer("c2")
+		c1.OutputByName("out").PipeTo(c2.InputByName("in"))
+		left := fmesh.New("left").WithComponents(c1, c2)
+		c1.InputByName("in").PutSignals(signal.New(3))
+
+		_, err := NewCoordinator().
+			WithPartition("left", NewLocalNode(left)).
+			WithPartition("right", NewLocalNode(fmesh.New("right").WithComponents(newDecrementer("c")))).
+			WithBoundaries(Boundary{
+				From: Endpoint{Partition: "left", Component: "c1", Port: "out"},
+				To:   Endpoint{Partition: "right", Component: "c", Port: "in"},
+			}).
+			Run(context.Background())
+		assert.ErrorIs(t, err, ErrPartitionStepFailed)
+		assert.ErrorIs(t, err, ErrBoundaryPortPiped)
+	})
+
+	t.Run("partition failure", func(t *testing.T) {
+		failing := nodeFunc(func(ctx context.Context, request *StepRequest) (*StepResponse, error) {
+			return nil, errors.New("partition is down")
+		})
+		_, err := NewCoordinator().WithPartition("failing", failing).Run(context.Background())
+		assert.ErrorIs(t, err, ErrPartitionStepFailed)
+		assert.ErrorContains(t, err, "partition is down")
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		rounds, err := NewCoordinator().WithPartition("p", NewLocalNode(fmesh.New("p"))).Run(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 0, rounds)
+	})
+}
+
+// nodeFunc is a node implemented by a function
+type nodeFunc func(ctx context.Context, request *StepRequest) (*StepResponse, error)
+
+// Step calls the function
+func (f nodeFunc) Step(ctx context.Context, request *StepRequest) (*StepResponse, error) {
+	return f(ctx, request)
+}
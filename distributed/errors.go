@@ -0,0 +1,12 @@
+package distributed
+
+import (
+	"errors"
+)
+
+var (
+	ErrNoPartitions        = errors.New("coordinator has no partitions")
+	ErrUnknownPartition    = errors.New("boundary refers to unknown partition")
+	ErrBoundaryPortPiped   = errors.New("boundary source port must not be piped locally")
+	ErrPartitionStepFailed = errors.New("partition failed to run a cycle")
+)
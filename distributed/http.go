@@ -0,0 +1,84 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hovsep/fmesh/remote"
+	"io"
+	"net/http"
+)
+
+// HTTPNode reaches a partition hosted by NewNodeHandler in another process
+type HTTPNode struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPNode creates a node posting step requests to the given url (http.DefaultClient is used when client is nil)
+func NewHTTPNode(url string, client *http.Client) *HTTPNode {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPNode{
+		url:    url,
+		client: client,
+	}
+}
+
+// Step posts the request and decodes the response
+func (n *HTTPNode) Step(ctx context.Context, request *StepRequest) (*StepResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := n.client.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(httpResponse.Body, 1024))
+		return nil, fmt.Errorf("%w: %d %s", remote.ErrUnexpectedStatus, httpResponse.StatusCode, bytes.TrimSpace(message))
+	}
+
+	response := &StepResponse{}
+	if err := json.NewDecoder(httpResponse.Body).Decode(response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// NewNodeHandler creates a handler serving step requests of HTTPNode by the given node (usually a LocalNode)
+func NewNodeHandler(node Node) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		request := &StepRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		response, err := node.Step(r.Context(), request)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+}
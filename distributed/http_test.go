@@ -0,0 +1,39 @@
+package distributed
+
+import (
+	"context"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPNode(t *testing.T) {
+	left := fmesh.New("left").WithComponents(newDecrementer("ping"))
+	right := fmesh.New("right").WithComponents(newDecrementer("pong"))
+	left.ComponentByName("ping").InputByName("in").PutSignals(signal.New(3))
+
+	leftServer := httptest.NewServer(NewNodeHandler(NewLocalNode(left)))
+	defer leftServer.Close()
+	rightServer := httptest.NewServer(NewNodeHandler(NewLocalNode(right)))
+	defer rightServer.Close()
+
+	rounds, err := NewCoordinator().
+		WithPartition("left", NewHTTPNode(leftServer.URL, nil)).
+		WithPartition("right", NewHTTPNode(rightServer.URL, nil)).
+		WithBoundaries(
+			Boundary{
+				From: Endpoint{Partition: "left", Component: "ping", Port: "out"},
+				To:   Endpoint{Partition: "right", Component: "pong", Port: "in"},
+			},
+			Boundary{
+				From: Endpoint{Partition: "right", Component: "pong", Port: "out"},
+				To:   Endpoint{Partition: "left", Component: "ping", Port: "in"},
+			},
+		).
+		Run(context.Background())
+	assert.NoError(t, err)
+	// 3 -> 2 -> 1 -> 0 takes 4 activations plus the final quiet round
+	assert.Equal(t, 5, rounds)
+}
@@ -0,0 +1,78 @@
+package distributed
+
+import (
+	"context"
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/remote"
+	"sync"
+)
+
+// LocalNode runs a partition mesh in the current process, it is also what a partition process exposes over a transport
+type LocalNode struct {
+	mu    sync.Mutex
+	mesh  *fmesh.FMesh
+	codec remote.Codec
+}
+
+// NewLocalNode creates a node driving the given mesh
+func NewLocalNode(mesh *fmesh.FMesh) *LocalNode {
+	return NewLocalNodeWithCodec(mesh, remote.JSONCodec{})
+}
+
+// NewLocalNodeWithCodec creates a node using the given payload codec
+func NewLocalNodeWithCodec(mesh *fmesh.FMesh, codec remote.Codec) *LocalNode {
+	return &LocalNode{
+		mesh:  mesh,
+		codec: codec,
+	}
+}
+
+// Step delivers signals, runs one activation cycle of the mesh and collects signals leaving the partition
+func (n *LocalNode) Step(_ context.Context, request *StepRequest) (*StepResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, delivery := range request.Deliveries {
+		signals, err := remote.DecodeSignals(delivery.Signals, n.codec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode signals for %s.%s: %w", delivery.Component, delivery.Port, err)
+		}
+		if err := n.mesh.Inject(delivery.Component, delivery.Port, signals...); err != nil {
+			return nil, err
+		}
+	}
+
+	activated, err := n.mesh.Advance()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &StepResponse{
+		Activated: activated,
+	}
+	for _, ref := range request.Collect {
+		out := n.mesh.ComponentByName(ref.Component).OutputByName(ref.Port)
+		if out.HasErr() {
+			return nil, out.Err()
+		}
+		if out.HasPipes() {
+			// Local pipes are flushed during the cycle, so the signals would never reach the other partition
+			return nil, fmt.Errorf("%w: %s.%s", ErrBoundaryPortPiped, ref.Component, ref.Port)
+		}
+		if !out.HasSignals() {
+			continue
+		}
+
+		signals, err := remote.EncodeSignals(out.AllSignalsOrNil(), n.codec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode signals from %s.%s: %w", ref.Component, ref.Port, err)
+		}
+		out.Clear()
+		response.Collected = append(response.Collected, PortSignals{
+			PortRef: ref,
+			Signals: signals,
+		})
+	}
+	return response, nil
+}
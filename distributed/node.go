@@ -0,0 +1,42 @@
+// Package distributed splits a mesh across multiple processes: each partition is a mesh of its own,
+// pipes crossing partitions are declared as boundaries and a coordinator advances all partitions in lock-step,
+// so a signal crossing a boundary arrives in the next cycle exactly as it does over a local pipe.
+// Partitions are reached through the Node interface, so any transport can be plugged in
+// (the wire contract is defined in partition.proto, NewHTTPNode is a dependency-free implementation)
+package distributed
+
+import (
+	"context"
+	"github.com/hovsep/fmesh/remote"
+)
+
+// PortRef refers to a port of a component within a partition
+type PortRef struct {
+	Component string `json:"component"`
+	Port      string `json:"port"`
+}
+
+// PortSignals are signals on the wire addressed to (or collected from) a port
+type PortSignals struct {
+	PortRef
+	Signals []remote.Signal `json:"signals"`
+}
+
+// StepRequest asks a partition to run one activation cycle
+type StepRequest struct {
+	// Deliveries are signals which crossed boundaries in the previous cycle, they are put to input ports before the cycle starts
+	Deliveries []PortSignals `json:"deliveries,omitempty"`
+	// Collect lists output ports which are sources of boundaries, their signals are taken after the cycle
+	Collect []PortRef `json:"collect,omitempty"`
+}
+
+// StepResponse is the outcome of one activation cycle of a partition
+type StepResponse struct {
+	Activated bool          `json:"activated"`
+	Collected []PortSignals `json:"collected,omitempty"`
+}
+
+// Node is a partition as seen by the coordinator
+type Node interface {
+	Step(ctx context.Context, request *StepRequest) (*StepResponse, error)
+}
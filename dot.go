@@ -0,0 +1,109 @@
+package fmesh
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/port"
+	"io"
+	"sort"
+	"strings"
+)
+
+// dotEscaper escapes DOT strings, new lines become centered line breaks
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// ExportDOT writes the mesh in Graphviz DOT format: each component is a cluster holding its ports, pipes are edges between ports.
+// User labels are shown on the mesh, components and ports, ports holding signals (e.g. when exported after a run) show their count.
+// The output is deterministic, so it can be diffed (see export/dot for a styled exporter with per-cycle graphs)
+func (fm *FMesh) ExportDOT(w io.Writer) error {
+	if fm.HasErr() {
+		return fm.Err()
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %s {\n", dotQuote(fm.Name()))
+	fmt.Fprintf(bw, "  label=%s;\n", dotQuote(dotTitle(fm.Name(), fm.Labels())))
+	bw.WriteString("  node [shape=box];\n")
+
+	components := fm.Components().ComponentsOrNil()
+	names := make([]string, 0, len(components))
+	owners := make(map[*port.Port]string)
+	for name, c := range components {
+		names = append(names, name)
+		for _, p := range c.Inputs().PortsOrNil() {
+			owners[p] = name
+		}
+	}
+	sort.Strings(names)
+
+	var edges []string
+	for _, name := range names {
+		c := components[name]
+		fmt.Fprintf(bw, "  subgraph %s {\n", dotQuote("cluster_"+name))
+		fmt.Fprintf(bw, "    label=%s;\n", dotQuote(dotTitle(name, c.Labels())))
+		writeDOTPorts(bw, name, "in", c.Inputs().PortsOrNil())
+		writeDOTPorts(bw, name, "out", c.Outputs().PortsOrNil())
+		bw.WriteString("  }\n")
+
+		for _, out := range c.Outputs().PortsOrNil() {
+			for _, dest := range out.Pipes().PortsOrNil() {
+				owner, ok := owners[dest]
+				if !ok {
+					// Pipes leading outside of the mesh are not drawn
+					continue
+				}
+				edges = append(edges, fmt.Sprintf("  %s -> %s;\n", dotQuote(dotPortID(name, "out", out.Name())), dotQuote(dotPortID(owner, "in", dest.Name()))))
+			}
+		}
+	}
+
+	sort.Strings(edges)
+	for _, edge := range edges {
+		bw.WriteString(edge)
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// writeDOTPorts writes port nodes sorted by name
+func writeDOTPorts(w io.Writer, componentName string, direction string, ports port.PortMap) {
+	names := make([]string, 0, len(ports))
+	for name := range ports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p := ports[name]
+		title := fmt.Sprintf("%s %s", direction, name)
+		if count := p.Buffer().Len(); count > 0 {
+			title = fmt.Sprintf("%s (%d)", title, count)
+		}
+		fmt.Fprintf(w, "    %s [label=%s];\n", dotQuote(dotPortID(componentName, direction, name)), dotQuote(dotTitle(title, p.Labels())))
+	}
+}
+
+// dotTitle returns the title followed by user labels on a separate line (labels of f-mesh itself are omitted)
+func dotTitle(title string, labels common.LabelsCollection) string {
+	userLabels := make(common.LabelsCollection, len(labels))
+	for key, value := range labels {
+		if !common.IsSystemLabel(key) {
+			userLabels[key] = value
+		}
+	}
+	if len(userLabels) == 0 {
+		return title
+	}
+	return title + "\n" + userLabels.String()
+}
+
+// dotPortID returns the node ID of the port
+func dotPortID(componentName string, direction string, portName string) string {
+	return componentName + "/" + direction + "/" + portName
+}
+
+// dotQuote returns the DOT quoted string
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
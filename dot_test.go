@@ -0,0 +1,57 @@
+package fmesh
+
+import (
+	"bytes"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFMesh_ExportDOT(t *testing.T) {
+	t.Run("components, ports, pipes and labels", func(t *testing.T) {
+		adder := component.New("adder").
+			WithLabels(common.LabelsCollection{"role": "math"}).
+			WithInputs("a", "b").
+			WithOutputs("sum").
+			WithActivationFunc(func(this *component.Component) error {
+				return nil
+			})
+		printer := component.New("printer").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+			return nil
+		})
+		adder.InputByName("a").PutSignals(signal.New(1), signal.New(2))
+		adder.OutputByName("sum").AddLabel("unit", `"kg"`)
+		adder.OutputByName("sum").PipeTo(printer.InputByName("in"))
+		fm := New("calc").WithLabels(common.LabelsCollection{"env": "test"}).WithComponents(adder, printer)
+
+		var buf bytes.Buffer
+		require.NoError(t, fm.ExportDOT(&buf))
+		assert.Equal(t, `digraph "calc" {
+  label="calc\nenv=test";
+  node [shape=box];
+  subgraph "cluster_adder" {
+    label="adder\nrole=math";
+    "adder/in/a" [label="in a (2)"];
+    "adder/in/b" [label="in b"];
+    "adder/out/sum" [label="out sum\nunit=\"kg\""];
+  }
+  subgraph "cluster_printer" {
+    label="printer";
+    "printer/in/in" [label="in in"];
+  }
+  "adder/out/sum" -> "printer/in/in";
+}
+`, buf.String())
+	})
+
+	t.Run("mesh with error", func(t *testing.T) {
+		fm := New("broken").WithComponents(component.New("c").WithErr(assert.AnError))
+
+		var buf bytes.Buffer
+		require.Error(t, fm.ExportDOT(&buf))
+		assert.Empty(t, buf.String())
+	})
+}
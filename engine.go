@@ -0,0 +1,410 @@
+package fmesh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Engine defines how activations are driven
+type Engine int
+
+const (
+	// CycleEngine activates components in lock-step cycles: all ready components activate, then all of them are drained.
+	// Runs are deterministic and fully recorded in cycles, so it suits simulations
+	CycleEngine Engine = iota
+
+	// EventEngine activates each component as soon as signals arrive at its inputs and flushes its outputs right after the activation,
+	// without waiting for other components, so latency of reactive workloads is not bound by the slowest component of a cycle.
+	// A component never activates concurrently with itself, but different components do, in no particular order.
+	// The run stops when no component has anything to process (or on error, according to the error handling strategy).
+	// Cycles are not recorded (the run returns no cycles, RuntimeInfo.Activations counts activations),
+	// so CyclesLimit, CyclePeriod, SchedulingPolicy, component priorities, PrioritizeSignals, cycle listeners, pausing and deadlock detection (see ErrDeadlock) do not apply,
+	// use the context or MaxDuration (measured by wall time) to limit the run
+	EventEngine
+)
+
+// eventEngine runs the mesh with EventEngine
+type eventEngine struct {
+	fm *FMesh
+	t  *topology
+	// locks guard input ports of each component: activation (with clearing of inputs) and delivery of signals do not interleave
+	locks []sync.Mutex
+	// exclusive is held for writing by activations of exclusive components and for reading by other activations,
+	// serial is held by activations of components pinned to serial execution, slots bound the number of concurrent activations
+	exclusive sync.RWMutex
+	serial    sync.Mutex
+	slots     chan struct{}
+	// wake holds a pending activation request of each component
+	wake []chan struct{}
+	// inflight counts pending and running activation requests, quiet is notified when it drops to zero
+	inflight atomic.Int64
+	quiet    chan struct{}
+	// activations counts activations
+	activations atomic.Int64
+	// held says, for each component ID, whether signals stay on its outputs as they did not fit into destinations blocking on overflow
+	held []atomic.Bool
+	// deferred says, for each component ID, whether it was not activated as ports it pipes to are over their high watermarks
+	deferred []atomic.Bool
+	// failed is closed on the first error stopping the run
+	failOnce sync.Once
+	failed   chan struct{}
+	err      error
+	// done stops the actors
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// runEventDriven runs the mesh with EventEngine
+func (fm *FMesh) runEventDriven(ctx context.Context) error {
+	if fm.config.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, fm.config.MaxDuration, ErrReachedMaxAllowedDuration)
+		defer cancel()
+	}
+
+	t := fm.compiledTopology()
+	e := &eventEngine{
+		fm:       fm,
+		t:        t,
+		locks:    make([]sync.Mutex, len(t.components)),
+		wake:     make([]chan struct{}, len(t.components)),
+		held:     make([]atomic.Bool, len(t.components)),
+		deferred: make([]atomic.Bool, len(t.components)),
+		quiet:    make(chan struct{}, 1),
+		failed:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if fm.config.MaxConcurrentActivations > 0 {
+		e.slots = make(chan struct{}, fm.config.MaxConcurrentActivations)
+	}
+	for id := range t.components {
+		e.wake[id] = make(chan struct{}, 1)
+		e.wg.Add(1)
+		go e.actor(id)
+	}
+	defer func() {
+		close(e.done)
+		e.wg.Wait()
+		if fm.runtimeInfo != nil {
+			fm.runtimeInfo.Activations = e.activations.Load()
+		}
+	}()
+
+	// Signals may be put on any component before the run, so everything is evaluated first
+	e.hold()
+	e.applyInjections()
+	for id := range t.components {
+		e.request(id)
+	}
+	e.settle()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-e.failed:
+			return e.err
+		case <-fm.injections.notifications():
+			e.hold()
+			e.applyInjections()
+			e.settle()
+		case <-e.quiet:
+			if e.inflight.Load() > 0 || fm.injections.hasPending() {
+				continue
+			}
+			if fm.stop.isRequested() || !fm.awaitInput(ctx) {
+				if ctx.Err() != nil {
+					return context.Cause(ctx)
+				}
+				return nil
+			}
+			// A source woke up or signals were injected, sources check their readiness on activation
+			e.hold()
+			e.applyInjections()
+			for id, c := range t.components {
+				if c.IsSource() {
+					e.request(id)
+				}
+			}
+			e.settle()
+		}
+	}
+}
+
+// hold keeps the coordinator from being notified while it requests activations, it is released by settle
+func (e *eventEngine) hold() {
+	e.inflight.Add(1)
+}
+
+// request asks the component to activate, requests made before the component picked up the previous one are coalesced
+func (e *eventEngine) request(id int) {
+	// Counted before sending, so the counter never drops to zero while the request is in flight
+	e.inflight.Add(1)
+	select {
+	case e.wake[id] <- struct{}{}:
+	default:
+		// The pending request covers the new signals as well
+		e.settle()
+	}
+}
+
+// settle completes a request, the last completed request notifies the coordinator
+func (e *eventEngine) settle() {
+	if e.inflight.Add(-1) == 0 {
+		select {
+		case e.quiet <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// applyInjections puts injected signals on their ports and requests activation of the receiving components
+func (e *eventEngine) applyInjections() {
+	for _, i := range e.fm.injections.takeAll() {
+		id, ok := e.t.ids[i.componentName]
+		if !ok {
+			continue
+		}
+		e.locks[id].Lock()
+		i.port.PutSignals(i.signals...)
+		e.locks[id].Unlock()
+		e.request(id)
+	}
+}
+
+// actor serves activation requests of a single component
+func (e *eventEngine) actor(id int) {
+	defer e.wg.Done()
+	for {
+		select {
+		case <-e.done:
+			return
+		case <-e.wake[id]:
+		}
+
+		select {
+		case <-e.failed:
+			// The run is stopping, so nothing is activated anymore
+		default:
+			e.activate(id)
+		}
+		e.settle()
+	}
+}
+
+// activate activates the component and delivers its output signals downstream
+func (e *eventEngine) activate(id int) {
+	c := e.t.components[id]
+	if e.t.pressurable {
+		// Marked before the pressure is checked, so a destination catching up right after does not miss the component
+		e.deferred[id].Store(true)
+		if e.backpressure(id) {
+			return
+		}
+		e.deferred[id].Store(false)
+	}
+
+	leave := e.enter(c)
+	e.locks[id].Lock()
+	activationResult := c.MaybeActivate()
+	e.fm.clearActivatedInputs(c, activationResult, 0)
+	waiting := component.IsWaitingForInput(activationResult)
+	// Failed at-least-once activation kept unacknowledged signals or spilled signals were loaded
+	retained := activationResult.Activated() && !waiting && c.Inputs().AnyHasSignals()
+	e.locks[id].Unlock()
+	leave()
+
+	if !activationResult.Activated() {
+		if e.held[id].Load() {
+			// Signals held back may fit into destinations now
+			e.flush(id)
+		}
+		return
+	}
+	e.activations.Add(1)
+	c.NotifyStateWatchers()
+	e.fm.notifyComponentActivated(activationResult)
+
+	if err := e.check(c, activationResult); err != nil {
+		e.fail(err)
+		return
+	}
+	if waiting {
+		// Components waiting for inputs are never drained, new signals will request the activation again
+		return
+	}
+	e.requestUpstream(id)
+
+	detachForwardedSignals(c)
+	e.fm.stampComponentSignals(c, 0, "")
+	e.flush(id)
+	if retained {
+		e.request(id)
+	}
+}
+
+// enter waits until the activation of the component may run: the number of concurrent activations is bounded,
+// exclusive components run alone and components pinned to serial execution run one at a time.
+// It returns the function leaving (which does nothing when there are no restrictions)
+func (e *eventEngine) enter(c *component.Component) func() {
+	if e.slots == nil && !e.t.exclusive && !e.t.serial {
+		return func() {}
+	}
+
+	if e.slots != nil {
+		e.slots <- struct{}{}
+	}
+	if e.t.exclusive {
+		if c.IsExclusive() {
+			e.exclusive.Lock()
+		} else {
+			e.exclusive.RLock()
+		}
+	}
+	if c.IsSerial() {
+		e.serial.Lock()
+	}
+
+	return func() {
+		if c.IsSerial() {
+			e.serial.Unlock()
+		}
+		if e.t.exclusive {
+			if c.IsExclusive() {
+				e.exclusive.Unlock()
+			} else {
+				e.exclusive.RUnlock()
+			}
+		}
+		if e.slots != nil {
+			<-e.slots
+		}
+	}
+}
+
+// flush delivers output signals of the component downstream and requests activation of the receiving components
+func (e *eventEngine) flush(id int) {
+	c := e.t.components[id]
+	e.fm.notifySignalsDelivered(e.fm.pendingDeliveries(c, e.t))
+
+	// Downstream components are locked in the order of IDs, so concurrent flushes do not deadlock
+	downstream := e.t.downstream[id]
+	for _, downstreamID := range downstream {
+		e.locks[downstreamID].Lock()
+	}
+	c.FlushOutputs()
+	if e.t.blocking {
+		// Marked before destinations are unlocked, so a destination freeing room right after does not miss the held signals
+		e.held[id].Store(holdsSignals(c))
+	}
+	for _, downstreamID := range downstream {
+		e.locks[downstreamID].Unlock()
+	}
+	if c.HasErr() {
+		e.fail(errors.Join(ErrFailedToDrain, c.Err()))
+		return
+	}
+
+	for _, downstreamID := range downstream {
+		e.request(downstreamID)
+	}
+}
+
+// backpressure says whether ports the component pipes to are over their high watermarks,
+// downstream components are locked (in the order of IDs), so their inputs are not cleared while being checked
+func (e *eventEngine) backpressure(id int) bool {
+	downstream := e.t.downstream[id]
+	for _, downstreamID := range downstream {
+		e.locks[downstreamID].Lock()
+	}
+	defer func() {
+		for _, downstreamID := range downstream {
+			e.locks[downstreamID].Unlock()
+		}
+	}()
+	return e.t.components[id].Backpressure()
+}
+
+// requestUpstream requests components holding signals for the component or pushed back by it, as its inputs were cleared
+// and may have room now
+func (e *eventEngine) requestUpstream(id int) {
+	if !e.t.blocking && !e.t.pressurable {
+		return
+	}
+	for _, upstreamID := range e.t.upstream[id] {
+		if e.held[upstreamID].Load() || e.deferred[upstreamID].Load() {
+			e.request(upstreamID)
+		}
+	}
+}
+
+// detachForwardedSignals replaces signals which the component forwards as it received them with copies sharing payloads and labels:
+// a piped signal may be received by many components, so it can not be stamped in place while others read it
+func detachForwardedSignals(c *component.Component) {
+	for _, p := range c.Outputs().PortsOrNil() {
+		if !p.HasPipes() {
+			continue
+		}
+
+		signals := p.AllSignalsOrNil()
+		if !slices.ContainsFunc(signals, isPiped) {
+			continue
+		}
+
+		detached := make(signal.Signals, len(signals))
+		for i, sig := range signals {
+			detached[i] = sig
+			if isPiped(sig) {
+				// Labels are copied on first modification
+				detached[i] = sig.Copy()
+			}
+		}
+		p.Clear().PutSignals(detached...)
+	}
+}
+
+// isPiped says whether the signal came through a pipe
+func isPiped(sig *signal.Signal) bool {
+	return sig.SourceComponent() != ""
+}
+
+// check returns the error stopping the run according to the error handling strategy (if any)
+func (e *eventEngine) check(c *component.Component, activationResult *component.ActivationResult) error {
+	if activationResult.HasErr() {
+		return activationResult.Err()
+	}
+
+	if activationResult.Restarted() {
+		// The failure is handled by the component
+		return nil
+	}
+
+	switch e.fm.config.ErrorHandlingStrategy {
+	case StopOnFirstErrorOrPanic:
+		if activationResult.IsError() || activationResult.IsPanic() {
+			return fmt.Errorf("%w, component %s: %w", ErrHitAnErrorOrPanic, c.Name(), activationResult.ActivationError())
+		}
+	case StopOnFirstPanic:
+		if activationResult.IsPanic() {
+			return fmt.Errorf("%w, component %s: %w", ErrHitAPanic, c.Name(), activationResult.ActivationError())
+		}
+	case IgnoreAll:
+	default:
+		return ErrUnsupportedErrorHandlingStrategy
+	}
+	return nil
+}
+
+// fail stops the run with the error (only the first error is kept)
+func (e *eventEngine) fail(err error) {
+	e.failOnce.Do(func() {
+		e.err = err
+		close(e.failed)
+	})
+}
@@ -0,0 +1,173 @@
+package fmesh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFMesh_EventEngine(t *testing.T) {
+	t.Run("chains are processed", func(t *testing.T) {
+		fm := getChainsMesh(10, 5, &Config{Engine: EventEngine})
+		cycles, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Nil(t, cycles)
+		for i := 0; i < 10; i++ {
+			payloads, err := fm.ComponentByName(fmt.Sprintf("chain-%d-4", i)).OutputByName("out").AllSignalsPayloads()
+			assert.NoError(t, err)
+			assert.Equal(t, []any{i}, payloads)
+		}
+		assert.Equal(t, int64(50), fm.RuntimeInfo().Activations)
+	})
+
+	t.Run("fast path is not held by slow component", func(t *testing.T) {
+		var (
+			mu       sync.Mutex
+			received []string
+		)
+		newWorker := func(name string, d time.Duration) *component.Component {
+			return component.New(name).WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+				time.Sleep(d)
+				return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+			})
+		}
+		sink := component.New("sink").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+			payloads, err := this.InputByName("in").AllSignalsPayloads()
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, payload := range payloads {
+				received = append(received, payload.(string))
+			}
+			return nil
+		})
+		fast1, fast2, slow := newWorker("fast1", 0), newWorker("fast2", 0), newWorker("slow", 50*time.Millisecond)
+		fast1.OutputByName("out").PipeTo(fast2.InputByName("in"))
+		fast2.OutputByName("out").PipeTo(sink.InputByName("in"))
+		slow.OutputByName("out").PipeTo(sink.InputByName("in"))
+		fast1.InputByName("in").PutSignals(signal.New("fast"))
+		slow.InputByName("in").PutSignals(signal.New("slow"))
+
+		fm := NewWithConfig("fm", &Config{Engine: EventEngine}).WithComponents(fast1, fast2, slow, sink)
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"fast", "slow"}, received)
+	})
+
+	t.Run("components waiting for inputs keep them", func(t *testing.T) {
+		a := component.New("a").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			time.Sleep(10 * time.Millisecond)
+			return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+		})
+		sum := component.New("sum").WithInputs("x", "y").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			if !this.InputByName("x").HasSignals() || !this.InputByName("y").HasSignals() {
+				return component.NewErrWaitForInputs(true)
+			}
+			x := this.InputByName("x").FirstSignalPayloadOrNil().(int)
+			y := this.InputByName("y").FirstSignalPayloadOrNil().(int)
+			this.OutputByName("out").PutSignals(signal.New(x + y))
+			return nil
+		})
+		a.OutputByName("out").PipeTo(sum.InputByName("y"))
+		a.InputByName("in").PutSignals(signal.New(2))
+		sum.InputByName("x").PutSignals(signal.New(3))
+
+		fm := NewWithConfig("fm", &Config{Engine: EventEngine}).WithComponents(a, sum)
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Equal(t, 5, sum.OutputByName("out").FirstSignalPayloadOrNil())
+	})
+
+	t.Run("loop runs until it is done", func(t *testing.T) {
+		counter := component.New("counter").WithInputs("in").WithOutputs("out", "result").WithActivationFunc(func(this *component.Component) error {
+			n := this.InputByName("in").FirstSignalPayloadOrNil().(int)
+			if n == 100 {
+				this.OutputByName("result").PutSignals(signal.New(n))
+				return nil
+			}
+			this.OutputByName("out").PutSignals(signal.New(n + 1))
+			return nil
+		})
+		counter.OutputByName("out").PipeTo(counter.InputByName("in"))
+		counter.InputByName("in").PutSignals(signal.New(0))
+
+		fm := NewWithConfig("fm", &Config{Engine: EventEngine}).WithComponents(counter)
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Equal(t, 100, counter.OutputByName("result").FirstSignalPayloadOrNil())
+		assert.Equal(t, int64(101), fm.RuntimeInfo().Activations)
+	})
+
+	t.Run("error handling strategy", func(t *testing.T) {
+		newMesh := func(strategy ErrorHandlingStrategy) *FMesh {
+			failing := component.New("failing").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+				this.OutputByName("out").PutSignals(signal.New("partial"))
+				return errors.New("boom")
+			})
+			sink := component.New("sink").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+				return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+			})
+			failing.OutputByName("out").PipeTo(sink.InputByName("in"))
+			failing.InputByName("in").PutSignals(signal.New(1))
+			return NewWithConfig("fm", &Config{Engine: EventEngine, ErrorHandlingStrategy: strategy}).WithComponents(failing, sink)
+		}
+
+		fm := newMesh(StopOnFirstErrorOrPanic)
+		_, err := fm.Run()
+		assert.ErrorIs(t, err, ErrHitAnErrorOrPanic)
+		assert.ErrorContains(t, err, "boom")
+		assert.False(t, fm.ComponentByName("sink").OutputByName("out").HasSignals())
+
+		fm = newMesh(IgnoreAll)
+		_, err = fm.Run()
+		assert.NoError(t, err)
+		assert.Equal(t, "partial", fm.ComponentByName("sink").OutputByName("out").FirstSignalPayloadOrNil())
+	})
+
+	t.Run("run is cancelled by context", func(t *testing.T) {
+		ping := component.New("ping").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+		})
+		ping.OutputByName("out").PipeTo(ping.InputByName("in"))
+		ping.InputByName("in").PutSignals(signal.New("ball"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		fm := NewWithConfig("fm", &Config{Engine: EventEngine}).WithComponents(ping)
+		_, err := fm.RunWithContext(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("forwarded signals are stamped without touching shared ones", func(t *testing.T) {
+		forward := func(this *component.Component) error {
+			return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+		}
+		src := component.New("src").WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+		left := component.New("left").WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+		right := component.New("right").WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+		sink := component.New("sink").WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+		src.OutputByName("out").PipeTo(left.InputByName("in"), right.InputByName("in"))
+		left.OutputByName("out").PipeTo(sink.InputByName("in"))
+		right.OutputByName("out").PipeTo(sink.InputByName("in"))
+		src.InputByName("in").PutSignals(signal.New(1))
+
+		fm := NewWithConfig("fm", &Config{Engine: EventEngine}).WithComponents(src, left, right, sink)
+		_, err := fm.Run()
+		assert.NoError(t, err)
+
+		var sources []string
+		for _, sig := range sink.OutputByName("out").AllSignalsOrNil() {
+			sources = append(sources, sig.SourceComponent())
+		}
+		assert.ElementsMatch(t, []string{"left", "right"}, sources)
+	})
+}
@@ -15,6 +15,13 @@ const (
 
 	// IgnoreAll allows to continue running the f-mesh regardless of how components finish their activation functions
 	IgnoreAll
+
+	// DegradeFailing continues the run, but marks components which returned an error or panicked as degraded
+	// (component.DegradedLabel). Inputs of a degraded component with a fallback (see component.WithFallback),
+	// including the ones of the failed activation, are rerouted to the fallback from then on,
+	// a degraded component without fallback keeps getting signals. Components stay degraded in next runs until Reset.
+	// Not supported by EventEngine
+	DegradeFailing
 )
 
 var (
@@ -22,8 +29,16 @@ var (
 	ErrHitAPanic                        = errors.New("f-mesh hit a panic and will be stopped")
 	ErrUnsupportedErrorHandlingStrategy = errors.New("unsupported error handling strategy")
 	ErrReachedMaxAllowedCycles          = errors.New("reached max allowed cycles")
+	ErrReachedMaxAllowedDuration        = errors.New("reached max allowed duration")
 	errFailedToRunCycle                 = errors.New("failed to run cycle")
 	errNoComponents                     = errors.New("no components found")
 	errFailedToClearInputs              = errors.New("failed to clear input ports")
 	ErrFailedToDrain                    = errors.New("failed to drain")
+	ErrInjectionTargetNotFound          = errors.New("injection target not found")
+	ErrFailedToInstallPlugin            = errors.New("failed to install plugin")
+	ErrFailedToConnect                  = errors.New("failed to connect ports")
+	ErrInvalidFallback                  = errors.New("invalid fallback")
+	ErrNotPaused                        = errors.New("mesh is not paused")
+	ErrRunEnded                         = errors.New("run ended before the step")
+	ErrDeadlock                         = errors.New("deadlock: components wait for inputs which can not arrive")
 )
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/hovsep/fmesh"
 	"github.com/hovsep/fmesh/component"
@@ -9,10 +10,11 @@ import (
 	"time"
 )
 
-// This example processes 1 url every 3 seconds
+// This example processes 1 url every 3 seconds: the mesh runs continuously and urls are injected while it runs
 // NOTE: urls are not crawled concurrently, because fm has only 1 worker (crawler component)
 func main() {
-	fm := getMesh()
+	resultsChan := make(chan map[string]http.Header)
+	fm := getMesh(resultsChan)
 
 	urls := []string{
 		"http://fffff.com",
@@ -25,62 +27,37 @@ func main() {
 		"https://postman-echo.com/delay/10",
 	}
 
-	ticker := time.NewTicker(3 * time.Second)
-	resultsChan := make(chan []any)
-	doneChan := make(chan struct{}) // Signals when all urls are processed
-
 	//Producer goroutine
 	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				if len(urls) == 0 {
-					close(resultsChan)
-					return
-				}
-				//Pop an url
-				url := urls[0]
-				urls = urls[1:]
-
-				fmt.Println("produce:", url)
-
-				fm.Components().ByName("web crawler").InputByName("url").PutSignals(signal.New(url))
-				_, err := fm.Run()
-				if err != nil {
-					fmt.Println("fmesh returned error ", err)
-				}
-
-				if fm.Components().ByName("web crawler").OutputByName("headers").HasSignals() {
-					results, err := fm.Components().ByName("web crawler").OutputByName("headers").AllSignalsPayloads()
-					if err != nil {
-						fmt.Println("Failed to get results ", err)
-					}
-					fm.Components().ByName("web crawler").OutputByName("headers").Clear() //@TODO maybe we can add fm.Reset() for cases when FMesh is reused (instead of cleaning ports explicitly)
-					resultsChan <- results
-				}
+		ticker := time.NewTicker(3 * time.Second)
+		defer ticker.Stop()
+
+		for _, url := range urls {
+			<-ticker.C
+			fmt.Println("produce:", url)
+			if err := fm.Inject("web crawler", "url", signal.New(url)); err != nil {
+				fmt.Println("failed to inject url ", err)
 			}
 		}
+
+		// Urls which are already injected are processed before the run returns
+		fm.Stop()
 	}()
 
 	//Consumer goroutine
 	go func() {
-		for {
-			select {
-			case r, ok := <-resultsChan:
-				if !ok {
-					fmt.Println("results chan is closed. shutting down the reader")
-					doneChan <- struct{}{}
-					return
-				}
-				fmt.Println(fmt.Sprintf("consume: %v", r))
-			}
+		for r := range resultsChan {
+			fmt.Println(fmt.Sprintf("consume: %v", r))
 		}
 	}()
 
-	<-doneChan
+	if _, err := fm.RunContinuously(context.Background()); err != nil {
+		fmt.Println("fmesh returned error ", err)
+	}
+	close(resultsChan)
 }
 
-func getMesh() *fmesh.FMesh {
+func getMesh(resultsChan chan<- map[string]http.Header) *fmesh.FMesh {
 	//Setup dependencies
 	client := &http.Client{}
 
@@ -106,12 +83,12 @@ func getMesh() *fmesh.FMesh {
 				// in order to call them concurrently we need run each request in separate goroutine and handle synchronization (e.g. waitgroup)
 				response, err := client.Get(url)
 				if err != nil {
-					this.OutputByName("errors").PutSignals(signal.New(fmt.Errorf("got error: %w from url: %s", err, url)))
+					this.OutputByName("errors").PutSignals(signal.NewError(fmt.Errorf("got error: %w from url: %s", err, url)))
 					continue
 				}
 
 				if len(response.Header) == 0 {
-					this.OutputByName("errors").PutSignals(signal.New(fmt.Errorf("no headers for url %s", url)))
+					this.OutputByName("errors").PutSignals(signal.NewError(fmt.Errorf("no headers for url %s", url)))
 					continue
 				}
 
@@ -131,14 +108,8 @@ func getMesh() *fmesh.FMesh {
 				return component.NewErrWaitForInputs(false)
 			}
 
-			allErrors, err := this.InputByName("error").AllSignalsPayloads()
-			if err != nil {
-				return err
-			}
-
-			for _, errVal := range allErrors {
-				e := errVal.(error)
-				if e != nil {
+			for _, sig := range this.InputByName("error").AllSignalsOrNil() {
+				if e := sig.ErrorOrNil(); e != nil {
 					fmt.Println("Error logger says:", e)
 				}
 			}
@@ -146,11 +117,15 @@ func getMesh() *fmesh.FMesh {
 			return nil
 		})
 
+	results := component.NewChannelSink("results", resultsChan)
+
 	//Define pipes
 	crawler.OutputByName("errors").PipeTo(logger.InputByName("error"))
+	crawler.OutputByName("headers").PipeTo(results.InputByName(component.ChannelSinkInput))
 
 	return fmesh.NewWithConfig("web scraper", &fmesh.Config{
 		ErrorHandlingStrategy: fmesh.StopOnFirstErrorOrPanic,
-	}).WithComponents(crawler, logger)
+		CyclesLimit:           fmesh.UnlimitedCycles,
+	}).WithComponents(crawler, logger, results)
 
 }
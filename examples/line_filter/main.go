@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"os"
+	"strings"
+)
+
+// This example reads lines from stdin, drops empty ones, upper-cases the rest and writes them to stdout
+// Usage: printf "hello\n\nworld\n" | go run ./examples/line_filter
+func main() {
+	stdin := component.NewLineReaderSource("stdin", os.Stdin)
+	stdout := component.NewLineWriterSink("stdout", os.Stdout)
+
+	filter := component.New("filter").
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("in").AllSignalsOrNil() {
+				line := strings.TrimSpace(sig.PayloadOrDefault("").(string))
+				if line == "" {
+					continue
+				}
+				this.OutputByName("out").PutSignals(signal.New(strings.ToUpper(line)))
+			}
+			return nil
+		})
+
+	stdin.OutputByName(component.ReaderSourceOutput).PipeTo(filter.InputByName("in"))
+	filter.OutputByName("out").PipeTo(stdout.InputByName(component.WriterSinkInput))
+
+	fm := fmesh.NewWithConfig("line filter", &fmesh.Config{
+		CyclesLimit: fmesh.UnlimitedCycles,
+	}).WithComponents(stdin, filter, stdout)
+
+	// The mesh runs until stdin is closed
+	if _, err := fm.Run(); err != nil {
+		fmt.Fprintln(os.Stderr, "F-Mesh returned an error:", err)
+		os.Exit(1)
+	}
+}
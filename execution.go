@@ -0,0 +1,226 @@
+package fmesh
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ExecutionStrategy defines how activations and flushes of components are spread over goroutines
+type ExecutionStrategy int
+
+const (
+	// GoroutinePerComponent starts a new goroutine for each activation and each flush
+	GoroutinePerComponent ExecutionStrategy = iota
+
+	// WorkerPool runs activations and flushes on persistent workers started once per run,
+	// each worker has its own queue of components and steals from other workers when its queue is empty,
+	// which avoids goroutine creation overhead in meshes with many tiny activation functions
+	WorkerPool
+)
+
+// executor runs a task for each given component ID and waits until all tasks are done
+type executor interface {
+	execute(ids []int, task func(id int))
+	stop()
+}
+
+// newExecutor creates the executor for the given strategy, maxConcurrent bounds the number of workers (0 means no limit)
+func newExecutor(strategy ExecutionStrategy, workers int, maxConcurrent int) executor {
+	if maxConcurrent > 0 {
+		if strategy != WorkerPool || workers <= 0 {
+			workers = maxConcurrent
+		}
+		return newWorkerPool(min(workers, maxConcurrent))
+	}
+	if strategy == WorkerPool {
+		return newWorkerPool(workers)
+	}
+	return goroutineExecutor{}
+}
+
+// goroutineExecutor starts a goroutine per task
+type goroutineExecutor struct{}
+
+func (goroutineExecutor) execute(ids []int, task func(id int)) {
+	var wg sync.WaitGroup
+	wg.Add(len(ids))
+	for _, id := range ids {
+		go func(id int) {
+			defer wg.Done()
+			task(id)
+		}(id)
+	}
+	wg.Wait()
+}
+
+func (goroutineExecutor) stop() {}
+
+// workQueue is a queue of component IDs owned by one worker, other workers steal from its tail,
+// so the owner runs its chunk in the scheduled order
+type workQueue struct {
+	mu   sync.Mutex
+	ids  []int
+	head int
+}
+
+// reset replaces the queue content
+func (q *workQueue) reset(ids []int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.ids = append(q.ids[:0], ids...)
+	q.head = 0
+}
+
+// pop takes an ID from the head (used by the owner)
+func (q *workQueue) pop() (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.head == len(q.ids) {
+		return 0, false
+	}
+	id := q.ids[q.head]
+	q.head++
+	return id, true
+}
+
+// steal takes an ID from the tail (used by other workers)
+func (q *workQueue) steal() (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.head == len(q.ids) {
+		return 0, false
+	}
+	id := q.ids[len(q.ids)-1]
+	q.ids = q.ids[:len(q.ids)-1]
+	return id, true
+}
+
+// workerPool is a pool of persistent workers with work stealing
+type workerPool struct {
+	queues []*workQueue
+	wake   []chan func(id int)
+	wg     sync.WaitGroup
+}
+
+// newWorkerPool starts the given number of workers (GOMAXPROCS when not positive)
+func newWorkerPool(workers int) *workerPool {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	pool := &workerPool{
+		queues: make([]*workQueue, workers),
+		wake:   make([]chan func(id int), workers),
+	}
+	for w := range pool.queues {
+		pool.queues[w] = &workQueue{}
+		pool.wake[w] = make(chan func(id int))
+		go pool.work(w)
+	}
+	return pool
+}
+
+// work is the loop of a single worker
+func (pool *workerPool) work(w int) {
+	for task := range pool.wake[w] {
+		for {
+			id, ok := pool.queues[w].pop()
+			if !ok {
+				id, ok = pool.stealFor(w)
+			}
+			if !ok {
+				break
+			}
+			task(id)
+		}
+		pool.wg.Done()
+	}
+}
+
+// stealFor takes an ID from the queue of any other worker
+func (pool *workerPool) stealFor(w int) (int, bool) {
+	for i := 1; i < len(pool.queues); i++ {
+		if id, ok := pool.queues[(w+i)%len(pool.queues)].steal(); ok {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// execute splits the IDs into contiguous chunks (one per worker) and wakes up all workers
+func (pool *workerPool) execute(ids []int, task func(id int)) {
+	if len(ids) == 0 {
+		return
+	}
+
+	chunkSize := (len(ids) + len(pool.queues) - 1) / len(pool.queues)
+	for w, q := range pool.queues {
+		from, to := min(w*chunkSize, len(ids)), min((w+1)*chunkSize, len(ids))
+		q.reset(ids[from:to])
+	}
+
+	pool.wg.Add(len(pool.queues))
+	for _, wake := range pool.wake {
+		wake <- task
+	}
+	pool.wg.Wait()
+}
+
+// stop terminates all workers
+func (pool *workerPool) stop() {
+	for _, wake := range pool.wake {
+		close(wake)
+	}
+}
+
+// serialChain is the task ID standing for all components pinned to serial execution
+const serialChain = -1
+
+// executeActivations runs the task for each given component ID, components pinned to serial execution are run
+// one after another (in the given order) as a single task alongside the others
+func (fm *FMesh) executeActivations(t *topology, ids []int, task func(id int)) {
+	if !t.serial {
+		fm.currentExecutor().execute(ids, task)
+		return
+	}
+
+	parallel, serial := t.arena.parallel[:0], t.arena.serial[:0]
+	for _, id := range ids {
+		if t.components[id].IsSerial() {
+			serial = append(serial, id)
+		} else {
+			parallel = append(parallel, id)
+		}
+	}
+	if len(serial) > 0 {
+		parallel = append(parallel, serialChain)
+	}
+	t.arena.parallel, t.arena.serial = parallel, serial
+
+	fm.currentExecutor().execute(parallel, func(id int) {
+		if id != serialChain {
+			task(id)
+			return
+		}
+		for _, serialID := range serial {
+			task(serialID)
+		}
+	})
+}
+
+// currentExecutor returns the executor of the current run (a goroutine per task when the mesh is not running)
+func (fm *FMesh) currentExecutor() executor {
+	if fm.executor == nil {
+		return goroutineExecutor{}
+	}
+	return fm.executor
+}
+
+// stopExecutor stops the executor of the current run
+func (fm *FMesh) stopExecutor() {
+	if fm.executor == nil {
+		return
+	}
+	fm.executor.stop()
+	fm.executor = nil
+}
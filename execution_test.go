@@ -0,0 +1,201 @@
+package fmesh
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var executionStrategies = map[string]ExecutionStrategy{
+	"goroutine per component": GoroutinePerComponent,
+	"worker pool":             WorkerPool,
+}
+
+func Test_executors(t *testing.T) {
+	executors := map[string]func() executor{
+		"goroutine per component": func() executor { return newExecutor(GoroutinePerComponent, 0, 0) },
+		"worker pool":             func() executor { return newExecutor(WorkerPool, 4, 0) },
+		"worker pool with single worker": func() executor {
+			return newExecutor(WorkerPool, 1, 0)
+		},
+	}
+
+	for name, newExec := range executors {
+		t.Run(name, func(t *testing.T) {
+			exec := newExec()
+			defer exec.stop()
+
+			// Execute several batches, each ID must be executed exactly once per batch
+			for batch := 0; batch < 3; batch++ {
+				ids := make([]int, 100)
+				for i := range ids {
+					ids[i] = i
+				}
+				counters := make([]atomic.Int32, len(ids))
+				exec.execute(ids, func(id int) {
+					counters[id].Add(1)
+				})
+				for id := range counters {
+					assert.Equal(t, int32(1), counters[id].Load(), "id %d in batch %d", id, batch)
+				}
+			}
+
+			exec.execute(nil, func(id int) {
+				t.Fatal("no tasks expected")
+			})
+		})
+	}
+}
+
+func Test_workerPool_stealing(t *testing.T) {
+	pool := newWorkerPool(2)
+	defer pool.stop()
+
+	// The first worker gets a slow task, so the rest of its chunk must be stolen by the second one
+	var executedBy [4]atomic.Int32
+	var slowDone atomic.Bool
+	pool.execute([]int{0, 1, 2, 3}, func(id int) {
+		if id == 0 {
+			time.Sleep(50 * time.Millisecond)
+			slowDone.Store(true)
+			return
+		}
+		if !slowDone.Load() {
+			executedBy[id].Store(1)
+		}
+	})
+
+	assert.True(t, slowDone.Load())
+	assert.Equal(t, int32(1), executedBy[1].Load(), "task must be stolen while the owner is busy")
+}
+
+func TestFMesh_ExecutionStrategy(t *testing.T) {
+	for name, strategy := range executionStrategies {
+		t.Run(name, func(t *testing.T) {
+			fm := getChainsMesh(10, 5, &Config{
+				ExecutionStrategy: strategy,
+				Workers:           3,
+			})
+			cycles, err := fm.Run()
+			assert.NoError(t, err)
+			assert.Len(t, cycles, 6)
+			for i := 0; i < 10; i++ {
+				payloads, err := fm.ComponentByName(fmt.Sprintf("chain-%d-4", i)).OutputByName("out").AllSignalsPayloads()
+				assert.NoError(t, err)
+				assert.Equal(t, []any{i}, payloads)
+			}
+			assert.Nil(t, fm.executor, "executor must be stopped after the run")
+		})
+	}
+}
+
+// getChainsMesh returns a mesh of independent chains of tiny forwarding components,
+// the first component of each chain has an input signal
+func getChainsMesh(chainsCount int, chainLength int, config *Config) *FMesh {
+	forward := func(this *component.Component) error {
+		return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+	}
+
+	fm := NewWithConfig("chains", config)
+	for i := 0; i < chainsCount; i++ {
+		var prev *component.Component
+		for j := 0; j < chainLength; j++ {
+			c := component.New(fmt.Sprintf("chain-%d-%d", i, j)).WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+			if prev != nil {
+				prev.OutputByName("out").PipeTo(c.InputByName("in"))
+			} else {
+				c.InputByName("in").PutSignals(signal.New(i))
+			}
+			fm.WithComponents(c)
+			prev = c
+		}
+	}
+	return fm
+}
+
+func BenchmarkFMesh_Run_ExecutionStrategies(b *testing.B) {
+	for name, strategy := range executionStrategies {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				fm := getChainsMesh(1000, 5, &Config{
+					CyclesLimit:       UnlimitedCycles,
+					ExecutionStrategy: strategy,
+				})
+				b.StartTimer()
+
+				if _, err := fm.Run(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestFMesh_MaxConcurrentActivations(t *testing.T) {
+	for _, engine := range []Engine{CycleEngine, EventEngine} {
+		var running, peak, serialRunning, serialOverlaps atomic.Int64
+		newWorker := func(name string) *component.Component {
+			return component.New(name).WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				if this.IsSerial() {
+					if serialRunning.Add(1) > 1 {
+						serialOverlaps.Add(1)
+					}
+					defer serialRunning.Add(-1)
+				}
+				time.Sleep(time.Millisecond)
+				return nil
+			})
+		}
+
+		var components []*component.Component
+		for i := 0; i < 20; i++ {
+			c := newWorker(fmt.Sprintf("c-%d", i))
+			if i%4 == 0 {
+				c.WithSerialExecution()
+			}
+			components = append(components, c)
+		}
+		fm := NewWithConfig("fm", &Config{
+			Engine:                   engine,
+			MaxConcurrentActivations: 3,
+		}).WithComponents(components...)
+		for _, c := range components {
+			c.InputByName("in").PutSignals(signal.New(1))
+		}
+
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, peak.Load(), int64(3), "engine %d", engine)
+		assert.Zero(t, serialOverlaps.Load(), "engine %d", engine)
+	}
+
+	t.Run("limit bounds workers of the pool", func(t *testing.T) {
+		for _, tt := range []struct {
+			strategy      ExecutionStrategy
+			workers, want int
+		}{
+			{strategy: GoroutinePerComponent, want: 2},
+			{strategy: WorkerPool, workers: 8, want: 2},
+			{strategy: WorkerPool, workers: 1, want: 1},
+		} {
+			exec := newExecutor(tt.strategy, tt.workers, 2)
+			assert.Len(t, exec.(*workerPool).queues, tt.want)
+			exec.stop()
+		}
+	})
+}
@@ -0,0 +1,435 @@
+// Package checkpoint saves the full state of a mesh (topology, labels, component states and signals buffered on ports)
+// and restores it into a new mesh, e.g. to resume a long computation after a restart or to inspect it with tooling.
+// Behavior can not be saved, so components are recreated by factories from Registry and the saved state is put on top of them
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"sort"
+	"sync"
+)
+
+// TypeLabel holds the type of a component, which is resolved to a factory on restore (the name of the component is used when it is not set)
+const TypeLabel = "checkpoint/type"
+
+var (
+	ErrUnknownType       = errors.New("no factory registered for component type")
+	ErrTypeRegistered    = errors.New("factory is already registered for component type")
+	ErrInvalidCheckpoint = errors.New("invalid checkpoint")
+	ErrFactoryFailed     = errors.New("factory returned no component")
+)
+
+// Checkpoint is the saved mesh
+type Checkpoint struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Components  []Component       `json:"components"`
+	Pipes       []Pipe            `json:"pipes"`
+}
+
+// Component is a saved component
+type Component struct {
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Description string            `json:"description,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	// StateVersion is the version of the state layout, so the state can be migrated on restore (see component.RestoreState)
+	StateVersion int               `json:"stateVersion,omitempty"`
+	State        map[string][]byte `json:"state,omitempty"`
+	Inputs       []Port            `json:"inputs"`
+	Outputs      []Port            `json:"outputs"`
+}
+
+// Port is a saved port with its buffered signals
+type Port struct {
+	Name    string            `json:"name"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Signals []Signal          `json:"signals,omitempty"`
+}
+
+// Signal is a saved signal, signals carrying errors keep only the error message
+type Signal struct {
+	Payload []byte            `json:"payload,omitempty"`
+	Error   string            `json:"error,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// Pipe is a saved pipe
+type Pipe struct {
+	From     string `json:"from"`
+	FromPort string `json:"fromPort"`
+	To       string `json:"to"`
+	ToPort   string `json:"toPort"`
+}
+
+// Factory creates a component with its behavior, ports declared in the checkpoint and missing on the component are added on restore
+type Factory func(name string) *component.Component
+
+// Registry maps component types to factories
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: make(map[string]Factory),
+	}
+}
+
+// Register makes the factory available for the component type
+func (r *Registry) Register(componentType string, factory Factory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.factories[componentType]; ok {
+		return fmt.Errorf("%w: %s", ErrTypeRegistered, componentType)
+	}
+	r.factories[componentType] = factory
+	return nil
+}
+
+// factory returns the factory of the component type
+func (r *Registry) factory(componentType string) (Factory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	factory, ok := r.factories[componentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownType, componentType)
+	}
+	return factory, nil
+}
+
+// Marshal saves the mesh as JSON, payloads and state values are encoded with signal.JSONCodec
+func Marshal(fm *fmesh.FMesh) ([]byte, error) {
+	checkpoint, err := FromMesh(fm, signal.JSONCodec{})
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(checkpoint, "", "  ")
+}
+
+// Unmarshal restores the mesh saved by Marshal, decoded payloads and state values have generic JSON types (see signal.JSONCodec)
+func Unmarshal(data []byte, registry *Registry) (*fmesh.FMesh, error) {
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, errors.Join(ErrInvalidCheckpoint, err)
+	}
+	return checkpoint.Restore(registry, signal.JSONCodec{})
+}
+
+// FromMesh saves the mesh, payloads and state values are encoded with the codec.
+// Components, ports and pipes are ordered by names, signals keep their order
+func FromMesh(fm *fmesh.FMesh, codec signal.Codec) (*Checkpoint, error) {
+	components, err := fm.Components().Components()
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := &Checkpoint{
+		Name:        fm.Name(),
+		Description: fm.Description(),
+		Labels:      labels(fm.Labels()),
+		Components:  make([]Component, 0, len(components)),
+		Pipes:       make([]Pipe, 0),
+	}
+
+	owners := make(map[*port.Port]string)
+	for name, c := range components {
+		for _, p := range c.Inputs().PortsOrNil() {
+			owners[p] = name
+		}
+	}
+
+	for _, c := range sortedComponents(components) {
+		saved, err := saveComponent(c, codec)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", c.Name(), err)
+		}
+		checkpoint.Components = append(checkpoint.Components, saved)
+
+		for _, out := range saved.Outputs {
+			for _, dest := range c.OutputByName(out.Name).Pipes().PortsOrNil() {
+				owner, ok := owners[dest]
+				if !ok {
+					return nil, fmt.Errorf("%w: pipe from %s.%s goes to port %s outside of the mesh", ErrInvalidCheckpoint, c.Name(), out.Name, dest.Name())
+				}
+				checkpoint.Pipes = append(checkpoint.Pipes, Pipe{
+					From:     c.Name(),
+					FromPort: out.Name,
+					To:       owner,
+					ToPort:   dest.Name(),
+				})
+			}
+		}
+	}
+
+	sort.Slice(checkpoint.Pipes, func(i, j int) bool {
+		a, b := checkpoint.Pipes[i], checkpoint.Pipes[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.FromPort != b.FromPort {
+			return a.FromPort < b.FromPort
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.ToPort < b.ToPort
+	})
+	return checkpoint, nil
+}
+
+// saveComponent saves the component
+func saveComponent(c *component.Component, codec signal.Codec) (Component, error) {
+	componentLabels := labels(c.Labels())
+	delete(componentLabels, TypeLabel)
+	saved := Component{
+		Name:         c.Name(),
+		Type:         c.LabelOrDefault(TypeLabel, c.Name()),
+		Description:  c.Description(),
+		Labels:       componentLabels,
+		StateVersion: c.StateVersion(),
+	}
+
+	if state := c.State(); len(state) > 0 {
+		saved.State = make(map[string][]byte, len(state))
+		for key, value := range state {
+			data, err := codec.Encode(value)
+			if err != nil {
+				return Component{}, fmt.Errorf("state key %s: %w", key, err)
+			}
+			saved.State[key] = data
+		}
+	}
+
+	var err error
+	if saved.Inputs, err = savePorts(c.Inputs().PortsOrNil(), codec); err != nil {
+		return Component{}, err
+	}
+	if saved.Outputs, err = savePorts(c.Outputs().PortsOrNil(), codec); err != nil {
+		return Component{}, err
+	}
+	return saved, nil
+}
+
+// savePorts saves ports sorted by name
+func savePorts(ports port.PortMap, codec signal.Codec) ([]Port, error) {
+	names := make([]string, 0, len(ports))
+	for name := range ports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	saved := make([]Port, 0, len(names))
+	for _, name := range names {
+		p := ports[name]
+		portLabels := labels(p.Labels())
+		// The direction is implied by the collection the port belongs to
+		delete(portLabels, port.DirectionLabel)
+		savedPort := Port{
+			Name:   name,
+			Labels: portLabels,
+		}
+
+		for _, sig := range p.AllSignalsOrNil() {
+			savedSignal := Signal{
+				Labels: labels(sig.Labels()),
+			}
+			if err := sig.ErrorOrNil(); err != nil {
+				savedSignal.Error = err.Error()
+			} else {
+				data, err := codec.Encode(sig.PayloadOrNil())
+				if err != nil {
+					return nil, fmt.Errorf("port %s: %w", name, err)
+				}
+				savedSignal.Payload = data
+			}
+			savedPort.Signals = append(savedPort.Signals, savedSignal)
+		}
+		saved = append(saved, savedPort)
+	}
+	return saved, nil
+}
+
+// Restore creates the mesh from the checkpoint, payloads and state values are decoded with the codec.
+// All invalid components and pipes are reported at once
+func (cp *Checkpoint) Restore(registry *Registry, codec signal.Codec) (*fmesh.FMesh, error) {
+	var errs []error
+	components := make(map[string]*component.Component, len(cp.Components))
+	for _, saved := range cp.Components {
+		if _, ok := components[saved.Name]; ok {
+			errs = append(errs, fmt.Errorf("component %s: duplicate name", saved.Name))
+			continue
+		}
+
+		c, err := restoreComponent(saved, registry, codec)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("component %s: %w", saved.Name, err))
+			continue
+		}
+		components[saved.Name] = c
+	}
+
+	for _, pipe := range cp.Pipes {
+		if err := restorePipe(components, pipe); err != nil {
+			errs = append(errs, fmt.Errorf("pipe %s.%s -> %s.%s: %w", pipe.From, pipe.FromPort, pipe.To, pipe.ToPort, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(append([]error{ErrInvalidCheckpoint}, errs...)...)
+	}
+
+	fm := fmesh.New(cp.Name).WithDescription(cp.Description).WithLabels(cp.Labels)
+	for _, saved := range cp.Components {
+		fm.WithComponents(components[saved.Name])
+	}
+	if fm.HasErr() {
+		return nil, fm.Err()
+	}
+	return fm, nil
+}
+
+// restoreComponent creates the component and puts the saved state on top of it
+func restoreComponent(saved Component, registry *Registry, codec signal.Codec) (*component.Component, error) {
+	factory, err := registry.factory(saved.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	c := factory(saved.Name)
+	if c == nil {
+		return nil, ErrFactoryFailed
+	}
+	if saved.Description != "" {
+		c.WithDescription(saved.Description)
+	}
+	c.WithInputs(missing(c.Inputs().PortsOrNil(), saved.Inputs)...).
+		WithOutputs(missing(c.Outputs().PortsOrNil(), saved.Outputs)...)
+	if c.HasErr() {
+		return nil, c.Err()
+	}
+
+	// Labels are added as they were, including the ones set by f-mesh (e.g. component.DegradedLabel)
+	c.AddLabels(saved.Labels)
+	if saved.Type != saved.Name {
+		c.AddLabel(TypeLabel, saved.Type)
+	}
+
+	state := component.NewState()
+	for key, data := range saved.State {
+		value, err := codec.Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("state key %s: %w", key, err)
+		}
+		state.Set(key, value)
+	}
+	if err := c.RestoreState(state, saved.StateVersion); err != nil {
+		return nil, err
+	}
+
+	if err := restorePorts(c.Inputs(), saved.Inputs, codec); err != nil {
+		return nil, err
+	}
+	if err := restorePorts(c.Outputs(), saved.Outputs, codec); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// restorePorts puts saved labels and signals on the ports
+func restorePorts(collection *port.Collection, saved []Port, codec signal.Codec) error {
+	for _, savedPort := range saved {
+		p, err := collection.ByNameE(savedPort.Name)
+		if err != nil {
+			return err
+		}
+		p.AddLabels(savedPort.Labels)
+
+		signals := make(signal.Signals, 0, len(savedPort.Signals))
+		for _, savedSignal := range savedPort.Signals {
+			var payload any
+			if savedSignal.Error != "" {
+				payload = errors.New(savedSignal.Error)
+			} else if payload, err = codec.Decode(savedSignal.Payload); err != nil {
+				return fmt.Errorf("port %s: %w", savedPort.Name, err)
+			}
+			signals = append(signals, signal.New(payload).WithLabels(savedSignal.Labels))
+		}
+		if len(signals) > 0 && p.PutSignals(signals...).HasErr() {
+			return p.Err()
+		}
+	}
+	return nil
+}
+
+// restorePipe creates the saved pipe
+func restorePipe(components map[string]*component.Component, pipe Pipe) error {
+	from, ok := components[pipe.From]
+	if !ok {
+		return fmt.Errorf("%w: component %s not found", ErrInvalidCheckpoint, pipe.From)
+	}
+	to, ok := components[pipe.To]
+	if !ok {
+		return fmt.Errorf("%w: component %s not found", ErrInvalidCheckpoint, pipe.To)
+	}
+
+	out, err := from.OutputByNameE(pipe.FromPort)
+	if err != nil {
+		return err
+	}
+	in, err := to.InputByNameE(pipe.ToPort)
+	if err != nil {
+		return err
+	}
+	if out.PipeTo(in).HasErr() {
+		return out.Err()
+	}
+	return nil
+}
+
+// missing returns names of saved ports which are not declared yet
+func missing(declared port.PortMap, saved []Port) []string {
+	var result []string
+	for _, p := range saved {
+		if _, ok := declared[p.Name]; !ok {
+			result = append(result, p.Name)
+		}
+	}
+	return result
+}
+
+// labels returns a copy of the labels (nil when there are none)
+func labels(collection common.LabelsCollection) map[string]string {
+	if len(collection) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(collection))
+	for key, value := range collection {
+		result[key] = value
+	}
+	return result
+}
+
+// sortedComponents returns components sorted by name
+func sortedComponents(components component.ComponentsMap) []*component.Component {
+	sorted := make([]*component.Component, 0, len(components))
+	for _, c := range components {
+		sorted = append(sorted, c)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name() < sorted[j].Name()
+	})
+	return sorted
+}
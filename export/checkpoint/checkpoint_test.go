@@ -0,0 +1,109 @@
+package checkpoint
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func newCounter(name string) *component.Component {
+	return component.New(name).
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			total := this.State().GetOrDefault("total", 0.0).(float64)
+			for _, sig := range this.InputByName("in").AllSignalsOrNil() {
+				total += sig.PayloadOrNil().(float64)
+			}
+			this.State().Set("total", total)
+			this.OutputByName("out").PutSignals(signal.New(total))
+			return nil
+		})
+}
+
+func newCollector(name string) *component.Component {
+	return component.New(name).
+		WithInputs("in").
+		WithActivationFunc(func(this *component.Component) error {
+			return nil
+		})
+}
+
+func newRegistry(t *testing.T) *Registry {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register("counter", newCounter))
+	require.NoError(t, registry.Register("collector", newCollector))
+	return registry
+}
+
+func TestCheckpoint_RoundTrip(t *testing.T) {
+	counter := newCounter("sum").WithDescription("sums numbers")
+	counter.AddLabel(TypeLabel, "counter")
+	counter.AddLabel("tier", "math")
+	counter.State().Set("total", 10.0)
+	counter.InputByName("in").PutSignals(
+		signal.New(1.0).WithLabels(common.LabelsCollection{"batch": "a"}),
+		signal.New(2.0),
+	)
+	collector := newCollector("collector")
+	collector.InputByName("in").PutSignals(signal.New(errors.New("lost")))
+	counter.OutputByName("out").PipeTo(collector.InputByName("in"))
+
+	fm := fmesh.New("totals").WithLabels(common.LabelsCollection{"env": "test"}).WithComponents(counter, collector)
+
+	data, err := Marshal(fm)
+	require.NoError(t, err)
+
+	restored, err := Unmarshal(data, newRegistry(t))
+	require.NoError(t, err)
+
+	restoredData, err := Marshal(restored)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(restoredData))
+
+	restoredCounter := restored.MustComponentByName("sum")
+	assert.Equal(t, "sums numbers", restoredCounter.Description())
+	assert.Equal(t, "math", restoredCounter.LabelOrDefault("tier", ""))
+	assert.Equal(t, "a", restoredCounter.InputByName("in").AllSignalsOrNil()[0].LabelOrDefault("batch", ""))
+	assert.EqualError(t, restored.MustComponentByName("collector").InputByName("in").AllSignalsOrNil()[0].ErrorOrNil(), "lost")
+
+	// The restored mesh resumes from the saved state
+	_, err = restored.Run()
+	require.NoError(t, err)
+	assert.InEpsilon(t, 13.0, restoredCounter.State().Get("total"), 1e-9)
+}
+
+func TestCheckpoint_Restore(t *testing.T) {
+	t.Run("all errors are reported", func(t *testing.T) {
+		checkpoint := &Checkpoint{
+			Name: "broken",
+			Components: []Component{
+				{Name: "a", Type: "counter", Outputs: []Port{{Name: "out"}}},
+				{Name: "b", Type: "unknown"},
+			},
+			Pipes: []Pipe{
+				{From: "a", FromPort: "out", To: "c", ToPort: "in"},
+			},
+		}
+
+		fm, err := checkpoint.Restore(newRegistry(t), signal.JSONCodec{})
+		assert.Nil(t, fm)
+		require.ErrorIs(t, err, ErrInvalidCheckpoint)
+		require.ErrorIs(t, err, ErrUnknownType)
+		assert.ErrorContains(t, err, "component c not found")
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := Unmarshal([]byte("{"), newRegistry(t))
+		assert.ErrorIs(t, err, ErrInvalidCheckpoint)
+	})
+
+	t.Run("duplicate factory", func(t *testing.T) {
+		assert.ErrorIs(t, newRegistry(t).Register("counter", newCounter), ErrTypeRegistered)
+	})
+}
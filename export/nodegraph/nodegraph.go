@@ -0,0 +1,345 @@
+// Package nodegraph converts meshes to and from the JSON interchange format of node-graph editors
+// (nodes with typed ports, edges between port handles, positions and parameters, as used by React Flow, Rete.js and alike),
+// so topologies can be drawn visually and materialized into a mesh, and vice versa.
+// Behavior can not be drawn, so each node has a type which is resolved to a component constructor via Registry
+package nodegraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+const (
+	// TypeLabel holds the node type of a component, it is set on import and used on export
+	TypeLabel = "nodegraph/type"
+	// XLabel and YLabel hold the position of the node in the editor, so it survives a round trip
+	XLabel = "nodegraph/x"
+	YLabel = "nodegraph/y"
+)
+
+var (
+	ErrUnknownNodeType   = errors.New("no constructor registered for node type")
+	ErrTypeRegistered    = errors.New("constructor is already registered for node type")
+	ErrInvalidGraph      = errors.New("invalid graph")
+	ErrNodeNotFound      = errors.New("node not found")
+	ErrHandleNotFound    = errors.New("port handle not found")
+	ErrConstructorFailed = errors.New("constructor returned no component")
+)
+
+// Graph is the interchange document
+type Graph struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Nodes       []Node            `json:"nodes"`
+	Edges       []Edge            `json:"edges"`
+}
+
+// Node is a component
+type Node struct {
+	// ID is the component name
+	ID          string            `json:"id"`
+	Type        string            `json:"type"`
+	Position    Position          `json:"position"`
+	Description string            `json:"description,omitempty"`
+	Inputs      []string          `json:"inputs"`
+	Outputs     []string          `json:"outputs"`
+	Params      map[string]any    `json:"params,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// Position is the position of a node in the editor
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Edge is a pipe from an output port (source handle) to an input port (target handle)
+type Edge struct {
+	ID           string `json:"id"`
+	Source       string `json:"source"`
+	SourceHandle string `json:"sourceHandle"`
+	Target       string `json:"target"`
+	TargetHandle string `json:"targetHandle"`
+}
+
+// Constructor creates a component of a node type, params are the parameters of the node (nil when there are none,
+// they become the component config unless the constructor sets one).
+// Ports declared by the node and missing on the component are added on import, so constructors may declare no ports
+type Constructor func(name string, params map[string]any) *component.Component
+
+// Registry maps node types to component constructors
+type Registry struct {
+	mu           sync.RWMutex
+	constructors map[string]Constructor
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		constructors: make(map[string]Constructor),
+	}
+}
+
+// Register makes the constructor available for the node type
+func (r *Registry) Register(nodeType string, constructor Constructor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.constructors[nodeType]; ok {
+		return fmt.Errorf("%w: %s", ErrTypeRegistered, nodeType)
+	}
+	r.constructors[nodeType] = constructor
+	return nil
+}
+
+// constructor returns the constructor of the node type
+func (r *Registry) constructor(nodeType string) (Constructor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	constructor, ok := r.constructors[nodeType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownNodeType, nodeType)
+	}
+	return constructor, nil
+}
+
+// Export returns the mesh in the interchange format
+func Export(fm *fmesh.FMesh) ([]byte, error) {
+	graph, err := FromMesh(fm)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(graph, "", "  ")
+}
+
+// Import builds the mesh from the interchange format, node types are resolved via the registry
+func Import(data []byte, registry *Registry) (*fmesh.FMesh, error) {
+	var graph Graph
+	if err := json.Unmarshal(data, &graph); err != nil {
+		return nil, errors.Join(ErrInvalidGraph, err)
+	}
+	return graph.Build(registry)
+}
+
+// FromMesh returns the graph of the mesh, nodes, ports and edges are ordered by names.
+// Parameters are exported when the component config (see component.WithConfig) is a map[string]any
+func FromMesh(fm *fmesh.FMesh) (*Graph, error) {
+	components, err := fm.Components().Components()
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &Graph{
+		Name:        fm.Name(),
+		Description: fm.Description(),
+		Labels:      userLabels(fm.Labels()),
+		Nodes:       make([]Node, 0, len(components)),
+		Edges:       make([]Edge, 0),
+	}
+
+	owners := make(map[*port.Port]string)
+	for name, c := range components {
+		for _, p := range c.Inputs().PortsOrNil() {
+			owners[p] = name
+		}
+	}
+
+	for _, c := range sortedComponents(components) {
+		node := Node{
+			ID:          c.Name(),
+			Type:        c.LabelOrDefault(TypeLabel, ""),
+			Position:    position(c),
+			Description: c.Description(),
+			Inputs:      sortedNames(c.Inputs().PortsOrNil()),
+			Outputs:     sortedNames(c.Outputs().PortsOrNil()),
+			Labels:      userLabels(c.Labels()),
+		}
+		if params, ok := c.Config().(map[string]any); ok {
+			node.Params = params
+		}
+		graph.Nodes = append(graph.Nodes, node)
+
+		for _, outName := range node.Outputs {
+			out := c.OutputByName(outName)
+			for _, dest := range out.Pipes().PortsOrNil() {
+				target, ok := owners[dest]
+				if !ok {
+					return nil, fmt.Errorf("%w: pipe from %s.%s goes to port %s outside of the mesh", ErrInvalidGraph, c.Name(), outName, dest.Name())
+				}
+				graph.Edges = append(graph.Edges, Edge{
+					ID:           fmt.Sprintf("%s.%s->%s.%s", c.Name(), outName, target, dest.Name()),
+					Source:       c.Name(),
+					SourceHandle: outName,
+					Target:       target,
+					TargetHandle: dest.Name(),
+				})
+			}
+		}
+	}
+
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		return graph.Edges[i].ID < graph.Edges[j].ID
+	})
+	return graph, nil
+}
+
+// Build creates the mesh from the graph, all invalid nodes and edges are reported at once
+func (g *Graph) Build(registry *Registry) (*fmesh.FMesh, error) {
+	var errs []error
+	components := make(map[string]*component.Component, len(g.Nodes))
+	for _, node := range g.Nodes {
+		if _, ok := components[node.ID]; ok {
+			errs = append(errs, fmt.Errorf("node %s: duplicate id", node.ID))
+			continue
+		}
+
+		c, err := buildComponent(node, registry)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("node %s: %w", node.ID, err))
+			continue
+		}
+		components[node.ID] = c
+	}
+
+	for _, edge := range g.Edges {
+		if err := connect(components, edge); err != nil {
+			errs = append(errs, fmt.Errorf("edge %s: %w", edge.ID, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(append([]error{ErrInvalidGraph}, errs...)...)
+	}
+
+	fm := fmesh.New(g.Name).WithDescription(g.Description).WithLabels(g.Labels)
+	for _, node := range g.Nodes {
+		fm.WithComponents(components[node.ID])
+	}
+	if fm.HasErr() {
+		return nil, fm.Err()
+	}
+	return fm, nil
+}
+
+// buildComponent creates the component of the node
+func buildComponent(node Node, registry *Registry) (*component.Component, error) {
+	constructor, err := registry.constructor(node.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	c := constructor(node.ID, node.Params)
+	if c == nil {
+		return nil, ErrConstructorFailed
+	}
+	if node.Description != "" {
+		c.WithDescription(node.Description)
+	}
+	if node.Params != nil && !c.HasConfig() {
+		// Params are kept, so they are exported back
+		c.WithConfig(node.Params)
+	}
+	c.WithInputs(missing(c.Inputs().PortsOrNil(), node.Inputs)...).
+		WithOutputs(missing(c.Outputs().PortsOrNil(), node.Outputs)...)
+	if c.HasErr() {
+		return nil, c.Err()
+	}
+
+	c.WithLabels(node.Labels)
+	c.AddLabels(common.LabelsCollection{
+		TypeLabel: node.Type,
+		XLabel:    strconv.FormatFloat(node.Position.X, 'f', -1, 64),
+		YLabel:    strconv.FormatFloat(node.Position.Y, 'f', -1, 64),
+	})
+	return c, nil
+}
+
+// connect creates the pipe of the edge
+func connect(components map[string]*component.Component, edge Edge) error {
+	source, ok := components[edge.Source]
+	if !ok {
+		return fmt.Errorf("%w: source %s", ErrNodeNotFound, edge.Source)
+	}
+	target, ok := components[edge.Target]
+	if !ok {
+		return fmt.Errorf("%w: target %s", ErrNodeNotFound, edge.Target)
+	}
+
+	out, err := source.OutputByNameE(edge.SourceHandle)
+	if err != nil {
+		return errors.Join(ErrHandleNotFound, err)
+	}
+	in, err := target.InputByNameE(edge.TargetHandle)
+	if err != nil {
+		return errors.Join(ErrHandleNotFound, err)
+	}
+	if out.PipeTo(in).HasErr() {
+		return out.Err()
+	}
+	return nil
+}
+
+// missing returns names of ports which are not declared yet
+func missing(declared port.PortMap, names []string) []string {
+	var result []string
+	for _, name := range names {
+		if _, ok := declared[name]; !ok {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// position returns the position of the node from the labels of the component
+func position(c *component.Component) Position {
+	x, _ := strconv.ParseFloat(c.LabelOrDefault(XLabel, "0"), 64)
+	y, _ := strconv.ParseFloat(c.LabelOrDefault(YLabel, "0"), 64)
+	return Position{X: x, Y: y}
+}
+
+// userLabels returns labels without the ones of this package and system ones (nil when nothing is left)
+func userLabels(labels common.LabelsCollection) map[string]string {
+	var result map[string]string
+	for key, value := range labels {
+		if key == TypeLabel || key == XLabel || key == YLabel || common.IsSystemLabel(key) {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// sortedComponents returns components sorted by name
+func sortedComponents(components component.ComponentsMap) []*component.Component {
+	sorted := make([]*component.Component, 0, len(components))
+	for _, c := range components {
+		sorted = append(sorted, c)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name() < sorted[j].Name()
+	})
+	return sorted
+}
+
+// sortedNames returns port names sorted
+func sortedNames(ports port.PortMap) []string {
+	names := make([]string, 0, len(ports))
+	for name := range ports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
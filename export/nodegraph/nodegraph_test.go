@@ -0,0 +1,124 @@
+package nodegraph
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const document = `{
+  "name": "pipeline",
+  "description": "scales numbers",
+  "nodes": [
+    {
+      "id": "printer",
+      "type": "sink",
+      "position": {
+        "x": 300,
+        "y": 40.5
+      },
+      "inputs": [
+        "in"
+      ],
+      "outputs": [
+        "out"
+      ]
+    },
+    {
+      "id": "scale",
+      "type": "multiplier",
+      "position": {
+        "x": 100,
+        "y": 40
+      },
+      "description": "multiplies by factor",
+      "inputs": [
+        "in"
+      ],
+      "outputs": [
+        "out"
+      ],
+      "params": {
+        "factor": 3
+      },
+      "labels": {
+        "tier": "math"
+      }
+    }
+  ],
+  "edges": [
+    {
+      "id": "scale.out->printer.in",
+      "source": "scale",
+      "sourceHandle": "out",
+      "target": "printer",
+      "targetHandle": "in"
+    }
+  ]
+}`
+
+func newRegistry(t *testing.T) *Registry {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register("multiplier", func(name string, params map[string]any) *component.Component {
+		factor := params["factor"].(float64)
+		return component.New(name).WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("in").AllSignalsOrNil() {
+				this.OutputByName("out").PutSignals(signal.New(sig.PayloadOrNil().(int) * int(factor)))
+			}
+			return nil
+		})
+	}))
+	require.NoError(t, registry.Register("sink", func(name string, params map[string]any) *component.Component {
+		// Ports are declared by the node
+		return component.New(name).WithActivationFunc(func(this *component.Component) error {
+			return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+		})
+	}))
+	return registry
+}
+
+func TestImportExport(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		fm, err := Import([]byte(document), newRegistry(t))
+		require.NoError(t, err)
+
+		fm.ComponentByName("scale").InputByName("in").PutSignals(signal.New(2))
+		_, err = fm.Run()
+		require.NoError(t, err)
+		payloads, err := fm.ComponentByName("printer").OutputByName("out").AllSignalsPayloads()
+		require.NoError(t, err)
+		assert.Equal(t, []any{6}, payloads)
+
+		exported, err := Export(fm)
+		require.NoError(t, err)
+		assert.JSONEq(t, document, string(exported))
+	})
+
+	t.Run("all problems are reported", func(t *testing.T) {
+		_, err := Import([]byte(`{
+			"name": "broken",
+			"nodes": [
+				{"id": "a", "type": "sink", "inputs": ["in"], "outputs": ["out"]},
+				{"id": "b", "type": "unknown"}
+			],
+			"edges": [
+				{"id": "e1", "source": "a", "sourceHandle": "typo", "target": "a", "targetHandle": "in"},
+				{"id": "e2", "source": "a", "sourceHandle": "out", "target": "c", "targetHandle": "in"}
+			]
+		}`), newRegistry(t))
+		assert.ErrorIs(t, err, ErrInvalidGraph)
+		assert.ErrorIs(t, err, ErrUnknownNodeType)
+		assert.ErrorIs(t, err, ErrHandleNotFound)
+		assert.ErrorIs(t, err, ErrNodeNotFound)
+		assert.ErrorContains(t, err, "node b: no constructor registered for node type: \"unknown\"")
+		assert.ErrorContains(t, err, "edge e2: node not found: target c")
+	})
+
+	t.Run("type registered twice", func(t *testing.T) {
+		registry := newRegistry(t)
+		assert.ErrorIs(t, registry.Register("sink", nil), ErrTypeRegistered)
+	})
+}
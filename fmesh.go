@@ -1,22 +1,49 @@
 package fmesh
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/hovsep/fmesh/clock"
 	"github.com/hovsep/fmesh/common"
 	"github.com/hovsep/fmesh/component"
 	"github.com/hovsep/fmesh/cycle"
+	"log/slog"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 )
 
 // FMesh is the functional mesh
 type FMesh struct {
 	common.NamedEntity
 	common.DescribedEntity
+	common.LabeledEntity
 	*common.Chainable
-	components *component.Collection
-	cycles     *cycle.Group
-	config     *Config
+	components  *component.Collection
+	cycles      *cycle.Group
+	config      *Config
+	runtimeInfo *RuntimeInfo
+	topology    *topology
+	executor    executor
+	injections  injectionQueue
+	stop        stopRequest
+	pause       pauseControl
+	// debugger is created on first use (see Debugger)
+	debugger     *Debugger
+	debuggerOnce sync.Once
+	plugins      plugins
+	hooks        hooks
+	deadLetters  DeadLetters
+	// slog is the structured logger created from Config.Slog on first use, currentCycle is the number of the cycle it reports
+	slog         *slog.Logger
+	currentCycle atomic.Int64
+	// duplicateNames are names of components replaced by other components with the same name (reported by Validate)
+	duplicateNames []string
+	// continuous is set during RunContinuously
+	continuous bool
+	// stateModifiedAt maps component names to the latest cycle their state was modified in (see StateStats)
+	stateModifiedAt map[string]int
 }
 
 // New creates a new f-mesh with default config
@@ -49,6 +76,40 @@ func (fm *FMesh) ComponentByName(name string) *component.Component {
 	return fm.Components().ByName(name)
 }
 
+// ComponentByNameE returns the component by name, unlike ComponentByName it returns the error instead of setting it on the components collection
+func (fm *FMesh) ComponentByNameE(name string) (*component.Component, error) {
+	c, err := fm.Components().ByNameE(name)
+	if err != nil {
+		return nil, fmt.Errorf("f-mesh %s: %w", fm.Name(), err)
+	}
+	return c, nil
+}
+
+// MustComponentByName returns the component by name and panics when there is none,
+// it is meant for wiring code where a missing component is a programming error
+func (fm *FMesh) MustComponentByName(name string) *component.Component {
+	c, err := fm.ComponentByNameE(name)
+	if err != nil {
+		panic(fmt.Sprintf("component lookup failed: %v", err))
+	}
+	return c
+}
+
+// Clock returns the clock used by the mesh (wall clock unless configured otherwise)
+func (fm *FMesh) Clock() clock.Clock {
+	if fm.config.Clock == nil {
+		return clock.Real()
+	}
+	return fm.config.Clock
+}
+
+// tickClock advances the simulation clock (if the mesh uses one) after a cycle is completed
+func (fm *FMesh) tickClock() {
+	if sim, ok := fm.config.Clock.(*clock.Simulation); ok {
+		sim.Tick()
+	}
+}
+
 // WithDescription sets a description
 func (fm *FMesh) WithDescription(description string) *FMesh {
 	if fm.HasErr() {
@@ -65,22 +126,59 @@ func (fm *FMesh) WithComponents(components ...*component.Component) *FMesh {
 		return fm
 	}
 
+	var errs []error
 	for _, c := range components {
-		fm.components = fm.components.With(c.WithLogger(fm.Logger()))
 		if c.HasErr() {
-			return fm.WithErr(c.Err())
+			// All broken components are reported at once
+			errs = append(errs, &ConstructionError{Component: c.Name(), Err: c.Err()})
+			continue
+		}
+		if existing, ok := fm.components.ComponentsOrNil()[c.Name()]; ok && existing != c {
+			fm.duplicateNames = append(fm.duplicateNames, c.Name())
+		}
+		// The seed is drawn even for components having their own generator, so seeds of others do not depend on it
+		if seed := fm.newRandSeed(); !c.HasRand() {
+			c.WithRandSeed(seed)
 		}
+		fm.components = fm.components.With(c.WithLogger(fm.Logger()).WithSlog(fm.Slog()).WithClock(fm.Clock()))
+		if fm.config.TransactionalState {
+			c.WithStateRollback()
+		}
+		if fm.config.AutoPorts {
+			c.WithAutoPorts()
+		}
+		if len(fm.plugins.labelChangeListeners) > 0 {
+			fm.watchLabels(c)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fm.WithErr(errors.Join(errs...))
 	}
 
+	// Topology must be recompiled as components changed
+	fm.topology = nil
+
 	fm.LogDebug(fmt.Sprintf("%d components added to mesh", fm.Components().Len()))
+	fm.notifyComponentAdded(components)
 	return fm
 }
 
+// newRandSeed returns a random generator seed for a component, drawn from the mesh random source (if set)
+func (fm *FMesh) newRandSeed() int64 {
+	if fm.config.RandSource == nil {
+		return rand.Int63()
+	}
+	return fm.config.RandSource.Int63()
+}
+
 // runCycle runs one activation cycle (tries to activate ready components)
 func (fm *FMesh) runCycle() {
 	newCycle := cycle.New().WithNumber(fm.cycles.Len() + 1)
 
 	fm.LogDebug(fmt.Sprintf("starting activation cycle #%d", newCycle.Number()))
+	fm.currentCycle.Store(int64(newCycle.Number()))
+	fm.notifyCycleStart(newCycle.Number())
 
 	if fm.HasErr() {
 		newCycle.SetErr(fm.Err())
@@ -90,29 +188,53 @@ func (fm *FMesh) runCycle() {
 		newCycle.SetErr(errors.Join(errFailedToRunCycle, errNoComponents))
 	}
 
-	var wg sync.WaitGroup
-
-	components, err := fm.Components().Components()
-	if err != nil {
-		newCycle.SetErr(errors.Join(errFailedToRunCycle, err))
+	if fm.Components().HasErr() {
+		newCycle.SetErr(errors.Join(errFailedToRunCycle, fm.Components().Err()))
 	}
 
-	for _, c := range components {
+	t := fm.compiledTopology()
+	fm.applyInjections(t)
+
+	// Each task writes only to its own slot (indexed by component ID), so no locking is needed
+	t.arena.reset()
+	activationResults := t.arena.activationResults
+	ready := t.arena.ready[:0]
+	stopping := fm.stop.isRequested()
+	for id, c := range t.components {
 		if c.HasErr() {
 			fm.SetErr(c.Err())
 		}
-		wg.Add(1)
 
-		go func(component *component.Component, cycle *cycle.Cycle) {
-			defer wg.Done()
+		if !t.scheduled[id] {
+			// Nothing new arrived to the component since its last evaluation, so it can not activate
+			activationResults[id] = t.quietResults[id]
+			continue
+		}
+
+		if stopping && c.IsSource() && !c.Inputs().AnyHasSignals() {
+			// Mesh is stopping gracefully, so sources do not produce new signals
+			activationResults[id] = t.quietResults[id]
+			continue
+		}
 
-			cycle.Lock()
-			cycle.ActivationResults().Add(c.MaybeActivate())
-			cycle.Unlock()
-		}(c, newCycle)
+		// Components pushed back by destinations wait until they catch up
+		t.deferred[id] = t.pressurable && c.Backpressure()
+		if t.deferred[id] {
+			activationResults[id] = t.quietResults[id]
+			continue
+		}
+		ready = append(ready, id)
 	}
+	ready = t.preempt(ready, activationResults)
+	t.scheduler.order(t, ready)
+	t.arena.ready = ready
 
-	wg.Wait()
+	fm.executeActivations(t, ready, func(id int) {
+		activationResults[id] = t.components[id].MaybeActivate()
+	})
+
+	newCycle.WithActivationResults(activationResults...)
+	t.arena.cycleNumber = newCycle.Number()
 
 	//Bubble up chain errors from activation results
 	for _, ar := range newCycle.ActivationResults() {
@@ -142,25 +264,30 @@ func (fm *FMesh) drainComponents() {
 		return
 	}
 
-	fm.clearInputs()
-	if fm.HasErr() {
+	if fm.Components().HasErr() {
+		fm.SetErr(errors.Join(ErrFailedToDrain, fm.Components().Err()))
 		return
 	}
 
-	components, err := fm.Components().Components()
-	if err != nil {
-		fm.SetErr(errors.Join(ErrFailedToDrain, err))
+	lastCycle := fm.cycles.Last()
+	t := fm.compiledTopology()
+	activationResults := t.activationResults(lastCycle)
+
+	reroutes := fm.degradeFailed(activationResults)
+	fm.clearInputs(activationResults)
+	if fm.HasErr() {
 		return
 	}
 
-	lastCycle := fm.cycles.Last()
-
-	for _, c := range components {
-		activationResult := lastCycle.ActivationResults().ByComponentName(c.Name())
+	// Components are flushed concurrently, destination ports guard their buffers individually
+	transfers := t.arena.transfers
+	drained := t.arena.ready[:0]
+	for id := range t.components {
+		activationResult := activationResults[id]
 
 		if activationResult.HasErr() {
 			fm.SetErr(errors.Join(ErrFailedToDrain, activationResult.Err()))
-			return
+			break
 		}
 
 		if !activationResult.Activated() {
@@ -175,56 +302,235 @@ func (fm *FMesh) drainComponents() {
 			continue
 		}
 
+		drained = append(drained, id)
+	}
+	t.arena.ready = drained
+
+	fm.stampSignalLabels(drained)
+	var deliveries [][]SignalDelivery
+	if len(fm.hooks.signalDelivered) > 0 {
+		deliveries = make([][]SignalDelivery, len(t.components))
+	}
+	fm.currentExecutor().execute(drained, func(id int) {
+		c := t.components[id]
+		transfers[id] = appendPendingTransfers(transfers[id], c, t)
+		if deliveries != nil {
+			deliveries[id] = fm.pendingDeliveries(c, t)
+		}
 		c.FlushOutputs()
+		if t.blocking {
+			t.held[id] = holdsSignals(c)
+		}
+	})
+	flushedHeld := fm.flushHeld(t, activationResults, deliveries)
+
+	for _, componentTransfers := range transfers {
+		lastCycle.WithTransfers(componentTransfers...)
+	}
+	for _, componentDeliveries := range deliveries {
+		fm.notifySignalsDelivered(componentDeliveries)
+	}
+
+	fm.prioritizeSignals()
+
+	t.scheduleNext(activationResults)
+	for _, id := range flushedHeld {
+		for _, downstreamID := range t.downstream[id] {
+			t.scheduled[downstreamID] = true
+		}
+	}
+	fm.rerouteDegraded(reroutes)
+	if fm.IsDebug() {
+		fm.LogDebug(fmt.Sprintf("%d components are quiet and will be skipped in the next cycle", t.quietCount()))
+	}
+}
+
+// flushHeld flushes components which were not drained in the cycle, but hold signals which did not fit into destinations
+// blocking on overflow (destinations may have room now as their inputs were cleared), returns IDs of flushed components
+func (fm *FMesh) flushHeld(t *topology, activationResults []*component.ActivationResult, deliveries [][]SignalDelivery) []int {
+	if !t.blocking {
+		return nil
+	}
+
+	var flushed []int
+	for id, c := range t.components {
+		if !t.held[id] || (activationResults[id].Activated() && !component.IsWaitingForInput(activationResults[id])) {
+			continue
+		}
+		t.arena.transfers[id] = appendPendingTransfers(t.arena.transfers[id], c, t)
+		if deliveries != nil {
+			deliveries[id] = fm.pendingDeliveries(c, t)
+		}
+		if c.FlushOutputs().HasErr() {
+			fm.SetErr(errors.Join(ErrFailedToDrain, c.Err()))
+			return nil
+		}
+		t.held[id] = holdsSignals(c)
+		flushed = append(flushed, id)
 	}
+	return flushed
+}
+
+// appendPendingTransfers appends the transfers which will happen when the given component is flushed
+func appendPendingTransfers(transfers []cycle.Transfer, c *component.Component, t *topology) []cycle.Transfer {
+	for _, out := range c.Outputs().PortsOrNil() {
+		signalsCount := out.Buffer().Len()
+		if signalsCount == 0 {
+			continue
+		}
+		for _, dest := range out.Pipes().PortsOrNil() {
+			transfers = append(transfers, cycle.Transfer{
+				SourceComponent: c.Name(),
+				SourcePort:      out.Name(),
+				DestComponent:   t.ownerName(dest),
+				DestPort:        dest.Name(),
+				SignalsCount:    signalsCount,
+			})
+		}
+	}
+	return transfers
 }
 
 // clearInputs clears all the input ports of all components activated in latest cycle
-func (fm *FMesh) clearInputs() {
+// activationResults are the results of the latest cycle indexed by component ID
+func (fm *FMesh) clearInputs(activationResults []*component.ActivationResult) {
 	if fm.HasErr() {
 		return
 	}
 
-	components, err := fm.Components().Components()
-	if err != nil {
-		fm.SetErr(errors.Join(errFailedToClearInputs, err))
+	if fm.Components().HasErr() {
+		fm.SetErr(errors.Join(errFailedToClearInputs, fm.Components().Err()))
 		return
 	}
 
-	lastCycle := fm.cycles.Last()
-
-	for _, c := range components {
-		activationResult := lastCycle.ActivationResults().ByComponentName(c.Name())
+	cycleNumber := fm.cycles.Last().Number()
+	for id, c := range fm.compiledTopology().components {
+		activationResult := activationResults[id]
 
 		if activationResult.HasErr() {
 			fm.SetErr(errors.Join(errFailedToClearInputs, activationResult.Err()))
 		}
 
-		if !activationResult.Activated() {
-			// Component did not activate hence it's inputs must be clear
-			continue
-		}
+		fm.clearActivatedInputs(c, activationResult, cycleNumber)
+	}
+}
 
-		if component.IsWaitingForInput(activationResult) && component.WantsToKeepInputs(activationResult) {
-			// Component want to keep inputs for the next cycle
-			//@TODO: add fine grained control on which ports to keep
-			continue
-		}
+// clearActivatedInputs clears input ports of the component after the activation,
+// inputs of failed activations are collected as dead letters
+func (fm *FMesh) clearActivatedInputs(c *component.Component, activationResult *component.ActivationResult, cycleNumber int) {
+	if !activationResult.Activated() {
+		// Component did not activate hence it's inputs must be clear
+		return
+	}
+
+	if c.IsAtLeastOnce() && (activationResult.IsError() || activationResult.IsPanic()) {
+		// Unacknowledged signals stay for the next attempt
+		c.ClearAckedInputs()
+		return
+	}
+
+	if component.IsWaitingForInput(activationResult) && component.WantsToKeepInputs(activationResult) {
+		// Component want to keep inputs for the next cycle
+		//@TODO: add fine grained control on which ports to keep
+		return
+	}
 
-		c.ClearInputs()
+	if activationResult.IsError() || activationResult.IsPanic() {
+		fm.deadLetters.collect(c, cycleNumber, activationResult.ActivationError())
 	}
+	c.ClearInputs()
 }
 
-// Run starts the computation until there is no component which activates (mesh has no unprocessed inputs)
+// clearAckedInputs removes acknowledged signals from inputs of at-least-once components failed in the latest cycle
+func (fm *FMesh) clearAckedInputs() {
+	t := fm.compiledTopology()
+	for id, activationResult := range t.activationResults(fm.cycles.Last()) {
+		c := t.components[id]
+		if c.IsAtLeastOnce() && (activationResult.IsError() || activationResult.IsPanic()) {
+			c.ClearAckedInputs()
+		}
+	}
+}
+
+// Run starts the computation until there is no component which activates (mesh has no unprocessed inputs).
+// Runs are warm: a run continues from where the previous one stopped, so a mesh can be fed in batches
+// (e.g. load spikes arriving between runs). What persists between runs:
+//   - signals left on ports: inputs kept by components waiting for inputs, unacknowledged and spilled signals,
+//     inputs of components of a run stopped on error, signals on output ports without pipes
+//   - states of components
+//   - cycles: numbering continues and CyclesLimit counts cycles of all runs (returned cycles include previous runs as well)
+//   - signals injected after the previous run (they are put on ports in the first cycle)
+//
+// Runtime info is replaced by each run. Use Reset to start a run cold
 func (fm *FMesh) Run() (cycle.Cycles, error) {
-	if fm.HasErr() {
-		return nil, fm.Err()
+	return fm.RunWithContext(context.Background())
+}
+
+// RunWithContext runs the mesh like Run, the context is available to activation functions via Context(),
+// the run stops with the context error once the context is cancelled (checked between activation cycles)
+func (fm *FMesh) RunWithContext(ctx context.Context) (cycles cycle.Cycles, err error) {
+	if err := fm.Validate(); err != nil {
+		return nil, err
+	}
+
+	fm.startRuntimeInfo()
+	defer fm.stopRuntimeInfo()
+	defer fm.stop.reset()
+
+	for _, c := range fm.Components().ComponentsOrNil() {
+		c.WithContext(ctx)
+	}
+	fm.trackStateModifications()
+
+	fm.reportPortBuffers(fm.compileTopology())
+
+	fm.executor = newExecutor(fm.config.ExecutionStrategy, fm.config.Workers, fm.config.MaxConcurrentActivations)
+	defer fm.stopExecutor()
+
+	fm.notifyRunStart()
+	defer func() {
+		fm.notifyRunStop(cycles, err)
+	}()
+
+	// step is the reply of the Step the current cycle is run for
+	var step chan<- stepResult
+	defer func() {
+		fm.pause.endRun(step, fm.cycles.Last(), err)
+	}()
+
+	if fm.config.Engine == EventEngine {
+		return nil, fm.runEventDriven(ctx)
 	}
 
 	for {
-		fm.runCycle()
+		if err := ctx.Err(); err != nil {
+			return fm.cycles.CyclesOrNil(), err
+		}
+
+		fm.Debugger().check()
+		nextStep, err := fm.pause.await(ctx, fm.stop.done())
+		if err != nil {
+			return fm.cycles.CyclesOrNil(), err
+		}
+		step = nextStep
 
-		if mustStop, err := fm.mustStop(); mustStop {
+		cycleStartedAt := fm.Clock().Now()
+		fm.runCycle()
+		fm.notifyCycle(fm.cycles.Last())
+
+		mustStop, err := fm.mustStop()
+		idle := false
+		if mustStop && err == nil && !fm.stop.isRequested() && (fm.awaitInput(ctx) || ctx.Err() != nil) {
+			// Mesh is idle, but a source woke up (or the run was cancelled while waiting, which is reported above), so the run goes on
+			mustStop = false
+			idle = true
+		}
+		if mustStop {
+			if err != nil {
+				// Inputs are not cleared when the run stops on an error, but acknowledged signals are processed already
+				fm.clearAckedInputs()
+			}
+			fm.notifyCycleEnd(fm.cycles.Last())
 			return fm.cycles.CyclesOrNil(), err
 		}
 
@@ -232,9 +538,55 @@ func (fm *FMesh) Run() (cycle.Cycles, error) {
 		if fm.HasErr() {
 			return nil, fm.Err()
 		}
+		fm.notifyCycleEnd(fm.cycles.Last())
+		if step != nil {
+			step <- stepResult{cycle: fm.cycles.Last()}
+			step = nil
+		}
+		fm.tickClock()
+		if !idle {
+			// A source woken after idle waiting is served right away, so only busy cycles are paced
+			fm.pace(ctx, cycleStartedAt)
+		}
 	}
 }
 
+// RunContinuously runs the mesh like RunWithContext, but the run does not end when the mesh is idle: it waits for signals
+// injected from other goroutines (see Inject) and processes them as they arrive, so the mesh serves as a long-running pipeline.
+// The run ends when the context is cancelled (with the context error), on Stop (once signals in the mesh are processed) or on error.
+// Cycles of the whole run are kept, so consider Config.CyclesLimit (e.g. UnlimitedCycles) for long runs
+func (fm *FMesh) RunContinuously(ctx context.Context) (cycle.Cycles, error) {
+	fm.continuous = true
+	defer func() {
+		fm.continuous = false
+	}()
+	return fm.RunWithContext(ctx)
+}
+
+// Advance runs a single activation cycle and drains it, so the mesh can be driven externally (e.g. by a distributed coordinator),
+// returns true if any component activated during the cycle.
+// Unlike Run it keeps the compiled topology between calls, so signals must be delivered to a driven mesh via Inject
+func (fm *FMesh) Advance() (bool, error) {
+	if fm.HasErr() {
+		return false, fm.Err()
+	}
+
+	fm.runCycle()
+
+	if _, err := fm.mustStop(); err != nil {
+		fm.notifyCycleEnd(fm.cycles.Last())
+		return false, err
+	}
+
+	fm.drainComponents()
+	if fm.HasErr() {
+		return false, fm.Err()
+	}
+	fm.notifyCycleEnd(fm.cycles.Last())
+	fm.tickClock()
+	return fm.cycles.Last().HasActivatedComponents(), nil
+}
+
 // mustStop defines when f-mesh must stop (it always checks only last cycle)
 func (fm *FMesh) mustStop() (bool, error) {
 	if fm.HasErr() {
@@ -247,26 +599,34 @@ func (fm *FMesh) mustStop() (bool, error) {
 		return true, ErrReachedMaxAllowedCycles
 	}
 
-	if !lastCycle.HasActivatedComponents() {
-		// Stop naturally (no components activated during the cycle => all inputs are processed)
+	if fm.config.MaxDuration > 0 && fm.runtimeInfo != nil && fm.Clock().Since(fm.runtimeInfo.StartedAt) > fm.config.MaxDuration {
+		return true, ErrReachedMaxAllowedDuration
+	}
+
+	if err := fm.detectDeadlock(lastCycle); err != nil {
+		return true, err
+	}
+
+	if !lastCycle.HasActivatedComponents() && !fm.injections.hasPending() {
+		// Stop naturally (no components activated during the cycle and nothing injected => all inputs are processed)
 		return true, nil
 	}
 
 	//Check if mesh must stop because of configured error handling strategy
 	switch fm.config.ErrorHandlingStrategy {
 	case StopOnFirstErrorOrPanic:
-		if lastCycle.HasErrors() || lastCycle.HasPanics() {
+		if err := escalatedErrors(lastCycle, true); err != nil {
 			//@TODO: add failing components names to error
-			return true, fmt.Errorf("%w, cycle # %d, activation errors: %w", ErrHitAnErrorOrPanic, lastCycle.Number(), lastCycle.AllErrorsCombined())
+			return true, fmt.Errorf("%w, cycle # %d, activation errors: %w", ErrHitAnErrorOrPanic, lastCycle.Number(), err)
 		}
 		return false, nil
 	case StopOnFirstPanic:
 		// @TODO: add more context to error
-		if lastCycle.HasPanics() {
+		if escalatedErrors(lastCycle, false) != nil {
 			return true, ErrHitAPanic
 		}
 		return false, nil
-	case IgnoreAll:
+	case IgnoreAll, DegradeFailing:
 		return false, nil
 	default:
 		return true, ErrUnsupportedErrorHandlingStrategy
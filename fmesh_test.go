@@ -1,15 +1,21 @@
 package fmesh
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/hovsep/fmesh/clock"
 	"github.com/hovsep/fmesh/common"
 	"github.com/hovsep/fmesh/component"
 	"github.com/hovsep/fmesh/cycle"
 	"github.com/hovsep/fmesh/port"
 	"github.com/hovsep/fmesh/signal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"runtime"
 	"testing"
+	"time"
 )
 
 func TestNew(t *testing.T) {
@@ -599,7 +605,9 @@ func TestFMesh_runCycle(t *testing.T) {
 		},
 		{
 			name: "all components activated in one cycle (concurrently)",
-			fm: New("test").WithComponents(
+			fm: NewWithConfig("test", &Config{
+				Clock: clock.NewVirtual(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			}).WithComponents(
 				component.New("c1").
 					WithDescription("").
 					WithInputs("i1").
@@ -634,13 +642,16 @@ func TestFMesh_runCycle(t *testing.T) {
 			want: cycle.New().WithActivationResults(
 				component.NewActivationResult("c1").
 					SetActivated(true).
-					WithActivationCode(component.ActivationCodeOK),
+					WithActivationCode(component.ActivationCodeOK).
+					WithStats(0, 1, 0),
 				component.NewActivationResult("c2").
 					SetActivated(true).
-					WithActivationCode(component.ActivationCodeOK),
+					WithActivationCode(component.ActivationCodeOK).
+					WithStats(0, 1, 5),
 				component.NewActivationResult("c3").
 					SetActivated(true).
-					WithActivationCode(component.ActivationCodeOK),
+					WithActivationCode(component.ActivationCodeOK).
+					WithStats(0, 1, 0),
 			).WithNumber(1),
 		},
 	}
@@ -971,3 +982,365 @@ func TO_BE_REWRITTEN_FMesh_drainComponents(t *testing.T) {
 		})
 	}
 }
+
+func TestFMesh_RandSource(t *testing.T) {
+	getFM := func(seed int64) *FMesh {
+		return NewWithConfig("fm", &Config{
+			RandSource: rand.NewSource(seed),
+		}).WithComponents(
+			component.New("dice").
+				WithInputs("roll").
+				WithOutputs("result").
+				WithActivationFunc(func(this *component.Component) error {
+					for range this.InputByName("roll").AllSignalsOrNil() {
+						this.OutputByName("result").PutSignals(signal.New(this.Rand().Intn(6) + 1))
+					}
+					return nil
+				}),
+		)
+	}
+
+	roll := func(fm *FMesh) []any {
+		fm.ComponentByName("dice").InputByName("roll").PutSignals(signal.New(1), signal.New(2), signal.New(3))
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		payloads, err := fm.ComponentByName("dice").OutputByName("result").AllSignalsPayloads()
+		assert.NoError(t, err)
+		return payloads
+	}
+
+	t.Run("same seed gives same results", func(t *testing.T) {
+		assert.Equal(t, roll(getFM(42)), roll(getFM(42)))
+	})
+
+	t.Run("component rand can be replaced", func(t *testing.T) {
+		fm := getFM(1)
+		fm.ComponentByName("dice").WithRand(rand.New(rand.NewSource(42)))
+		expected := rand.New(rand.NewSource(42))
+		assert.Equal(t, []any{expected.Intn(6) + 1, expected.Intn(6) + 1, expected.Intn(6) + 1}, roll(fm))
+	})
+
+	t.Run("rand set before adding the component is kept", func(t *testing.T) {
+		dice := component.New("dice").
+			WithInputs("roll").
+			WithOutputs("result").
+			WithRand(rand.New(rand.NewSource(42))).
+			WithActivationFunc(func(this *component.Component) error {
+				for range this.InputByName("roll").AllSignalsOrNil() {
+					this.OutputByName("result").PutSignals(signal.New(this.Rand().Intn(6) + 1))
+				}
+				return nil
+			})
+		fm := NewWithConfig("fm", &Config{
+			RandSource: rand.NewSource(1),
+		}).WithComponents(dice)
+
+		expected := rand.New(rand.NewSource(42))
+		assert.Equal(t, []any{expected.Intn(6) + 1, expected.Intn(6) + 1, expected.Intn(6) + 1}, roll(fm))
+	})
+}
+
+// getProducersMesh returns a mesh with given number of concurrent producers, optionally all piped into one sink
+func getProducersMesh(producersCount int, fanIn bool) *FMesh {
+	sink := component.New("sink").
+		WithInputs("in").
+		WithActivationFunc(func(this *component.Component) error {
+			return nil
+		})
+
+	fm := NewWithConfig("producers", &Config{
+		CyclesLimit: UnlimitedCycles,
+	}).WithComponents(sink)
+
+	for i := 0; i < producersCount; i++ {
+		producer := component.New(fmt.Sprintf("producer-%d", i)).
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				for j := 0; j < 10; j++ {
+					this.OutputByName("out").PutSignals(signal.New(j))
+				}
+				return nil
+			})
+		if fanIn {
+			producer.OutputByName("out").PipeTo(sink.InputByName("in"))
+		}
+		fm.WithComponents(producer)
+		producer.InputByName("in").PutSignals(signal.New("start"))
+	}
+	return fm
+}
+
+func BenchmarkFMesh_Run_ConcurrentProducers(b *testing.B) {
+	for _, fanIn := range []bool{false, true} {
+		b.Run(fmt.Sprintf("500 producers, fan-in: %t", fanIn), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				fm := getProducersMesh(500, fanIn)
+				b.StartTimer()
+
+				if _, err := fm.Run(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkFMesh_LargeMesh builds a chain of 100k small components and reports retained heap per component
+func BenchmarkFMesh_LargeMesh(b *testing.B) {
+	const componentsCount = 100_000
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		components := make([]*component.Component, componentsCount)
+		for j := range components {
+			components[j] = component.New(fmt.Sprintf("cell-%d", j)).
+				WithInputs("in").
+				WithOutputs("out").
+				WithActivationFunc(func(this *component.Component) error {
+					return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+				})
+			if j > 0 {
+				components[j-1].OutputByName("out").PipeTo(components[j].InputByName("in"))
+			}
+		}
+		fm := New("large").WithComponents(components...)
+
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/componentsCount, "heap-B/component")
+		runtime.KeepAlive(fm)
+	}
+}
+
+func TestFMesh_QuietComponentsAreSkipped(t *testing.T) {
+	forward := func(this *component.Component) error {
+		return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+	}
+	c1 := component.New("c1").WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+	c2 := component.New("c2").WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+	idle := component.New("idle").WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+	c1.OutputByName("out").PipeTo(c2.InputByName("in"))
+
+	fm := New("fm").WithComponents(c1, c2, idle)
+	c1.InputByName("in").PutSignals(signal.New(1))
+
+	cycles, err := fm.Run()
+	assert.NoError(t, err)
+	assert.Len(t, cycles, 3)
+
+	// Idle component is evaluated in the first cycle only, later it is reported as not activated without evaluation
+	quietResult := fm.compiledTopology().quietResults[fm.compiledTopology().ids["idle"]]
+	assert.NotSame(t, quietResult, cycles[0].ActivationResults().ByComponentName("idle"))
+	assert.Same(t, quietResult, cycles[1].ActivationResults().ByComponentName("idle"))
+	assert.Same(t, quietResult, cycles[2].ActivationResults().ByComponentName("idle"))
+	assert.Equal(t, component.ActivationCodeNoInput, cycles[1].ActivationResults().ByComponentName("idle").Code())
+	assert.True(t, cycles[1].ActivationResults().ByComponentName("c2").Activated())
+}
+
+func TestFMesh_Advance(t *testing.T) {
+	forward := func(this *component.Component) error {
+		return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+	}
+	c1 := component.New("c1").WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+	c2 := component.New("c2").WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+	c1.OutputByName("out").PipeTo(c2.InputByName("in"))
+
+	fm := New("fm").WithComponents(c1, c2)
+	assert.NoError(t, fm.Inject("c1", "in", signal.New(1)))
+
+	activated, err := fm.Advance()
+	assert.NoError(t, err)
+	assert.True(t, activated)
+	assert.True(t, c2.InputByName("in").HasSignals())
+
+	activated, err = fm.Advance()
+	assert.NoError(t, err)
+	assert.True(t, activated)
+	assert.Equal(t, 1, c2.OutputByName("out").FirstSignalPayloadOrNil())
+
+	activated, err = fm.Advance()
+	assert.NoError(t, err)
+	assert.False(t, activated)
+	assert.Equal(t, 3, fm.cycles.Len())
+
+	t.Run("activation error", func(t *testing.T) {
+		broken := New("fm").WithComponents(component.New("c").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+			return errors.New("boom")
+		}))
+		assert.NoError(t, broken.Inject("c", "in", signal.New(1)))
+		_, err := broken.Advance()
+		assert.ErrorIs(t, err, ErrHitAnErrorOrPanic)
+	})
+}
+
+func TestFMesh_RunWithContext(t *testing.T) {
+	type contextKey string
+
+	t.Run("values are available in activation functions", func(t *testing.T) {
+		var tenant any
+		c := component.New("c").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+			tenant = this.Context().Value(contextKey("tenant"))
+			return nil
+		})
+		fm := New("fm").WithComponents(c)
+		c.InputByName("in").PutSignals(signal.New(1))
+
+		_, err := fm.RunWithContext(context.WithValue(context.Background(), contextKey("tenant"), "acme"))
+		assert.NoError(t, err)
+		assert.Equal(t, "acme", tenant)
+	})
+
+	t.Run("cancellation stops endless loop", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		activations := 0
+		loop := component.New("loop").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			activations++
+			if activations == 5 {
+				cancel()
+			}
+			return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+		})
+		loop.OutputByName("out").PipeTo(loop.InputByName("in"))
+		fm := NewWithConfig("fm", &Config{
+			CyclesLimit: UnlimitedCycles,
+		}).WithComponents(loop)
+		loop.InputByName("in").PutSignals(signal.New(1))
+
+		cycles, err := fm.RunWithContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Len(t, cycles, 5)
+	})
+
+	t.Run("cancellation stops waiting for sources", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		source := component.New("source").
+			WithReadinessFunc(func(this *component.Component) bool {
+				return false
+			}).
+			WithIdleWaitFunc(func(this *component.Component, cancel <-chan struct{}) bool {
+				<-cancel
+				return false
+			}).
+			WithActivationFunc(func(this *component.Component) error {
+				return nil
+			})
+
+		_, err := New("fm").WithComponents(source).RunWithContext(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+// BenchmarkFMesh_Run_MostlyQuiet runs a mesh where a short chain is active while most components stay idle
+func BenchmarkFMesh_Run_MostlyQuiet(b *testing.B) {
+	const (
+		chainLength = 20
+		idleCount   = 5000
+	)
+	forward := func(this *component.Component) error {
+		return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+	}
+
+	fm := NewWithConfig("mostly quiet", &Config{
+		CyclesLimit: UnlimitedCycles,
+	})
+	chain := make([]*component.Component, chainLength)
+	for i := range chain {
+		chain[i] = component.New(fmt.Sprintf("chain-%d", i)).WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+		if i > 0 {
+			chain[i-1].OutputByName("out").PipeTo(chain[i].InputByName("in"))
+		}
+		fm.WithComponents(chain[i])
+	}
+	for i := 0; i < idleCount; i++ {
+		fm.WithComponents(component.New(fmt.Sprintf("idle-%d", i)).WithInputs("in").WithOutputs("out").WithActivationFunc(forward))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		chain[0].InputByName("in").PutSignals(signal.New(i))
+		if _, err := fm.Run(); err != nil {
+			b.Fatal(err)
+		}
+		chain[chainLength-1].OutputByName("out").Clear()
+	}
+}
+
+func TestFMesh_AutoPorts(t *testing.T) {
+	c := component.New("c").WithActivationFunc(func(this *component.Component) error {
+		return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+	})
+	fm := NewWithConfig("fm", &Config{AutoPorts: true}).WithComponents(c)
+	c.InputByName("in").PutSignals(signal.New(1))
+
+	_, err := fm.Run()
+	assert.NoError(t, err)
+	payloads, err := c.OutputByName("out").AllSignalsPayloads()
+	assert.NoError(t, err)
+	assert.Equal(t, []any{1}, payloads)
+}
+
+func TestFMesh_ErrorReturningLookups(t *testing.T) {
+	fm := New("fm").WithComponents(component.New("c1"))
+
+	c, err := fm.ComponentByNameE("c1")
+	assert.NoError(t, err)
+	assert.Equal(t, "c1", c.Name())
+	assert.Same(t, c, fm.MustComponentByName("c1"))
+
+	_, err = fm.ComponentByNameE("c2")
+	assert.ErrorIs(t, err, component.ErrNotFound)
+	assert.EqualError(t, err, "f-mesh fm: component not found, component name: c2")
+	assert.False(t, fm.Components().HasErr(), "chain error is not set")
+	assert.PanicsWithValue(t, "component lookup failed: f-mesh fm: component not found, component name: c2", func() {
+		fm.MustComponentByName("c2")
+	})
+}
+
+func TestFMesh_MaxDuration(t *testing.T) {
+	// The counter is looped on itself, so without limits the mesh runs forever
+	getFM := func(config *Config) *FMesh {
+		counter := component.New("counter").
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+				return nil
+			})
+		counter.OutputByName("out").PipeTo(counter.InputByName("in"))
+		counter.InputByName("in").PutSignals(signal.New(1))
+		return NewWithConfig("fm", config).WithComponents(counter)
+	}
+
+	t.Run("cycle engine with simulated clock", func(t *testing.T) {
+		fm := getFM(&Config{
+			Clock:       clock.NewSimulation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Second),
+			MaxDuration: 10 * time.Second,
+		})
+
+		cycles, err := fm.Run()
+		require.ErrorIs(t, err, ErrReachedMaxAllowedDuration)
+		// The clock ticks after each cycle, so the limit is exceeded in a deterministic cycle
+		assert.Len(t, cycles, 12)
+	})
+
+	t.Run("event engine", func(t *testing.T) {
+		fm := getFM(&Config{
+			Engine:      EventEngine,
+			MaxDuration: 20 * time.Millisecond,
+		})
+
+		_, err := fm.Run()
+		require.ErrorIs(t, err, ErrReachedMaxAllowedDuration)
+	})
+}
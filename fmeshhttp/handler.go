@@ -0,0 +1,124 @@
+// Package fmeshhttp serves a mesh over HTTP: selected input ports are exposed as endpoints,
+// the request body becomes a signal and signals emitted on the response port with the same correlation ID
+// become the HTTP response
+package fmeshhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/signal"
+	"io"
+	"net/http"
+)
+
+const (
+	// CorrelationIDLabel is the label carrying the correlation ID of the request which caused the signal
+	CorrelationIDLabel = signal.CorrelationIDLabel
+
+	// CorrelationIDHeader is the response header carrying the correlation ID
+	CorrelationIDHeader = "X-Correlation-ID"
+)
+
+// Endpoint is a port of a component
+type Endpoint struct {
+	Component string
+	Port      string
+}
+
+// Route connects an HTTP endpoint to the mesh: the request is put to the input port,
+// the response is taken from the output port (which must not be piped, so signals stay there after the run)
+type Route struct {
+	Input  Endpoint
+	Output Endpoint
+}
+
+// RouteMap maps http.ServeMux patterns (e.g. "POST /orders") to routes
+type RouteMap map[string]Route
+
+// NewHandler creates a handler exposing the mesh routes.
+// Request body is decoded as JSON into the payload of a signal labeled with a new correlation ID,
+// components must keep the label on signals they emit (see Reply), so the response can be matched to the request.
+// Response is the JSON encoded payload of the correlated signal (or an array of payloads when there are many).
+// Requests arriving while the mesh runs are served together by the next run, which gets the request context
+// (a run serving many requests is cancelled once all of them are gone). States of components persist between requests,
+// signals of a request are removed from the mesh once it is served and a failed run fails only the requests it served
+func NewHandler(mesh *fmesh.FMesh, routes RouteMap) http.Handler {
+	r := newRunner(mesh)
+
+	mux := http.NewServeMux()
+	for pattern, route := range routes {
+		mux.Handle(pattern, routeHandler(r, route))
+	}
+	return mux
+}
+
+// Reply creates a signal correlated with the request signal (see signal.NewCorrelated),
+// signals which did not come from HTTP requests are not correlated, so their replies stay uncorrelated too
+func Reply(request *signal.Signal, payload any) *signal.Signal {
+	if request.CorrelationID() == "" {
+		return signal.New(payload)
+	}
+	return signal.NewCorrelated(request, payload)
+}
+
+// routeHandler handles requests of a single route
+func routeHandler(runner *runner, route Route) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := decodeBody(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		id, response, ok, err := runner.call(r.Context(), route.Input, []Endpoint{route.Output}, payload)
+		if id != "" {
+			w.Header().Set(CorrelationIDHeader, id)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		if !ok {
+			http.Error(w, "mesh produced no response", http.StatusBadGateway)
+			return
+		}
+		writeSignals(w, response.signals)
+	})
+}
+
+// errorStatus returns the status code of the response reporting the error of the request
+func errorStatus(err error) int {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// decodeBody decodes JSON body, empty body gives nil payload
+func decodeBody(body io.Reader) (any, error) {
+	var payload any
+	if err := json.NewDecoder(body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to decode request body: %w", err)
+	}
+	return payload, nil
+}
+
+// writeSignals writes payloads of the response signals
+func writeSignals(w http.ResponseWriter, signals signal.Signals) {
+	payloads, err := signal.NewGroup().With(signals...).AllPayloads()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var body any = payloads
+	if len(payloads) == 1 {
+		body = payloads[0]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,217 @@
+package fmeshhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// getCalculatorMesh returns a mesh doubling numbers, with components emitting nothing, failing and waiting until the request is gone
+func getCalculatorMesh(cyclesLimit int) *fmesh.FMesh {
+	parser := component.New("parser").
+		WithInputs("req").
+		WithOutputs("num", "invalid").
+		WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("req").AllSignalsOrNil() {
+				num, ok := sig.PayloadOrNil().(float64)
+				if !ok {
+					this.OutputByName("invalid").PutSignals(Reply(sig, "not a number"))
+					continue
+				}
+				this.OutputByName("num").PutSignals(Reply(sig, num))
+			}
+			return nil
+		})
+	doubler := component.New("doubler").
+		WithInputs("num").
+		WithOutputs("res").
+		WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("num").AllSignalsOrNil() {
+				this.OutputByName("res").PutSignals(Reply(sig, sig.PayloadOrNil().(float64)*2))
+			}
+			return nil
+		})
+	silent := component.New("silent").
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			return nil
+		})
+	broken := component.New("broken").
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			return errors.New("boom")
+		})
+	slow := component.New("slow").
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			<-this.Context().Done()
+			return nil
+		})
+	parser.OutputByName("num").PipeTo(doubler.InputByName("num"))
+
+	return fmesh.NewWithConfig("calculator", &fmesh.Config{
+		CyclesLimit: cyclesLimit,
+	}).WithComponents(parser, doubler, silent, broken, slow)
+}
+
+func TestNewHandler(t *testing.T) {
+	server := httptest.NewServer(NewHandler(getCalculatorMesh(fmesh.UnlimitedCycles), RouteMap{
+		"POST /double": {
+			Input:  Endpoint{Component: "parser", Port: "req"},
+			Output: Endpoint{Component: "doubler", Port: "res"},
+		},
+		"POST /silent": {
+			Input:  Endpoint{Component: "silent", Port: "in"},
+			Output: Endpoint{Component: "silent", Port: "out"},
+		},
+		"POST /unknown": {
+			Input:  Endpoint{Component: "missing", Port: "in"},
+			Output: Endpoint{Component: "missing", Port: "out"},
+		},
+	}))
+	defer server.Close()
+
+	post := func(t *testing.T, path string, body string) (int, string, http.Header) {
+		response, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		responseBody, err := io.ReadAll(response.Body)
+		assert.NoError(t, err)
+		return response.StatusCode, strings.TrimSpace(string(responseBody)), response.Header
+	}
+
+	t.Run("response is correlated with request", func(t *testing.T) {
+		status, body, header := post(t, "/double", "21")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "42", body)
+		assert.Len(t, header.Get(CorrelationIDHeader), 32)
+	})
+
+	t.Run("concurrent requests", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				status, body, _ := post(t, "/double", fmt.Sprint(i))
+				assert.Equal(t, http.StatusOK, status)
+
+				var result float64
+				assert.NoError(t, json.Unmarshal([]byte(body), &result))
+				assert.Equal(t, float64(i*2), result)
+			}(i)
+		}
+		wg.Wait()
+	})
+
+	t.Run("no response", func(t *testing.T) {
+		status, _, _ := post(t, "/double", `"abc"`)
+		assert.Equal(t, http.StatusBadGateway, status)
+
+		status, _, _ = post(t, "/silent", "1")
+		assert.Equal(t, http.StatusBadGateway, status)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		status, _, _ := post(t, "/double", "{")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("unknown input", func(t *testing.T) {
+		status, _, _ := post(t, "/unknown", "1")
+		assert.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("unknown route", func(t *testing.T) {
+		status, _, _ := post(t, "/missing", "1")
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
+
+func TestNewHandler_RunError(t *testing.T) {
+	server := httptest.NewServer(NewHandler(getCalculatorMesh(fmesh.UnlimitedCycles), RouteMap{
+		"POST /broken": {
+			Input:  Endpoint{Component: "broken", Port: "in"},
+			Output: Endpoint{Component: "broken", Port: "out"},
+		},
+		"POST /double": {
+			Input:  Endpoint{Component: "parser", Port: "req"},
+			Output: Endpoint{Component: "doubler", Port: "res"},
+		},
+	}))
+	defer server.Close()
+
+	post := func(t *testing.T, path string, body string) int {
+		response, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		return response.StatusCode
+	}
+
+	t.Run("failed request is reported to its caller", func(t *testing.T) {
+		assert.Equal(t, http.StatusInternalServerError, post(t, "/broken", "1"))
+	})
+
+	t.Run("failed request does not affect the following ones", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, post(t, "/double", "1"))
+		assert.Equal(t, http.StatusInternalServerError, post(t, "/broken", "1"))
+		assert.Equal(t, http.StatusOK, post(t, "/double", "2"))
+	})
+}
+
+func TestNewHandler_CyclesDoNotAccumulate(t *testing.T) {
+	// Each request takes a few cycles, so the limit would be exceeded if cycles accumulated across requests
+	server := httptest.NewServer(NewHandler(getCalculatorMesh(5), RouteMap{
+		"POST /double": {
+			Input:  Endpoint{Component: "parser", Port: "req"},
+			Output: Endpoint{Component: "doubler", Port: "res"},
+		},
+	}))
+	defer server.Close()
+
+	for i := 0; i < 10; i++ {
+		response, err := http.Post(server.URL+"/double", "application/json", strings.NewReader("1"))
+		assert.NoError(t, err)
+		response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+}
+
+func TestNewHandler_RequestContext(t *testing.T) {
+	handler := NewHandler(getCalculatorMesh(fmesh.UnlimitedCycles), RouteMap{
+		"POST /slow": {
+			Input:  Endpoint{Component: "slow", Port: "in"},
+			Output: Endpoint{Component: "slow", Port: "out"},
+		},
+		"POST /double": {
+			Input:  Endpoint{Component: "parser", Port: "req"},
+			Output: Endpoint{Component: "doubler", Port: "res"},
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/slow", strings.NewReader("1")).WithContext(ctx))
+	assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+
+	// The run is cancelled with the request, so the following requests are served
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/double", strings.NewReader("1")))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "2", strings.TrimSpace(recorder.Body.String()))
+}
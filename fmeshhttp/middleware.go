@@ -0,0 +1,100 @@
+package fmeshhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"net/http"
+)
+
+// MiddlewareConfig describes how a mesh acts as a middleware stage
+type MiddlewareConfig struct {
+	// Input receives the incoming request (*http.Request payload)
+	Input Endpoint
+	// Next is the output port where the (possibly enriched) *http.Request is emitted to pass it to the next handler
+	Next Endpoint
+	// Respond is the output port where a *Response is emitted to answer the request without calling the next handler
+	Respond Endpoint
+}
+
+// Response is emitted on the Respond port to answer the request from the mesh
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	// Body is written as is when it is []byte or string, otherwise it is encoded as JSON
+	Body any
+}
+
+// Middleware turns the mesh into a middleware stage (auth, validation, enrichment, etc.):
+// the request traverses the mesh as a signal and the correlated signal the mesh emits decides what happens next,
+// a *http.Request on the Next port calls the next handler, a *Response on the Respond port is written back.
+// Components must keep the correlation label on emitted signals (see Reply).
+// When the mesh emits nothing the request is rejected with 403 Forbidden.
+// Requests are served like with NewHandler
+func Middleware(mesh *fmesh.FMesh, config MiddlewareConfig) func(next http.Handler) http.Handler {
+	runner := newRunner(mesh)
+	outputs := []Endpoint{config.Respond, config.Next}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, reply, ok, err := runner.call(r.Context(), config.Input, outputs, r)
+			if err != nil {
+				http.Error(w, err.Error(), errorStatus(err))
+				return
+			}
+			if !ok {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
+			payload := reply.signals[len(reply.signals)-1].PayloadOrNil()
+			switch reply.output {
+			case config.Next:
+				request, isRequest := payload.(*http.Request)
+				if !isRequest {
+					http.Error(w, fmt.Sprintf("mesh emitted %T instead of *http.Request", payload), http.StatusInternalServerError)
+					return
+				}
+				next.ServeHTTP(w, request)
+			default:
+				response, isResponse := payload.(*Response)
+				if !isResponse {
+					http.Error(w, fmt.Sprintf("mesh emitted %T instead of *fmeshhttp.Response", payload), http.StatusInternalServerError)
+					return
+				}
+				writeResponse(w, response)
+			}
+		})
+	}
+}
+
+// writeResponse writes the response emitted by the mesh
+func writeResponse(w http.ResponseWriter, response *Response) {
+	for key, values := range response.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+
+	statusCode := response.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	switch body := response.Body.(type) {
+	case nil:
+		w.WriteHeader(statusCode)
+	case []byte:
+		w.WriteHeader(statusCode)
+		_, _ = w.Write(body)
+	case string:
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	default:
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.WriteHeader(statusCode)
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
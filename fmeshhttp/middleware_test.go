@@ -0,0 +1,196 @@
+package fmeshhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type userKey struct{}
+
+// getAuthMesh returns a chain of responsibility: auth rejects requests without token, enrich puts the user into request context
+func getAuthMesh() *fmesh.FMesh {
+	auth := component.New("auth").
+		WithInputs("req").
+		WithOutputs("ok", "denied").
+		WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("req").AllSignalsOrNil() {
+				r := sig.PayloadOrNil().(*http.Request)
+				switch r.Header.Get("Authorization") {
+				case "":
+					this.OutputByName("denied").PutSignals(Reply(sig, &Response{
+						StatusCode: http.StatusUnauthorized,
+						Body:       map[string]string{"error": "missing token"},
+					}))
+				case "drop":
+					// Emits nothing
+				case "fail":
+					return errors.New("auth backend is down")
+				case "slow":
+					// Auth backend answers only when the request is gone
+					<-this.Context().Done()
+				default:
+					this.OutputByName("ok").PutSignals(sig)
+				}
+			}
+			return nil
+		})
+	enrich := component.New("enrich").
+		WithInputs("req").
+		WithOutputs("req").
+		WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("req").AllSignalsOrNil() {
+				r := sig.PayloadOrNil().(*http.Request)
+				user := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+				this.OutputByName("req").PutSignals(Reply(sig, r.WithContext(context.WithValue(r.Context(), userKey{}, user))))
+			}
+			return nil
+		})
+	respond := component.New("respond").
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+		})
+	auth.OutputByName("ok").PipeTo(enrich.InputByName("req"))
+	auth.OutputByName("denied").PipeTo(respond.InputByName("in"))
+
+	return fmesh.NewWithConfig("auth", &fmesh.Config{
+		CyclesLimit: fmesh.UnlimitedCycles,
+	}).WithComponents(auth, enrich, respond)
+}
+
+func TestMiddleware(t *testing.T) {
+	app := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "hello "+r.Context().Value(userKey{}).(string))
+	})
+	middleware := Middleware(getAuthMesh(), MiddlewareConfig{
+		Input:   Endpoint{Component: "auth", Port: "req"},
+		Next:    Endpoint{Component: "enrich", Port: "req"},
+		Respond: Endpoint{Component: "respond", Port: "out"},
+	})
+	server := httptest.NewServer(middleware(app))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name:          "request passes to the next handler",
+			authorization: "Bearer alice",
+			wantStatus:    http.StatusOK,
+			wantBody:      "hello alice",
+		},
+		{
+			name:       "mesh responds",
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   `{"error":"missing token"}`,
+		},
+		{
+			name:          "mesh emits nothing",
+			authorization: "drop",
+			wantStatus:    http.StatusForbidden,
+			wantBody:      "Forbidden",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			assert.NoError(t, err)
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			assert.NoError(t, err)
+			defer response.Body.Close()
+			body, err := io.ReadAll(response.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.wantStatus, response.StatusCode)
+			assert.Equal(t, tt.wantBody, strings.TrimSpace(string(body)))
+		})
+	}
+}
+
+func TestMiddleware_RunError(t *testing.T) {
+	app := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "hello "+r.Context().Value(userKey{}).(string))
+	})
+	middleware := Middleware(getAuthMesh(), MiddlewareConfig{
+		Input:   Endpoint{Component: "auth", Port: "req"},
+		Next:    Endpoint{Component: "enrich", Port: "req"},
+		Respond: Endpoint{Component: "respond", Port: "out"},
+	})
+	server := httptest.NewServer(middleware(app))
+	defer server.Close()
+
+	get := func(t *testing.T, authorization string) int {
+		request, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", authorization)
+		response, err := http.DefaultClient.Do(request)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		return response.StatusCode
+	}
+
+	assert.Equal(t, http.StatusInternalServerError, get(t, "fail"))
+	assert.Equal(t, http.StatusOK, get(t, "Bearer alice"))
+	assert.Equal(t, http.StatusInternalServerError, get(t, "fail"))
+	assert.Equal(t, http.StatusOK, get(t, "Bearer bob"))
+}
+
+func TestMiddleware_Concurrency(t *testing.T) {
+	app := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "hello "+r.Context().Value(userKey{}).(string))
+	})
+	handler := Middleware(getAuthMesh(), MiddlewareConfig{
+		Input:   Endpoint{Component: "auth", Port: "req"},
+		Next:    Endpoint{Component: "enrich", Port: "req"},
+		Respond: Endpoint{Component: "respond", Port: "out"},
+	})(app)
+
+	serve := func(ctx context.Context, authorization string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+		request.Header.Set("Authorization", authorization)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("concurrent requests get their own responses", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(user string) {
+				defer wg.Done()
+				recorder := serve(context.Background(), "Bearer "+user)
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, "hello "+user, recorder.Body.String())
+			}(fmt.Sprint("user", i))
+		}
+		wg.Wait()
+	})
+
+	t.Run("request context is honoured", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, http.StatusGatewayTimeout, serve(ctx, "slow").Code)
+		assert.Equal(t, http.StatusOK, serve(context.Background(), "Bearer bob").Code)
+	})
+}
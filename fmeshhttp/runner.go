@@ -0,0 +1,221 @@
+package fmeshhttp
+
+import (
+	"context"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"sync"
+	"sync/atomic"
+)
+
+// reply holds signals correlated with a request and the output port they were taken from
+type reply struct {
+	output  Endpoint
+	signals signal.Signals
+}
+
+// result is what a request gets from the run serving it
+type result struct {
+	response reply
+	ok       bool
+	err      error
+}
+
+// request is a call waiting to be served by a run
+type request struct {
+	ctx     context.Context
+	id      string
+	input   Endpoint
+	outputs []Endpoint
+	payload any
+	done    chan result
+}
+
+// runner runs the mesh on behalf of requests: requests arriving while the mesh runs are queued
+// and served together by the next run, signals of each request are told apart by its correlation ID
+type runner struct {
+	mesh *fmesh.FMesh
+
+	mu      sync.Mutex
+	queue   []*request
+	running bool
+}
+
+// newRunner creates a runner of the mesh
+func newRunner(mesh *fmesh.FMesh) *runner {
+	return &runner{
+		mesh: mesh,
+	}
+}
+
+// call puts the payload to the input port labeled with a new correlation ID, waits for the run serving it
+// and returns correlated signals found on the first of outputs having them (ok is false when there are none).
+// The call returns the context error as soon as the context is done
+func (r *runner) call(ctx context.Context, input Endpoint, outputs []Endpoint, payload any) (id string, response reply, ok bool, err error) {
+	req := &request{
+		ctx:     ctx,
+		id:      signal.NewCorrelationID(),
+		input:   input,
+		outputs: outputs,
+		payload: payload,
+		done:    make(chan result, 1),
+	}
+
+	r.mu.Lock()
+	r.queue = append(r.queue, req)
+	if !r.running {
+		r.running = true
+		go r.serve()
+	}
+	r.mu.Unlock()
+
+	select {
+	case res := <-req.done:
+		return req.id, res.response, res.ok, res.err
+	case <-ctx.Done():
+		return req.id, reply{}, false, ctx.Err()
+	}
+}
+
+// serve runs the mesh until no requests are queued
+func (r *runner) serve() {
+	for {
+		r.mu.Lock()
+		batch := r.queue
+		r.queue = nil
+		if len(batch) == 0 {
+			r.running = false
+			r.mu.Unlock()
+			return
+		}
+		r.mu.Unlock()
+
+		r.run(batch)
+	}
+}
+
+// run serves the batch of requests with a single run of the mesh.
+// Signals of the requests are removed from the mesh afterwards (whether the run succeeded or not), while states and other signals are kept,
+// a failed run fails all requests of the batch, but not the following ones
+func (r *runner) run(batch []*request) {
+	ids := make(map[string]bool, len(batch))
+	results := make(map[*request]result, len(batch))
+	defer func() {
+		// Requests get results once the mesh is settled
+		r.discard(ids)
+		for req, res := range results {
+			req.done <- res
+		}
+	}()
+
+	served := make([]*request, 0, len(batch))
+	for _, req := range batch {
+		if req.ctx.Err() != nil {
+			// Caller is gone already
+			continue
+		}
+		ids[req.id] = true
+		if err := r.mesh.Inject(req.input.Component, req.input.Port, signal.New(req.payload).WithCorrelationID(req.id)); err != nil {
+			results[req] = result{err: err}
+			continue
+		}
+		served = append(served, req)
+	}
+	if len(served) == 0 {
+		return
+	}
+
+	ctx, cancel := runContext(served)
+	defer cancel()
+
+	hadErr := r.mesh.HasErr()
+	r.mesh.DropCycles()
+	_, err := r.mesh.RunWithContext(ctx)
+	if err != nil && !hadErr {
+		// The error is reported to requests of this run only, errors of the mesh itself (made while building it) stay
+		r.mesh.ClearErr()
+	}
+
+	for _, req := range served {
+		if err != nil {
+			results[req] = result{err: err}
+			continue
+		}
+		response, ok, err := r.collect(req.id, req.outputs)
+		results[req] = result{
+			response: response,
+			ok:       ok,
+			err:      err,
+		}
+	}
+}
+
+// runContext returns the context of the run serving the requests: the request context itself when the run serves one request
+// (so activation functions get its values), otherwise a context cancelled once contexts of all requests are done
+func runContext(served []*request) (context.Context, context.CancelFunc) {
+	if len(served) == 1 {
+		return served[0].ctx, func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var remaining atomic.Int64
+	remaining.Store(int64(len(served)))
+	stops := make([]func() bool, 0, len(served))
+	for _, req := range served {
+		stops = append(stops, context.AfterFunc(req.ctx, func() {
+			if remaining.Add(-1) == 0 {
+				cancel()
+			}
+		}))
+	}
+	return ctx, func() {
+		for _, stop := range stops {
+			stop()
+		}
+		cancel()
+	}
+}
+
+// collect returns signals correlated with the request found on the first of outputs having them
+func (r *runner) collect(id string, outputs []Endpoint) (reply, bool, error) {
+	for _, output := range outputs {
+		c, err := r.mesh.ComponentByNameE(output.Component)
+		if err != nil {
+			return reply{}, false, err
+		}
+		out, err := c.Outputs().ByNameE(output.Port)
+		if err != nil {
+			return reply{}, false, err
+		}
+
+		if correlated := out.SignalsByCorrelation(id); len(correlated) > 0 {
+			return reply{
+				output:  output,
+				signals: correlated,
+			}, true, nil
+		}
+	}
+	return reply{}, false, nil
+}
+
+// discard removes signals correlated with the requests from all ports, so responses do not pile up on output ports
+// and signals left behind by a failed run do not affect the following ones
+func (r *runner) discard(ids map[string]bool) {
+	if len(ids) == 0 {
+		return
+	}
+
+	keep := func(sig *signal.Signal) bool {
+		return !ids[sig.CorrelationID()]
+	}
+	for _, c := range r.mesh.Components().ComponentsOrNil() {
+		for _, ports := range []port.PortMap{c.Inputs().PortsOrNil(), c.Outputs().PortsOrNil()} {
+			for _, p := range ports {
+				if p.HasSignals() {
+					p.RetainSignals(keep)
+				}
+			}
+		}
+	}
+}
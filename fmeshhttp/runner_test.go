@@ -0,0 +1,162 @@
+package fmeshhttp
+
+import (
+	"context"
+	"errors"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+type tenantKey struct{}
+
+// newReplier returns a component replying to each input signal with the payload made by the function
+func newReplier(name string, payload func(this *component.Component, request any) any) *component.Component {
+	return component.New(name).
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("in").AllSignalsOrNil() {
+				this.OutputByName("out").PutSignals(Reply(sig, payload(this, sig.PayloadOrNil())))
+			}
+			return nil
+		})
+}
+
+func endpoints(name string) (Endpoint, []Endpoint) {
+	return Endpoint{Component: name, Port: "in"}, []Endpoint{{Component: name, Port: "out"}}
+}
+
+func Test_runner(t *testing.T) {
+	t.Run("states persist between requests and responses do not pile up", func(t *testing.T) {
+		counter := newReplier("counter", func(this *component.Component, request any) any {
+			count := this.State().GetOrDefault("count", 0).(int) + 1
+			this.State().Set("count", count)
+			return count
+		})
+		r := newRunner(fmesh.New("fm").WithComponents(counter))
+		input, outputs := endpoints("counter")
+
+		for want := 1; want <= 3; want++ {
+			_, response, ok, err := r.call(context.Background(), input, outputs, nil)
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Equal(t, want, response.signals[0].PayloadOrNil())
+		}
+		assert.False(t, counter.OutputByName("out").HasSignals())
+	})
+
+	t.Run("requests arriving during a run are served together by the next one", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		var mu sync.Mutex
+		var batches []int
+		gate := component.New("gate").
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				signals := this.InputByName("in").AllSignalsOrNil()
+				mu.Lock()
+				batches = append(batches, len(signals))
+				first := len(batches) == 1
+				mu.Unlock()
+				if first {
+					close(started)
+					<-release
+				}
+				for _, sig := range signals {
+					this.OutputByName("out").PutSignals(Reply(sig, sig.PayloadOrNil()))
+				}
+				return nil
+			})
+		r := newRunner(fmesh.New("fm").WithComponents(gate))
+		input, outputs := endpoints("gate")
+
+		call := func(wg *sync.WaitGroup, payload int) {
+			defer wg.Done()
+			_, response, ok, err := r.call(context.Background(), input, outputs, payload)
+			assert.NoError(t, err)
+			if assert.True(t, ok) && assert.Len(t, response.signals, 1) {
+				assert.Equal(t, payload, response.signals[0].PayloadOrNil())
+			}
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go call(&wg, 0)
+		<-started
+		for i := 1; i <= 3; i++ {
+			wg.Add(1)
+			go call(&wg, i)
+		}
+		assert.Eventually(t, func() bool {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			return len(r.queue) == 3
+		}, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, []int{1, 3}, batches)
+	})
+
+	t.Run("run gets the request context", func(t *testing.T) {
+		tenant := newReplier("tenant", func(this *component.Component, request any) any {
+			return this.Context().Value(tenantKey{})
+		})
+		slow := component.New("slow").
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				<-this.Context().Done()
+				return nil
+			})
+		r := newRunner(fmesh.New("fm").WithComponents(tenant, slow))
+
+		input, outputs := endpoints("tenant")
+		_, response, ok, err := r.call(context.WithValue(context.Background(), tenantKey{}, "acme"), input, outputs, nil)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, "acme", response.signals[0].PayloadOrNil())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		input, outputs = endpoints("slow")
+		_, _, _, err = r.call(ctx, input, outputs, nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// The run of the cancelled request is stopped, so the following requests are served
+		input, outputs = endpoints("tenant")
+		_, _, ok, err = r.call(context.Background(), input, outputs, nil)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.False(t, slow.Inputs().AnyHasSignals(), "signals of the cancelled request are discarded")
+	})
+
+	t.Run("failed run does not fail the following requests", func(t *testing.T) {
+		echo := newReplier("echo", func(this *component.Component, request any) any {
+			return request
+		})
+		fm := fmesh.New("fm").WithComponents(echo).OnComponentActivated(func(fm *fmesh.FMesh, activationResult *component.ActivationResult) {
+			if echo.State().Has("fail") {
+				fm.SetErr(errors.New("mesh failed"))
+			}
+		})
+		r := newRunner(fm)
+		input, outputs := endpoints("echo")
+
+		echo.State().Set("fail", true)
+		_, _, _, err := r.call(context.Background(), input, outputs, 1)
+		assert.ErrorContains(t, err, "mesh failed")
+
+		echo.State().Delete("fail")
+		_, response, ok, err := r.call(context.Background(), input, outputs, 2)
+		assert.NoError(t, err)
+		if assert.True(t, ok) {
+			assert.Equal(t, 2, response.signals[0].PayloadOrNil())
+		}
+	})
+}
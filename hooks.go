@@ -0,0 +1,117 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/cycle"
+	"github.com/hovsep/fmesh/signal"
+)
+
+// CycleStartHook is called before components are activated in the cycle
+type CycleStartHook func(fm *FMesh, cycleNumber int)
+
+// CycleEndHook is called when the cycle is over (after outputs of activated components are drained, or when the run stops on the cycle)
+type CycleEndHook func(fm *FMesh, c *cycle.Cycle)
+
+// ComponentActivatedHook is called for each activation of a component (including failed ones and the ones waiting for inputs)
+type ComponentActivatedHook func(fm *FMesh, activationResult *component.ActivationResult)
+
+// SignalDeliveredHook is called for signals delivered through a pipe
+type SignalDeliveredHook func(fm *FMesh, delivery SignalDelivery)
+
+// SignalDelivery describes signals moved through one pipe
+type SignalDelivery struct {
+	SourceComponent string
+	SourcePort      string
+	DestComponent   string
+	DestPort        string
+	Signals         signal.Signals
+}
+
+// hooks holds registered hooks, unlike plugins hooks are plain functions, so execution can be observed without defining types
+type hooks struct {
+	cycleStart         []CycleStartHook
+	cycleEnd           []CycleEndHook
+	componentActivated []ComponentActivatedHook
+	signalDelivered    []SignalDeliveredHook
+}
+
+// OnCycleStart registers the hook called when a cycle starts (not called with EventEngine, which has no cycles)
+func (fm *FMesh) OnCycleStart(hook CycleStartHook) *FMesh {
+	fm.hooks.cycleStart = append(fm.hooks.cycleStart, hook)
+	return fm
+}
+
+// OnCycleEnd registers the hook called when a cycle is over (not called with EventEngine, which has no cycles)
+func (fm *FMesh) OnCycleEnd(hook CycleEndHook) *FMesh {
+	fm.hooks.cycleEnd = append(fm.hooks.cycleEnd, hook)
+	return fm
+}
+
+// OnComponentActivated registers the hook called after each activation.
+// With CycleEngine hooks are called in the order of components after the cycle, with EventEngine they are called concurrently
+func (fm *FMesh) OnComponentActivated(hook ComponentActivatedHook) *FMesh {
+	fm.hooks.componentActivated = append(fm.hooks.componentActivated, hook)
+	return fm
+}
+
+// OnSignalDelivered registers the hook called for signals delivered through each pipe.
+// With CycleEngine hooks are called after the cycle is drained, with EventEngine they are called concurrently before signals are delivered
+func (fm *FMesh) OnSignalDelivered(hook SignalDeliveredHook) *FMesh {
+	fm.hooks.signalDelivered = append(fm.hooks.signalDelivered, hook)
+	return fm
+}
+
+// notifyCycleStart calls cycle start hooks
+func (fm *FMesh) notifyCycleStart(cycleNumber int) {
+	for _, hook := range fm.hooks.cycleStart {
+		hook(fm, cycleNumber)
+	}
+}
+
+// notifyCycleEnd calls cycle end hooks
+func (fm *FMesh) notifyCycleEnd(c *cycle.Cycle) {
+	for _, hook := range fm.hooks.cycleEnd {
+		hook(fm, c)
+	}
+}
+
+// notifyComponentActivated calls component activation hooks
+func (fm *FMesh) notifyComponentActivated(activationResult *component.ActivationResult) {
+	for _, hook := range fm.hooks.componentActivated {
+		hook(fm, activationResult)
+	}
+}
+
+// notifySignalsDelivered calls signal delivery hooks
+func (fm *FMesh) notifySignalsDelivered(deliveries []SignalDelivery) {
+	for _, delivery := range deliveries {
+		for _, hook := range fm.hooks.signalDelivered {
+			hook(fm, delivery)
+		}
+	}
+}
+
+// pendingDeliveries returns deliveries which will happen when the component is flushed (nil when nobody is listening)
+func (fm *FMesh) pendingDeliveries(c *component.Component, t *topology) []SignalDelivery {
+	if len(fm.hooks.signalDelivered) == 0 {
+		return nil
+	}
+
+	var deliveries []SignalDelivery
+	for _, out := range c.Outputs().PortsOrNil() {
+		signals := out.AllSignalsOrNil()
+		if len(signals) == 0 {
+			continue
+		}
+		for _, dest := range out.Pipes().PortsOrNil() {
+			deliveries = append(deliveries, SignalDelivery{
+				SourceComponent: c.Name(),
+				SourcePort:      out.Name(),
+				DestComponent:   t.ownerName(dest),
+				DestPort:        dest.Name(),
+				Signals:         signals,
+			})
+		}
+	}
+	return deliveries
+}
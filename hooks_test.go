@@ -0,0 +1,91 @@
+package fmesh
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/cycle"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+func TestFMesh_Hooks(t *testing.T) {
+	getFM := func(engine Engine) *FMesh {
+		newForwarder := func(name string) *component.Component {
+			return component.New(name).
+				WithInputs("in").
+				WithOutputs("out").
+				WithActivationFunc(func(this *component.Component) error {
+					port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+					return nil
+				})
+		}
+		a, b := newForwarder("a"), newForwarder("b")
+		a.OutputByName("out").PipeTo(b.InputByName("in"))
+		a.InputByName("in").PutSignals(signal.New(1), signal.New(2))
+		return NewWithConfig("fm", &Config{Engine: engine}).WithComponents(a, b)
+	}
+
+	t.Run("cycle engine", func(t *testing.T) {
+		var events []string
+		fm := getFM(CycleEngine).
+			OnCycleStart(func(fm *FMesh, cycleNumber int) {
+				events = append(events, fmt.Sprintf("cycle %d started", cycleNumber))
+			}).
+			OnComponentActivated(func(fm *FMesh, activationResult *component.ActivationResult) {
+				events = append(events, fmt.Sprintf("%s activated", activationResult.ComponentName()))
+			}).
+			OnSignalDelivered(func(fm *FMesh, delivery SignalDelivery) {
+				events = append(events, fmt.Sprintf("%d signals delivered %s.%s -> %s.%s", len(delivery.Signals), delivery.SourceComponent, delivery.SourcePort, delivery.DestComponent, delivery.DestPort))
+			}).
+			OnCycleEnd(func(fm *FMesh, c *cycle.Cycle) {
+				events = append(events, fmt.Sprintf("cycle %d ended", c.Number()))
+			})
+
+		_, err := fm.Run()
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"cycle 1 started",
+			"a activated",
+			"2 signals delivered a.out -> b.in",
+			"cycle 1 ended",
+			"cycle 2 started",
+			"b activated",
+			"cycle 2 ended",
+			"cycle 3 started",
+			"cycle 3 ended",
+		}, events)
+	})
+
+	t.Run("event engine", func(t *testing.T) {
+		var (
+			mu          sync.Mutex
+			activations []string
+			delivered   int
+			cycles      int
+		)
+		fm := getFM(EventEngine).
+			OnCycleStart(func(fm *FMesh, cycleNumber int) {
+				cycles++
+			}).
+			OnComponentActivated(func(fm *FMesh, activationResult *component.ActivationResult) {
+				mu.Lock()
+				defer mu.Unlock()
+				activations = append(activations, activationResult.ComponentName())
+			}).
+			OnSignalDelivered(func(fm *FMesh, delivery SignalDelivery) {
+				mu.Lock()
+				defer mu.Unlock()
+				delivered += len(delivery.Signals)
+			})
+
+		_, err := fm.Run()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"a", "b"}, activations)
+		assert.Equal(t, 2, delivered)
+		assert.Zero(t, cycles)
+	})
+}
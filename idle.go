@@ -0,0 +1,134 @@
+package fmesh
+
+import (
+	"context"
+	"github.com/hovsep/fmesh/clock"
+	"sync"
+)
+
+// awaitInput blocks until a source waiting while the mesh is idle wakes up or, in continuous runs (see RunContinuously),
+// until signals are injected. Returns false when nothing can wake the mesh up anymore, the context is cancelled or the mesh is stopped
+func (fm *FMesh) awaitInput(ctx context.Context) bool {
+	if !fm.continuous {
+		return fm.awaitSources(ctx)
+	}
+
+	for {
+		if fm.injections.hasPending() {
+			return true
+		}
+
+		waitCtx, cancel := context.WithCancel(ctx)
+		go func() {
+			defer cancel()
+			select {
+			case <-fm.injections.notifications():
+			case <-fm.stop.done():
+			case <-waitCtx.Done():
+			}
+		}()
+		if fm.awaitSources(waitCtx) {
+			cancel()
+			return true
+		}
+		// Sources may be exhausted (or there are none), injections are still awaited
+		<-waitCtx.Done()
+
+		if ctx.Err() != nil || fm.stop.isRequested() {
+			return false
+		}
+		// Notification may be left by an injection which is already applied, so pending injections are checked again
+	}
+}
+
+// awaitSources blocks until one of the sources waiting while the mesh is idle wakes up,
+// returns false when there are no such sources, all of them are exhausted, the context is cancelled or the mesh is stopped
+func (fm *FMesh) awaitSources(ctx context.Context) bool {
+	var waiting []int
+	t := fm.compiledTopology()
+	for id, c := range t.components {
+		if c.HasIdleWait() {
+			waiting = append(waiting, id)
+		}
+	}
+	if len(waiting) == 0 {
+		return false
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		cancel   = make(chan struct{})
+		woken    = false
+		wokenMu  sync.Mutex
+		returned = make(chan struct{}, len(waiting))
+	)
+	sim, simulated := fm.Clock().(*clock.Simulation)
+	var mark uint64
+	if simulated {
+		mark = sim.Mark()
+	}
+	for _, id := range waiting {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			if !t.components[id].IdleWait(cancel) {
+				returned <- struct{}{}
+				return
+			}
+
+			wokenMu.Lock()
+			woken = true
+			wokenMu.Unlock()
+			// The first woken source cancels waiting of others
+			once.Do(func() {
+				close(cancel)
+			})
+		}(id)
+	}
+
+	// Cancelled or stopped run stops waiting as well
+	waitersDone := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-fm.stop.done():
+		case <-waitersDone:
+			return
+		}
+		once.Do(func() {
+			close(cancel)
+		})
+	}()
+
+	if simulated {
+		go skipIdleTime(sim, mark, len(waiting), returned, cancel)
+	}
+
+	wg.Wait()
+	close(waitersDone)
+
+	// Sources are evaluated in every cycle, so the woken one activates in the next cycle
+	return woken
+}
+
+// skipIdleTime moves the simulation clock to the nearest deadline once all idle sources either wait for the clock or gave up,
+// so the mesh does not wait for simulated time to pass
+func skipIdleTime(sim *clock.Simulation, mark uint64, sources int, returned <-chan struct{}, cancel <-chan struct{}) {
+	gaveUp := 0
+	for {
+		changed := sim.Changed()
+		if sim.WaitingSince(mark)+gaveUp >= sources {
+			sim.AdvanceToNext(mark)
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-returned:
+			gaveUp++
+		case <-cancel:
+			return
+		}
+	}
+}
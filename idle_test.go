@@ -0,0 +1,55 @@
+package fmesh
+
+import (
+	"context"
+	"github.com/hovsep/fmesh/component"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFMesh_awaitSources(t *testing.T) {
+	// newWaitingSource returns a source whose idle wait returns the value received from the channel
+	newWaitingSource := func(name string, wake <-chan bool) *component.Component {
+		return component.New(name).
+			WithReadinessFunc(func(this *component.Component) bool {
+				return false
+			}).
+			WithIdleWaitFunc(func(this *component.Component, cancel <-chan struct{}) bool {
+				select {
+				case woken := <-wake:
+					return woken
+				case <-cancel:
+					return false
+				}
+			}).
+			WithActivationFunc(func(this *component.Component) error {
+				return nil
+			})
+	}
+
+	t.Run("no waiting sources", func(t *testing.T) {
+		fm := New("fm").WithComponents(component.New("c"))
+		assert.False(t, fm.awaitSources(context.Background()))
+	})
+
+	t.Run("exhausted sources", func(t *testing.T) {
+		wake := make(chan bool, 2)
+		wake <- false
+		wake <- false
+		fm := New("fm").WithComponents(newWaitingSource("s1", wake), newWaitingSource("s2", wake))
+		assert.False(t, fm.awaitSources(context.Background()))
+	})
+
+	t.Run("first woken source cancels others", func(t *testing.T) {
+		wake := make(chan bool)
+		never := make(chan bool)
+		fm := New("fm").WithComponents(newWaitingSource("s1", wake), newWaitingSource("s2", never))
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			wake <- true
+		}()
+		assert.True(t, fm.awaitSources(context.Background()))
+	})
+}
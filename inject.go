@@ -0,0 +1,116 @@
+package fmesh
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"slices"
+	"sync"
+)
+
+// injection is a group of signals waiting to be put on an input port
+type injection struct {
+	componentName string
+	port          *port.Port
+	signals       signal.Signals
+}
+
+// injectionQueue holds injections made from other goroutines until the next activation cycle
+type injectionQueue struct {
+	mu      sync.Mutex
+	pending []injection
+	// arrived receives a notification when an injection is queued (used by EventEngine, which has no cycles to pick injections up)
+	arrived chan struct{}
+}
+
+// push queues the injection
+func (q *injectionQueue) push(i injection) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, i)
+	select {
+	case q.arrivals() <- struct{}{}:
+	default:
+	}
+}
+
+// arrivals returns the channel notified when injections are queued, it must be called with the lock held
+func (q *injectionQueue) arrivals() chan struct{} {
+	if q.arrived == nil {
+		q.arrived = make(chan struct{}, 1)
+	}
+	return q.arrived
+}
+
+// notifications returns the channel notified when injections are queued
+func (q *injectionQueue) notifications() <-chan struct{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.arrivals()
+}
+
+// takeAll removes and returns all queued injections
+func (q *injectionQueue) takeAll() []injection {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	pending := q.pending
+	q.pending = nil
+	return pending
+}
+
+// targets returns names of components with pending injections
+func (q *injectionQueue) targets() map[string]bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	targets := make(map[string]bool, len(q.pending))
+	for _, i := range q.pending {
+		targets[i.componentName] = true
+	}
+	return targets
+}
+
+// hasPending says whether there are queued injections
+func (q *injectionQueue) hasPending() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending) > 0
+}
+
+// Inject queues signals for the given input port, they are put on the port at the start of the next activation cycle
+// (or the first cycle of the next run when the mesh is not running).
+// It is safe to call from other goroutines while the mesh runs, a run does not stop while injected signals are pending
+func (fm *FMesh) Inject(componentName string, portName string, signals ...*signal.Signal) error {
+	c, ok := fm.Components().ComponentsOrNil()[componentName]
+	if !ok {
+		return fmt.Errorf("%w: component %s not found", ErrInjectionTargetNotFound, componentName)
+	}
+
+	p, ok := c.Inputs().PortsOrNil()[portName]
+	if !ok {
+		return fmt.Errorf("%w: component %s has no input port %s", ErrInjectionTargetNotFound, componentName, portName)
+	}
+
+	for _, sig := range signals {
+		if sig == nil {
+			return signal.ErrInvalidSignal
+		}
+	}
+
+	fm.injections.push(injection{
+		componentName: componentName,
+		port:          p,
+		signals:       slices.Clone(signals),
+	})
+	return nil
+}
+
+// applyInjections puts all queued signals on their ports and schedules the receiving components
+func (fm *FMesh) applyInjections(t *topology) {
+	for _, i := range fm.injections.takeAll() {
+		i.port.PutSignals(i.signals...)
+		if id, ok := t.ids[i.componentName]; ok {
+			t.scheduled[id] = true
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestFMesh_Inject(t *testing.T) {
+	getFM := func() *FMesh {
+		return NewWithConfig("fm", &Config{
+			CyclesLimit: UnlimitedCycles,
+		}).WithComponents(
+			component.New("collector").
+				WithInputs("in").
+				WithOutputs("out").
+				WithActivationFunc(func(this *component.Component) error {
+					this.OutputByName("out").WithSignalGroups(this.InputByName("in").Buffer())
+					return nil
+				}),
+		)
+	}
+
+	t.Run("unknown targets", func(t *testing.T) {
+		fm := getFM()
+		assert.ErrorIs(t, fm.Inject("unknown", "in", signal.New(1)), ErrInjectionTargetNotFound)
+		assert.ErrorIs(t, fm.Inject("collector", "out", signal.New(1)), ErrInjectionTargetNotFound)
+		assert.ErrorIs(t, fm.Inject("collector", "in", nil), signal.ErrInvalidSignal)
+	})
+
+	t.Run("signals injected before run are delivered in the first cycle", func(t *testing.T) {
+		fm := getFM()
+		assert.NoError(t, fm.Inject("collector", "in", signal.New(1), signal.New(2)))
+		assert.False(t, fm.ComponentByName("collector").InputByName("in").HasSignals(), "signals must be queued until the next cycle")
+
+		cycles, err := fm.Run()
+		assert.NoError(t, err)
+		assert.True(t, cycles[0].ActivationResults().ByComponentName("collector").Activated())
+		assert.Equal(t, 2, fm.ComponentByName("collector").OutputByName("out").Buffer().Len())
+	})
+
+	t.Run("signals injected concurrently during run", func(t *testing.T) {
+		started, injected := make(chan struct{}), make(chan struct{})
+		fm := getFM()
+		gate := component.New("gate").
+			WithInputs("in").
+			WithActivationFunc(func(this *component.Component) error {
+				close(started)
+				<-injected
+				return nil
+			})
+		fm.WithComponents(gate)
+		gate.InputByName("in").PutSignals(signal.New("open"))
+
+		const injectors = 10
+		go func() {
+			<-started
+			var wg sync.WaitGroup
+			for i := 0; i < injectors; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					assert.NoError(t, fm.Inject("collector", "in", signal.New(i)))
+				}(i)
+			}
+			wg.Wait()
+			close(injected)
+		}()
+
+		cycles, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Len(t, cycles, 3, "gate, collector, natural stop")
+		assert.Equal(t, injectors, fm.ComponentByName("collector").OutputByName("out").Buffer().Len())
+	})
+}
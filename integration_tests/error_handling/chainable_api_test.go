@@ -98,7 +98,7 @@ func Test_FMesh(t *testing.T) {
 				_, err := fm.Run()
 				assert.True(t, fm.HasErr())
 				assert.Error(t, err)
-				assert.EqualError(t, err, "some error in component")
+				assert.EqualError(t, err, "component c1: some error in component")
 			},
 		},
 		{
@@ -0,0 +1,69 @@
+package piping
+
+import (
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"sync"
+	"testing"
+)
+
+func Test_BatchStreaming(t *testing.T) {
+	const total = 1000
+	next, inFlight, maxInFlight := 0, 0, 0
+	// Source and sink activate concurrently
+	var mu sync.Mutex
+
+	// Dataset is generated on the fly, as if it was read from a cursor
+	source := component.NewBatchSource("rows", func(limit int) ([]int, error) {
+		batch := make([]int, 0, limit)
+		for ; len(batch) < limit && next < total; next++ {
+			batch = append(batch, next)
+		}
+		mu.Lock()
+		inFlight += len(batch)
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		if next == total {
+			return batch, io.EOF
+		}
+		return batch, nil
+	}, component.BatchSourceConfig{BatchSize: 10, MaxInFlight: 25})
+
+	double := component.New("double").
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("in").AllSignalsOrNil() {
+				this.OutputByName("out").PutSignals(signal.New(sig.PayloadOrNil().(int) * 2))
+			}
+			return nil
+		})
+
+	sum := 0
+	sink := component.NewBatchSink("table", func(records []int) error {
+		for _, record := range records {
+			sum += record
+		}
+		mu.Lock()
+		inFlight -= len(records)
+		mu.Unlock()
+		return nil
+	}, 8)
+
+	source.OutputByName(component.BatchSourceOutput).PipeTo(double.InputByName("in"))
+	double.OutputByName("out").PipeTo(sink.InputByName(component.BatchSinkInput))
+	sink.OutputByName(component.BatchSinkAck).PipeTo(source.InputByName(component.BatchSourceAck))
+
+	fm := fmesh.NewWithConfig("etl", &fmesh.Config{
+		CyclesLimit: fmesh.UnlimitedCycles,
+	}).WithComponents(source, double, sink)
+
+	_, err := fm.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, total*(total-1), sum)
+	assert.Equal(t, 0, inFlight)
+	assert.LessOrEqual(t, maxInFlight, 25)
+}
@@ -0,0 +1,53 @@
+package piping
+
+import (
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_ChannelBridge(t *testing.T) {
+	numbers := make(chan int, 10)
+	squares := make(chan int, 10)
+
+	// Existing goroutine pipeline stage producing numbers
+	go func() {
+		defer close(numbers)
+		for i := 1; i <= 5; i++ {
+			numbers <- i
+		}
+	}()
+
+	source := component.NewChannelSource("numbers", numbers)
+	square := component.New("square").
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("in").AllSignalsOrNil() {
+				n := sig.PayloadOrNil().(int)
+				this.OutputByName("out").PutSignals(signal.New(n * n))
+			}
+			return nil
+		})
+	sink := component.NewChannelSink("squares", squares)
+
+	source.OutputByName(component.ChannelSourceOutput).PipeTo(square.InputByName("in"))
+	square.OutputByName("out").PipeTo(sink.InputByName(component.ChannelSinkInput))
+
+	fm := fmesh.NewWithConfig("channel bridge", &fmesh.Config{
+		CyclesLimit: fmesh.UnlimitedCycles,
+	}).WithComponents(source, square, sink)
+
+	// The mesh stops once the channel is drained, so run it until all numbers are consumed
+	got := make([]int, 0)
+	for len(got) < 5 {
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		for len(squares) > 0 {
+			got = append(got, <-squares)
+		}
+	}
+	assert.Equal(t, []int{1, 4, 9, 16, 25}, got)
+}
@@ -0,0 +1,46 @@
+package piping
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_LoadBalancer(t *testing.T) {
+	processed := make(map[string]int)
+	newWorker := func(index int) *component.Component {
+		return component.New(fmt.Sprintf("worker-%d", index)).
+			WithInputs(component.WorkerInput).
+			WithOutputs(component.WorkerOutput).
+			WithActivationFunc(func(this *component.Component) error {
+				processed[this.Name()] += this.InputByName(component.WorkerInput).Buffer().Len()
+				return port.ForwardSignals(this.InputByName(component.WorkerInput), this.OutputByName(component.WorkerOutput))
+			})
+	}
+
+	sink := component.New("sink").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+		return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+	})
+	group := component.NewLoadBalancer("lb", newWorker, 3, component.BalanceRoundRobin)
+	group[0].OutputByName(component.LoadBalancerOutput).PipeTo(sink.InputByName("in"))
+
+	fm := fmesh.NewWithConfig("load-balancer", &fmesh.Config{
+		ExecutionStrategy: fmesh.WorkerPool,
+		Workers:           1,
+		CyclesLimit:       10,
+	}).WithComponents(group...).WithComponents(sink)
+	group[0].InputByName(component.LoadBalancerInput).PutSignals(signal.NewGroup(1, 2, 3, 4, 5, 6).SignalsOrNil()...)
+
+	_, err := fm.Run()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"worker-1": 2, "worker-2": 2, "worker-3": 2}, processed)
+
+	results, err := sink.OutputByName("out").AllSignalsPayloads()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []any{1, 2, 3, 4, 5, 6}, results)
+}
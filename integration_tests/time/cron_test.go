@@ -0,0 +1,58 @@
+package time
+
+import (
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/clock"
+	"github.com/hovsep/fmesh/component"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_CronSource(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewVirtual(start)
+
+	var (
+		mu       sync.Mutex
+		triggers []any
+	)
+	cron := component.NewCronSource("nightly", "@daily")
+	etl := component.New("etl").
+		WithInputs("trigger").
+		WithActivationFunc(func(this *component.Component) error {
+			mu.Lock()
+			defer mu.Unlock()
+			triggers = append(triggers, this.InputByName("trigger").FirstSignalPayloadOrNil())
+			return nil
+		})
+	cron.OutputByName(component.CronSourceOutput).PipeTo(etl.InputByName("trigger"))
+
+	// Each firing takes 3 cycles: cron activates, etl activates, mesh gets idle and waits for the next firing
+	fm := fmesh.NewWithConfig("nightly etl", &fmesh.Config{
+		CyclesLimit: 9,
+		Clock:       clk,
+	}).WithComponents(cron, etl)
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			// Wait until the idle mesh waits for the next firing
+			for clk.Pending() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			clk.Advance(24 * time.Hour)
+		}
+	}()
+
+	_, err := fm.Run()
+	assert.ErrorIs(t, err, fmesh.ErrReachedMaxAllowedCycles)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []any{
+		start.AddDate(0, 0, 1),
+		start.AddDate(0, 0, 2),
+		start.AddDate(0, 0, 3),
+	}, triggers)
+}
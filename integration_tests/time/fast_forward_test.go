@@ -0,0 +1,129 @@
+package time
+
+import (
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/clock"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/hovsep/fmesh/testkit"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func Test_FastForward(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		setupFM    func(clk clock.Clock) *fmesh.FMesh
+		total      time.Duration
+		step       time.Duration
+		beforeRun  func(fm *fmesh.FMesh, now time.Time)
+		assertions func(t *testing.T, fm *fmesh.FMesh, err error)
+	}{
+		{
+			name: "hourly window fires 24 times a day",
+			setupFM: func(clk clock.Clock) *fmesh.FMesh {
+				return fmesh.NewWithConfig("aggregator", &fmesh.Config{
+					CyclesLimit: fmesh.UnlimitedCycles,
+					Clock:       clk,
+				}).WithComponents(
+					component.New("hourly sum").
+						WithInputs("measurement").
+						WithOutputs("sum").
+						WithInitialState(func(state component.State) {
+							state.Set("window_start", clk.Now())
+							state.Set("sum", 0)
+						}).
+						WithActivationFunc(func(this *component.Component) error {
+							sum := this.State().Get("sum").(int)
+							for _, p := range this.InputByName("measurement").AllSignalsOrNil() {
+								sum += p.PayloadOrDefault(0).(int)
+							}
+
+							windowStart := this.State().Get("window_start").(time.Time)
+							if this.Clock().Since(windowStart) >= time.Hour {
+								this.OutputByName("sum").PutSignals(signal.New(sum))
+								this.State().Set("window_start", this.Clock().Now())
+								sum = 0
+							}
+							this.State().Set("sum", sum)
+							return nil
+						}),
+				)
+			},
+			total: 24 * time.Hour,
+			step:  time.Minute,
+			beforeRun: func(fm *fmesh.FMesh, now time.Time) {
+				fm.ComponentByName("hourly sum").InputByName("measurement").PutSignals(signal.New(1))
+			},
+			assertions: func(t *testing.T, fm *fmesh.FMesh, err error) {
+				assert.NoError(t, err)
+				sums, err := fm.ComponentByName("hourly sum").OutputByName("sum").AllSignalsPayloads()
+				assert.NoError(t, err)
+				assert.Len(t, sums, 24)
+				for _, sum := range sums {
+					assert.Equal(t, 60, sum)
+				}
+			},
+		},
+		{
+			name: "ttl cache evicts expired entries",
+			setupFM: func(clk clock.Clock) *fmesh.FMesh {
+				return fmesh.NewWithConfig("cache", &fmesh.Config{
+					CyclesLimit: fmesh.UnlimitedCycles,
+					Clock:       clk,
+				}).WithComponents(
+					component.New("ttl cache").
+						WithInputs("set", "tick").
+						WithOutputs("evicted").
+						WithActivationFunc(func(this *component.Component) error {
+							now := this.Clock().Now()
+							for _, key := range this.InputByName("set").AllSignalsOrNil() {
+								this.State().Set(key.PayloadOrNil().(string), now.Add(90*time.Minute))
+							}
+
+							for key, expiresAt := range this.State() {
+								if !now.Before(expiresAt.(time.Time)) {
+									this.State().Delete(key)
+									this.OutputByName("evicted").PutSignals(signal.New(key))
+								}
+							}
+							return nil
+						}),
+				)
+			},
+			total: 2 * time.Hour,
+			step:  15 * time.Minute,
+			beforeRun: func(fm *fmesh.FMesh, now time.Time) {
+				c := fm.ComponentByName("ttl cache")
+				c.InputByName("tick").PutSignals(signal.New(now))
+				if now.Minute() == 15 && now.Hour() == 0 {
+					c.InputByName("set").PutSignals(signal.New("session-1"))
+				}
+				if now.Hour() == 1 && now.Minute() == 0 {
+					c.InputByName("set").PutSignals(signal.New("session-2"))
+				}
+			},
+			assertions: func(t *testing.T, fm *fmesh.FMesh, err error) {
+				assert.NoError(t, err)
+				c := fm.ComponentByName("ttl cache")
+				evicted, err := c.OutputByName("evicted").AllSignalsPayloads()
+				assert.NoError(t, err)
+				assert.Equal(t, []any{"session-1"}, evicted)
+				assert.True(t, c.State().Has("session-2"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewVirtual(start)
+			fm := tt.setupFM(clk)
+			err := testkit.FastForward(fm, clk, tt.total, tt.step, func(now time.Time) {
+				tt.beforeRun(fm, now)
+			})
+			tt.assertions(t, fm, err)
+		})
+	}
+}
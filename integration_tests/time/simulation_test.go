@@ -0,0 +1,97 @@
+package time
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/clock"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func Test_SimulatedTime(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// run simulates a week of hourly measurements, each cycle takes a minute,
+	// so a measurement is processed 3 minutes after the firing (the idle cycle, sensor and filter cycles)
+	run := func() ([]time.Time, *clock.Simulation) {
+		clk := clock.NewSimulation(start, time.Minute)
+
+		var processedAt []time.Time
+		sensor := component.NewCronSource("sensor", "@hourly")
+		filter := component.New("filter").
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				this.OutputByName("out").PutSignals(signal.New(this.Clock().Now()))
+				return nil
+			})
+		recorder := component.New("recorder").
+			WithInputs("in").
+			WithActivationFunc(func(this *component.Component) error {
+				processedAt = append(processedAt, this.Clock().Now())
+				return nil
+			})
+		sensor.OutputByName(component.CronSourceOutput).PipeTo(filter.InputByName("in"))
+		filter.OutputByName("out").PipeTo(recorder.InputByName("in"))
+
+		// Each measurement takes 4 cycles: sensor, filter, recorder and the idle one
+		fm := fmesh.NewWithConfig("body", &fmesh.Config{
+			CyclesLimit: 7 * 24 * 4,
+			Clock:       clk,
+		}).WithComponents(sensor, filter, recorder)
+
+		_, err := fm.Run()
+		assert.ErrorIs(t, err, fmesh.ErrReachedMaxAllowedCycles)
+		return processedAt, clk
+	}
+
+	processedAt, clk := run()
+	if assert.Len(t, processedAt, 7*24) {
+		assert.Equal(t, start.Add(time.Hour+3*time.Minute), processedAt[0])
+		assert.Equal(t, start.Add(2*time.Hour+3*time.Minute), processedAt[1])
+	}
+	assert.Equal(t, int64(7*24*4), clk.Ticks())
+
+	again, _ := run()
+	assert.Equal(t, processedAt, again, "simulation must be deterministic")
+}
+
+func Test_SimulatedRetries(t *testing.T) {
+	attempts := 0
+	flaky := component.New("flaky").
+		WithInputs("in").
+		WithOutputs("out").
+		WithRetryPolicy(3, component.ExponentialBackoff(time.Minute, time.Hour)).
+		WithActivationFunc(func(this *component.Component) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("temporary failure")
+			}
+			return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+		})
+
+	fm := fmesh.NewWithConfig("retries", &fmesh.Config{
+		Clock: clock.NewSimulation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Second),
+	}).WithComponents(flaky)
+	flaky.InputByName("in").PutSignals(signal.New(1))
+
+	done := make(chan error)
+	go func() {
+		_, err := fm.Run()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+		payloads, err := flaky.OutputByName("out").AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1}, payloads)
+	case <-time.After(5 * time.Second):
+		t.Fatal("run is blocked by the retry backoff")
+	}
+}
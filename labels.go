@@ -0,0 +1,134 @@
+package fmesh
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"maps"
+	"strconv"
+)
+
+// WithLabels sets labels of the mesh, with Config.InheritLabels they are inherited by all components
+func (fm *FMesh) WithLabels(labels common.LabelsCollection) *FMesh {
+	if fm.HasErr() {
+		return fm
+	}
+
+	if err := common.ValidateLabels(labels); err != nil {
+		return fm.WithErr(err)
+	}
+
+	fm.LabeledEntity.SetLabels(labels)
+	return fm
+}
+
+// validateLabels makes sure system labels are not clobbered by user code:
+// mesh and components must not have system labels, ports must keep the direction label matching their collection
+func (fm *FMesh) validateLabels() error {
+	if err := common.ValidateLabels(fm.Labels()); err != nil {
+		return err
+	}
+
+	for _, c := range fm.Components().ComponentsOrNil() {
+		if err := common.ValidateLabels(userLabels(c)); err != nil {
+			return fmt.Errorf("component %s: %w", c.Name(), err)
+		}
+		if err := validatePortLabels(c.Inputs().PortsOrNil(), port.DirectionIn); err != nil {
+			return fmt.Errorf("component %s: %w", c.Name(), err)
+		}
+		if err := validatePortLabels(c.Outputs().PortsOrNil(), port.DirectionOut); err != nil {
+			return fmt.Errorf("component %s: %w", c.Name(), err)
+		}
+	}
+	return nil
+}
+
+// userLabels returns labels of the component without the ones set by the mesh itself (see DegradeFailing)
+func userLabels(c *component.Component) common.LabelsCollection {
+	if !c.HasLabel(component.DegradedLabel) {
+		return c.Labels()
+	}
+	labels := maps.Clone(c.Labels())
+	delete(labels, component.DegradedLabel)
+	return labels
+}
+
+// validatePortLabels checks that the direction label is the only system label of the ports and it is intact
+func validatePortLabels(ports port.PortMap, direction string) error {
+	for _, p := range ports {
+		// Labels are read in place as ports share them (see port.Collection), this runs on every run
+		if p.LabelOrDefault(port.DirectionLabel, "") != direction {
+			return fmt.Errorf("port %s: %w: %s must be %q", p.Name(), common.ErrReservedLabel, port.DirectionLabel, direction)
+		}
+
+		for label := range p.AllLabels() {
+			if common.IsSystemLabel(label) && label != port.DirectionLabel {
+				return fmt.Errorf("port %s: %w: %s", p.Name(), common.ErrReservedLabel, label)
+			}
+		}
+	}
+	return nil
+}
+
+// inheritLabels passes mesh labels down to components and component labels down to their ports,
+// own labels of each entity override inherited ones
+func (fm *FMesh) inheritLabels() {
+	if !fm.config.InheritLabels {
+		return
+	}
+
+	for _, c := range fm.Components().ComponentsOrNil() {
+		c.InheritLabels(fm.Labels())
+		for _, p := range c.Inputs().PortsOrNil() {
+			p.InheritLabelsOf(&c.LabeledEntity)
+		}
+		for _, p := range c.Outputs().PortsOrNil() {
+			p.InheritLabelsOf(&c.LabeledEntity)
+		}
+	}
+}
+
+// stampSignalLabels stamps the signals about to be flushed: signals leaving through pipes get origin system labels
+// (error envelopes get their origin too), and labels of output ports are passed down to them when inheritance is enabled.
+// Signals may be shared between components, so it must not run concurrently
+func (fm *FMesh) stampSignalLabels(ids []int) {
+	t := fm.compiledTopology()
+	// Formatted once, as signals of all components share the cycle
+	cycle := fm.cycles.Last().Number()
+	cycleNumber := strconv.Itoa(cycle)
+	for _, id := range ids {
+		fm.stampComponentSignals(t.components[id], cycle, cycleNumber)
+	}
+}
+
+// stampComponentSignals stamps the signals on output ports of the component, cycle 0 means the signals were not emitted
+// in an activation cycle (see EventEngine), so they get no cycle label
+func (fm *FMesh) stampComponentSignals(c *component.Component, cycle int, cycleNumber string) {
+	for _, p := range c.Outputs().PortsOrNil() {
+		piped := p.HasPipes()
+		if !piped && !fm.config.InheritLabels {
+			continue
+		}
+
+		for _, sig := range p.AllSignalsOrNil() {
+			if fm.config.InheritLabels {
+				sig.InheritLabelsOf(&p.LabeledEntity)
+			}
+			if piped {
+				sig.AddLabel(signal.SourceComponentLabel, c.Name())
+				sig.AddLabel(signal.SourcePortLabel, p.Name())
+				if cycle > 0 {
+					sig.AddLabel(signal.CycleLabel, cycleNumber)
+				}
+				if e, ok := sig.ErrorPayload(); ok {
+					e.SetOrigin(c.Name(), p.Name(), cycle)
+				}
+				if fm.config.TraceSignals {
+					sig.AddHop(c.Name(), p.Name(), cycle)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package fmesh
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFMesh_InheritLabels(t *testing.T) {
+	// newMesh builds producer -> consumer, the consumer collects labels of received signals
+	newMesh := func(inherit bool, received *[]common.LabelsCollection) *FMesh {
+		producer := component.New("producer").
+			WithLabels(common.LabelsCollection{
+				"team": "payments",
+			}).
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				this.OutputByName("out").PutSignals(
+					signal.New(1),
+					signal.New(2).WithLabels(common.LabelsCollection{
+						"env": "dev",
+					}),
+				)
+				return nil
+			})
+		producer.OutputByName("out").AddLabel("stage", "ingest")
+
+		consumer := component.New("consumer").
+			WithInputs("in").
+			WithActivationFunc(func(this *component.Component) error {
+				for _, sig := range this.InputByName("in").AllSignalsOrNil() {
+					*received = append(*received, sig.Labels())
+				}
+				return nil
+			})
+		producer.OutputByName("out").PipeTo(consumer.InputByName("in"))
+
+		fm := NewWithConfig("fm", &Config{
+			CyclesLimit:   10,
+			InheritLabels: inherit,
+		}).
+			WithLabels(common.LabelsCollection{
+				"env":  "staging",
+				"team": "core",
+			}).
+			WithComponents(producer, consumer)
+		producer.InputByName("in").PutSignals(signal.New("start"))
+		return fm
+	}
+
+	t.Run("labels are passed down with override semantics", func(t *testing.T) {
+		var received []common.LabelsCollection
+		fm := newMesh(true, &received)
+
+		_, err := fm.Run()
+		assert.NoError(t, err)
+
+		producer := fm.ComponentByName("producer")
+		assert.Equal(t, common.LabelsCollection{
+			"env":  "staging",
+			"team": "payments",
+		}, producer.Labels())
+		assert.Equal(t, "payments", producer.InputByName("in").LabelOrDefault("team", ""))
+		assert.Equal(t, port.DirectionIn, producer.InputByName("in").LabelOrDefault(port.DirectionLabel, ""))
+		assert.Equal(t, common.LabelsCollection{
+			port.DirectionLabel: port.DirectionOut,
+			"env":               "staging",
+			"team":              "payments",
+			"stage":             "ingest",
+		}, producer.OutputByName("out").Labels())
+		assert.Equal(t, common.LabelsCollection{
+			"env":  "staging",
+			"team": "core",
+		}, fm.ComponentByName("consumer").Labels())
+
+		assert.Equal(t, []common.LabelsCollection{
+			{
+				"env":                       "staging",
+				"team":                      "payments",
+				"stage":                     "ingest",
+				signal.SourceComponentLabel: "producer",
+				signal.SourcePortLabel:      "out",
+				signal.CycleLabel:           "1",
+			},
+			{
+				"env":                       "dev",
+				"team":                      "payments",
+				"stage":                     "ingest",
+				signal.SourceComponentLabel: "producer",
+				signal.SourcePortLabel:      "out",
+				signal.CycleLabel:           "1",
+			},
+		}, received)
+	})
+
+	t.Run("labels are not inherited by default", func(t *testing.T) {
+		var received []common.LabelsCollection
+		fm := newMesh(false, &received)
+
+		_, err := fm.Run()
+		assert.NoError(t, err)
+
+		assert.Equal(t, common.LabelsCollection{
+			"team": "payments",
+		}, fm.ComponentByName("producer").Labels())
+		assert.Equal(t, []common.LabelsCollection{
+			{
+				signal.SourceComponentLabel: "producer",
+				signal.SourcePortLabel:      "out",
+				signal.CycleLabel:           "1",
+			},
+			{
+				"env":                       "dev",
+				signal.SourceComponentLabel: "producer",
+				signal.SourcePortLabel:      "out",
+				signal.CycleLabel:           "1",
+			},
+		}, received)
+	})
+}
+
+func TestFMesh_OriginLabels(t *testing.T) {
+	t.Run("signals are stamped with the last hop", func(t *testing.T) {
+		var received *signal.Signal
+		relay := component.New("relay").
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+			})
+		sink := component.New("sink").
+			WithInputs("in").
+			WithOutputs("unpiped").
+			WithActivationFunc(func(this *component.Component) error {
+				received = this.InputByName("in").Buffer().First()
+				return port.ForwardSignals(this.InputByName("in"), this.OutputByName("unpiped"))
+			})
+		producer := component.New("producer").
+			WithInputs("in").
+			WithOutputs("result").
+			WithActivationFunc(func(this *component.Component) error {
+				this.OutputByName("result").PutSignals(signal.New(42))
+				return nil
+			})
+		producer.OutputByName("result").PipeTo(relay.InputByName("in"))
+		relay.OutputByName("out").PipeTo(sink.InputByName("in"))
+
+		fm := New("fm").WithComponents(producer, relay, sink)
+		producer.InputByName("in").PutSignals(signal.New("start"))
+
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		if assert.NotNil(t, received) {
+			assert.Equal(t, "relay", received.SourceComponent())
+			assert.Equal(t, "out", received.SourcePort())
+			assert.Equal(t, 2, received.SourceCycle())
+		}
+
+		// Signals which are not piped keep the origin of their last hop
+		unpiped := sink.OutputByName("unpiped").Buffer().First()
+		assert.Equal(t, "relay", unpiped.SourceComponent())
+	})
+
+	t.Run("error envelopes keep the origin of the first hop", func(t *testing.T) {
+		var received *signal.Signal
+		relay := component.New("relay").
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+			})
+		dlq := component.New("dlq").
+			WithInputs("in").
+			WithActivationFunc(func(this *component.Component) error {
+				received = this.InputByName("in").Buffer().First()
+				return nil
+			})
+		producer := component.New("producer").
+			WithInputs("in").
+			WithOutputs("errors").
+			WithActivationFunc(func(this *component.Component) error {
+				this.OutputByName("errors").PutSignals(signal.NewError(errors.New("boom")))
+				return nil
+			})
+		producer.OutputByName("errors").PipeTo(relay.InputByName("in"))
+		relay.OutputByName("out").PipeTo(dlq.InputByName("in"))
+
+		fm := New("fm").WithComponents(producer, relay, dlq)
+		producer.InputByName("in").PutSignals(signal.New("start"))
+
+		_, err := fm.Run()
+		assert.NoError(t, err)
+
+		if assert.NotNil(t, received) {
+			assert.Equal(t, "relay", received.SourceComponent())
+			assert.EqualError(t, received.ErrorOrNil(), "producer.errors (cycle 1): boom")
+		}
+	})
+}
+
+func TestFMesh_TraceSignals(t *testing.T) {
+	newRelay := func(name string) *component.Component {
+		return component.New(name).
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+			})
+	}
+
+	for _, engine := range []Engine{CycleEngine, EventEngine} {
+		a, b, c := newRelay("a"), newRelay("b"), newRelay("c")
+		a.OutputByName("out").PipeTo(b.InputByName("in"))
+		b.OutputByName("out").PipeTo(c.InputByName("in"))
+		a.InputByName("in").PutSignals(signal.New(1))
+
+		fm := NewWithConfig("fm", &Config{TraceSignals: true, Engine: engine}).WithComponents(a, b, c)
+		_, err := fm.Run()
+		require.NoError(t, err)
+
+		trace := c.OutputByName("out").Buffer().First().Trace()
+		assert.NotEmpty(t, trace.ID)
+		if engine == CycleEngine {
+			assert.Equal(t, "a.out@1 -> b.out@2", trace.String())
+		} else {
+			assert.Equal(t, "a.out -> b.out", trace.String())
+		}
+	}
+
+	t.Run("tracing is disabled by default", func(t *testing.T) {
+		a, b := newRelay("a"), newRelay("b")
+		a.OutputByName("out").PipeTo(b.InputByName("in"))
+		a.InputByName("in").PutSignals(signal.New(1))
+
+		_, err := New("fm").WithComponents(a, b).Run()
+		require.NoError(t, err)
+		assert.Empty(t, b.OutputByName("out").Buffer().First().Trace().Hops)
+	})
+}
+
+func TestFMesh_validateLabels(t *testing.T) {
+	newComponent := func() *component.Component {
+		return component.New("c1").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			return nil
+		})
+	}
+
+	tests := []struct {
+		name    string
+		fm      func() *FMesh
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "user labels",
+			fm: func() *FMesh {
+				c := newComponent()
+				c.AddLabel("env", "staging")
+				c.OutputByName("out").AddLabel("role", "log-out")
+				return New("fm").WithComponents(c)
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "system label added to component",
+			fm: func() *FMesh {
+				c := newComponent()
+				c.AddLabel(signal.CycleLabel, "1")
+				return New("fm").WithComponents(c)
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, common.ErrReservedLabel)
+			},
+		},
+		{
+			name: "direction label is lost",
+			fm: func() *FMesh {
+				c := newComponent()
+				c.OutputByName("out").WithLabels(common.LabelsCollection{"role": "log-out"})
+				return New("fm").WithComponents(c)
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, common.ErrReservedLabel) && assert.ErrorContains(t, err, "port out")
+			},
+		},
+		{
+			name: "direction label is clobbered",
+			fm: func() *FMesh {
+				c := newComponent()
+				c.InputByName("in").AddLabel(port.DirectionLabel, port.DirectionOut)
+				return New("fm").WithComponents(c)
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, common.ErrReservedLabel) && assert.ErrorContains(t, err, "port in")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm := tt.fm()
+			tt.wantErr(t, fm.validateLabels())
+
+			// Run refuses to start with invalid labels
+			_, err := fm.Run()
+			tt.wantErr(t, err)
+		})
+	}
+
+	t.Run("mesh labels", func(t *testing.T) {
+		fm := New("fm").WithLabels(common.LabelsCollection{port.DirectionLabel: port.DirectionIn})
+		assert.ErrorIs(t, fm.Err(), common.ErrReservedLabel)
+	})
+}
@@ -1,8 +1,12 @@
 package fmesh
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"sync/atomic"
 )
 
 // Logger getter
@@ -17,16 +21,68 @@ func getDefaultLogger() *log.Logger {
 	return logger
 }
 
+// Slog returns the structured logger of the mesh: Config.Slog (slog.Default() when not set) with the "mesh" attribute.
+// Records are enriched with the "cycle" attribute holding the number of the current activation cycle (not with EventEngine, which has no cycles),
+// loggers of components are derived from it (see component.Slog)
+func (fm *FMesh) Slog() *slog.Logger {
+	if fm.slog == nil {
+		root := fm.config.Slog
+		if root == nil {
+			root = slog.Default()
+		}
+		fm.slog = slog.New(&cycleHandler{
+			Handler: root.Handler(),
+			cycle:   &fm.currentCycle,
+		}).With("mesh", fm.Name())
+	}
+	return fm.slog
+}
+
 // IsDebug returns true when debug mode is enabled
 func (fm *FMesh) IsDebug() bool {
 	return fm.config.Debug
 }
 
-// LogDebug logs a debug message only when debug mode is enabled (no-op otherwise)
+// LogDebug logs a debug message only when debug mode is enabled (no-op otherwise),
+// with Config.Slog the message is logged at debug level by the structured logger
 func (fm *FMesh) LogDebug(v ...any) {
 	if !fm.IsDebug() {
 		return
 	}
 
+	if fm.config.Slog != nil {
+		fm.Slog().Debug(fmt.Sprint(v...))
+		return
+	}
 	fm.Logger().Println(append([]any{"DEBUG:"}, v...)...)
 }
+
+// cycleHandler adds the number of the current activation cycle to records
+type cycleHandler struct {
+	slog.Handler
+	cycle *atomic.Int64
+}
+
+// Handle adds the cycle attribute (when the mesh is in a cycle) and passes the record on
+func (h *cycleHandler) Handle(ctx context.Context, record slog.Record) error {
+	if number := h.cycle.Load(); number > 0 {
+		record.AddAttrs(slog.Int64("cycle", number))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs returns the handler with attributes, which still adds the cycle attribute
+func (h *cycleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &cycleHandler{
+		Handler: h.Handler.WithAttrs(attrs),
+		cycle:   h.cycle,
+	}
+}
+
+// WithGroup returns the handler with the group, which still adds the cycle attribute
+func (h *cycleHandler) WithGroup(name string) slog.Handler {
+	return &cycleHandler{
+		Handler: h.Handler.WithGroup(name),
+		cycle:   h.cycle,
+	}
+}
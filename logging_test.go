@@ -0,0 +1,52 @@
+package fmesh
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestFMesh_Slog(t *testing.T) {
+	var buf bytes.Buffer
+	fm := NewWithConfig("fm", &Config{
+		Debug: true,
+		Slog:  slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}).WithComponents(
+		component.New("greeter").
+			WithInputs("in").
+			WithActivationFunc(func(this *component.Component) error {
+				this.Slog().Info("hello", "name", this.InputByName("in").FirstSignalPayloadOrNil())
+				return nil
+			}),
+	)
+	fm.ComponentByName("greeter").InputByName("in").PutSignals(signal.New("world"))
+
+	_, err := fm.Run()
+	require.NoError(t, err)
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+
+	var greeting map[string]any
+	for _, record := range records {
+		assert.Equal(t, "fm", record["mesh"])
+		if record["msg"] == "hello" {
+			greeting = record
+		}
+	}
+	require.NotNil(t, greeting, "component logged with mesh logger")
+	assert.Equal(t, "greeter", greeting["component"])
+	assert.Equal(t, "world", greeting["name"])
+	assert.InDelta(t, 1, greeting["cycle"], 0)
+	assert.Equal(t, "DEBUG", records[0]["level"], "debug messages are logged by the structured logger")
+}
@@ -0,0 +1,201 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/port"
+	"reflect"
+	"sort"
+)
+
+// ComponentMemoryStats is the estimated memory held by a single component
+type ComponentMemoryStats struct {
+	Component string
+	// StateBytes is the estimated size of the component state (keys and values)
+	StateBytes int
+	// InputBufferBytes is the estimated size of signals buffered in input ports (including payloads and labels)
+	InputBufferBytes int
+	// OutputBufferBytes is the estimated size of signals buffered in output ports (including payloads and labels)
+	OutputBufferBytes int
+	// LabelsBytes is the estimated size of labels of the component and its ports
+	LabelsBytes int
+	// BufferedSignals is the number of signals buffered in all ports of the component
+	BufferedSignals int
+}
+
+// TotalBytes returns the estimated memory held by the component
+func (stats ComponentMemoryStats) TotalBytes() int {
+	return stats.StateBytes + stats.InputBufferBytes + stats.OutputBufferBytes + stats.LabelsBytes
+}
+
+// MemoryStats is the estimated memory held by components of the mesh
+type MemoryStats struct {
+	// Components holds stats of all components, the ones holding more memory go first
+	Components []ComponentMemoryStats
+}
+
+// TotalBytes returns the estimated memory held by all components
+func (stats *MemoryStats) TotalBytes() int {
+	total := 0
+	for _, componentStats := range stats.Components {
+		total += componentStats.TotalBytes()
+	}
+	return total
+}
+
+// Top returns stats of at most n components holding the most memory
+func (stats *MemoryStats) Top(n int) []ComponentMemoryStats {
+	if n > len(stats.Components) {
+		n = len(stats.Components)
+	}
+	return stats.Components[:n]
+}
+
+// MemoryStats estimates the memory held by each component (state, port buffers and labels),
+// so components hoarding signals or state can be found in large meshes.
+// The estimation walks the values, memory shared by several components (e.g. a signal delivered by fan-out)
+// is attributed to the first of them by name. Must not be called while the mesh is running
+func (fm *FMesh) MemoryStats() *MemoryStats {
+	stats := &MemoryStats{
+		Components: make([]ComponentMemoryStats, 0, fm.Components().Len()),
+	}
+
+	components := make([]string, 0, fm.Components().Len())
+	for name := range fm.Components().ComponentsOrNil() {
+		components = append(components, name)
+	}
+	sort.Strings(components)
+
+	estimator := newSizeEstimator()
+	for _, name := range components {
+		c := fm.Components().ByName(name)
+		componentStats := ComponentMemoryStats{
+			Component:   name,
+			StateBytes:  estimator.sizeOf(c.State()),
+			LabelsBytes: estimator.sizeOf(c.Labels()),
+		}
+
+		componentStats.InputBufferBytes = estimator.portsSize(c.Inputs().PortsOrNil(), &componentStats)
+		componentStats.OutputBufferBytes = estimator.portsSize(c.Outputs().PortsOrNil(), &componentStats)
+		stats.Components = append(stats.Components, componentStats)
+	}
+
+	sort.SliceStable(stats.Components, func(i, j int) bool {
+		return stats.Components[i].TotalBytes() > stats.Components[j].TotalBytes()
+	})
+	return stats
+}
+
+// sizeEstimator estimates memory held by values, each referenced memory block is counted once
+type sizeEstimator struct {
+	visited map[uintptr]bool
+}
+
+// newSizeEstimator creates an estimator
+func newSizeEstimator() *sizeEstimator {
+	return &sizeEstimator{
+		visited: make(map[uintptr]bool),
+	}
+}
+
+// portsSize returns the size of signals buffered in the ports, port labels and signals count are added to the given stats
+func (e *sizeEstimator) portsSize(ports port.PortMap, stats *ComponentMemoryStats) int {
+	size := 0
+	for _, p := range ports {
+		stats.LabelsBytes += e.sizeOf(p.Labels())
+		for _, sig := range p.AllSignalsOrNil() {
+			stats.BufferedSignals++
+			size += e.sizeOf(sig)
+		}
+	}
+	return size
+}
+
+// sizeOf returns the estimated size of the value including all memory referenced by it
+func (e *sizeEstimator) sizeOf(value any) int {
+	if value == nil {
+		return 0
+	}
+	return e.size(reflect.ValueOf(value))
+}
+
+// size returns the inline size of the value plus the memory referenced by it
+func (e *sizeEstimator) size(v reflect.Value) int {
+	return int(v.Type().Size()) + e.referenced(v)
+}
+
+// referenced returns the size of memory referenced by the value (not counting the value itself)
+func (e *sizeEstimator) referenced(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || !e.visit(v.Pointer()) {
+			return 0
+		}
+		return e.size(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		return e.size(v.Elem())
+	case reflect.String:
+		return v.Len()
+	case reflect.Slice:
+		if v.IsNil() || !e.visit(v.Pointer()) {
+			return 0
+		}
+		size := v.Cap() * int(v.Type().Elem().Size())
+		if hasReferences(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				size += e.referenced(v.Index(i))
+			}
+		}
+		return size
+	case reflect.Map:
+		if v.IsNil() || !e.visit(v.Pointer()) {
+			return 0
+		}
+		// Buckets are approximated by the size of keys and values
+		size := v.Len() * int(v.Type().Key().Size()+v.Type().Elem().Size())
+		iter := v.MapRange()
+		for iter.Next() {
+			size += e.referenced(iter.Key()) + e.referenced(iter.Value())
+		}
+		return size
+	case reflect.Struct:
+		size := 0
+		for i := 0; i < v.NumField(); i++ {
+			size += e.referenced(v.Field(i))
+		}
+		return size
+	case reflect.Array:
+		size := 0
+		if hasReferences(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				size += e.referenced(v.Index(i))
+			}
+		}
+		return size
+	default:
+		// Scalars have no references, channels and functions are not followed
+		return 0
+	}
+}
+
+// visit marks the memory block as visited, returns false when it was already visited
+func (e *sizeEstimator) visit(address uintptr) bool {
+	if e.visited[address] {
+		return false
+	}
+	e.visited[address] = true
+	return true
+}
+
+// hasReferences says whether values of the given type may reference other memory
+func hasReferences(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	default:
+		return true
+	}
+}
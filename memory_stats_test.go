@@ -0,0 +1,92 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFMesh_MemoryStats(t *testing.T) {
+	t.Run("empty mesh", func(t *testing.T) {
+		stats := New("fm").MemoryStats()
+		assert.Empty(t, stats.Components)
+		assert.Zero(t, stats.TotalBytes())
+		assert.Empty(t, stats.Top(3))
+	})
+
+	t.Run("hoarding components go first", func(t *testing.T) {
+		hoarder := component.New("hoarder").WithInputs("in").WithInitialState(func(state component.State) {
+			state.Set("cache", make([]byte, 1<<20))
+		})
+		buffering := component.New("buffering").WithInputs("in").WithOutputs("out")
+		buffering.InputByName("in").PutSignals(signal.New(make([]byte, 1024)), signal.New(make([]byte, 1024)))
+		buffering.OutputByName("out").PutSignals(signal.New("done").WithLabels(common.LabelsCollection{"l1": "v1"}))
+		idle := component.New("idle").WithInputs("in")
+
+		stats := New("fm").WithComponents(idle, buffering, hoarder).MemoryStats()
+		assert.Len(t, stats.Components, 3)
+		assert.Equal(t, []string{"hoarder", "buffering", "idle"}, []string{
+			stats.Components[0].Component,
+			stats.Components[1].Component,
+			stats.Components[2].Component,
+		})
+
+		assert.Greater(t, stats.Components[0].StateBytes, 1<<20)
+		assert.Zero(t, stats.Components[0].BufferedSignals)
+
+		bufferingStats := stats.Components[1]
+		assert.Equal(t, 3, bufferingStats.BufferedSignals)
+		assert.Greater(t, bufferingStats.InputBufferBytes, 2048)
+		assert.Less(t, bufferingStats.InputBufferBytes, 4096)
+		assert.Greater(t, bufferingStats.OutputBufferBytes, len("done")+len("l1")+len("v1"))
+
+		assert.Zero(t, stats.Components[2].InputBufferBytes)
+		assert.Equal(t, stats.Components[:1], stats.Top(1))
+		assert.Equal(t, stats.Components[0].TotalBytes()+stats.Components[1].TotalBytes()+stats.Components[2].TotalBytes(), stats.TotalBytes())
+	})
+
+	t.Run("shared signals are counted once", func(t *testing.T) {
+		payload := make([]byte, 4096)
+		sig := signal.New(payload)
+		c1 := component.New("c1").WithInputs("in")
+		c2 := component.New("c2").WithInputs("in")
+		c1.InputByName("in").PutSignals(sig)
+		c2.InputByName("in").PutSignals(sig)
+
+		stats := New("fm").WithComponents(c1, c2).MemoryStats()
+		assert.Greater(t, stats.Components[0].InputBufferBytes, 4096)
+		assert.Equal(t, "c1", stats.Components[0].Component)
+		assert.Less(t, stats.Components[1].InputBufferBytes, 4096)
+		assert.Equal(t, 1, stats.Components[1].BufferedSignals)
+	})
+}
+
+func Test_sizeEstimator(t *testing.T) {
+	type node struct {
+		name string
+		next *node
+	}
+	loop := &node{name: "a"}
+	loop.next = &node{name: "b", next: loop}
+
+	tests := []struct {
+		name  string
+		value any
+		want  int
+	}{
+		{name: "nil", value: nil, want: 0},
+		{name: "int", value: 42, want: 8},
+		{name: "string", value: "hello", want: 16 + 5},
+		{name: "bytes", value: make([]byte, 10, 100), want: 24 + 100},
+		{name: "strings", value: []string{"ab", "cde"}, want: 24 + 2*16 + 5},
+		{name: "map", value: map[string]int{"ab": 1}, want: 8 + (16 + 8) + 2},
+		{name: "cyclic pointers", value: loop, want: 8 + 2*(16+8) + 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newSizeEstimator().sizeOf(tt.value))
+		})
+	}
+}
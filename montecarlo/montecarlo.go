@@ -0,0 +1,200 @@
+// Package montecarlo evaluates stochastic meshes statistically: the mesh is built and run many times with different
+// seeds of its random source (see fmesh.Config.RandSource), payloads left on chosen output ports are collected
+// and summarized (mean, standard deviation, percentiles)
+package montecarlo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"math"
+	"math/rand"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+var (
+	ErrInvalidRuns       = errors.New("number of runs must be positive")
+	ErrProbeNotFound     = errors.New("probe port not found")
+	ErrNotNumericPayload = errors.New("payload is not numeric")
+)
+
+// Probe is an output port whose payloads are collected after each run (the port must not be piped, so signals stay there)
+type Probe struct {
+	Component string
+	Port      string
+}
+
+// String returns a readable probe representation
+func (p Probe) String() string {
+	return fmt.Sprintf("%s.%s", p.Component, p.Port)
+}
+
+// BuildFunc builds a fresh mesh for a run, the mesh must draw its randomness from the given source (set it as Config.RandSource)
+type BuildFunc func(source rand.Source) *fmesh.FMesh
+
+// Config configures the runner
+type Config struct {
+	// Runs is the number of runs
+	Runs int
+	// Seed is the seed of the first run, run i is seeded with Seed+i, so any run can be reproduced on its own
+	Seed int64
+	// Workers is the number of runs executed concurrently, 0 means one at a time
+	Workers int
+	// Probes are the ports whose payloads are collected
+	Probes []Probe
+}
+
+// Result holds payloads collected from probes across all runs
+type Result struct {
+	// Runs is the number of runs
+	Runs int
+	// Payloads holds, for each probe, payloads of all runs in the order of runs
+	Payloads map[Probe][]any
+	// Errors holds errors of failed runs by run index, payloads of failed runs are collected as well
+	Errors map[int]error
+}
+
+// Run builds and runs the mesh config.Runs times and collects payloads of the probes
+func Run(config Config, build BuildFunc) (*Result, error) {
+	if config.Runs <= 0 {
+		return nil, ErrInvalidRuns
+	}
+
+	perRun := make([]map[Probe][]any, config.Runs)
+	errs := make([]error, config.Runs)
+	probeErrs := make([]error, config.Runs)
+
+	runs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(config.Workers, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for run := range runs {
+				fm := build(rand.NewSource(config.Seed + int64(run)))
+				_, errs[run] = fm.Run()
+				perRun[run], probeErrs[run] = collect(fm, config.Probes)
+			}
+		}()
+	}
+	for run := 0; run < config.Runs; run++ {
+		runs <- run
+	}
+	close(runs)
+	wg.Wait()
+
+	result := &Result{
+		Runs:     config.Runs,
+		Payloads: make(map[Probe][]any, len(config.Probes)),
+		Errors:   make(map[int]error),
+	}
+	for run := 0; run < config.Runs; run++ {
+		if probeErrs[run] != nil {
+			return nil, fmt.Errorf("run %d: %w", run, probeErrs[run])
+		}
+		if errs[run] != nil {
+			result.Errors[run] = errs[run]
+		}
+		for _, probe := range config.Probes {
+			result.Payloads[probe] = append(result.Payloads[probe], perRun[run][probe]...)
+		}
+	}
+	return result, nil
+}
+
+// collect returns payloads of the probes
+func collect(fm *fmesh.FMesh, probes []Probe) (map[Probe][]any, error) {
+	payloads := make(map[Probe][]any, len(probes))
+	for _, probe := range probes {
+		c, ok := fm.Components().ComponentsOrNil()[probe.Component]
+		if !ok {
+			return nil, fmt.Errorf("%w: component %s", ErrProbeNotFound, probe)
+		}
+		p, ok := c.Outputs().PortsOrNil()[probe.Port]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrProbeNotFound, probe)
+		}
+		for _, sig := range p.AllSignalsOrNil() {
+			payloads[probe] = append(payloads[probe], sig.PayloadOrNil())
+		}
+	}
+	return payloads, nil
+}
+
+// Stats summarizes numeric payloads of the probe
+func (r *Result) Stats(probe Probe) (*Stats, error) {
+	samples := make([]float64, 0, len(r.Payloads[probe]))
+	for _, payload := range r.Payloads[probe] {
+		value, ok := toFloat(payload)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has %T", ErrNotNumericPayload, probe, payload)
+		}
+		samples = append(samples, value)
+	}
+	return NewStats(samples), nil
+}
+
+// toFloat converts numeric payloads (any int, uint or float kind) to float64
+func toFloat(payload any) (float64, bool) {
+	v := reflect.ValueOf(payload)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// Stats are aggregate statistics of samples
+type Stats struct {
+	Count  int
+	Mean   float64
+	StdDev float64
+	Min    float64
+	Max    float64
+	// sorted holds samples in ascending order for percentiles
+	sorted []float64
+}
+
+// NewStats computes statistics of the samples
+func NewStats(samples []float64) *Stats {
+	stats := &Stats{
+		Count:  len(samples),
+		sorted: slices.Sorted(slices.Values(samples)),
+	}
+	if stats.Count == 0 {
+		return stats
+	}
+
+	sum := 0.0
+	for _, sample := range samples {
+		sum += sample
+	}
+	stats.Mean = sum / float64(stats.Count)
+
+	squares := 0.0
+	for _, sample := range samples {
+		squares += (sample - stats.Mean) * (sample - stats.Mean)
+	}
+	stats.StdDev = math.Sqrt(squares / float64(stats.Count))
+	stats.Min, stats.Max = stats.sorted[0], stats.sorted[stats.Count-1]
+	return stats
+}
+
+// Percentile returns the p-th percentile (0-100) interpolated linearly between the closest samples, NaN when there are no samples
+func (s *Stats) Percentile(p float64) float64 {
+	if s.Count == 0 {
+		return math.NaN()
+	}
+
+	rank := math.Min(math.Max(p, 0), 100) / 100 * float64(s.Count-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return s.sorted[lower] + (rank-float64(lower))*(s.sorted[upper]-s.sorted[lower])
+}
@@ -0,0 +1,115 @@
+package montecarlo
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// buildDice builds a mesh rolling two dice and emitting their sum, the run fails on double six
+func buildDice(source rand.Source) *fmesh.FMesh {
+	dice := component.New("dice").
+		WithInputs("roll").
+		WithOutputs("sum").
+		WithActivationFunc(func(this *component.Component) error {
+			a, b := this.Rand().Intn(6)+1, this.Rand().Intn(6)+1
+			this.OutputByName("sum").PutSignals(signal.New(a + b))
+			if a == 6 && b == 6 {
+				return errors.New("double six")
+			}
+			return nil
+		})
+	dice.InputByName("roll").PutSignals(signal.New(struct{}{}))
+	return fmesh.NewWithConfig("dice", &fmesh.Config{
+		CyclesLimit: 10,
+		RandSource:  source,
+	}).WithComponents(dice)
+}
+
+func TestRun(t *testing.T) {
+	sum := Probe{Component: "dice", Port: "sum"}
+
+	t.Run("invalid runs", func(t *testing.T) {
+		_, err := Run(Config{Runs: 0}, buildDice)
+		assert.ErrorIs(t, err, ErrInvalidRuns)
+	})
+
+	t.Run("unknown probe", func(t *testing.T) {
+		_, err := Run(Config{Runs: 1, Probes: []Probe{{Component: "dice", Port: "product"}}}, buildDice)
+		assert.ErrorIs(t, err, ErrProbeNotFound)
+	})
+
+	t.Run("payloads are collected and reproducible", func(t *testing.T) {
+		result, err := Run(Config{Runs: 2000, Seed: 42, Probes: []Probe{sum}}, buildDice)
+		assert.NoError(t, err)
+		assert.Equal(t, 2000, result.Runs)
+		assert.Len(t, result.Payloads[sum], 2000)
+		assert.NotEmpty(t, result.Errors, "some runs roll double six")
+		for _, err := range result.Errors {
+			assert.ErrorContains(t, err, "double six")
+		}
+
+		stats, err := result.Stats(sum)
+		assert.NoError(t, err)
+		assert.InDelta(t, 7, stats.Mean, 0.2)
+		assert.Equal(t, float64(2), stats.Min)
+		assert.Equal(t, float64(12), stats.Max)
+		assert.Equal(t, float64(7), stats.Percentile(50))
+
+		concurrent, err := Run(Config{Runs: 2000, Seed: 42, Workers: 4, Probes: []Probe{sum}}, buildDice)
+		assert.NoError(t, err)
+		assert.Equal(t, result, concurrent, "runs are seeded independently of workers")
+	})
+
+	t.Run("stats of non-numeric payloads", func(t *testing.T) {
+		result := &Result{Payloads: map[Probe][]any{sum: {1, "two"}}}
+		_, err := result.Stats(sum)
+		assert.ErrorIs(t, err, ErrNotNumericPayload)
+	})
+}
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name        string
+		samples     []float64
+		wantMean    float64
+		wantStdDev  float64
+		percentiles map[float64]float64
+	}{
+		{
+			name:        "single sample",
+			samples:     []float64{5},
+			wantMean:    5,
+			percentiles: map[float64]float64{0: 5, 50: 5, 100: 5},
+		},
+		{
+			name:        "interpolated percentiles",
+			samples:     []float64{4, 1, 3, 2},
+			wantMean:    2.5,
+			wantStdDev:  math.Sqrt(1.25),
+			percentiles: map[float64]float64{0: 1, 50: 2.5, 90: 3.7, 100: 4, 150: 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := NewStats(tt.samples)
+			assert.Equal(t, len(tt.samples), stats.Count)
+			assert.InDelta(t, tt.wantMean, stats.Mean, 1e-9)
+			assert.InDelta(t, tt.wantStdDev, stats.StdDev, 1e-9)
+			for p, want := range tt.percentiles {
+				assert.InDelta(t, want, stats.Percentile(p), 1e-9, "percentile %v", p)
+			}
+		})
+	}
+
+	t.Run("no samples", func(t *testing.T) {
+		stats := NewStats(nil)
+		assert.Zero(t, stats.Count)
+		assert.True(t, math.IsNaN(stats.Percentile(50)))
+	})
+}
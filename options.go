@@ -0,0 +1,55 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+)
+
+// Option configures a mesh created with NewWithOptions
+type Option func(fm *FMesh) *FMesh
+
+// NewWithOptions creates a mesh and applies the options in order, it is an alternative to the builder chain
+// for cases where options are assembled programmatically. Components are set up with the config of the mesh when added,
+// so WithConfig must go before WithComponents
+func NewWithOptions(name string, options ...Option) *FMesh {
+	fm := New(name)
+	for _, option := range options {
+		fm = option(fm)
+	}
+	return fm
+}
+
+// WithConfig is the option setting the config (see NewWithConfig)
+func WithConfig(config *Config) Option {
+	return func(fm *FMesh) *FMesh {
+		return fm.withConfig(config)
+	}
+}
+
+// WithComponents is the option adding components (see FMesh.WithComponents)
+func WithComponents(components ...*component.Component) Option {
+	return func(fm *FMesh) *FMesh {
+		return fm.WithComponents(components...)
+	}
+}
+
+// WithDescription is the option setting the description (see FMesh.WithDescription)
+func WithDescription(description string) Option {
+	return func(fm *FMesh) *FMesh {
+		return fm.WithDescription(description)
+	}
+}
+
+// WithLabels is the option adding labels (see FMesh.WithLabels)
+func WithLabels(labels common.LabelsCollection) Option {
+	return func(fm *FMesh) *FMesh {
+		return fm.WithLabels(labels)
+	}
+}
+
+// WithPlugins is the option installing plugins (see FMesh.WithPlugins)
+func WithPlugins(plugins ...Plugin) Option {
+	return func(fm *FMesh) *FMesh {
+		return fm.WithPlugins(plugins...)
+	}
+}
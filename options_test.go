@@ -0,0 +1,31 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	plugin := &auditPlugin{}
+	fm := NewWithOptions("fm",
+		WithConfig(&Config{CyclesLimit: 5, AutoPorts: true}),
+		WithDescription("demo"),
+		WithLabels(common.LabelsCollection{"env": "test"}),
+		WithComponents(component.NewWithOptions("c", component.WithActivationFunc(func(this *component.Component) error {
+			return nil
+		}))),
+		WithPlugins(plugin),
+	)
+
+	assert.NoError(t, fm.Err())
+	assert.Equal(t, "demo", fm.Description())
+	assert.Equal(t, "test", fm.LabelOrDefault("env", ""))
+	assert.Equal(t, 5, fm.config.CyclesLimit)
+	// Components are set up with the config given before them
+	assert.NotNil(t, fm.ComponentByName("c").InputByName("undeclared"))
+	assert.False(t, fm.ComponentByName("c").HasErr())
+	assert.NotNil(t, fm.ComponentByName("audit"), "plugin is installed")
+	assert.False(t, fm.Components().HasErr())
+}
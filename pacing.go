@@ -0,0 +1,56 @@
+package fmesh
+
+import (
+	"context"
+	"fmt"
+	"github.com/hovsep/fmesh/clock"
+	"github.com/hovsep/fmesh/cycle"
+	"time"
+)
+
+// PacingReport describes how paced cycles kept up with Config.CyclePeriod during the run
+type PacingReport struct {
+	Period time.Duration
+	// Overruns is the number of cycles which took longer than the period
+	Overruns int
+	// MaxOverrun is the longest time a cycle exceeded the period by
+	MaxOverrun time.Duration
+}
+
+// OverrunListener is notified when a paced cycle (including draining) takes longer than Config.CyclePeriod,
+// the next cycle starts right away then, so the mesh falls behind the schedule by the overrun
+type OverrunListener interface {
+	OnOverrun(fm *FMesh, c *cycle.Cycle, overrun time.Duration)
+}
+
+// pace waits until the cycle started at the given time lasts for the configured period, or reports the overrun.
+// The simulation clock is not paced, as simulated time does not flow by itself
+func (fm *FMesh) pace(ctx context.Context, startedAt time.Time) {
+	period := fm.config.CyclePeriod
+	if period <= 0 {
+		return
+	}
+	if _, simulated := fm.config.Clock.(*clock.Simulation); simulated {
+		return
+	}
+
+	elapsed := fm.Clock().Since(startedAt)
+	if elapsed <= period {
+		select {
+		case <-fm.Clock().After(period - elapsed):
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	overrun := elapsed - period
+	lastCycle := fm.cycles.Last()
+	fm.LogDebug(fmt.Sprintf("cycle #%d overran the period of %s by %s", lastCycle.Number(), period, overrun))
+	if fm.runtimeInfo != nil {
+		fm.runtimeInfo.Pacing.Overruns++
+		fm.runtimeInfo.Pacing.MaxOverrun = max(fm.runtimeInfo.Pacing.MaxOverrun, overrun)
+	}
+	for _, listener := range fm.plugins.overrunListeners {
+		listener.OnOverrun(fm, lastCycle, overrun)
+	}
+}
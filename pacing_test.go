@@ -0,0 +1,75 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/clock"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/cycle"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// overrunRecorder records overrun cycles
+type overrunRecorder struct {
+	cycles []int
+}
+
+func (r *overrunRecorder) Install(fm *FMesh) error {
+	return nil
+}
+
+func (r *overrunRecorder) OnOverrun(fm *FMesh, c *cycle.Cycle, overrun time.Duration) {
+	r.cycles = append(r.cycles, c.Number())
+}
+
+func TestFMesh_CyclePeriod(t *testing.T) {
+	// newChain returns a mesh of 3 forwarding components, the second one takes the given time to activate
+	newChain := func(config *Config, slow time.Duration) *FMesh {
+		newComponent := func(name string, d time.Duration) *component.Component {
+			return component.New(name).WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+				time.Sleep(d)
+				return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+			})
+		}
+		c1, c2, c3 := newComponent("c1", 0), newComponent("c2", slow), newComponent("c3", 0)
+		c1.OutputByName("out").PipeTo(c2.InputByName("in"))
+		c2.OutputByName("out").PipeTo(c3.InputByName("in"))
+		c1.InputByName("in").PutSignals(signal.New(1))
+		return NewWithConfig("fm", config).WithComponents(c1, c2, c3)
+	}
+
+	t.Run("cycles are paced", func(t *testing.T) {
+		fm := newChain(&Config{CyclesLimit: 10, CyclePeriod: 20 * time.Millisecond}, 0)
+		cycles, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Len(t, cycles, 4)
+		// The last cycle activates nothing, so the run stops without waiting
+		assert.GreaterOrEqual(t, fm.RuntimeInfo().Duration, 60*time.Millisecond)
+		assert.Equal(t, PacingReport{Period: 20 * time.Millisecond}, fm.RuntimeInfo().Pacing)
+	})
+
+	t.Run("overruns are reported", func(t *testing.T) {
+		recorder := &overrunRecorder{}
+		fm := newChain(&Config{CyclesLimit: 10, CyclePeriod: 5 * time.Millisecond}, 30*time.Millisecond).WithPlugins(recorder)
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2}, recorder.cycles)
+
+		pacing := fm.RuntimeInfo().Pacing
+		assert.Equal(t, 1, pacing.Overruns)
+		assert.GreaterOrEqual(t, pacing.MaxOverrun, 20*time.Millisecond)
+	})
+
+	t.Run("simulation clock is not paced", func(t *testing.T) {
+		fm := newChain(&Config{
+			CyclesLimit: 10,
+			CyclePeriod: time.Hour,
+			Clock:       clock.NewSimulation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Second),
+		}, 0)
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Zero(t, fm.RuntimeInfo().Pacing.Overruns)
+	})
+}
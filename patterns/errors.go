@@ -0,0 +1,8 @@
+package patterns
+
+import "errors"
+
+var (
+	ErrInvalidRoute = errors.New("invalid route")
+	ErrInvalidJoin  = errors.New("invalid join")
+)
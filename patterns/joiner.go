@@ -0,0 +1,44 @@
+package patterns
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+)
+
+// JoinerOutput is the output port of joiners
+const JoinerOutput = "out"
+
+// JoinFunc combines signals received on the inputs (keyed by input port names) into a single signal, nil signal emits nothing
+type JoinFunc func(inputs map[string]signal.Signals) (*signal.Signal, error)
+
+// NewJoiner creates a component waiting until all the inputs have signals (signals arrived earlier are kept meanwhile),
+// then joining all of them with the join function into the signal emitted on JoinerOutput port
+func NewJoiner(name string, inputs []string, join JoinFunc) *component.Component {
+	if len(inputs) == 0 || join == nil {
+		return component.New(name).WithErr(fmt.Errorf("%w: joiner needs inputs and the join function", ErrInvalidJoin))
+	}
+
+	return component.New(name).
+		WithDescription(fmt.Sprintf("joins signals of %d inputs", len(inputs))).
+		WithInputs(inputs...).
+		WithOutputs(JoinerOutput).
+		WithActivationFunc(func(this *component.Component) error {
+			if !this.Inputs().ByNames(inputs...).AllHaveSignals() {
+				return component.NewErrWaitForInputs(true)
+			}
+
+			signals := make(map[string]signal.Signals, len(inputs))
+			for _, input := range inputs {
+				signals[input] = this.InputByName(input).AllSignalsOrNil()
+			}
+			joined, err := join(signals)
+			if err != nil {
+				return fmt.Errorf("failed to join: %w", err)
+			}
+			if joined != nil {
+				this.OutputByName(JoinerOutput).PutSignals(joined)
+			}
+			return nil
+		})
+}
@@ -0,0 +1,74 @@
+package patterns
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNewJoiner(t *testing.T) {
+	sum := func(inputs map[string]signal.Signals) (*signal.Signal, error) {
+		total := 0
+		for _, signals := range inputs {
+			for _, sig := range signals {
+				total += sig.PayloadOrNil().(int)
+			}
+		}
+		return signal.New(total), nil
+	}
+
+	t.Run("waits for all inputs", func(t *testing.T) {
+		joiner := NewJoiner("joiner", []string{"a", "b"}, sum)
+		joiner.InputByName("a").PutSignals(signal.New(1), signal.New(2))
+
+		result := joiner.MaybeActivate()
+		assert.True(t, component.IsWaitingForInput(result))
+		assert.True(t, component.WantsToKeepInputs(result))
+		assert.False(t, joiner.OutputByName(JoinerOutput).HasSignals())
+
+		joiner.InputByName("b").PutSignals(signal.New(3))
+		result = joiner.MaybeActivate()
+		require.NoError(t, result.ActivationError())
+		assert.Equal(t, 6, joiner.OutputByName(JoinerOutput).Buffer().First().PayloadOrNil())
+	})
+
+	t.Run("inputs arriving in different cycles are joined", func(t *testing.T) {
+		// fast feeds the joiner right away, slow goes through a relay
+		newForwarder := func(name string) *component.Component {
+			return component.New(name).WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+				return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+			})
+		}
+		fast, slow, relay := newForwarder("fast"), newForwarder("slow"), newForwarder("relay")
+		joiner := NewJoiner("joiner", []string{"fast", "slow"}, sum)
+		fast.OutputByName("out").PipeTo(joiner.InputByName("fast"))
+		slow.OutputByName("out").PipeTo(relay.InputByName("in"))
+		relay.OutputByName("out").PipeTo(joiner.InputByName("slow"))
+
+		fm := fmesh.New("fm").WithComponents(fast, slow, relay, joiner)
+		fast.InputByName("in").PutSignals(signal.New(10))
+		slow.InputByName("in").PutSignals(signal.New(20))
+
+		_, err := fm.Run()
+		require.NoError(t, err)
+		assert.Equal(t, 30, joiner.OutputByName(JoinerOutput).Buffer().First().PayloadOrNil())
+	})
+
+	t.Run("join errors fail the activation", func(t *testing.T) {
+		joiner := NewJoiner("joiner", []string{"a"}, func(inputs map[string]signal.Signals) (*signal.Signal, error) {
+			return nil, errors.New("incompatible payloads")
+		})
+		joiner.InputByName("a").PutSignals(signal.New(1))
+		assert.ErrorContains(t, joiner.MaybeActivate().ActivationError(), "failed to join: incompatible payloads")
+	})
+
+	t.Run("invalid joiner", func(t *testing.T) {
+		assert.ErrorIs(t, NewJoiner("joiner", nil, sum).Err(), ErrInvalidJoin)
+		assert.ErrorIs(t, NewJoiner("joiner", []string{"a"}, nil).Err(), ErrInvalidJoin)
+	})
+}
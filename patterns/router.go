@@ -0,0 +1,56 @@
+// Package patterns provides high-order components implementing common integration patterns (routing, joining, etc.),
+// so they are not hand-written per mesh
+package patterns
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"sort"
+)
+
+const (
+	// RouterInput is the input port of routers
+	RouterInput = "in"
+
+	// DefaultRoute is the output port of routers receiving signals which match no route
+	DefaultRoute = "default"
+)
+
+// Predicate says whether the signal matches
+type Predicate func(sig *signal.Signal) bool
+
+// NewRouter creates a component forwarding each signal received on RouterInput port to the output port of the first matching route
+// (chain-of-responsibility style, routes are tried in the order of their names), signals matching no route go to DefaultRoute port.
+// Each route gets the output port named after it:
+//
+//	NewRouter("by-size", map[string]Predicate{"1-large": isLarge, "2-medium": isMedium})
+func NewRouter(name string, routes map[string]Predicate) *component.Component {
+	names := make([]string, 0, len(routes))
+	for route, predicate := range routes {
+		if route == DefaultRoute || route == "" || predicate == nil {
+			return component.New(name).WithErr(fmt.Errorf("%w: %q", ErrInvalidRoute, route))
+		}
+		names = append(names, route)
+	}
+	sort.Strings(names)
+
+	return component.New(name).
+		WithDescription(fmt.Sprintf("routes signals over %d routes", len(names))).
+		WithInputs(RouterInput).
+		WithOutputs(names...).
+		WithOutputs(DefaultRoute).
+		WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName(RouterInput).AllSignalsOrNil() {
+				matched := DefaultRoute
+				for _, route := range names {
+					if routes[route](sig) {
+						matched = route
+						break
+					}
+				}
+				this.OutputByName(matched).PutSignals(sig)
+			}
+			return nil
+		})
+}
@@ -0,0 +1,65 @@
+package patterns
+
+import (
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNewRouter(t *testing.T) {
+	atLeast := func(threshold int) Predicate {
+		return func(sig *signal.Signal) bool {
+			return sig.PayloadOrNil().(int) >= threshold
+		}
+	}
+
+	tests := []struct {
+		name   string
+		routes map[string]Predicate
+		want   map[string][]any
+	}{
+		{
+			name: "first matching route wins",
+			routes: map[string]Predicate{
+				"1-large":  atLeast(100),
+				"2-medium": atLeast(10),
+			},
+			want: map[string][]any{
+				"1-large":    {100, 500},
+				"2-medium":   {10},
+				DefaultRoute: {1},
+			},
+		},
+		{
+			name:   "no routes",
+			routes: nil,
+			want: map[string][]any{
+				DefaultRoute: {1, 100, 10, 500},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter("router", tt.routes)
+			require.False(t, router.HasErr())
+			router.InputByName(RouterInput).PutSignals(signal.NewGroup(1, 100, 10, 500).SignalsOrNil()...)
+
+			result := router.MaybeActivate()
+			require.NoError(t, result.ActivationError())
+
+			got := make(map[string][]any)
+			for name, p := range router.Outputs().PortsOrNil() {
+				payloads, err := p.AllSignalsPayloads()
+				require.NoError(t, err)
+				got[name] = payloads
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("invalid routes", func(t *testing.T) {
+		assert.ErrorIs(t, NewRouter("router", map[string]Predicate{DefaultRoute: atLeast(1)}).Err(), ErrInvalidRoute)
+		assert.ErrorIs(t, NewRouter("router", map[string]Predicate{"large": nil}).Err(), ErrInvalidRoute)
+	})
+}
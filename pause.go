@@ -0,0 +1,133 @@
+package fmesh
+
+import (
+	"context"
+	"github.com/hovsep/fmesh/cycle"
+	"slices"
+	"sync"
+)
+
+// stepResult is the outcome of a cycle run on Step
+type stepResult struct {
+	cycle *cycle.Cycle
+	err   error
+}
+
+// pauseControl holds the cycle loop between cycles while the mesh is paused
+type pauseControl struct {
+	mu     sync.Mutex
+	paused bool
+	// steps are replies of pending Step calls, each one lets a single cycle run
+	steps []chan stepResult
+	// changed is closed (and replaced) when the mesh is resumed or a step is requested
+	changed chan struct{}
+}
+
+// notify wakes up the cycle loop waiting for a change, must be called with the lock held
+func (p *pauseControl) notify() {
+	if p.changed != nil {
+		close(p.changed)
+		p.changed = nil
+	}
+}
+
+// await blocks while the mesh is paused, it returns the reply of the step the next cycle is run for (if any).
+// A requested stop releases the mesh, so it can finish gracefully
+func (p *pauseControl) await(ctx context.Context, stopped <-chan struct{}) (chan<- stepResult, error) {
+	for {
+		p.mu.Lock()
+		if len(p.steps) > 0 {
+			step := p.steps[0]
+			p.steps = p.steps[1:]
+			p.mu.Unlock()
+			return step, nil
+		}
+		if !p.paused {
+			p.mu.Unlock()
+			return nil, nil
+		}
+		if p.changed == nil {
+			p.changed = make(chan struct{})
+		}
+		changed := p.changed
+		p.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-stopped:
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// endRun replies to the step of the last cycle (if any) and fails steps which were not taken
+func (p *pauseControl) endRun(step chan<- stepResult, last *cycle.Cycle, err error) {
+	if step != nil {
+		step <- stepResult{cycle: last, err: err}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, pending := range p.steps {
+		pending <- stepResult{err: ErrRunEnded}
+	}
+	p.steps = nil
+}
+
+// Pause holds the running mesh between activation cycles (the current cycle is completed), so it can be inspected
+// and advanced cycle by cycle with Step. A mesh paused before Run starts the run paused. Pausing applies to CycleEngine only.
+// It is safe to call from other goroutines
+func (fm *FMesh) Pause() {
+	fm.pause.mu.Lock()
+	defer fm.pause.mu.Unlock()
+	fm.pause.paused = true
+}
+
+// Resume lets the paused mesh continue the run
+func (fm *FMesh) Resume() {
+	fm.pause.mu.Lock()
+	defer fm.pause.mu.Unlock()
+	fm.pause.paused = false
+	fm.pause.notify()
+}
+
+// IsPaused says whether the mesh is paused
+func (fm *FMesh) IsPaused() bool {
+	fm.pause.mu.Lock()
+	defer fm.pause.mu.Unlock()
+	return fm.pause.paused
+}
+
+// Step runs a single activation cycle of the paused mesh and returns it once it is drained,
+// the error is the one the run stops with when it stops on that cycle. Returns ErrNotPaused when the mesh is not paused
+// and ErrRunEnded when the run ended before the cycle. If the mesh is not running the step is taken by the next run
+func (fm *FMesh) Step() (*cycle.Cycle, error) {
+	return fm.StepWithContext(context.Background())
+}
+
+// StepWithContext is Step which gives up waiting for the cycle when the context is cancelled
+func (fm *FMesh) StepWithContext(ctx context.Context) (*cycle.Cycle, error) {
+	fm.pause.mu.Lock()
+	if !fm.pause.paused {
+		fm.pause.mu.Unlock()
+		return nil, ErrNotPaused
+	}
+	reply := make(chan stepResult, 1)
+	fm.pause.steps = append(fm.pause.steps, reply)
+	fm.pause.notify()
+	fm.pause.mu.Unlock()
+
+	select {
+	case result := <-reply:
+		return result.cycle, result.err
+	case <-ctx.Done():
+		fm.pause.mu.Lock()
+		fm.pause.steps = slices.DeleteFunc(fm.pause.steps, func(step chan stepResult) bool {
+			return step == reply
+		})
+		fm.pause.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,117 @@
+package fmesh
+
+import (
+	"context"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/cycle"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestFMesh_PauseResumeStep(t *testing.T) {
+	// counter passes the number to itself until it reaches 5
+	getFM := func() *FMesh {
+		counter := component.New("counter").
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				n := this.InputByName("in").FirstSignalPayloadOrDefault(0).(int)
+				this.State().Set("n", n)
+				if n < 5 {
+					this.OutputByName("out").PutSignals(signal.New(n + 1))
+				}
+				return nil
+			})
+		counter.OutputByName("out").PipeTo(counter.InputByName("in"))
+		counter.InputByName("in").PutSignals(signal.New(1))
+		return New("fm").WithComponents(counter)
+	}
+
+	type runResult struct {
+		cycles cycle.Cycles
+		err    error
+	}
+	runAsync := func(ctx context.Context, fm *FMesh) <-chan runResult {
+		done := make(chan runResult, 1)
+		go func() {
+			cycles, err := fm.RunWithContext(ctx)
+			done <- runResult{cycles: cycles, err: err}
+		}()
+		return done
+	}
+
+	t.Run("step and resume", func(t *testing.T) {
+		fm := getFM()
+		fm.Pause()
+		done := runAsync(context.Background(), fm)
+
+		for number := 1; number <= 3; number++ {
+			c, err := fm.Step()
+			require.NoError(t, err)
+			assert.Equal(t, number, c.Number())
+			assert.Equal(t, number, fm.ComponentByName("counter").State().Get("n"))
+		}
+
+		select {
+		case <-done:
+			t.Fatal("paused mesh must not finish the run")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		fm.Resume()
+		result := <-done
+		require.NoError(t, result.err)
+		assert.Len(t, result.cycles, 6)
+		assert.False(t, fm.IsPaused())
+	})
+
+	t.Run("step is taken by the last cycle", func(t *testing.T) {
+		fm := getFM()
+		fm.Pause()
+		done := runAsync(context.Background(), fm)
+
+		var c *cycle.Cycle
+		var err error
+		for range 6 {
+			c, err = fm.Step()
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 6, c.Number())
+		assert.False(t, c.HasActivatedComponents())
+		require.NoError(t, (<-done).err)
+	})
+
+	t.Run("step requires pause", func(t *testing.T) {
+		_, err := getFM().Step()
+		assert.ErrorIs(t, err, ErrNotPaused)
+	})
+
+	t.Run("stop releases paused mesh", func(t *testing.T) {
+		fm := getFM()
+		fm.Pause()
+		done := runAsync(context.Background(), fm)
+		fm.Stop()
+
+		result := <-done
+		require.NoError(t, result.err)
+		assert.Equal(t, 5, fm.ComponentByName("counter").State().Get("n"))
+	})
+
+	t.Run("cancelled while paused", func(t *testing.T) {
+		fm := getFM()
+		fm.Pause()
+		ctx, cancel := context.WithCancel(context.Background())
+		done := runAsync(ctx, fm)
+		cancel()
+
+		assert.ErrorIs(t, (<-done).err, context.Canceled)
+
+		stepCtx, stepCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer stepCancel()
+		_, err := fm.StepWithContext(stepCtx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,277 @@
+package fmesh
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/cycle"
+	"github.com/hovsep/fmesh/port"
+	"strings"
+)
+
+// Plugin extends the mesh: on install it can inspect components and their labels (see ComponentsByLabel), add components and pipes,
+// and it subscribes to runtime events by implementing any of the listener interfaces below.
+// Plugins compose: each one is installed on the mesh as left by the previous ones
+type Plugin interface {
+	Install(fm *FMesh) error
+}
+
+// ComponentAddedListener is notified about components added to the mesh after the plugin is installed,
+// so plugins discovering components by labels also handle the ones added later
+type ComponentAddedListener interface {
+	OnComponentAdded(fm *FMesh, c *component.Component)
+}
+
+// Validator is a plugin checking the mesh it is installed on (e.g. that wiring it is responsible for is complete),
+// problems it finds are returned by Validate, so the mesh does not run
+type Validator interface {
+	Validate(fm *FMesh) error
+}
+
+// RunStartListener is notified when a run starts (after the mesh is prepared, before the first cycle)
+type RunStartListener interface {
+	OnRunStart(fm *FMesh)
+}
+
+// CycleListener is notified after each activation cycle (before outputs of activated components are drained)
+type CycleListener interface {
+	OnCycle(fm *FMesh, c *cycle.Cycle)
+}
+
+// RunStopListener is notified when a run is over with the same results the run returns
+type RunStopListener interface {
+	OnRunStop(fm *FMesh, cycles cycle.Cycles, err error)
+}
+
+// ResetListener is notified when the mesh is reset (see FMesh.Reset), so plugins can drop what they collected from previous runs
+type ResetListener interface {
+	OnReset(fm *FMesh)
+}
+
+// LabelNamespaceOwner is a plugin reserving label namespaces (prefixes like "autopipe:"), so its labels do not collide with labels of other plugins.
+// Namespaces of installed plugins must not overlap, the f-mesh namespace (common.SystemLabelPrefix) can not be reserved
+type LabelNamespaceOwner interface {
+	LabelNamespaces() []string
+}
+
+// LabelChangeListener is notified when labels of components or their ports change (e.g. relabeling done by activation functions),
+// so plugins driving behavior from labels do not need to poll. Only ports the component had when added to the mesh are watched.
+// Activation functions run concurrently, so the listener must be safe for concurrent use
+type LabelChangeListener interface {
+	OnLabelChange(fm *FMesh, event LabelChangeEvent)
+}
+
+// LabelChangeEvent describes a label change of a component or one of its ports
+type LabelChangeEvent struct {
+	common.LabelChange
+	Component *component.Component
+	// Port is nil when a label of the component itself changed
+	Port *port.Port
+}
+
+// plugins is the registry of installed plugins
+type plugins struct {
+	installed               []Plugin
+	componentAddedListeners []ComponentAddedListener
+	runStartListeners       []RunStartListener
+	cycleListeners          []CycleListener
+	runStopListeners        []RunStopListener
+	overrunListeners        []OverrunListener
+	resetListeners          []ResetListener
+	// labelChangeListeners are not notified until components are watched (see watchLabels)
+	labelChangeListeners []LabelChangeListener
+	// labelNamespaces maps reserved label namespaces to their owners
+	labelNamespaces map[string]Plugin
+}
+
+// WithPlugins installs plugins in the given order, a plugin failing to install puts the mesh into error state
+func (fm *FMesh) WithPlugins(plugins ...Plugin) *FMesh {
+	if fm.HasErr() {
+		return fm
+	}
+
+	for _, p := range plugins {
+		if err := fm.reserveLabelNamespaces(p); err != nil {
+			return fm.WithErr(fmt.Errorf("%w %T: %w", ErrFailedToInstallPlugin, p, err))
+		}
+		if err := p.Install(fm); err != nil {
+			return fm.WithErr(fmt.Errorf("%w %T: %w", ErrFailedToInstallPlugin, p, err))
+		}
+		if fm.HasErr() {
+			return fm
+		}
+
+		fm.plugins.installed = append(fm.plugins.installed, p)
+		if listener, ok := p.(ComponentAddedListener); ok {
+			fm.plugins.componentAddedListeners = append(fm.plugins.componentAddedListeners, listener)
+		}
+		if listener, ok := p.(RunStartListener); ok {
+			fm.plugins.runStartListeners = append(fm.plugins.runStartListeners, listener)
+		}
+		if listener, ok := p.(CycleListener); ok {
+			fm.plugins.cycleListeners = append(fm.plugins.cycleListeners, listener)
+		}
+		if listener, ok := p.(RunStopListener); ok {
+			fm.plugins.runStopListeners = append(fm.plugins.runStopListeners, listener)
+		}
+		if listener, ok := p.(OverrunListener); ok {
+			fm.plugins.overrunListeners = append(fm.plugins.overrunListeners, listener)
+		}
+		if listener, ok := p.(ResetListener); ok {
+			fm.plugins.resetListeners = append(fm.plugins.resetListeners, listener)
+		}
+		if listener, ok := p.(LabelChangeListener); ok {
+			fm.plugins.labelChangeListeners = append(fm.plugins.labelChangeListeners, listener)
+			if len(fm.plugins.labelChangeListeners) == 1 {
+				// Components are watched only when somebody listens, the first listener starts watching those added so far
+				for _, c := range fm.components.ComponentsOrNil() {
+					fm.watchLabels(c)
+				}
+			}
+		}
+	}
+	return fm
+}
+
+// Use installs plugins in the given order (see WithPlugins)
+func (fm *FMesh) Use(plugins ...Plugin) *FMesh {
+	return fm.WithPlugins(plugins...)
+}
+
+// ComponentsByLabel returns components having the label with the given value
+func (fm *FMesh) ComponentsByLabel(label string, value string) *component.Collection {
+	return fm.Components().ByLabel(label, value)
+}
+
+// reserveLabelNamespaces reserves label namespaces of the plugin (if it owns any)
+func (fm *FMesh) reserveLabelNamespaces(p Plugin) error {
+	owner, ok := p.(LabelNamespaceOwner)
+	if !ok {
+		return nil
+	}
+
+	namespaces := owner.LabelNamespaces()
+	for _, namespace := range namespaces {
+		if namespace == "" || common.LabelNamespacesOverlap(namespace, common.SystemLabelPrefix) {
+			return fmt.Errorf("%w: %q is reserved for f-mesh", common.ErrLabelNamespaceConflict, namespace)
+		}
+		for reserved, reservedBy := range fm.plugins.labelNamespaces {
+			if common.LabelNamespacesOverlap(namespace, reserved) {
+				return fmt.Errorf("%w: %q overlaps with %q of %T", common.ErrLabelNamespaceConflict, namespace, reserved, reservedBy)
+			}
+		}
+	}
+
+	if fm.plugins.labelNamespaces == nil {
+		fm.plugins.labelNamespaces = make(map[string]Plugin)
+	}
+	for _, namespace := range namespaces {
+		fm.plugins.labelNamespaces[namespace] = p
+	}
+	return nil
+}
+
+// LabelOwner returns the plugin which reserved the namespace of the label
+func (fm *FMesh) LabelOwner(label string) (Plugin, bool) {
+	for namespace, owner := range fm.plugins.labelNamespaces {
+		if strings.HasPrefix(label, namespace) {
+			return owner, true
+		}
+	}
+	return nil, false
+}
+
+// Plugins returns installed plugins in the order of installation
+func (fm *FMesh) Plugins() []Plugin {
+	return fm.plugins.installed
+}
+
+// validatePlugins returns problems found by plugins
+func (fm *FMesh) validatePlugins() error {
+	var errs []error
+	for _, p := range fm.plugins.installed {
+		validator, ok := p.(Validator)
+		if !ok {
+			continue
+		}
+		if err := validator.Validate(fm); err != nil {
+			errs = append(errs, fmt.Errorf("plugin %T: %w", p, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// notifyComponentAdded notifies plugins about components added to the mesh
+func (fm *FMesh) notifyComponentAdded(components []*component.Component) {
+	for _, listener := range fm.plugins.componentAddedListeners {
+		for _, c := range components {
+			listener.OnComponentAdded(fm, c)
+		}
+	}
+}
+
+// notifyRunStart notifies plugins about the start of a run
+func (fm *FMesh) notifyRunStart() {
+	for _, listener := range fm.plugins.runStartListeners {
+		listener.OnRunStart(fm)
+	}
+}
+
+// notifyCycle notifies plugins about the finished activation cycle and state watchers of activated components about state changes
+func (fm *FMesh) notifyCycle(c *cycle.Cycle) {
+	t := fm.compiledTopology()
+	for id, activationResult := range t.activationResults(c) {
+		if !activationResult.Activated() {
+			continue
+		}
+		t.components[id].NotifyStateWatchers()
+		fm.recordStateModification(t.components[id], c.Number())
+		fm.notifyComponentActivated(activationResult)
+	}
+
+	for _, listener := range fm.plugins.cycleListeners {
+		listener.OnCycle(fm, c)
+	}
+}
+
+// notifyRunStop notifies plugins about the end of a run
+func (fm *FMesh) notifyRunStop(cycles cycle.Cycles, err error) {
+	for _, listener := range fm.plugins.runStopListeners {
+		listener.OnRunStop(fm, cycles, err)
+	}
+}
+
+// notifyReset notifies plugins about the reset of the mesh
+func (fm *FMesh) notifyReset() {
+	for _, listener := range fm.plugins.resetListeners {
+		listener.OnReset(fm)
+	}
+}
+
+// watchLabels subscribes to label changes of the component and its ports
+func (fm *FMesh) watchLabels(c *component.Component) {
+	c.OnLabelChange(func(change common.LabelChange) {
+		fm.notifyLabelChange(LabelChangeEvent{LabelChange: change, Component: c})
+	})
+	for _, p := range c.Inputs().PortsOrNil() {
+		fm.watchPortLabels(c, p)
+	}
+	for _, p := range c.Outputs().PortsOrNil() {
+		fm.watchPortLabels(c, p)
+	}
+}
+
+// watchPortLabels subscribes to label changes of the port
+func (fm *FMesh) watchPortLabels(c *component.Component, p *port.Port) {
+	p.OnLabelChange(func(change common.LabelChange) {
+		fm.notifyLabelChange(LabelChangeEvent{LabelChange: change, Component: c, Port: p})
+	})
+}
+
+// notifyLabelChange notifies plugins about the label change
+func (fm *FMesh) notifyLabelChange(event LabelChangeEvent) {
+	for _, listener := range fm.plugins.labelChangeListeners {
+		listener.OnLabelChange(fm, event)
+	}
+}
@@ -0,0 +1,266 @@
+package fmesh
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/cycle"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+// auditPlugin attaches an audit component to all output ports labeled "audit" and records runtime events
+type auditPlugin struct {
+	events []string
+}
+
+func (p *auditPlugin) Install(fm *FMesh) error {
+	audit := component.New("audit").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+		return nil
+	})
+	for _, c := range fm.Components().ComponentsOrNil() {
+		for _, out := range c.Outputs().PortsOrNil() {
+			if out.HasLabel("audit") {
+				out.PipeTo(audit.InputByName("in"))
+			}
+		}
+	}
+	fm.WithComponents(audit)
+	return nil
+}
+
+func (p *auditPlugin) OnRunStart(fm *FMesh) {
+	p.events = append(p.events, "start")
+}
+
+func (p *auditPlugin) OnCycle(fm *FMesh, c *cycle.Cycle) {
+	p.events = append(p.events, "cycle")
+}
+
+func (p *auditPlugin) OnRunStop(fm *FMesh, cycles cycle.Cycles, err error) {
+	p.events = append(p.events, "stop")
+}
+
+// metricsPlugin discovers components labeled "metrics" and counts their activations,
+// components added after install are discovered as well
+type metricsPlugin struct {
+	mu          sync.Mutex
+	activations map[string]int
+}
+
+func (p *metricsPlugin) Install(fm *FMesh) error {
+	p.activations = make(map[string]int)
+	for name := range fm.ComponentsByLabel("metrics", "true").ComponentsOrNil() {
+		p.activations[name] = 0
+	}
+	fm.OnComponentActivated(func(fm *FMesh, activationResult *component.ActivationResult) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if _, ok := p.activations[activationResult.ComponentName()]; ok {
+			p.activations[activationResult.ComponentName()]++
+		}
+	})
+	return nil
+}
+
+func (p *metricsPlugin) OnComponentAdded(fm *FMesh, c *component.Component) {
+	if c.LabelOrDefault("metrics", "") == "true" {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.activations[c.Name()] = 0
+	}
+}
+
+// strictPlugin finds problems on validation
+type strictPlugin struct{}
+
+func (strictPlugin) Install(fm *FMesh) error {
+	return nil
+}
+
+func (strictPlugin) Validate(fm *FMesh) error {
+	return errors.New("component c1 is not allowed")
+}
+
+// failingPlugin fails to install
+type failingPlugin struct{}
+
+func (failingPlugin) Install(fm *FMesh) error {
+	return errors.New("incompatible mesh")
+}
+
+// relabelWatcher records label changes
+type relabelWatcher struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (w *relabelWatcher) Install(fm *FMesh) error {
+	return nil
+}
+
+func (w *relabelWatcher) OnLabelChange(fm *FMesh, event LabelChangeEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entity := event.Component.Name()
+	if event.Port != nil {
+		entity += "." + event.Port.Name()
+	}
+	w.events = append(w.events, fmt.Sprintf("%s %s: %q -> %q", entity, event.Label, event.OldValue, event.NewValue))
+}
+
+func TestFMesh_LabelChangeListener(t *testing.T) {
+	newComponent := func(name string) *component.Component {
+		return component.New(name).WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			this.AddLabel("state", "busy")
+			this.OutputByName("out").AddLabel("mode", "drain")
+			this.DeleteLabel("tier")
+			return nil
+		})
+	}
+
+	watcher := &relabelWatcher{}
+	c1 := newComponent("c1").WithLabels(common.LabelsCollection{"tier": "backend"})
+	// c1 is added before the listener, c2 after it
+	fm := New("fm").WithComponents(c1).WithPlugins(watcher)
+	c2 := newComponent("c2")
+	fm.WithComponents(c2)
+
+	c1.InputByName("in").PutSignals(signal.New(1))
+	_, err := fm.Run()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`c1 state: "" -> "busy"`,
+		`c1.out mode: "" -> "drain"`,
+		`c1 tier: "backend" -> ""`,
+	}, watcher.events)
+
+	// Setting the same value again is not a change
+	c2.OutputByName("out").AddLabel("mode", "drain")
+	c2.OutputByName("out").AddLabel("mode", "drain")
+	assert.Equal(t, `c2.out mode: "" -> "drain"`, watcher.events[len(watcher.events)-1])
+	assert.Len(t, watcher.events, 4)
+}
+
+// namespacedPlugin reserves label namespaces
+type namespacedPlugin struct {
+	namespaces []string
+}
+
+func (p *namespacedPlugin) Install(fm *FMesh) error {
+	return nil
+}
+
+func (p *namespacedPlugin) LabelNamespaces() []string {
+	return p.namespaces
+}
+
+func TestFMesh_LabelNamespaces(t *testing.T) {
+	autopipe := &namespacedPlugin{namespaces: []string{"autopipe:"}}
+	router := &namespacedPlugin{namespaces: []string{"router:"}}
+
+	tests := []struct {
+		name    string
+		plugins []Plugin
+		wantErr error
+	}{
+		{
+			name:    "distinct namespaces",
+			plugins: []Plugin{autopipe, router},
+		},
+		{
+			name:    "overlapping namespaces",
+			plugins: []Plugin{autopipe, &namespacedPlugin{namespaces: []string{"autopipe:group:"}}},
+			wantErr: common.ErrLabelNamespaceConflict,
+		},
+		{
+			name:    "f-mesh namespace",
+			plugins: []Plugin{&namespacedPlugin{namespaces: []string{common.SystemLabelPrefix + "mine:"}}},
+			wantErr: common.ErrLabelNamespaceConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm := New("fm").WithPlugins(tt.plugins...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, fm.Err(), ErrFailedToInstallPlugin)
+				assert.ErrorIs(t, fm.Err(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, fm.Err())
+
+			owner, ok := fm.LabelOwner("autopipe:role")
+			assert.True(t, ok)
+			assert.Same(t, autopipe, owner)
+			_, ok = fm.LabelOwner("env")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestFMesh_WithPlugins(t *testing.T) {
+	t.Run("plugin adds components and listens to events", func(t *testing.T) {
+		c1 := component.New("c1").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			this.OutputByName("out").PutSignals(signal.New(1))
+			return nil
+		})
+		c1.OutputByName("out").AddLabel("audit", "true")
+
+		plugin := &auditPlugin{}
+		fm := New("fm").WithComponents(c1).WithPlugins(plugin)
+		assert.False(t, fm.HasErr())
+		assert.Equal(t, []Plugin{plugin}, fm.Plugins())
+		assert.NotNil(t, fm.ComponentByName("audit"))
+
+		c1.InputByName("in").PutSignals(signal.New(0))
+		cycles, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Len(t, cycles, 3)
+		assert.Equal(t, []string{"start", "cycle", "cycle", "cycle", "stop"}, plugin.events)
+	})
+
+	t.Run("plugin discovers components by label", func(t *testing.T) {
+		newComponent := func(name string, metrics string) *component.Component {
+			return component.New(name).
+				WithLabels(common.LabelsCollection{"metrics": metrics}).
+				WithInputs("in").
+				WithActivationFunc(func(this *component.Component) error {
+					return nil
+				})
+		}
+		c1, c2, c3 := newComponent("c1", "true"), newComponent("c2", "false"), newComponent("c3", "true")
+
+		plugin := &metricsPlugin{}
+		fm := New("fm").WithComponents(c1, c2).Use(plugin).WithComponents(c3)
+		assert.False(t, fm.HasErr())
+		for _, c := range []*component.Component{c1, c2, c3} {
+			c.InputByName("in").PutSignals(signal.New(1))
+		}
+
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"c1": 1, "c3": 1}, plugin.activations)
+	})
+
+	t.Run("plugin validates the mesh", func(t *testing.T) {
+		c1 := component.New("c1").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+			return nil
+		})
+		fm := New("fm").WithComponents(c1).Use(strictPlugin{})
+		assert.EqualError(t, fm.Validate(), "plugin fmesh.strictPlugin: component c1 is not allowed")
+
+		_, err := fm.Run()
+		assert.Error(t, err)
+	})
+
+	t.Run("failed install", func(t *testing.T) {
+		fm := New("fm").WithPlugins(failingPlugin{}, &auditPlugin{})
+		assert.ErrorIs(t, fm.Err(), ErrFailedToInstallPlugin)
+		assert.Empty(t, fm.Plugins())
+	})
+}
@@ -0,0 +1,147 @@
+// Package autopipe is the mesh plugin wiring ports by labels: a port labeled with the component and the port on the other end
+// gets the pipe created as soon as both ends are in the mesh, so wiring can be declared where ports are declared
+package autopipe
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"sort"
+	"strings"
+)
+
+const (
+	// Namespace is the label namespace reserved by the plugin
+	Namespace = "@autopipe-"
+
+	// ComponentLabel names the component on the other end of the pipe: the destination for output ports and the source for input ports.
+	// Several comma separated names make 1:N wiring (an output piped to many components, or an input fed by many components),
+	// N:1 wiring is made by labeling many output ports with the same destination
+	ComponentLabel = Namespace + "component"
+
+	// PortLabel names the port on the other end of the pipe, the name of the labeled port is used when it is not set
+	PortLabel = Namespace + "port"
+)
+
+var ErrUnresolvedTarget = errors.New("autopipe target not found")
+
+// Plugin creates pipes declared by labels, targets which are not in the mesh when it is validated (e.g. on Run) are reported as errors
+type Plugin struct {
+	// wired are the labeled ports whose pipes are created
+	wired map[*port.Port]bool
+}
+
+// New creates the plugin
+func New() *Plugin {
+	return &Plugin{
+		wired: make(map[*port.Port]bool),
+	}
+}
+
+// LabelNamespaces returns the label namespace reserved by the plugin
+func (p *Plugin) LabelNamespaces() []string {
+	return []string{Namespace}
+}
+
+// Install wires ports of the components added so far, targets which are not in the mesh yet are wired when they are added
+func (p *Plugin) Install(fm *fmesh.FMesh) error {
+	p.wire(fm)
+	return nil
+}
+
+// OnComponentAdded wires ports waiting for the component and ports of the component
+func (p *Plugin) OnComponentAdded(fm *fmesh.FMesh, c *component.Component) {
+	p.wire(fm)
+}
+
+// Validate wires what is left and reports labeled ports whose targets are not in the mesh (see ErrUnresolvedTarget)
+func (p *Plugin) Validate(fm *fmesh.FMesh) error {
+	return p.wire(fm)
+}
+
+// wire creates pipes of labeled ports which are not wired yet (ordered by component and port names),
+// a port is wired only when all its targets are resolved, so pipes are never created twice
+func (p *Plugin) wire(fm *fmesh.FMesh) error {
+	components := fm.Components().ComponentsOrNil()
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		for _, ports := range []*port.Collection{components[name].Inputs(), components[name].Outputs()} {
+			portMap := ports.PortsOrNil()
+			for _, portName := range sortedPortNames(portMap) {
+				labeled := portMap[portName]
+				if p.wired[labeled] || !labeled.HasLabel(ComponentLabel) {
+					continue
+				}
+
+				targets, err := resolve(components, labeled)
+				if err == nil {
+					err = pipe(labeled, targets)
+				}
+				if err != nil {
+					errs = append(errs, &fmesh.ConstructionError{Component: name, Port: portName, Err: err})
+					continue
+				}
+				p.wired[labeled] = true
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// resolve returns ports on the other end of pipes declared by labels of the port
+func resolve(components component.ComponentsMap, labeled *port.Port) (port.Ports, error) {
+	targetPort := labeled.LabelOrDefault(PortLabel, labeled.Name())
+	isOutput := labeled.LabelOrDefault(port.DirectionLabel, "") == port.DirectionOut
+
+	var targets port.Ports
+	for _, targetComponent := range strings.Split(labeled.LabelOrDefault(ComponentLabel, ""), ",") {
+		targetComponent = strings.TrimSpace(targetComponent)
+		c, ok := components[targetComponent]
+		if !ok {
+			return nil, fmt.Errorf("%w: component %q", ErrUnresolvedTarget, targetComponent)
+		}
+
+		ports := c.Outputs()
+		if isOutput {
+			ports = c.Inputs()
+		}
+		target, ok := ports.PortsOrNil()[targetPort]
+		if !ok {
+			return nil, fmt.Errorf("%w: port %q of component %q", ErrUnresolvedTarget, targetPort, targetComponent)
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// pipe creates pipes between the labeled port and its targets
+func pipe(labeled *port.Port, targets port.Ports) error {
+	if labeled.LabelOrDefault(port.DirectionLabel, "") == port.DirectionOut {
+		return labeled.PipeTo(targets...).Err()
+	}
+
+	for _, source := range targets {
+		if err := source.PipeTo(labeled).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedPortNames returns names of the ports sorted
+func sortedPortNames(ports port.PortMap) []string {
+	names := make([]string, 0, len(ports))
+	for name := range ports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
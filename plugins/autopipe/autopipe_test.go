@@ -0,0 +1,99 @@
+package autopipe
+
+import (
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPlugin(t *testing.T) {
+	newComponent := func(name string) *component.Component {
+		return component.New(name).WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+		})
+	}
+	label := func(p *port.Port, targetComponent string, targetPort string) {
+		p.AddLabel(ComponentLabel, targetComponent)
+		if targetPort != "" {
+			p.AddLabel(PortLabel, targetPort)
+		}
+	}
+	payloads := func(p *port.Port) []any {
+		payloads, err := p.AllSignalsPayloads()
+		require.NoError(t, err)
+		return payloads
+	}
+
+	t.Run("1:N from an output port", func(t *testing.T) {
+		src, a, b := newComponent("src"), newComponent("a"), newComponent("b")
+		label(src.OutputByName("out"), "a, b", "in")
+
+		fm := fmesh.New("fm").WithComponents(src, a, b).Use(New())
+		require.False(t, fm.HasErr())
+		assert.Equal(t, 2, src.OutputByName("out").Pipes().Len())
+
+		src.InputByName("in").PutSignals(signal.New(1))
+		_, err := fm.Run()
+		require.NoError(t, err)
+		assert.Equal(t, []any{1}, payloads(a.OutputByName("out")))
+		assert.Equal(t, []any{1}, payloads(b.OutputByName("out")))
+	})
+
+	t.Run("N:1 from an input port, targets added after install", func(t *testing.T) {
+		sink, a, b := newComponent("sink"), newComponent("a"), newComponent("b")
+		// The port name defaults to the one of the labeled port, so it is set explicitly here
+		label(sink.InputByName("in"), "a,b", "out")
+
+		fm := fmesh.New("fm").WithComponents(sink).Use(New()).WithComponents(a, b)
+		require.False(t, fm.HasErr())
+		assert.Equal(t, 1, a.OutputByName("out").Pipes().Len())
+		assert.Equal(t, 1, b.OutputByName("out").Pipes().Len())
+
+		a.InputByName("in").PutSignals(signal.New(1))
+		b.InputByName("in").PutSignals(signal.New(2))
+		_, err := fm.Run()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []any{1, 2}, payloads(sink.OutputByName("out")))
+	})
+
+	t.Run("port name defaults to the labeled port name", func(t *testing.T) {
+		src := component.New("src").WithOutputs("data").WithActivationFunc(func(this *component.Component) error {
+			return nil
+		})
+		dst := component.New("dst").WithInputs("data").WithActivationFunc(func(this *component.Component) error {
+			return nil
+		})
+		label(src.OutputByName("data"), "dst", "")
+
+		fmesh.New("fm").WithComponents(src, dst).Use(New())
+		assert.Equal(t, 1, src.OutputByName("data").Pipes().Len())
+	})
+
+	t.Run("unresolved targets fail validation", func(t *testing.T) {
+		src, a := newComponent("src"), newComponent("a")
+		label(src.OutputByName("out"), "a,ghost", "in")
+		label(a.OutputByName("out"), "src", "missing")
+
+		fm := fmesh.New("fm").WithComponents(src, a).Use(New())
+		err := fm.Validate()
+		assert.ErrorIs(t, err, ErrUnresolvedTarget)
+		assert.ErrorContains(t, err, `component src, port out: autopipe target not found: component "ghost"`)
+		assert.ErrorContains(t, err, `component a, port out: autopipe target not found: port "missing" of component "src"`)
+		// Ports are wired only when all targets are resolved
+		assert.Zero(t, src.OutputByName("out").Pipes().Len())
+
+		_, err = fm.Run()
+		assert.ErrorIs(t, err, ErrUnresolvedTarget)
+	})
+
+	t.Run("label namespace is reserved", func(t *testing.T) {
+		fm := fmesh.New("fm").Use(New())
+		owner, ok := fm.LabelOwner(ComponentLabel)
+		assert.True(t, ok)
+		assert.IsType(t, &Plugin{}, owner)
+	})
+}
@@ -0,0 +1,180 @@
+package port
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/signal"
+)
+
+// OverflowPolicy defines what happens to signals arriving at a port which is at capacity
+type OverflowPolicy int
+
+const (
+	// OverflowDropOldest evicts the oldest buffered signals to make room for arriving ones
+	OverflowDropOldest OverflowPolicy = iota
+
+	// OverflowDropNewest drops arriving signals which do not fit
+	OverflowDropNewest
+
+	// OverflowBlock makes pipes stop delivering to the full port: signals which do not fit stay on source output ports
+	// (for all pipes of the source port, so each destination gets the same signals) until the port is cleared.
+	// The limit applies to pipes only, writes made by activation functions are not limited, and a few signals
+	// may overshoot it when many sources flush into the port concurrently
+	OverflowBlock
+
+	// OverflowError fails writes which do not fit (see ErrPortOverflow), so the run stops with the error
+	OverflowError
+)
+
+// String returns the name of the policy
+func (policy OverflowPolicy) String() string {
+	switch policy {
+	case OverflowDropOldest:
+		return "DropOldest"
+	case OverflowDropNewest:
+		return "DropNewest"
+	case OverflowBlock:
+		return "Block"
+	case OverflowError:
+		return "Error"
+	default:
+		return "Unsupported overflow policy"
+	}
+}
+
+// capacity bounds the number of signals buffered by a port
+type capacity struct {
+	limit  int
+	policy OverflowPolicy
+	// dropped is the number of signals dropped by the policy
+	dropped int
+}
+
+// WithCapacity bounds the number of signals buffered by the port, so long-running meshes do not grow memory without limit,
+// the policy defines what happens to signals which do not fit. Capacity 0 makes the port unbounded.
+// It can not be combined with spilling (see WithSpill), which bounds memory by moving signals to disk
+func (p *Port) WithCapacity(limit int, policy OverflowPolicy) *Port {
+	if p.HasErr() {
+		return p
+	}
+
+	if limit < 0 || policy < OverflowDropOldest || policy > OverflowError {
+		p.SetErr(fmt.Errorf("%w: capacity %d, policy %s", ErrInvalidCapacity, limit, policy))
+		return New("").WithErr(p.Err())
+	}
+	if limit == 0 {
+		p.capacity = nil
+		return p
+	}
+	if p.spill != nil {
+		p.SetErr(fmt.Errorf("%w: port spills to disk", ErrInvalidCapacity))
+		return New("").WithErr(p.Err())
+	}
+
+	p.capacity = &capacity{
+		limit:  limit,
+		policy: policy,
+	}
+	return p
+}
+
+// Capacity returns the max number of signals buffered by the port (0 means unbounded)
+func (p *Port) Capacity() int {
+	if p.capacity == nil {
+		return 0
+	}
+	return p.capacity.limit
+}
+
+// OverflowPolicy returns the overflow policy of the port (meaningful only when the port has capacity)
+func (p *Port) OverflowPolicy() OverflowPolicy {
+	if p.capacity == nil {
+		return OverflowDropOldest
+	}
+	return p.capacity.policy
+}
+
+// BlocksOnOverflow says whether pipes stop delivering to the port when it is full (see OverflowBlock)
+func (p *Port) BlocksOnOverflow() bool {
+	return p.capacity != nil && p.capacity.policy == OverflowBlock
+}
+
+// HasHeldSignals says whether signals stay on the port as they did not fit into destinations blocking on overflow
+// (output ports with pipes are emptied by flushing otherwise)
+func (p *Port) HasHeldSignals() bool {
+	return p.HasPipes() && p.HasSignals()
+}
+
+// Dropped returns the number of signals dropped by the overflow policy
+func (p *Port) Dropped() int {
+	if !p.lockFree {
+		p.bufferMu.Lock()
+		defer p.bufferMu.Unlock()
+	}
+	if p.capacity == nil {
+		return 0
+	}
+	return p.capacity.dropped
+}
+
+// IsFull says whether the port buffers as many signals as its capacity allows (always false for unbounded ports)
+func (p *Port) IsFull() bool {
+	if !p.lockFree {
+		p.bufferMu.Lock()
+		defer p.bufferMu.Unlock()
+	}
+	return p.capacity != nil && p.buffer.Len() >= p.capacity.limit
+}
+
+// bound applies the overflow policy to arriving signals and returns the ones to be buffered, it must be called with the lock held
+func (p *Port) bound(signals signal.Signals) (signal.Signals, error) {
+	if p.capacity == nil || p.capacity.policy == OverflowBlock {
+		return signals, nil
+	}
+
+	free := max(p.capacity.limit-p.buffer.Len(), 0)
+	if len(signals) <= free {
+		return signals, nil
+	}
+
+	switch p.capacity.policy {
+	case OverflowDropNewest:
+		p.capacity.dropped += len(signals) - free
+		return signals[:free], nil
+	case OverflowDropOldest:
+		if len(signals) >= p.capacity.limit {
+			p.capacity.dropped += p.buffer.Len() + len(signals) - p.capacity.limit
+			p.buffer = signal.NewGroup()
+			return signals[len(signals)-p.capacity.limit:], nil
+		}
+		evicted := len(signals) - free
+		p.capacity.dropped += evicted
+		p.buffer = signal.NewGroup().With(p.buffer.SignalsOrNil()[evicted:]...)
+		return signals, nil
+	default:
+		return nil, fmt.Errorf("%w: port %s has capacity %d, %d signals buffered, %d arrived", ErrPortOverflow, p.Name(), p.capacity.limit, p.buffer.Len(), len(signals))
+	}
+}
+
+// room returns the number of signals pipes can deliver to the port (-1 means unlimited)
+func (p *Port) room() int {
+	if !p.BlocksOnOverflow() {
+		return -1
+	}
+
+	if !p.lockFree {
+		p.bufferMu.Lock()
+		defer p.bufferMu.Unlock()
+	}
+	return max(p.capacity.limit-p.buffer.Len(), 0)
+}
+
+// flushable returns the number of buffered signals which fit into all destinations
+func flushable(signals signal.Signals, dests Ports) int {
+	n := len(signals)
+	for _, dest := range dests {
+		if room := dest.room(); room >= 0 && room < n {
+			n = room
+		}
+	}
+	return n
+}
@@ -0,0 +1,126 @@
+package port
+
+import (
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPort_WithCapacity(t *testing.T) {
+	payloads := func(p *Port) []any {
+		payloads, err := p.AllSignalsPayloads()
+		require.NoError(t, err)
+		return payloads
+	}
+
+	tests := []struct {
+		name         string
+		policy       OverflowPolicy
+		puts         [][]any
+		wantPayloads []any
+		wantDropped  int
+		wantErr      error
+	}{
+		{
+			name:         "drop oldest evicts buffered signals",
+			policy:       OverflowDropOldest,
+			puts:         [][]any{{1, 2}, {3, 4}},
+			wantPayloads: []any{2, 3, 4},
+			wantDropped:  1,
+		},
+		{
+			name:         "drop oldest keeps the tail of a large write",
+			policy:       OverflowDropOldest,
+			puts:         [][]any{{1}, {2, 3, 4, 5}},
+			wantPayloads: []any{3, 4, 5},
+			wantDropped:  2,
+		},
+		{
+			name:         "drop newest drops arriving signals",
+			policy:       OverflowDropNewest,
+			puts:         [][]any{{1, 2}, {3, 4}},
+			wantPayloads: []any{1, 2, 3},
+			wantDropped:  1,
+		},
+		{
+			name:         "error fails the write",
+			policy:       OverflowError,
+			puts:         [][]any{{1, 2}, {3, 4}},
+			wantPayloads: []any{1, 2},
+			wantErr:      ErrPortOverflow,
+		},
+		{
+			name:         "block does not limit direct writes",
+			policy:       OverflowBlock,
+			puts:         [][]any{{1, 2}, {3, 4}},
+			wantPayloads: []any{1, 2, 3, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New("in").WithCapacity(3, tt.policy)
+			require.False(t, p.HasErr())
+
+			var result *Port
+			for _, put := range tt.puts {
+				result = p.PutSignals(signal.NewGroup(put...).SignalsOrNil()...)
+			}
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, result.Err(), tt.wantErr)
+				return
+			}
+			require.False(t, result.HasErr())
+			assert.Equal(t, tt.wantPayloads, payloads(p))
+			assert.Equal(t, tt.wantDropped, p.Dropped())
+			assert.Equal(t, 3, p.Capacity())
+			assert.True(t, p.IsFull())
+		})
+	}
+
+	t.Run("invalid capacity", func(t *testing.T) {
+		assert.ErrorIs(t, New("in").WithCapacity(-1, OverflowDropNewest).Err(), ErrInvalidCapacity)
+		assert.ErrorIs(t, New("in").WithCapacity(1, OverflowPolicy(42)).Err(), ErrInvalidCapacity)
+	})
+
+	t.Run("zero capacity makes the port unbounded", func(t *testing.T) {
+		p := New("in").WithCapacity(1, OverflowDropNewest).WithCapacity(0, OverflowDropNewest)
+		p.PutSignals(signal.New(1), signal.New(2))
+		assert.Equal(t, 0, p.Capacity())
+		assert.False(t, p.IsFull())
+		assert.Equal(t, 2, p.Buffer().Len())
+	})
+
+	t.Run("capacity can not be combined with spilling", func(t *testing.T) {
+		p := New("in").WithSpill(SpillConfig{Dir: t.TempDir(), MemoryThreshold: 10}).WithCapacity(1, OverflowDropNewest)
+		assert.ErrorIs(t, p.Err(), ErrInvalidCapacity)
+
+		p = New("in").WithCapacity(1, OverflowDropNewest).WithSpill(SpillConfig{Dir: t.TempDir(), MemoryThreshold: 10})
+		assert.ErrorIs(t, p.Err(), ErrInvalidSpillConfig)
+	})
+
+	t.Run("blocking destinations keep signals on the source", func(t *testing.T) {
+		out := New("out").WithLabels(common.LabelsCollection{DirectionLabel: DirectionOut})
+		small := New("small").WithLabels(common.LabelsCollection{DirectionLabel: DirectionIn}).WithCapacity(2, OverflowBlock)
+		unbounded := New("unbounded").WithLabels(common.LabelsCollection{DirectionLabel: DirectionIn})
+		out.PipeTo(small, unbounded)
+
+		out.PutSignals(signal.NewGroup(1, 2, 3).SignalsOrNil()...)
+		require.False(t, out.Flush().HasErr())
+		assert.Equal(t, []any{1, 2}, payloads(small))
+		assert.Equal(t, []any{1, 2}, payloads(unbounded))
+		assert.Equal(t, []any{3}, payloads(out))
+		assert.True(t, out.HasHeldSignals())
+
+		// Nothing is delivered while the destination is full
+		require.False(t, out.Flush().HasErr())
+		assert.Equal(t, []any{3}, payloads(out))
+
+		small.Clear()
+		require.False(t, out.Flush().HasErr())
+		assert.Equal(t, []any{3}, payloads(small))
+		assert.Equal(t, []any{1, 2, 3}, payloads(unbounded))
+		assert.False(t, out.HasHeldSignals())
+	})
+}
@@ -0,0 +1,41 @@
+package port
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/signal"
+)
+
+// FromChannel receives all values currently available in the channel (without blocking) and returns them as signals,
+// it stops when the channel is empty or closed
+func FromChannel[T any](ch <-chan T) *signal.Group {
+	group := signal.NewGroup()
+	for {
+		select {
+		case value, ok := <-ch:
+			if !ok {
+				return group
+			}
+			group = group.With(signal.New(value))
+		default:
+			return group
+		}
+	}
+}
+
+// ToChannel sends payloads of all signals buffered in the port to the channel (blocking on each send),
+// payloads must be of the channel element type, the port is not cleared
+func ToChannel[T any](p *Port, ch chan<- T) error {
+	signals, err := p.AllSignals()
+	if err != nil {
+		return err
+	}
+
+	for _, sig := range signals {
+		value, err := signal.PayloadAs[T](sig)
+		if err != nil {
+			return fmt.Errorf("port %s: %w", p.Name(), err)
+		}
+		ch <- value
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package port
+
+import (
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFromChannel(t *testing.T) {
+	t.Run("available values are received", func(t *testing.T) {
+		ch := make(chan int, 5)
+		ch <- 1
+		ch <- 2
+		ch <- 3
+
+		payloads, err := FromChannel(ch).AllPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, 2, 3}, payloads)
+		assert.Empty(t, ch)
+	})
+
+	t.Run("empty channel does not block", func(t *testing.T) {
+		assert.Zero(t, FromChannel(make(chan int)).Len())
+	})
+
+	t.Run("closed channel", func(t *testing.T) {
+		ch := make(chan string, 1)
+		ch <- "last"
+		close(ch)
+
+		payloads, err := FromChannel(ch).AllPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"last"}, payloads)
+	})
+}
+
+func TestToChannel(t *testing.T) {
+	t.Run("payloads are sent", func(t *testing.T) {
+		ch := make(chan int, 3)
+		p := New("p").WithSignalGroups(signal.NewGroup(1, 2, 3))
+
+		assert.NoError(t, ToChannel(p, ch))
+		close(ch)
+
+		var got []int
+		for v := range ch {
+			got = append(got, v)
+		}
+		assert.Equal(t, []int{1, 2, 3}, got)
+		assert.True(t, p.HasSignals(), "port must not be cleared")
+	})
+
+	t.Run("unexpected payload type", func(t *testing.T) {
+		ch := make(chan int, 3)
+		p := New("p").WithSignalGroups(signal.NewGroup(1, "two"))
+
+		assert.ErrorIs(t, ToChannel(p, ch), ErrUnexpectedPayloadType)
+		assert.Len(t, ch, 1)
+	})
+
+	t.Run("port with chain error", func(t *testing.T) {
+		p := New("p").WithErr(ErrNilPort)
+		assert.ErrorIs(t, ToChannel(p, make(chan int)), ErrNilPort)
+	})
+}
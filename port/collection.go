@@ -28,11 +28,13 @@ func NewCollection() *Collection {
 	}
 }
 
-// ByName returns a port by its name
+// ByName returns a port by its name, the port is a stable handle: it stays the same until the port is replaced,
+// so activation functions can look it up once and reuse it (e.g. within a loop over signals)
 func (collection *Collection) ByName(name string) *Port {
 	if collection.HasErr() {
 		return New("").WithErr(collection.Err())
 	}
+
 	port, ok := collection.ports[name]
 	if !ok {
 		collection.SetErr(fmt.Errorf("%w, port name: %s", ErrPortNotFoundInCollection, name))
@@ -41,6 +43,19 @@ func (collection *Collection) ByName(name string) *Port {
 	return port
 }
 
+// ByNameE returns the port by name, unlike ByName it returns the error (wrapping ErrPortNotFoundInCollection) instead of setting it on the collection
+func (collection *Collection) ByNameE(name string) (*Port, error) {
+	if collection.HasErr() {
+		return nil, collection.Err()
+	}
+
+	port, ok := collection.ports[name]
+	if !ok {
+		return nil, fmt.Errorf("%w, port name: %s", ErrPortNotFoundInCollection, name)
+	}
+	return port, nil
+}
+
 // ByNames returns multiple ports by their names
 func (collection *Collection) ByNames(names ...string) *Collection {
 	if collection.HasErr() {
@@ -59,6 +74,54 @@ func (collection *Collection) ByNames(names ...string) *Collection {
 	return selectedPorts
 }
 
+// ByLabelMatch returns ports having a label which key and value match the patterns (see common.LabelMatcher)
+func (collection *Collection) ByLabelMatch(keyPattern string, valuePattern string) *Collection {
+	if collection.HasErr() {
+		return NewCollection().WithErr(collection.Err())
+	}
+
+	matcher, err := common.NewLabelMatcher(keyPattern, valuePattern)
+	if err != nil {
+		collection.SetErr(err)
+		return NewCollection().WithErr(collection.Err())
+	}
+
+	//Preserve collection config
+	selectedPorts := NewCollection().WithDefaultLabels(collection.defaultLabels)
+
+	for _, p := range collection.ports {
+		if p.MatchesLabels(matcher) {
+			selectedPorts.With(p)
+		}
+	}
+
+	return selectedPorts
+}
+
+// ByLabelQuery returns ports satisfying the query, e.g. "stage > 3" (see common.LabelQuery)
+func (collection *Collection) ByLabelQuery(query string) *Collection {
+	if collection.HasErr() {
+		return NewCollection().WithErr(collection.Err())
+	}
+
+	q, err := common.ParseLabelQuery(query)
+	if err != nil {
+		collection.SetErr(err)
+		return NewCollection().WithErr(collection.Err())
+	}
+
+	//Preserve collection config
+	selectedPorts := NewCollection().WithDefaultLabels(collection.defaultLabels)
+
+	for _, p := range collection.ports {
+		if p.MatchesLabels(q) {
+			selectedPorts.With(p)
+		}
+	}
+
+	return selectedPorts
+}
+
 // AnyHasSignals returns true if at least one port in collection has signals
 func (collection *Collection) AnyHasSignals() bool {
 	if collection.HasErr() {
@@ -156,7 +219,12 @@ func (collection *Collection) With(ports ...*Port) *Collection {
 		if port.HasErr() {
 			return collection.WithErr(port.Err())
 		}
-		port.AddLabels(collection.defaultLabels)
+		if port.LabelsCount() == 0 {
+			// Ports without own labels share the default ones (copied on write), which saves a map per port
+			port.ShareLabels(collection.defaultLabels)
+		} else {
+			port.AddLabels(collection.defaultLabels)
+		}
 		collection.ports[port.Name()] = port
 	}
 
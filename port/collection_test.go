@@ -6,6 +6,7 @@ import (
 	"github.com/hovsep/fmesh/common"
 	"github.com/hovsep/fmesh/signal"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -122,11 +123,43 @@ func TestCollection_ByName(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := tt.collection.ByName(tt.args.name)
-			assert.Equal(t, tt.want, got)
+			if tt.want.HasErr() {
+				assert.Equal(t, tt.want, got)
+				return
+			}
+			// Labels may be shared with the collection, so compare the observable state rather than the struct
+			assert.Equal(t, tt.want.Name(), got.Name())
+			assert.Equal(t, tt.want.Labels(), got.Labels())
+			assert.Equal(t, tt.want.AllSignalsOrNil(), got.AllSignalsOrNil())
+			assert.Equal(t, tt.want.Pipes(), got.Pipes())
 		})
 	}
 }
 
+func TestCollection_SharedDefaultLabels(t *testing.T) {
+	collection := NewCollection().WithDefaultLabels(common.LabelsCollection{
+		DirectionLabel: DirectionIn,
+	}).With(New("p1"), New("p2"))
+
+	collection.ByName("p1").Labels()["l1"] = "v1"
+	assert.False(t, collection.ByName("p1").HasLabel("l1"))
+	assert.False(t, collection.ByName("p2").HasLabel("l1"), "labels of other ports must not change")
+
+	filtered := collection.ByNames("p1", "p2")
+	assert.Equal(t, 2, filtered.Len())
+	assert.Equal(t, DirectionIn, filtered.ByName("p2").LabelOrDefault(DirectionLabel, ""))
+}
+
+func TestCollection_ByName_StableHandle(t *testing.T) {
+	collection := NewCollection().With(New("p1"), New("p2"))
+	p1 := collection.ByName("p1")
+	assert.Same(t, p1, collection.ByName(strings.Repeat("p", 1)+"1"), "names built at runtime resolve to the same port")
+
+	replacement := New("p1")
+	collection.With(replacement)
+	assert.Same(t, replacement, collection.ByName("p1"))
+}
+
 func TestCollection_ByNames(t *testing.T) {
 	type args struct {
 		names []string
@@ -424,3 +457,82 @@ func TestCollection_Signals(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkCollection_ByName(b *testing.B) {
+	for _, size := range []int{3, 8, 50} {
+		b.Run(fmt.Sprintf("%d ports", size), func(b *testing.B) {
+			collection := NewCollection().WithIndexed("port_", 1, size)
+			name := fmt.Sprintf("port_%d", size)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				collection.ByName(name)
+			}
+		})
+	}
+}
+
+func TestCollection_ByLabelMatch(t *testing.T) {
+	newCollection := func() *Collection {
+		return NewCollection().With(
+			New("p1").WithLabels(common.LabelsCollection{"region": "region/eu/west"}),
+			New("p2").WithLabels(common.LabelsCollection{"region": "region/us/east"}),
+			New("p3"),
+		)
+	}
+
+	type args struct {
+		keyPattern   string
+		valuePattern string
+	}
+	tests := []struct {
+		name       string
+		collection *Collection
+		args       args
+		want       []string
+		wantErr    error
+	}{
+		{
+			name:       "glob",
+			collection: newCollection(),
+			args: args{
+				keyPattern:   "region",
+				valuePattern: "region/eu/*",
+			},
+			want: []string{"p1"},
+		},
+		{
+			name:       "regexp",
+			collection: newCollection(),
+			args: args{
+				keyPattern:   "region",
+				valuePattern: "re:^region/(eu|us)/",
+			},
+			want: []string{"p1", "p2"},
+		},
+		{
+			name:       "invalid pattern",
+			collection: newCollection(),
+			args: args{
+				keyPattern:   "region",
+				valuePattern: "[",
+			},
+			wantErr: common.ErrInvalidLabelPattern,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected := tt.collection.ByLabelMatch(tt.args.keyPattern, tt.args.valuePattern)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, selected.Err(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, selected.Err())
+			var names []string
+			for name := range selected.PortsOrNil() {
+				names = append(names, name)
+			}
+			assert.ElementsMatch(t, tt.want, names)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package port
+
+import (
+	"github.com/hovsep/fmesh/signal"
+)
+
+// dedupWindow remembers idempotency keys of the latest signals accepted by a port
+type dedupWindow struct {
+	size int
+	seen map[string]struct{}
+	// keys is the ring of remembered keys in order of arrival, next is the slot to overwrite
+	keys []string
+	next int
+}
+
+// WithDedupWindow makes the port drop incoming signals whose idempotency key (see signal.WithIdempotencyKey)
+// is among the keys of the latest size accepted signals, so redelivered signals are not processed twice.
+// Signals without a key are always accepted
+func (p *Port) WithDedupWindow(size int) *Port {
+	if p.HasErr() {
+		return p
+	}
+
+	if size <= 0 {
+		p.dedup = nil
+		return p
+	}
+
+	p.dedup = &dedupWindow{
+		size: size,
+		seen: make(map[string]struct{}, size),
+		keys: make([]string, 0, size),
+	}
+	return p
+}
+
+// accept filters out duplicates and remembers the keys of accepted signals
+func (w *dedupWindow) accept(signals signal.Signals) signal.Signals {
+	accepted := make(signal.Signals, 0, len(signals))
+	for _, sig := range signals {
+		key := sig.IdempotencyKey()
+		if key == "" {
+			accepted = append(accepted, sig)
+			continue
+		}
+
+		if _, ok := w.seen[key]; ok {
+			continue
+		}
+		w.remember(key)
+		accepted = append(accepted, sig)
+	}
+	return accepted
+}
+
+// remember adds the key to the window, evicting the oldest key when the window is full
+func (w *dedupWindow) remember(key string) {
+	w.seen[key] = struct{}{}
+	if len(w.keys) < w.size {
+		w.keys = append(w.keys, key)
+		return
+	}
+
+	delete(w.seen, w.keys[w.next])
+	w.keys[w.next] = key
+	w.next = (w.next + 1) % w.size
+}
@@ -0,0 +1,89 @@
+package port
+
+import (
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPort_WithDedupWindow(t *testing.T) {
+	keyed := func(payload any, key string) *signal.Signal {
+		return signal.New(payload).WithIdempotencyKey(key)
+	}
+
+	tests := []struct {
+		name    string
+		window  int
+		batches [][]*signal.Signal
+		want    []any
+	}{
+		{
+			name:   "duplicates are dropped",
+			window: 10,
+			batches: [][]*signal.Signal{
+				{keyed(1, "a"), keyed(2, "b")},
+				{keyed(3, "a"), keyed(4, "c"), keyed(5, "c")},
+			},
+			want: []any{1, 2, 4},
+		},
+		{
+			name:   "signals without key are always accepted",
+			window: 10,
+			batches: [][]*signal.Signal{
+				{signal.New(1), signal.New(1)},
+			},
+			want: []any{1, 1},
+		},
+		{
+			name:   "oldest keys are evicted",
+			window: 2,
+			batches: [][]*signal.Signal{
+				{keyed(1, "a"), keyed(2, "b"), keyed(3, "c")},
+				{keyed(4, "a"), keyed(5, "c")},
+			},
+			want: []any{1, 2, 3, 4},
+		},
+		{
+			name:   "disabled",
+			window: 0,
+			batches: [][]*signal.Signal{
+				{keyed(1, "a"), keyed(2, "a")},
+			},
+			want: []any{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New("p").WithDedupWindow(tt.window)
+			for _, batch := range tt.batches {
+				p.PutSignals(batch...)
+			}
+			payloads, err := p.AllSignalsPayloads()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, payloads)
+		})
+	}
+
+	t.Run("piped signals are deduplicated", func(t *testing.T) {
+		src := New("src").WithLabels(map[string]string{DirectionLabel: DirectionOut})
+		dst := New("dst").WithLabels(map[string]string{DirectionLabel: DirectionIn}).WithDedupWindow(10)
+		src.PipeTo(dst)
+
+		src.PutSignals(keyed(1, "a")).Flush()
+		src.PutSignals(keyed(2, "a"), keyed(3, "b")).Flush()
+
+		payloads, err := dst.AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, 3}, payloads)
+	})
+
+	t.Run("retained signals are not duplicates", func(t *testing.T) {
+		p := New("p").WithDedupWindow(10).PutSignals(keyed(1, "a"), keyed(2, "b"))
+		p.RetainSignals(func(sig *signal.Signal) bool {
+			return sig.IdempotencyKey() == "b"
+		})
+		payloads, err := p.AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{2}, payloads)
+	})
+}
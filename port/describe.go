@@ -0,0 +1,72 @@
+package port
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/common"
+	"strings"
+)
+
+// String returns a short readable representation of the port, e.g. "out port sum (2 signals, 1 pipe)"
+func (p *Port) String() string {
+	if p.HasErr() {
+		return fmt.Sprintf("port %s (error: %v)", p.Name(), p.Err())
+	}
+
+	details := []string{plural(p.Buffer().Len(), "signal")}
+	if spilled := p.SpilledLen(); spilled > 0 {
+		details = append(details, fmt.Sprintf("%d spilled", spilled))
+	}
+	if p.HasPipes() {
+		details = append(details, plural(p.Pipes().Len(), "pipe"))
+	}
+	return fmt.Sprintf("%s port %s (%s)", p.LabelOrDefault(DirectionLabel, "unknown"), p.Name(), strings.Join(details, ", "))
+}
+
+// Describe returns a readable summary of the port: the short representation, user labels and names of piped ports
+// (ports do not know their components, see FMesh.Describe for pipes with components)
+func (p *Port) Describe() string {
+	var sb strings.Builder
+	sb.WriteString(p.String())
+	if p.HasErr() {
+		return sb.String()
+	}
+
+	if labels := userLabels(p.Labels()); len(labels) > 0 {
+		sb.WriteString("\n  labels: " + labels.String())
+	}
+	if p.HasPipes() {
+		names := make([]string, 0, p.Pipes().Len())
+		for i, dest := range p.Pipes().PortsOrNil() {
+			if rule, ok := p.rules[i]; ok {
+				names = append(names, fmt.Sprintf("%s (%s)", dest.Name(), rule.description))
+				continue
+			}
+			names = append(names, dest.Name())
+		}
+		sb.WriteString("\n  pipes to: " + strings.Join(names, ", "))
+	}
+	return sb.String()
+}
+
+// userLabels returns labels without the direction label, which is implied by the collection a port belongs to
+func userLabels(labels common.LabelsCollection) common.LabelsCollection {
+	if _, ok := labels[DirectionLabel]; !ok {
+		return labels
+	}
+
+	userLabels := make(common.LabelsCollection, len(labels)-1)
+	for key, value := range labels {
+		if key != DirectionLabel {
+			userLabels[key] = value
+		}
+	}
+	return userLabels
+}
+
+// plural returns the count with the noun in singular or plural form
+func plural(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
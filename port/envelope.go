@@ -0,0 +1,49 @@
+package port
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/signal"
+)
+
+// SignalsByHeader returns buffered envelope signals having the header with the given value (see signal.Envelope)
+func (p *Port) SignalsByHeader(key string, value any) signal.Signals {
+	return p.Buffer().ByHeader(key, value).SignalsOrNil()
+}
+
+// RouteByHeader puts buffered envelope signals to the port mapped to the value of their header, bodies are not touched.
+// Signals which can not be routed (not envelopes, without the header, with a value of another type or not in routes)
+// are returned, so the caller decides what to do with them. The source port is not cleared
+func RouteByHeader[K comparable](source *Port, key string, routes map[K]*Port) (signal.Signals, error) {
+	if source == nil {
+		return nil, ErrNilPort
+	}
+
+	signals, err := source.AllSignals()
+	if err != nil {
+		return nil, err
+	}
+
+	var unrouted signal.Signals
+	for _, sig := range signals {
+		header, ok := sig.Header(key)
+		if !ok {
+			unrouted = append(unrouted, sig)
+			continue
+		}
+		value, ok := header.(K)
+		if !ok {
+			unrouted = append(unrouted, sig)
+			continue
+		}
+		dest, ok := routes[value]
+		if !ok {
+			unrouted = append(unrouted, sig)
+			continue
+		}
+
+		if dest.PutSignals(sig).HasErr() {
+			return nil, fmt.Errorf("failed to route signal to port %s: %w", dest.Name(), dest.Err())
+		}
+	}
+	return unrouted, nil
+}
@@ -0,0 +1,31 @@
+package port
+
+import (
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRouteByHeader(t *testing.T) {
+	source := New("in").WithSignals(
+		signal.NewEnvelopeSignal(signal.Headers{"kind": "order"}, 1),
+		signal.NewEnvelopeSignal(signal.Headers{"kind": "refund"}, 2),
+		signal.NewEnvelopeSignal(signal.Headers{"kind": "unknown"}, 3),
+		signal.NewEnvelopeSignal(signal.Headers{"kind": 42}, 4),
+		signal.New("not an envelope"),
+	)
+	orders, refunds := New("orders"), New("refunds")
+
+	unrouted, err := RouteByHeader(source, "kind", map[string]*Port{
+		"order":  orders,
+		"refund": refunds,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, unrouted, 3)
+	assert.Len(t, orders.AllSignalsOrNil(), 1)
+	assert.Len(t, refunds.AllSignalsOrNil(), 1)
+	assert.Equal(t, 5, source.Buffer().Len(), "source is not cleared")
+
+	assert.Len(t, source.SignalsByHeader("kind", "order"), 1)
+	assert.Len(t, source.SignalsByHeader("kind", 42), 1)
+}
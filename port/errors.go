@@ -2,6 +2,7 @@ package port
 
 import (
 	"errors"
+	"github.com/hovsep/fmesh/signal"
 )
 
 var (
@@ -10,4 +11,13 @@ var (
 	ErrNilPort                     = errors.New("port is nil")
 	ErrMissingLabel                = errors.New("port is missing required label")
 	ErrInvalidPipeDirection        = errors.New("pipe must go from output to input")
+	ErrUnexpectedPayloadType       = signal.ErrUnexpectedPayloadType
+	ErrInvalidSpillConfig          = errors.New("invalid spill config")
+	ErrFailedToSpill               = errors.New("failed to spill signal to disk")
+	ErrFailedToLoadSpill           = errors.New("failed to load spilled signals")
+	ErrEmptyPortName               = errors.New("port name is empty")
+	ErrDuplicatePortName           = errors.New("duplicate port name")
+	ErrTransformFailed             = errors.New("pipe transform failed")
+	ErrInvalidCapacity             = errors.New("invalid port capacity")
+	ErrPortOverflow                = errors.New("port is at capacity")
 )
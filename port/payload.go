@@ -0,0 +1,52 @@
+package port
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/signal"
+)
+
+// FirstPayloadAs returns the payload of the first signal in the port as T (see signal.PayloadAs)
+func FirstPayloadAs[T any](p *Port) (T, error) {
+	var zero T
+	if p == nil {
+		return zero, ErrNilPort
+	}
+	if p.HasErr() {
+		return zero, p.Err()
+	}
+
+	signals, err := p.AllSignals()
+	if err != nil {
+		return zero, err
+	}
+	if len(signals) == 0 {
+		return zero, fmt.Errorf("port %s: %w", p.Name(), signal.ErrNoSignalsInGroup)
+	}
+
+	value, err := signal.PayloadAs[T](signals[0])
+	if err != nil {
+		return zero, fmt.Errorf("port %s: %w", p.Name(), err)
+	}
+	return value, nil
+}
+
+// AllPayloadsAs returns payloads of all signals in the port as T (see signal.PayloadAs)
+func AllPayloadsAs[T any](p *Port) ([]T, error) {
+	if p == nil {
+		return nil, ErrNilPort
+	}
+
+	signals, err := p.AllSignals()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]T, len(signals))
+	for i, sig := range signals {
+		values[i], err = signal.PayloadAs[T](sig)
+		if err != nil {
+			return nil, fmt.Errorf("port %s, signal %d: %w", p.Name(), i, err)
+		}
+	}
+	return values, nil
+}
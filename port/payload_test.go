@@ -0,0 +1,54 @@
+package port
+
+import (
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFirstPayloadAs(t *testing.T) {
+	tests := []struct {
+		name    string
+		port    *Port
+		want    int
+		wantErr string
+	}{
+		{
+			name: "first payload",
+			port: New("p").WithSignalGroups(signal.NewGroup(1, "2")),
+			want: 1,
+		},
+		{
+			name:    "mismatch",
+			port:    New("p").WithSignalGroups(signal.NewGroup("1")),
+			wantErr: "port p: unexpected payload type: want int, got string",
+		},
+		{
+			name:    "empty port",
+			port:    New("p"),
+			wantErr: "port p: group has no signals",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FirstPayloadAs[int](tt.port)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.False(t, tt.port.HasErr(), "port is not put into error state")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAllPayloadsAs(t *testing.T) {
+	got, err := AllPayloadsAs[int](New("p").WithSignalGroups(signal.NewGroup(1, 2)))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, got)
+
+	_, err = AllPayloadsAs[int](New("p").WithSignalGroups(signal.NewGroup(1, "2")))
+	assert.ErrorIs(t, err, ErrUnexpectedPayloadType)
+	assert.EqualError(t, err, "port p, signal 1: unexpected payload type: want int, got string")
+}
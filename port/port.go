@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/hovsep/fmesh/common"
 	"github.com/hovsep/fmesh/signal"
+	"sync"
 )
 
 const (
@@ -19,6 +20,20 @@ type Port struct {
 	*common.Chainable
 	buffer *signal.Group
 	pipes  *Group //Outbound pipes
+	// rules map indexes of pipes to rules filtering and transforming signals flowing through them (nil when all pipes are plain)
+	rules map[int]pipeRule
+	// bufferMu guards buffer writes, so concurrent writers to disjoint ports never contend
+	bufferMu sync.Mutex
+	// lockFree is set when the port is known to have a single writer at a time, so buffer writes are not locked
+	lockFree bool
+	// dedup is set when duplicate signals must be dropped
+	dedup *dedupWindow
+	// spill is set when signals beyond the memory threshold are kept on disk
+	spill *spill
+	// capacity is set when the number of buffered signals is bounded
+	capacity *capacity
+	// highWatermark is the number of buffered signals making the port push back on sources (0 means never)
+	highWatermark int
 }
 
 // New creates a new port
@@ -71,6 +86,69 @@ func (p *Port) PutSignals(signals ...*signal.Signal) *Port {
 	if p.HasErr() {
 		return p
 	}
+
+	if !p.lockFree {
+		p.bufferMu.Lock()
+		defer p.bufferMu.Unlock()
+	}
+	if p.dedup != nil {
+		signals = p.dedup.accept(signals)
+	}
+	signals, err := p.bound(signals)
+	if err != nil {
+		p.SetErr(err)
+		return New("").WithErr(p.Err())
+	}
+	signals, err = p.admit(signals)
+	if err != nil {
+		p.SetErr(err)
+		return New("").WithErr(p.Err())
+	}
+	return p.withBuffer(p.Buffer().With(signals...))
+}
+
+// PrioritizeSignals orders buffered signals by priority (highest first), signals of the same priority keep their order
+func (p *Port) PrioritizeSignals() *Port {
+	if p.HasErr() {
+		return p
+	}
+
+	if !p.lockFree {
+		p.bufferMu.Lock()
+		defer p.bufferMu.Unlock()
+	}
+	signal.SortByPriority(p.buffer.SignalsOrNil())
+	return p
+}
+
+// putGroup adds all signals of the group to buffer as a single batch
+func (p *Port) putGroup(group *signal.Group) *Port {
+	if p.HasErr() {
+		return p
+	}
+
+	if !p.lockFree {
+		p.bufferMu.Lock()
+		defer p.bufferMu.Unlock()
+	}
+	if (p.dedup == nil && p.spill == nil && p.capacity == nil) || group.HasErr() {
+		return p.withBuffer(p.Buffer().WithGroup(group))
+	}
+
+	signals := group.SignalsOrNil()
+	if p.dedup != nil {
+		signals = p.dedup.accept(signals)
+	}
+	signals, err := p.bound(signals)
+	if err != nil {
+		p.SetErr(err)
+		return New("").WithErr(p.Err())
+	}
+	signals, err = p.admit(signals)
+	if err != nil {
+		p.SetErr(err)
+		return New("").WithErr(p.Err())
+	}
 	return p.withBuffer(p.Buffer().With(signals...))
 }
 
@@ -89,12 +167,11 @@ func (p *Port) WithSignalGroups(signalGroups ...*signal.Group) *Port {
 		return p
 	}
 	for _, group := range signalGroups {
-		signals, err := group.Signals()
-		if err != nil {
-			p.SetErr(err)
+		if group.HasErr() {
+			p.SetErr(group.Err())
 			return New("").WithErr(p.Err())
 		}
-		p.PutSignals(signals...)
+		p.putGroup(group)
 		if p.HasErr() {
 			return New("").WithErr(p.Err())
 		}
@@ -104,11 +181,50 @@ func (p *Port) WithSignalGroups(signalGroups ...*signal.Group) *Port {
 }
 
 // Clear removes all signals from the port buffer
+// (when the port spills to disk, the next batch of spilled signals is loaded instead, see WithSpill)
 func (p *Port) Clear() *Port {
 	if p.HasErr() {
 		return p
 	}
-	return p.withBuffer(signal.NewGroup())
+
+	if !p.lockFree {
+		p.bufferMu.Lock()
+		defer p.bufferMu.Unlock()
+	}
+	return p.withBuffer(signal.NewGroup()).refill()
+}
+
+// RetainSignals keeps only the buffered signals for which keep returns true (in their order),
+// unlike Clear followed by PutSignals it does not treat kept signals as new arrivals
+func (p *Port) RetainSignals(keep func(sig *signal.Signal) bool) *Port {
+	if p.HasErr() {
+		return p
+	}
+
+	if !p.lockFree {
+		p.bufferMu.Lock()
+		defer p.bufferMu.Unlock()
+	}
+	signals := p.buffer.SignalsOrNil()
+	kept := make(signal.Signals, 0, len(signals))
+	for _, sig := range signals {
+		if keep(sig) {
+			kept = append(kept, sig)
+		}
+	}
+	return p.withBuffer(signal.NewGroup().With(kept...)).refill()
+}
+
+// SetLockFree switches the buffer to lock-free mode, which is only safe when the port has a single writer at a time
+// (used by the mesh for input ports fed by a single component)
+// @TODO: hide this method from user
+func (p *Port) SetLockFree(lockFree bool) {
+	p.lockFree = lockFree
+}
+
+// IsLockFree says whether the buffer is in lock-free mode
+func (p *Port) IsLockFree() bool {
+	return p.lockFree
 }
 
 // Flush pushes buffer to pipes and clears the port
@@ -130,13 +246,28 @@ func (p *Port) Flush() *Port {
 		return New("").WithErr(p.Err())
 	}
 
-	for _, outboundPort := range pipes {
-		//Fan-Out
-		err = ForwardSignals(p, outboundPort)
-		if err != nil {
-			p.SetErr(err)
-			return New("").WithErr(p.Err())
-		}
+	// Signals which do not fit into destinations blocking on overflow stay for the next flush
+	signals := p.AllSignalsOrNil()
+	n := flushable(signals, pipes)
+	if n == 0 {
+		return p
+	}
+	if n < len(signals) {
+		p.buffer = signal.NewGroup().With(signals[:n]...)
+	}
+
+	//Fan-Out
+	if p.rules == nil {
+		err = ForwardSignals(p, pipes...)
+	} else {
+		err = p.forwardByRules(pipes)
+	}
+	if err != nil {
+		p.SetErr(err)
+		return New("").WithErr(p.Err())
+	}
+	if n < len(signals) {
+		return p.withBuffer(signal.NewGroup().With(signals[n:]...))
 	}
 	return p.Clear()
 }
@@ -196,23 +327,64 @@ func (p *Port) WithLabels(labels common.LabelsCollection) *Port {
 	return p
 }
 
-// ForwardSignals copies all buffer from source port to destination port, without clearing the source port
-func ForwardSignals(source *Port, dest *Port) error {
+// ForwardSignals copies all buffer from source port to destination port(s), without clearing the source port,
+// the whole buffer is appended to each destination as a single batch
+func ForwardSignals(source *Port, dests ...*Port) error {
 	if source.HasErr() {
 		return source.Err()
 	}
 
-	if dest.HasErr() {
-		return dest.Err()
+	buffer := source.Buffer()
+	if buffer.HasErr() {
+		return buffer.Err()
+	}
+
+	if len(dests) > 1 && buffer.HasStreams() {
+		return forwardStreams(buffer.SignalsOrNil(), dests)
+	}
+
+	for _, dest := range dests {
+		if dest.HasErr() {
+			return dest.Err()
+		}
+
+		dest.putGroup(buffer)
+		if dest.HasErr() {
+			return dest.Err()
+		}
+	}
+	return nil
+}
+
+// MoveSignals forwards all buffer from source port to destination port(s) like ForwardSignals and clears the source port,
+// the source is not cleared when forwarding fails
+func MoveSignals(source *Port, dests ...*Port) error {
+	if err := ForwardSignals(source, dests...); err != nil {
+		return err
+	}
+
+	if source.Clear().HasErr() {
+		return source.Err()
 	}
+	return nil
+}
 
-	signals, err := source.AllSignals()
+// forwardStreams forwards signals to many destinations, each destination gets its own branch of every stream
+func forwardStreams(signals signal.Signals, dests []*Port) error {
+	teed, err := signal.TeeSignals(signals, len(dests))
 	if err != nil {
 		return err
 	}
-	dest.PutSignals(signals...)
-	if dest.HasErr() {
-		return dest.Err()
+
+	for i, dest := range dests {
+		if dest.HasErr() {
+			return dest.Err()
+		}
+
+		dest.PutSignals(teed[i]...)
+		if dest.HasErr() {
+			return dest.Err()
+		}
 	}
 	return nil
 }
@@ -252,6 +424,11 @@ func (p *Port) AllSignalsOrDefault(defaultSignals signal.Signals) signal.Signals
 	return p.Buffer().SignalsOrDefault(defaultSignals)
 }
 
+// SignalsByCorrelation returns buffered signals with the given correlation ID (see signal.NewCorrelated)
+func (p *Port) SignalsByCorrelation(id string) signal.Signals {
+	return p.Buffer().ByCorrelation(id).SignalsOrNil()
+}
+
 // AllSignalsPayloads is shortcut method
 func (p *Port) AllSignalsPayloads() ([]any, error) {
 	return p.Buffer().AllPayloads()
@@ -5,6 +5,8 @@ import (
 	"github.com/hovsep/fmesh/common"
 	"github.com/hovsep/fmesh/signal"
 	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
 	"testing"
 )
 
@@ -436,6 +438,85 @@ func TestPort_Flush(t *testing.T) {
 	}
 }
 
+func TestForwardSignals(t *testing.T) {
+	inLabels := common.LabelsCollection{DirectionLabel: DirectionIn}
+
+	t.Run("whole buffer is forwarded to all destinations", func(t *testing.T) {
+		source := New("src").WithSignalGroups(signal.NewGroup(1, 2, 3))
+		d1 := New("d1").WithLabels(inLabels).WithSignalGroups(signal.NewGroup(0))
+		d2 := New("d2").WithLabels(inLabels)
+
+		assert.NoError(t, ForwardSignals(source, d1, d2))
+		assert.Equal(t, 3, source.Buffer().Len(), "source must not be cleared")
+
+		payloads, err := d1.AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{0, 1, 2, 3}, payloads)
+
+		payloads, err = d2.AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, 2, 3}, payloads)
+	})
+
+	t.Run("destinations do not share the buffer", func(t *testing.T) {
+		source := New("src").WithSignalGroups(signal.NewGroup(1))
+		d1, d2 := New("d1"), New("d2")
+
+		assert.NoError(t, ForwardSignals(source, d1, d2))
+		d1.PutSignals(signal.New(2))
+		assert.Equal(t, 1, d2.Buffer().Len())
+		assert.Equal(t, 1, source.Buffer().Len())
+	})
+
+	t.Run("each destination gets its own branch of a stream", func(t *testing.T) {
+		source := New("src").WithSignals(signal.New(1), signal.NewStreamSignal(strings.NewReader("data")))
+		d1, d2 := New("d1"), New("d2")
+
+		assert.NoError(t, ForwardSignals(source, d1, d2))
+		for _, dest := range []*Port{d1, d2} {
+			signals := dest.AllSignalsOrNil()
+			assert.Equal(t, 1, signals[0].PayloadOrNil())
+
+			stream, err := signals[1].Stream()
+			assert.NoError(t, err)
+			got, err := io.ReadAll(stream)
+			assert.NoError(t, err)
+			assert.Equal(t, "data", string(got))
+		}
+	})
+
+	t.Run("source with chain error", func(t *testing.T) {
+		source := New("src").WithErr(errors.New("some error"))
+		assert.EqualError(t, ForwardSignals(source, New("d1")), "some error")
+	})
+
+	t.Run("destination with chain error", func(t *testing.T) {
+		source := New("src").WithSignalGroups(signal.NewGroup(1))
+		assert.EqualError(t, ForwardSignals(source, New("d1"), New("d2").WithErr(errors.New("some error"))), "some error")
+	})
+}
+
+func TestMoveSignals(t *testing.T) {
+	t.Run("source is cleared", func(t *testing.T) {
+		source := New("src").WithSignalGroups(signal.NewGroup(1, 2))
+		d1, d2 := New("d1"), New("d2")
+
+		assert.NoError(t, MoveSignals(source, d1, d2))
+		assert.False(t, source.HasSignals())
+		for _, dest := range []*Port{d1, d2} {
+			payloads, err := dest.AllSignalsPayloads()
+			assert.NoError(t, err)
+			assert.Equal(t, []any{1, 2}, payloads)
+		}
+	})
+
+	t.Run("source is kept when forwarding fails", func(t *testing.T) {
+		source := New("src").WithSignalGroups(signal.NewGroup(1))
+		assert.EqualError(t, MoveSignals(source, New("d1").WithErr(errors.New("some error"))), "some error")
+		assert.True(t, source.HasSignals())
+	})
+}
+
 func TestPort_WithLabels(t *testing.T) {
 	type args struct {
 		labels common.LabelsCollection
@@ -540,3 +621,41 @@ func TestPort_ShortcutGetters(t *testing.T) {
 		assert.Equal(t, signal.NewGroup(999).SignalsOrNil(), port.AllSignalsOrDefault(signal.NewGroup(999).SignalsOrNil()))
 	})
 }
+
+func BenchmarkPort_PutSignals(b *testing.B) {
+	sig := signal.New(1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p := New("p")
+		for j := 0; j < 1000; j++ {
+			p.PutSignals(sig)
+		}
+	}
+}
+
+func BenchmarkPort_Flush(b *testing.B) {
+	signals := signal.NewGroup(make([]any, 1000)...).SignalsOrNil()
+	src := New("src").WithLabels(common.LabelsCollection{DirectionLabel: DirectionOut})
+	dests := NewGroup("d1", "d2", "d3").WithPortLabels(common.LabelsCollection{DirectionLabel: DirectionIn}).PortsOrNil()
+	src.PipeTo(dests...)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		src.PutSignals(signals...).Flush()
+		for _, d := range dests {
+			d.Clear()
+		}
+	}
+}
+
+func TestPort_PrioritizeSignals(t *testing.T) {
+	p := New("p").PutSignals(
+		signal.New(1),
+		signal.New(2).WithPriority(signal.PriorityHigh),
+		signal.New(3),
+	)
+	payloads, err := p.PrioritizeSignals().AllSignalsPayloads()
+	assert.NoError(t, err)
+	assert.Equal(t, []any{2, 1, 3}, payloads)
+}
@@ -0,0 +1,56 @@
+package port
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/signal"
+	"sort"
+)
+
+// DefaultRoute is the route of signals which can not be routed by RouteByLabel (like component.LabelRouterDefaultOutput)
+const DefaultRoute = "default"
+
+// RouteByLabel creates pipes delivering each signal only to the port routed by the value of its routeLabelKey label,
+// signals without the label or with a value having no route go to DefaultRoute (or they are dropped when it is not set).
+// Routing pipes make router components unnecessary:
+//
+//	out.RouteByLabel("genre", map[string]*port.Port{
+//		"rock":            rock.InputByName("in"),
+//		"jazz":            jazz.InputByName("in"),
+//		port.DefaultRoute: other.InputByName("in"),
+//	})
+//
+// @TODO: hide this method from AF
+func (p *Port) RouteByLabel(routeLabelKey string, routes map[string]*Port) *Port {
+	if p.HasErr() {
+		return p
+	}
+
+	values := make([]string, 0, len(routes))
+	for value := range routes {
+		values = append(values, value)
+	}
+	// Pipes are created in a stable order
+	sort.Strings(values)
+
+	// routeOf returns the route of the signal
+	routeOf := func(sig *signal.Signal) string {
+		route := sig.LabelOrDefault(routeLabelKey, DefaultRoute)
+		if _, ok := routes[route]; !ok {
+			return DefaultRoute
+		}
+		return route
+	}
+
+	for _, value := range values {
+		rule := pipeRule{
+			filter: func(sig *signal.Signal) bool {
+				return routeOf(sig) == value
+			},
+			description: fmt.Sprintf("%s=%s", routeLabelKey, value),
+		}
+		if p.pipeToWithRule(routes[value], rule).HasErr() {
+			return New("").WithErr(p.Err())
+		}
+	}
+	return p
+}
@@ -0,0 +1,81 @@
+package port
+
+import (
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPort_RouteByLabel(t *testing.T) {
+	newPort := func(name string, direction string) *Port {
+		return New(name).WithLabels(common.LabelsCollection{
+			DirectionLabel: direction,
+		})
+	}
+	newSignal := func(payload any, genre string) *signal.Signal {
+		sig := signal.New(payload)
+		if genre != "" {
+			sig.AddLabel("genre", genre)
+		}
+		return sig
+	}
+	payloads := func(p *Port) []any {
+		payloads, err := p.AllSignalsPayloads()
+		require.NoError(t, err)
+		return payloads
+	}
+
+	t.Run("signals are routed by label values", func(t *testing.T) {
+		out := newPort("out", DirectionOut)
+		rock, jazz, other := newPort("rock", DirectionIn), newPort("jazz", DirectionIn), newPort("other", DirectionIn)
+		out.RouteByLabel("genre", map[string]*Port{
+			"rock":       rock,
+			"jazz":       jazz,
+			DefaultRoute: other,
+		})
+		require.False(t, out.HasErr())
+		assert.Equal(t, 3, out.Pipes().Len())
+
+		out.PutSignals(newSignal(1, "rock"), newSignal(2, "jazz"), newSignal(3, "pop"), newSignal(4, ""), newSignal(5, "rock"))
+		require.False(t, out.Flush().HasErr())
+
+		assert.Equal(t, []any{1, 5}, payloads(rock))
+		assert.Equal(t, []any{2}, payloads(jazz))
+		assert.Equal(t, []any{3, 4}, payloads(other))
+		assert.Contains(t, out.Describe(), "pipes to: other (genre=default), jazz (genre=jazz), rock (genre=rock)")
+	})
+
+	t.Run("unroutable signals are dropped without default route", func(t *testing.T) {
+		out, rock := newPort("out", DirectionOut), newPort("rock", DirectionIn)
+		out.RouteByLabel("genre", map[string]*Port{
+			"rock": rock,
+		})
+
+		out.PutSignals(newSignal(1, "pop"), newSignal(2, "rock"))
+		out.Flush()
+		assert.Equal(t, []any{2}, payloads(rock))
+		assert.False(t, out.HasSignals())
+	})
+
+	t.Run("routes are combined with plain pipes", func(t *testing.T) {
+		out, rock, audit := newPort("out", DirectionOut), newPort("rock", DirectionIn), newPort("audit", DirectionIn)
+		out.PipeTo(audit).RouteByLabel("genre", map[string]*Port{
+			"rock": rock,
+		})
+
+		out.PutSignals(newSignal(1, "pop"), newSignal(2, "rock"))
+		out.Flush()
+		assert.Equal(t, []any{2}, payloads(rock))
+		assert.Equal(t, []any{1, 2}, payloads(audit))
+	})
+
+	t.Run("invalid route", func(t *testing.T) {
+		out := newPort("out", DirectionOut)
+		out.RouteByLabel("genre", map[string]*Port{
+			"rock": newPort("out2", DirectionOut),
+		})
+		assert.ErrorIs(t, out.Err(), ErrInvalidPipeDirection)
+	})
+}
@@ -0,0 +1,311 @@
+package port
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/signal"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// DefaultSpillSegmentSize is the size of a segment file when SpillConfig.SegmentSize is not set
+const DefaultSpillSegmentSize = 64 << 20
+
+// SpillConfig configures spilling of port buffer to disk
+type SpillConfig struct {
+	// Dir is the directory of segment files (created when missing), it must not be shared with other ports
+	Dir string
+	// MemoryThreshold is the max number of signals kept in memory, signals beyond it are spilled to disk
+	MemoryThreshold int
+	// SegmentSize is the size in bytes after which a new segment file is started, 0 means DefaultSpillSegmentSize
+	SegmentSize int64
+	// Codec encodes payloads, nil means signal.JSONCodec (so decoded payloads have generic JSON types)
+	Codec signal.Codec
+}
+
+// spillRecord is a signal in a segment file
+type spillRecord struct {
+	Payload []byte                  `json:"p"`
+	Labels  common.LabelsCollection `json:"l,omitempty"`
+}
+
+// spill is the on-disk FIFO queue of signals which did not fit into memory,
+// signals are appended to segment files, segments are removed once they are read completely
+type spill struct {
+	config SpillConfig
+	// segments are paths of segment files in order, the last one is being written, the first one is being read
+	segments []string
+	nextID   int
+	writer   *os.File
+	written  *bufio.Writer
+	size     int64
+	reader   *os.File
+	read     *bufio.Reader
+	// len is the number of signals on disk
+	len int
+}
+
+// WithSpill makes the port keep at most config.MemoryThreshold signals in memory and append the rest to segment files,
+// so large backlogs do not exhaust memory. Spilled signals are loaded back in order each time the port is cleared
+// (e.g. after the owning component processed the signals in memory), so an activation sees at most MemoryThreshold signals.
+// Payloads are encoded with the codec, so they must be serializable
+func (p *Port) WithSpill(config SpillConfig) *Port {
+	if p.HasErr() {
+		return p
+	}
+
+	if config.MemoryThreshold <= 0 {
+		p.SetErr(fmt.Errorf("%w: memory threshold must be positive, got %d", ErrInvalidSpillConfig, config.MemoryThreshold))
+		return New("").WithErr(p.Err())
+	}
+	if p.capacity != nil {
+		p.SetErr(fmt.Errorf("%w: port has capacity", ErrInvalidSpillConfig))
+		return New("").WithErr(p.Err())
+	}
+	if config.SegmentSize <= 0 {
+		config.SegmentSize = DefaultSpillSegmentSize
+	}
+	if config.Codec == nil {
+		config.Codec = signal.JSONCodec{}
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		p.SetErr(fmt.Errorf("%w: %w", ErrInvalidSpillConfig, err))
+		return New("").WithErr(p.Err())
+	}
+
+	p.spill = &spill{config: config}
+	return p
+}
+
+// SpilledLen returns the number of signals spilled to disk
+func (p *Port) SpilledLen() int {
+	if p.spill == nil {
+		return 0
+	}
+	return p.spill.len
+}
+
+// DiscardSpill drops spilled signals and removes segment files
+func (p *Port) DiscardSpill() error {
+	if p.spill == nil {
+		return nil
+	}
+
+	if !p.lockFree {
+		p.bufferMu.Lock()
+		defer p.bufferMu.Unlock()
+	}
+	return p.spill.discard()
+}
+
+// admit splits incoming signals into the ones kept in memory and spills the rest,
+// order is preserved: nothing is kept in memory while older signals are on disk
+func (p *Port) admit(signals signal.Signals) (signal.Signals, error) {
+	if p.spill == nil {
+		return signals, nil
+	}
+
+	room := 0
+	if p.spill.len == 0 {
+		room = max(p.spill.config.MemoryThreshold-p.buffer.Len(), 0)
+	}
+	if room >= len(signals) {
+		return signals, nil
+	}
+
+	for _, sig := range signals[room:] {
+		if err := p.spill.push(sig); err != nil {
+			return nil, err
+		}
+	}
+	return signals[:room], nil
+}
+
+// refill loads spilled signals into free room of the buffer
+func (p *Port) refill() *Port {
+	if p.spill == nil || p.spill.len == 0 {
+		return p
+	}
+
+	room := p.spill.config.MemoryThreshold - p.buffer.Len()
+	if room <= 0 {
+		return p
+	}
+
+	signals, err := p.spill.pop(room)
+	if err != nil {
+		p.SetErr(err)
+		return New("").WithErr(p.Err())
+	}
+	return p.withBuffer(p.buffer.With(signals...))
+}
+
+// push appends the signal to the last segment
+func (s *spill) push(sig *signal.Signal) error {
+	payload, err := sig.Payload()
+	if err != nil {
+		return err
+	}
+	data, err := s.config.Codec.Encode(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSpill, err)
+	}
+	record, err := json.Marshal(spillRecord{Payload: data, Labels: sig.Labels()})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSpill, err)
+	}
+
+	if s.writer == nil || s.size >= s.config.SegmentSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(record)))
+	if _, err := s.written.Write(header[:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSpill, err)
+	}
+	if _, err := s.written.Write(record); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSpill, err)
+	}
+	s.size += int64(len(header) + len(record))
+	s.len++
+	return nil
+}
+
+// rotate starts a new segment
+func (s *spill) rotate() error {
+	if err := s.closeWriter(); err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.config.Dir, fmt.Sprintf("%020d.seg", s.nextID))
+	s.nextID++
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSpill, err)
+	}
+	s.segments = append(s.segments, path)
+	s.writer, s.written, s.size = f, bufio.NewWriter(f), 0
+	return nil
+}
+
+// pop reads up to n oldest signals
+func (s *spill) pop(n int) (signal.Signals, error) {
+	if s.written != nil {
+		// Records of the segment being written may be read as well
+		if err := s.written.Flush(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailedToLoadSpill, err)
+		}
+	}
+
+	signals := make(signal.Signals, 0, min(n, s.len))
+	for len(signals) < n && s.len > 0 {
+		if s.reader == nil {
+			f, err := os.Open(s.segments[0])
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrFailedToLoadSpill, err)
+			}
+			s.reader, s.read = f, bufio.NewReader(f)
+		}
+
+		var header [4]byte
+		_, err := io.ReadFull(s.read, header[:])
+		if errors.Is(err, io.EOF) && len(s.segments) > 1 {
+			// Segment is read completely and it is not written anymore
+			if err := s.dropFirstSegment(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailedToLoadSpill, err)
+		}
+
+		record := make([]byte, binary.BigEndian.Uint32(header[:]))
+		if _, err := io.ReadFull(s.read, record); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailedToLoadSpill, err)
+		}
+		sig, err := s.decode(record)
+		if err != nil {
+			return nil, err
+		}
+		signals = append(signals, sig)
+		s.len--
+	}
+
+	if s.len == 0 {
+		// Everything is read, so segments are not needed anymore
+		if err := s.discard(); err != nil {
+			return nil, err
+		}
+	}
+	return signals, nil
+}
+
+// decode creates a signal from the record
+func (s *spill) decode(data []byte) (*signal.Signal, error) {
+	var record spillRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToLoadSpill, err)
+	}
+	payload, err := s.config.Codec.Decode(record.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToLoadSpill, err)
+	}
+
+	sig := signal.New(payload)
+	if len(record.Labels) > 0 {
+		sig.WithLabels(record.Labels)
+	}
+	return sig, nil
+}
+
+// dropFirstSegment closes and removes the segment which is read completely
+func (s *spill) dropFirstSegment() error {
+	if err := s.reader.Close(); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToLoadSpill, err)
+	}
+	s.reader, s.read = nil, nil
+	if err := os.Remove(s.segments[0]); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToLoadSpill, err)
+	}
+	s.segments = s.segments[1:]
+	return nil
+}
+
+// closeWriter flushes and closes the segment being written
+func (s *spill) closeWriter() error {
+	if s.writer == nil {
+		return nil
+	}
+	if err := s.written.Flush(); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSpill, err)
+	}
+	if err := s.writer.Close(); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSpill, err)
+	}
+	s.writer, s.written = nil, nil
+	return nil
+}
+
+// discard drops all spilled signals and removes segment files
+func (s *spill) discard() error {
+	errs := []error{s.closeWriter()}
+	if s.reader != nil {
+		errs = append(errs, s.reader.Close())
+		s.reader, s.read = nil, nil
+	}
+	for _, path := range s.segments {
+		errs = append(errs, os.Remove(path))
+	}
+	s.segments = nil
+	s.len = 0
+	return errors.Join(errs...)
+}
@@ -0,0 +1,115 @@
+package port
+
+import (
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestPort_WithSpill(t *testing.T) {
+	// drain clears the port until everything is loaded back, returns payloads of each batch
+	drain := func(p *Port) [][]any {
+		var batches [][]any
+		for p.HasSignals() {
+			payloads, err := p.AllSignalsPayloads()
+			require.NoError(t, err)
+			batches = append(batches, payloads)
+			p.Clear()
+		}
+		return batches
+	}
+
+	tests := []struct {
+		name        string
+		config      SpillConfig
+		put         func(p *Port)
+		wantSpilled int
+		wantBatches [][]any
+		wantErr     error
+	}{
+		{
+			name:   "below threshold nothing is spilled",
+			config: SpillConfig{MemoryThreshold: 3},
+			put: func(p *Port) {
+				p.PutSignals(signal.NewGroup(1, 2).SignalsOrNil()...)
+			},
+			wantSpilled: 0,
+			wantBatches: [][]any{{1, 2}},
+		},
+		{
+			name:   "signals beyond threshold are spilled and loaded back in order",
+			config: SpillConfig{MemoryThreshold: 2},
+			put: func(p *Port) {
+				p.PutSignals(signal.NewGroup(1, 2, 3).SignalsOrNil()...)
+				p.WithSignalGroups(signal.NewGroup(4, 5))
+			},
+			wantSpilled: 3,
+			wantBatches: [][]any{{1, 2}, {float64(3), float64(4)}, {float64(5)}},
+		},
+		{
+			name:   "small segments",
+			config: SpillConfig{MemoryThreshold: 1, SegmentSize: 1},
+			put: func(p *Port) {
+				p.PutSignals(signal.NewGroup("a", "b", "c", "d").SignalsOrNil()...)
+			},
+			wantSpilled: 3,
+			wantBatches: [][]any{{"a"}, {"b"}, {"c"}, {"d"}},
+		},
+		{
+			name:    "invalid threshold",
+			config:  SpillConfig{},
+			put:     func(p *Port) {},
+			wantErr: ErrInvalidSpillConfig,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.config.Dir = dir
+			p := New("p").WithSpill(tt.config)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, p.Err(), tt.wantErr)
+				return
+			}
+
+			tt.put(p)
+			require.False(t, p.HasErr())
+			assert.Equal(t, tt.wantSpilled, p.SpilledLen())
+			assert.Equal(t, tt.wantBatches, drain(p))
+			assert.Equal(t, 0, p.SpilledLen())
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Empty(t, entries, "segments are removed once loaded")
+		})
+	}
+
+	t.Run("labels are kept", func(t *testing.T) {
+		p := New("p").WithSpill(SpillConfig{Dir: t.TempDir(), MemoryThreshold: 1})
+		p.PutSignals(signal.New(1), signal.New(2).WithIdempotencyKey("k"))
+		p.Clear()
+		assert.Equal(t, "k", p.Buffer().First().IdempotencyKey())
+	})
+
+	t.Run("discard", func(t *testing.T) {
+		dir := t.TempDir()
+		p := New("p").WithSpill(SpillConfig{Dir: dir, MemoryThreshold: 1})
+		p.PutSignals(signal.NewGroup(1, 2, 3).SignalsOrNil()...)
+		require.NoError(t, p.DiscardSpill())
+		assert.Equal(t, 0, p.SpilledLen())
+		p.Clear()
+		assert.False(t, p.HasSignals())
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("unserializable payload", func(t *testing.T) {
+		p := New("p").WithSpill(SpillConfig{Dir: t.TempDir(), MemoryThreshold: 1})
+		p.PutSignals(signal.New(1), signal.New(func() {}))
+		assert.ErrorIs(t, p.Err(), ErrFailedToSpill)
+	})
+}
@@ -0,0 +1,118 @@
+package port
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/signal"
+)
+
+// Transform changes a signal flowing through a pipe: it gets a copy of the signal (see signal.Copy), so it can relabel it
+// or replace the payload in place, or it can return another signal (e.g. wrapping the payload). Returning nil drops the signal,
+// returning a signal with an error fails the flush (see ErrTransformFailed)
+type Transform func(sig *signal.Signal) *signal.Signal
+
+// pipeRule defines which signals flow through a pipe and how they are changed
+type pipeRule struct {
+	// filter selects signals flowing through the pipe (nil means all signals)
+	filter func(sig *signal.Signal) bool
+	// transform changes selected signals (nil means signals are delivered as they are)
+	transform Transform
+	// description is shown by Describe
+	description string
+}
+
+// PipeToWith creates a pipe to the port which transforms signals in flight, so format conversions need no glue components.
+// Transforms run when the port is flushed, each pipe applies its own transform to its own copies of signals
+// @TODO: hide this method from AF
+func (p *Port) PipeToWith(destPort *Port, transform Transform) *Port {
+	if transform == nil {
+		return p.PipeTo(destPort)
+	}
+	return p.pipeToWithRule(destPort, pipeRule{
+		transform:   transform,
+		description: "transformed",
+	})
+}
+
+// HasTransform says whether signals flowing through the pipe with the given index are transformed
+func (p *Port) HasTransform(pipeIndex int) bool {
+	return p.rules[pipeIndex].transform != nil
+}
+
+// pipeToWithRule creates a pipe to the port which applies the rule
+func (p *Port) pipeToWithRule(destPort *Port, rule pipeRule) *Port {
+	if p.HasErr() {
+		return p
+	}
+
+	index := p.pipes.Len()
+	if p.PipeTo(destPort).HasErr() {
+		return New("").WithErr(p.Err())
+	}
+	if p.rules == nil {
+		p.rules = make(map[int]pipeRule)
+	}
+	p.rules[index] = rule
+	return p
+}
+
+// forwardByRules forwards signals like ForwardSignals, but signals flowing through pipes with rules are filtered and transformed
+func (p *Port) forwardByRules(pipes Ports) error {
+	signals := p.AllSignalsOrNil()
+	batches := make([]signal.Signals, len(pipes))
+	if len(pipes) > 1 && p.buffer.HasStreams() {
+		teed, err := signal.TeeSignals(signals, len(pipes))
+		if err != nil {
+			return err
+		}
+		batches = teed
+	} else {
+		for i := range batches {
+			batches[i] = signals
+		}
+	}
+
+	for i, dest := range pipes {
+		if dest.HasErr() {
+			return dest.Err()
+		}
+
+		batch := batches[i]
+		if rule, ok := p.rules[i]; ok {
+			applied, err := rule.apply(batch)
+			if err != nil {
+				return fmt.Errorf("pipe to %s: %w", dest.Name(), err)
+			}
+			batch = applied
+		}
+
+		dest.PutSignals(batch...)
+		if dest.HasErr() {
+			return dest.Err()
+		}
+	}
+	return nil
+}
+
+// apply returns signals selected by the filter, transformed copies of them when the rule has a transform (dropped signals are skipped)
+func (rule pipeRule) apply(signals signal.Signals) (signal.Signals, error) {
+	applied := make(signal.Signals, 0, len(signals))
+	for _, sig := range signals {
+		if rule.filter != nil && !rule.filter(sig) {
+			continue
+		}
+		if rule.transform == nil {
+			applied = append(applied, sig)
+			continue
+		}
+
+		result := rule.transform(sig.Copy())
+		if result == nil {
+			continue
+		}
+		if result.HasErr() {
+			return nil, fmt.Errorf("%w: %w", ErrTransformFailed, result.Err())
+		}
+		applied = append(applied, result)
+	}
+	return applied, nil
+}
@@ -0,0 +1,90 @@
+package port
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestPort_PipeToWith(t *testing.T) {
+	newPort := func(name string, direction string) *Port {
+		return New(name).WithLabels(common.LabelsCollection{
+			DirectionLabel: direction,
+		})
+	}
+
+	t.Run("transforms signals in flight", func(t *testing.T) {
+		out := newPort("out", DirectionOut)
+		plain, upper, relabeled := newPort("plain", DirectionIn), newPort("upper", DirectionIn), newPort("relabeled", DirectionIn)
+		out.PipeTo(plain).
+			PipeToWith(upper, func(sig *signal.Signal) *signal.Signal {
+				return sig.WithPayload(strings.ToUpper(sig.PayloadOrNil().(string)))
+			}).
+			PipeToWith(relabeled, func(sig *signal.Signal) *signal.Signal {
+				sig.AddLabel("format", "raw")
+				return sig
+			})
+		require.False(t, out.HasErr())
+
+		original := signal.New("hello").WithLabels(common.LabelsCollection{"lang": "en"})
+		out.PutSignals(original)
+		require.False(t, out.Flush().HasErr())
+
+		assert.Same(t, original, plain.Buffer().First())
+		assert.Equal(t, "HELLO", upper.FirstSignalPayloadOrNil())
+		assert.Equal(t, common.LabelsCollection{"lang": "en", "format": "raw"}, relabeled.Buffer().First().Labels())
+		// The original signal is not changed by transforms
+		assert.Equal(t, "hello", original.PayloadOrNil())
+		assert.Equal(t, common.LabelsCollection{"lang": "en"}, original.Labels())
+		assert.False(t, out.HasSignals())
+	})
+
+	t.Run("dropped signals", func(t *testing.T) {
+		out, in := newPort("out", DirectionOut), newPort("in", DirectionIn)
+		out.PipeToWith(in, func(sig *signal.Signal) *signal.Signal {
+			if sig.PayloadOrNil().(int)%2 == 0 {
+				return nil
+			}
+			return sig
+		})
+
+		out.PutSignals(signal.NewGroup(1, 2, 3).SignalsOrNil()...)
+		out.Flush()
+
+		payloads, err := in.AllSignalsPayloads()
+		require.NoError(t, err)
+		assert.Equal(t, []any{1, 3}, payloads)
+	})
+
+	t.Run("failed transform", func(t *testing.T) {
+		out, in := newPort("out", DirectionOut), newPort("in", DirectionIn)
+		out.PipeToWith(in, func(sig *signal.Signal) *signal.Signal {
+			return sig.WithErr(errors.New("not a number"))
+		})
+
+		out.PutSignals(signal.New("x"))
+		assert.ErrorIs(t, out.Flush().Err(), ErrTransformFailed)
+		assert.False(t, in.HasSignals())
+	})
+
+	t.Run("invalid pipe", func(t *testing.T) {
+		out := newPort("out", DirectionOut)
+		out.PipeToWith(newPort("out2", DirectionOut), func(sig *signal.Signal) *signal.Signal {
+			return sig
+		})
+		assert.ErrorIs(t, out.Err(), ErrInvalidPipeDirection)
+	})
+
+	t.Run("described", func(t *testing.T) {
+		out := newPort("out", DirectionOut)
+		out.PipeTo(newPort("a", DirectionIn)).PipeToWith(newPort("b", DirectionIn), func(sig *signal.Signal) *signal.Signal {
+			return sig
+		})
+		assert.True(t, out.HasTransform(1))
+		assert.Contains(t, out.Describe(), "pipes to: a, b (transformed)")
+	})
+}
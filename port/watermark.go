@@ -0,0 +1,37 @@
+package port
+
+// WithHighWatermark makes the port push back on components piping to it while it buffers at least threshold signals:
+// the mesh does not schedule them until the port catches up (see component.Backpressure).
+// Unlike capacity, the watermark never drops or holds back signals, so a few signals may arrive above it.
+// Non-positive threshold disables backpressure
+func (p *Port) WithHighWatermark(threshold int) *Port {
+	if p.HasErr() {
+		return p
+	}
+
+	p.highWatermark = max(threshold, 0)
+	return p
+}
+
+// HighWatermark returns the high watermark of the port (0 means the port never pushes back)
+func (p *Port) HighWatermark() int {
+	return p.highWatermark
+}
+
+// IsOverHighWatermark says whether the port buffers as many signals as its high watermark, spilled signals included
+// (always false when it is not set)
+func (p *Port) IsOverHighWatermark() bool {
+	if p.highWatermark == 0 {
+		return false
+	}
+
+	if !p.lockFree {
+		p.bufferMu.Lock()
+		defer p.bufferMu.Unlock()
+	}
+	buffered := p.buffer.Len()
+	if p.spill != nil {
+		buffered += p.spill.len
+	}
+	return buffered >= p.highWatermark
+}
@@ -0,0 +1,31 @@
+package port
+
+import (
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPort_WithHighWatermark(t *testing.T) {
+	p := New("in").WithHighWatermark(2)
+	assert.Equal(t, 2, p.HighWatermark())
+	assert.False(t, p.IsOverHighWatermark())
+
+	p.PutSignals(signal.New(1))
+	assert.False(t, p.IsOverHighWatermark())
+
+	// Signals above the watermark are accepted
+	p.PutSignals(signal.New(2), signal.New(3))
+	assert.True(t, p.IsOverHighWatermark())
+	assert.Equal(t, 3, p.Buffer().Len())
+
+	p.Clear()
+	assert.False(t, p.IsOverHighWatermark())
+
+	t.Run("non-positive threshold disables the watermark", func(t *testing.T) {
+		p := New("in").WithHighWatermark(2).WithHighWatermark(-1)
+		p.PutSignals(signal.New(1), signal.New(2))
+		assert.Equal(t, 0, p.HighWatermark())
+		assert.False(t, p.IsOverHighWatermark())
+	})
+}
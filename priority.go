@@ -0,0 +1,16 @@
+package fmesh
+
+// prioritizeSignals orders signals delivered to input ports by priority
+func (fm *FMesh) prioritizeSignals() {
+	if !fm.config.PrioritizeSignals {
+		return
+	}
+
+	for _, c := range fm.compiledTopology().components {
+		for _, p := range c.Inputs().PortsOrNil() {
+			if p.HasSignals() {
+				p.PrioritizeSignals()
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFMesh_PrioritizeSignals(t *testing.T) {
+	newMesh := func(prioritize bool, received *[]any) *FMesh {
+		producer := component.New("producer").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			this.OutputByName("out").PutSignals(
+				signal.New("data-1"),
+				signal.New("shutdown").WithPriority(signal.PriorityHigh),
+				signal.New("metrics").WithPriority(signal.PriorityLow),
+				signal.New("data-2"),
+			)
+			return nil
+		})
+		sink := component.New("sink").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+			payloads, err := this.InputByName("in").AllSignalsPayloads()
+			*received = append(*received, payloads...)
+			return err
+		})
+		producer.OutputByName("out").PipeTo(sink.InputByName("in"))
+
+		fm := NewWithConfig("fm", &Config{
+			CyclesLimit:       10,
+			PrioritizeSignals: prioritize,
+		}).WithComponents(producer, sink)
+		producer.InputByName("in").PutSignals(signal.New(1))
+		return fm
+	}
+
+	t.Run("high priority signals come first", func(t *testing.T) {
+		var received []any
+		_, err := newMesh(true, &received).Run()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"shutdown", "data-1", "data-2", "metrics"}, received)
+	})
+
+	t.Run("arrival order by default", func(t *testing.T) {
+		var received []any
+		_, err := newMesh(false, &received).Run()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"data-1", "shutdown", "metrics", "data-2"}, received)
+	})
+}
@@ -0,0 +1,61 @@
+package remote
+
+import (
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/signal"
+)
+
+// Codec encodes signal payloads for the wire
+type Codec = signal.Codec
+
+// JSONCodec encodes payloads as JSON, decoded payloads have generic JSON types (float64, string, map[string]any, etc.)
+type JSONCodec = signal.JSONCodec
+
+// EncodeSignals encodes payloads and copies labels of the signals for the wire
+func EncodeSignals(signals signal.Signals, codec Codec) ([]Signal, error) {
+	wireSignals := make([]Signal, 0, len(signals))
+	for _, sig := range signals {
+		payload, err := sig.Payload()
+		if err != nil {
+			return nil, err
+		}
+		data, err := codec.Encode(payload)
+		if err != nil {
+			return nil, err
+		}
+		wireSignals = append(wireSignals, Signal{
+			Payload: data,
+			Labels:  copyLabels(sig.Labels()),
+		})
+	}
+	return wireSignals, nil
+}
+
+// DecodeSignals creates signals from the wire
+func DecodeSignals(wireSignals []Signal, codec Codec) (signal.Signals, error) {
+	signals := make(signal.Signals, 0, len(wireSignals))
+	for _, wireSignal := range wireSignals {
+		payload, err := codec.Decode(wireSignal.Payload)
+		if err != nil {
+			return nil, err
+		}
+		sig := signal.New(payload)
+		if len(wireSignal.Labels) > 0 {
+			sig.WithLabels(copyLabels(wireSignal.Labels))
+		}
+		signals = append(signals, sig)
+	}
+	return signals, nil
+}
+
+// copyLabels returns a copy of labels (nil when there are none)
+func copyLabels(labels common.LabelsCollection) common.LabelsCollection {
+	if len(labels) == 0 {
+		return nil
+	}
+	labelsCopy := make(common.LabelsCollection, len(labels))
+	for k, v := range labels {
+		labelsCopy[k] = v
+	}
+	return labelsCopy
+}
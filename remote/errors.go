@@ -0,0 +1,10 @@
+package remote
+
+import (
+	"errors"
+)
+
+var (
+	ErrRemoteActivationFailed = errors.New("remote activation failed")
+	ErrUnexpectedStatus       = errors.New("unexpected response status")
+)
@@ -0,0 +1,71 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPTransport(t *testing.T) {
+	// Remote side: a component hosted behind an HTTP server
+	upper := component.New("upper").
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			payloads, err := this.InputByName("in").AllSignalsPayloads()
+			if err != nil {
+				return err
+			}
+			for _, payload := range payloads {
+				this.OutputByName("out").PutSignals(signal.New(strings.ToUpper(payload.(string))))
+			}
+			return nil
+		})
+	server := httptest.NewServer(NewHTTPHandler(NewServer(upper)))
+	defer server.Close()
+
+	t.Run("proxy in a mesh", func(t *testing.T) {
+		var received []any
+		proxy := NewProxy("upper", NewHTTPTransport(server.URL, nil)).WithInputs("in").WithOutputs("out")
+		sink := component.New("sink").
+			WithInputs("in").
+			WithActivationFunc(func(this *component.Component) error {
+				payloads, err := this.InputByName("in").AllSignalsPayloads()
+				received = append(received, payloads...)
+				return err
+			})
+		proxy.OutputByName("out").PipeTo(sink.InputByName("in"))
+
+		fm := fmesh.New("local").WithComponents(proxy, sink)
+		proxy.InputByName("in").PutSignals(signal.New("hello"), signal.New("world"))
+
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"HELLO", "WORLD"}, received)
+	})
+
+	t.Run("unexpected status", func(t *testing.T) {
+		failing := httptest.NewServer(NewHTTPHandler(TransportFunc(func(ctx context.Context, request *ActivationRequest) (*ActivationResponse, error) {
+			return nil, errors.New("remote is down")
+		})))
+		defer failing.Close()
+
+		_, err := NewHTTPTransport(failing.URL, nil).Activate(context.Background(), &ActivationRequest{})
+		assert.ErrorIs(t, err, ErrUnexpectedStatus)
+		assert.ErrorContains(t, err, "remote is down")
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		response, err := http.Get(server.URL)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+	})
+}
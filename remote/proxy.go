@@ -0,0 +1,95 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"sort"
+)
+
+// NewProxy creates a component forwarding all input signals to the remote component via the transport
+// and emitting the returned signals on output ports with the same names,
+// ports are added as usual: NewProxy("scorer", transport).WithInputs("features").WithOutputs("score")
+func NewProxy(name string, transport Transport) *component.Component {
+	return NewProxyWithCodec(name, transport, JSONCodec{})
+}
+
+// NewProxyWithCodec creates a proxy component using the given payload codec
+func NewProxyWithCodec(name string, transport Transport, codec Codec) *component.Component {
+	return component.New(name).
+		WithDescription("proxy to a remote component").
+		WithActivationFunc(func(this *component.Component) error {
+			request, err := encodePorts(this.Inputs(), codec)
+			if err != nil {
+				return err
+			}
+
+			response, err := transport.Activate(this.Context(), &ActivationRequest{
+				Component: this.Name(),
+				Inputs:    request,
+			})
+			if err != nil {
+				return errors.Join(ErrRemoteActivationFailed, err)
+			}
+			if response.Error != "" {
+				return fmt.Errorf("%w: %s", ErrRemoteActivationFailed, response.Error)
+			}
+
+			return decodePorts(response.Outputs, this.Outputs(), codec)
+		})
+}
+
+// encodePorts encodes all ports having signals (sorted by name, so requests are deterministic)
+func encodePorts(collection *port.Collection, codec Codec) ([]Port, error) {
+	ports, err := collection.Ports()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(ports))
+	for name, p := range ports {
+		if p.HasSignals() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	encoded := make([]Port, 0, len(names))
+	for _, name := range names {
+		signals, err := ports[name].AllSignals()
+		if err != nil {
+			return nil, err
+		}
+
+		wireSignals, err := EncodeSignals(signals, codec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode signals on port %s: %w", name, err)
+		}
+		encoded = append(encoded, Port{
+			Name:    name,
+			Signals: wireSignals,
+		})
+	}
+	return encoded, nil
+}
+
+// decodePorts puts decoded signals to the ports of the collection
+func decodePorts(wirePorts []Port, collection *port.Collection, codec Codec) error {
+	for _, wirePort := range wirePorts {
+		// Unknown ports do not put the collection into error state, so a bad request does not break the following ones
+		p, err := collection.ByNameE(wirePort.Name)
+		if err != nil {
+			return err
+		}
+
+		signals, err := DecodeSignals(wirePort.Signals, codec)
+		if err != nil {
+			return fmt.Errorf("failed to decode signals on port %s: %w", wirePort.Name, err)
+		}
+		if p.PutSignals(signals...).HasErr() {
+			return p.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewProxy(t *testing.T) {
+	tests := []struct {
+		name       string
+		transport  TransportFunc
+		inputs     map[string][]*signal.Signal
+		assertions func(t *testing.T, proxy *component.Component, activationResult *component.ActivationResult)
+	}{
+		{
+			name: "forwards inputs and emits responses",
+			transport: func(ctx context.Context, request *ActivationRequest) (*ActivationResponse, error) {
+				assert.Equal(t, "proxy", request.Component)
+				assert.Equal(t, []Port{
+					{
+						Name: "a",
+						Signals: []Signal{
+							{Payload: []byte("1"), Labels: common.LabelsCollection{"k": "v"}},
+						},
+					},
+					{
+						Name: "b",
+						Signals: []Signal{
+							{Payload: []byte(`"x"`)},
+							{Payload: []byte(`"y"`)},
+						},
+					},
+				}, request.Inputs)
+				return &ActivationResponse{
+					Outputs: []Port{
+						{
+							Name: "out",
+							Signals: []Signal{
+								{Payload: []byte(`{"sum":3}`), Labels: map[string]string{"source": "remote"}},
+							},
+						},
+					},
+				}, nil
+			},
+			inputs: map[string][]*signal.Signal{
+				"a": {signal.New(1).WithLabels(common.LabelsCollection{"k": "v"})},
+				"b": {signal.New("x"), signal.New("y")},
+			},
+			assertions: func(t *testing.T, proxy *component.Component, activationResult *component.ActivationResult) {
+				assert.Equal(t, component.ActivationCodeOK, activationResult.Code())
+				out := proxy.OutputByName("out").AllSignalsOrNil()
+				assert.Len(t, out, 1)
+				assert.Equal(t, map[string]any{"sum": float64(3)}, out[0].PayloadOrNil())
+				assert.Equal(t, "remote", out[0].LabelOrDefault("source", ""))
+			},
+		},
+		{
+			name: "transport error",
+			transport: func(ctx context.Context, request *ActivationRequest) (*ActivationResponse, error) {
+				return nil, errors.New("connection refused")
+			},
+			inputs: map[string][]*signal.Signal{
+				"a": {signal.New(1)},
+			},
+			assertions: func(t *testing.T, proxy *component.Component, activationResult *component.ActivationResult) {
+				assert.Equal(t, component.ActivationCodeReturnedError, activationResult.Code())
+				assert.ErrorIs(t, activationResult.ActivationError(), ErrRemoteActivationFailed)
+			},
+		},
+		{
+			name: "remote activation error",
+			transport: func(ctx context.Context, request *ActivationRequest) (*ActivationResponse, error) {
+				return &ActivationResponse{Error: "boom"}, nil
+			},
+			inputs: map[string][]*signal.Signal{
+				"a": {signal.New(1)},
+			},
+			assertions: func(t *testing.T, proxy *component.Component, activationResult *component.ActivationResult) {
+				assert.Equal(t, component.ActivationCodeReturnedError, activationResult.Code())
+				assert.ErrorIs(t, activationResult.ActivationError(), ErrRemoteActivationFailed)
+				assert.ErrorContains(t, activationResult.ActivationError(), "boom")
+			},
+		},
+		{
+			name: "response to unknown port",
+			transport: func(ctx context.Context, request *ActivationRequest) (*ActivationResponse, error) {
+				return &ActivationResponse{
+					Outputs: []Port{{Name: "missing", Signals: []Signal{{Payload: []byte("1")}}}},
+				}, nil
+			},
+			inputs: map[string][]*signal.Signal{
+				"a": {signal.New(1)},
+			},
+			assertions: func(t *testing.T, proxy *component.Component, activationResult *component.ActivationResult) {
+				assert.Equal(t, component.ActivationCodeReturnedError, activationResult.Code())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := NewProxy("proxy", tt.transport).WithInputs("a", "b").WithOutputs("out")
+			for portName, signals := range tt.inputs {
+				proxy.InputByName(portName).PutSignals(signals...)
+			}
+			tt.assertions(t, proxy, proxy.MaybeActivate())
+		})
+	}
+}
+
+func TestNewProxy_ActivationContext(t *testing.T) {
+	type tenantKey struct{}
+	var got context.Context
+	proxy := NewProxy("proxy", TransportFunc(func(ctx context.Context, request *ActivationRequest) (*ActivationResponse, error) {
+		got = ctx
+		return &ActivationResponse{}, ctx.Err()
+	})).WithInputs("in")
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), tenantKey{}, "acme"))
+	cancel()
+	proxy.WithContext(ctx).InputByName("in").PutSignals(signal.New(1))
+
+	activationResult := proxy.MaybeActivate()
+	assert.Equal(t, "acme", got.Value(tenantKey{}), "transport gets the activation context")
+	assert.ErrorIs(t, activationResult.ActivationError(), context.Canceled)
+}
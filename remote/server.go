@@ -0,0 +1,63 @@
+package remote
+
+import (
+	"context"
+	"github.com/hovsep/fmesh/component"
+	"sync"
+)
+
+// Server hosts a component on the remote side, it implements Transport,
+// so it can be exposed by any transport server (e.g. a gRPC service generated from fmesh.proto or NewHTTPHandler)
+type Server struct {
+	mu        sync.Mutex
+	component *component.Component
+	codec     Codec
+}
+
+// NewServer creates a server activating the given component on each request
+func NewServer(c *component.Component) *Server {
+	return NewServerWithCodec(c, JSONCodec{})
+}
+
+// NewServerWithCodec creates a server using the given payload codec
+func NewServerWithCodec(c *component.Component, codec Codec) *Server {
+	return &Server{
+		component: c,
+		codec:     codec,
+	}
+}
+
+// Activate puts request signals to the component inputs, activates it and returns the output signals,
+// activations are serialized as a component is not safe for concurrent use. The component is activated with the request context
+func (s *Server) Activate(ctx context.Context, request *ActivationRequest) (*ActivationResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.component
+	defer func() {
+		c.ClearInputs()
+		c.Outputs().Clear()
+	}()
+
+	if err := decodePorts(request.Inputs, c.Inputs(), s.codec); err != nil {
+		return nil, err
+	}
+
+	activationResult := c.WithContext(ctx).MaybeActivate()
+	if activationResult.HasErr() {
+		return nil, activationResult.Err()
+	}
+	if activationResult.IsError() || activationResult.IsPanic() {
+		return &ActivationResponse{
+			Error: activationResult.ActivationError().Error(),
+		}, nil
+	}
+
+	outputs, err := encodePorts(c.Outputs(), s.codec)
+	if err != nil {
+		return nil, err
+	}
+	return &ActivationResponse{
+		Outputs: outputs,
+	}, nil
+}
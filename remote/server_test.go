@@ -0,0 +1,67 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestServer_Activate(t *testing.T) {
+	doubler := component.New("doubler").
+		WithInputs("num").
+		WithOutputs("res").
+		WithActivationFunc(func(this *component.Component) error {
+			num := this.InputByName("num").FirstSignalPayloadOrDefault(0.0).(float64)
+			if num < 0 {
+				return errors.New("negative number")
+			}
+			if err := this.Context().Err(); err != nil {
+				return err
+			}
+			this.OutputByName("res").PutSignals(signal.New(num * 2))
+			return nil
+		})
+	server := NewServer(doubler)
+
+	t.Run("returns outputs", func(t *testing.T) {
+		response, err := server.Activate(context.Background(), &ActivationRequest{
+			Inputs: []Port{{Name: "num", Signals: []Signal{{Payload: []byte("21")}}}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &ActivationResponse{
+			Outputs: []Port{{Name: "res", Signals: []Signal{{Payload: []byte("42")}}}},
+		}, response)
+
+		// Ports are cleared after each activation
+		assert.False(t, doubler.Inputs().AnyHasSignals())
+		assert.False(t, doubler.Outputs().AnyHasSignals())
+	})
+
+	t.Run("returns activation error", func(t *testing.T) {
+		response, err := server.Activate(context.Background(), &ActivationRequest{
+			Inputs: []Port{{Name: "num", Signals: []Signal{{Payload: []byte("-1")}}}},
+		})
+		assert.NoError(t, err)
+		assert.Contains(t, response.Error, "negative number")
+	})
+
+	t.Run("unknown input port", func(t *testing.T) {
+		_, err := server.Activate(context.Background(), &ActivationRequest{
+			Inputs: []Port{{Name: "missing", Signals: []Signal{{Payload: []byte("1")}}}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("activates with the request context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		response, err := server.Activate(ctx, &ActivationRequest{
+			Inputs: []Port{{Name: "num", Signals: []Signal{{Payload: []byte("1")}}}},
+		})
+		assert.NoError(t, err)
+		assert.Contains(t, response.Error, context.Canceled.Error())
+	})
+}
@@ -0,0 +1,68 @@
+// Package remote allows a part of a topology to live in another process:
+// a proxy component forwards its input signals to a remote component and emits the responses as output signals.
+// The wire contract is defined in fmesh.proto and mirrored by the message types below, the package ships an HTTP transport
+// carrying them as JSON (see NewHTTPTransport), other transports can be plugged in by implementing Transport.
+//
+// The module does not depend on gRPC, so stubs are generated by the application (protoc --go_out=. --go-grpc_out=. remote/fmesh.proto)
+// and adapted with a few lines, messages are converted field by field:
+//
+//	// Client side: the proxy calls the generated client
+//	client := remotev1.NewComponentServiceClient(conn)
+//	proxy := remote.NewProxy("scorer", remote.TransportFunc(func(ctx context.Context, request *remote.ActivationRequest) (*remote.ActivationResponse, error) {
+//		response, err := client.Activate(ctx, toProto(request))
+//		if err != nil {
+//			return nil, err
+//		}
+//		return fromProto(response), nil
+//	}))
+//
+//	// Server side: the generated service delegates to Server
+//	func (s *service) Activate(ctx context.Context, request *remotev1.ActivationRequest) (*remotev1.ActivationResponse, error) {
+//		response, err := s.server.Activate(ctx, fromProtoRequest(request))
+//		if err != nil {
+//			return nil, err
+//		}
+//		return toProtoResponse(response), nil
+//	}
+package remote
+
+import (
+	"context"
+)
+
+// Signal is a signal on the wire (mirrors fmesh.remote.v1.Signal)
+type Signal struct {
+	Payload []byte            `json:"payload"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// Port is a port with signals on the wire (mirrors fmesh.remote.v1.Port)
+type Port struct {
+	Name    string   `json:"name"`
+	Signals []Signal `json:"signals"`
+}
+
+// ActivationRequest is sent by the proxy on each activation (mirrors fmesh.remote.v1.ActivationRequest)
+type ActivationRequest struct {
+	Component string `json:"component"`
+	Inputs    []Port `json:"inputs"`
+}
+
+// ActivationResponse is returned by the remote side (mirrors fmesh.remote.v1.ActivationResponse)
+type ActivationResponse struct {
+	Outputs []Port `json:"outputs"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Transport delivers activation requests to the remote component
+type Transport interface {
+	Activate(ctx context.Context, request *ActivationRequest) (*ActivationResponse, error)
+}
+
+// TransportFunc is an adapter to use ordinary functions as transports
+type TransportFunc func(ctx context.Context, request *ActivationRequest) (*ActivationResponse, error)
+
+// Activate calls the function
+func (f TransportFunc) Activate(ctx context.Context, request *ActivationRequest) (*ActivationResponse, error) {
+	return f(ctx, request)
+}
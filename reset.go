@@ -0,0 +1,69 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/cycle"
+	"github.com/hovsep/fmesh/port"
+)
+
+// Reset makes the next run start cold, as if the mesh never ran: cycles, runtime info, dead letters and pending injections are dropped,
+// signals are removed from all ports (including spilled ones), states of all components are reset to empty and degraded components are restored.
+// Components, pipes, labels, config and plugins are kept, plugins are notified (see ResetListener).
+// Without Reset runs are warm, see Run for what persists between runs
+func (fm *FMesh) Reset() *FMesh {
+	if fm.HasErr() {
+		return fm
+	}
+
+	for _, c := range fm.Components().ComponentsOrNil() {
+		if err := clearPorts(c.Inputs().PortsOrNil()); err != nil {
+			return fm.WithErr(err)
+		}
+		if err := clearPorts(c.Outputs().PortsOrNil()); err != nil {
+			return fm.WithErr(err)
+		}
+		c.ResetState()
+		c.DeleteLabel(component.DegradedLabel)
+	}
+
+	fm.cycles = cycle.NewGroup()
+	fm.runtimeInfo = nil
+	fm.stateModifiedAt = nil
+	fm.injections.takeAll()
+	fm.deadLetters.Drain()
+	// Quiet components are tracked from the previous run, so the topology is compiled again
+	fm.topology = nil
+	fm.notifyReset()
+	return fm
+}
+
+// clearPorts removes all signals from the ports, including the ones spilled to disk
+func clearPorts(ports port.PortMap) error {
+	for _, p := range ports {
+		if err := p.DiscardSpill(); err != nil {
+			return err
+		}
+		if p.Clear().HasErr() {
+			return p.Err()
+		}
+	}
+	return nil
+}
+
+// ClearErr drops the error of the mesh (e.g. the one a failed run stopped with, which makes the following runs fail too),
+// so a long-lived mesh can run again keeping states and signals. Errors of components are found again by the next run (see Validate)
+func (fm *FMesh) ClearErr() *FMesh {
+	fm.SetErr(nil)
+	return fm
+}
+
+// DropCycles drops cycles of previous runs, so they do not pile up (and count against Config.CyclesLimit)
+// when a mesh is run repeatedly, unlike Reset it keeps states and signals
+func (fm *FMesh) DropCycles() *FMesh {
+	if fm.HasErr() {
+		return fm
+	}
+
+	fm.cycles = cycle.NewGroup()
+	return fm
+}
@@ -0,0 +1,127 @@
+package fmesh
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFMesh_WarmAndColdRuns(t *testing.T) {
+	// newMesh returns a mesh where a balancer forwards spikes to a worker counting them in its state,
+	// the worker reports the running total on a port without pipes
+	newMesh := func() *FMesh {
+		balancer := component.New("balancer").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+		})
+		worker := component.New("worker").WithInputs("in").WithOutputs("total").WithActivationFunc(func(this *component.Component) error {
+			total := this.State().GetOrDefault("processed", 0).(int) + this.InputByName("in").Buffer().Len()
+			this.State().Set("processed", total)
+			this.OutputByName("total").PutSignals(signal.New(total))
+			return nil
+		})
+		balancer.OutputByName("out").PipeTo(worker.InputByName("in"))
+		return NewWithConfig("fm", &Config{CyclesLimit: 100}).WithComponents(balancer, worker)
+	}
+
+	t.Run("runs are warm", func(t *testing.T) {
+		fm := newMesh()
+		worker := fm.ComponentByName("worker")
+
+		fm.ComponentByName("balancer").InputByName("in").PutSignals(signal.New("spike-1"))
+		cycles, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Len(t, cycles, 3)
+
+		fm.ComponentByName("balancer").InputByName("in").PutSignals(signal.New("spike-2"), signal.New("spike-3"))
+		cycles, err = fm.Run()
+		assert.NoError(t, err)
+		assert.Len(t, cycles, 6, "cycles of all runs are returned")
+		assert.Equal(t, 3, worker.State().Get("processed"))
+		payloads, err := worker.OutputByName("total").AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, 3}, payloads, "signals on ports without pipes are kept")
+	})
+
+	t.Run("reset starts cold", func(t *testing.T) {
+		fm := newMesh()
+		worker := fm.ComponentByName("worker")
+
+		fm.ComponentByName("balancer").InputByName("in").PutSignals(signal.New("spike-1"), signal.New("spike-2"), signal.New("spike-3"))
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.NoError(t, fm.Inject("balancer", "in", signal.New("spike-4")))
+
+		assert.False(t, fm.Reset().HasErr())
+		assert.Nil(t, fm.RuntimeInfo())
+		assert.False(t, worker.OutputByName("total").HasSignals())
+		assert.False(t, worker.State().Has("processed"))
+
+		fm.ComponentByName("balancer").InputByName("in").PutSignals(signal.New("spike-5"), signal.New("spike-6"))
+		cycles, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Len(t, cycles, 3)
+		assert.Equal(t, 2, worker.State().Get("processed"))
+	})
+}
+
+func TestFMesh_ClearErr(t *testing.T) {
+	fm := New("fm").WithComponents(component.New("c1").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+		this.State().Set("activated", true)
+		return nil
+	}))
+	fm.ComponentByName("c1").InputByName("in").PutSignals(signal.New(1))
+
+	fm.SetErr(errors.New("run failed"))
+	_, err := fm.Run()
+	assert.EqualError(t, err, "run failed")
+
+	assert.False(t, fm.ClearErr().HasErr())
+	_, err = fm.Run()
+	assert.NoError(t, err)
+	assert.True(t, fm.ComponentByName("c1").State().Has("activated"))
+}
+
+func TestFMesh_DropCycles(t *testing.T) {
+	c1 := component.New("c1").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+		this.State().Set("count", this.State().GetOrDefault("count", 0).(int)+1)
+		return nil
+	})
+	fm := NewWithConfig("fm", &Config{CyclesLimit: 3}).WithComponents(c1)
+
+	for i := 0; i < 5; i++ {
+		c1.InputByName("in").PutSignals(signal.New(i))
+		cycles, err := fm.Run()
+		assert.NoError(t, err, "cycles of previous runs do not count against the limit")
+		assert.Len(t, cycles, 2)
+		assert.False(t, fm.DropCycles().HasErr())
+	}
+	assert.Equal(t, 5, c1.State().Get("count"), "states are kept")
+}
+
+// resetCounter is a plugin counting resets of the mesh
+type resetCounter struct {
+	resets int
+}
+
+func (p *resetCounter) Install(fm *FMesh) error {
+	return nil
+}
+
+func (p *resetCounter) OnReset(fm *FMesh) {
+	p.resets++
+}
+
+func TestFMesh_ResetListener(t *testing.T) {
+	counter := &resetCounter{}
+	fm := New("fm").Use(counter)
+
+	fm.Reset().Reset()
+	assert.Equal(t, 2, counter.resets)
+
+	fm.SetErr(errors.New("broken"))
+	fm.Reset()
+	assert.Equal(t, 2, counter.resets, "mesh in error state is not reset")
+}
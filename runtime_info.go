@@ -0,0 +1,132 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/cycle"
+	"time"
+)
+
+// RuntimeInfo contains information about the latest run of the mesh
+type RuntimeInfo struct {
+	Cycles      cycle.Cycles
+	StartedAt   time.Time
+	StoppedAt   time.Time
+	Duration    time.Duration
+	PortBuffers PortBuffersReport
+	// Activations is the number of activations (only with EventEngine, which does not record cycles)
+	Activations int64
+	// Pacing reports overruns of paced cycles (only when Config.CyclePeriod is set)
+	Pacing PacingReport
+	// States holds per-component state stats at the end of the run (only when Config.StateStats is enabled)
+	States []ComponentStateStats
+	// Report summarizes activations of each cycle (empty with EventEngine, which does not record cycles)
+	Report RunReport
+}
+
+// RunReport summarizes activations of the cycles (cycles of previous runs are included, like in Cycles)
+type RunReport struct {
+	Cycles []CycleReport
+}
+
+// CycleReport summarizes activations of one cycle
+type CycleReport struct {
+	Number int
+	// Activations holds activated components by names
+	Activations map[string]ActivationReport
+}
+
+// ActivationReport describes one activation of a component
+type ActivationReport struct {
+	Code component.ActivationResultCode
+	// Err is the error returned by the activation function (or the panic), nil when the activation succeeded
+	Err             error
+	Duration        time.Duration
+	SignalsConsumed int
+	SignalsProduced int
+	// Retries is the number of times the failed activation was retried (see component.WithRetryPolicy)
+	Retries int
+	// Stack is the stack trace of the panic the activation failed with (nil when it did not panic)
+	Stack []byte
+	// Restarted is set when the failed activation was handled by restarting the component (see component.WithRestartStrategy)
+	Restarted bool
+}
+
+// newRunReport builds the report of the cycles
+func newRunReport(cycles cycle.Cycles) RunReport {
+	report := RunReport{
+		Cycles: make([]CycleReport, 0, len(cycles)),
+	}
+	for _, c := range cycles {
+		cycleReport := CycleReport{
+			Number:      c.Number(),
+			Activations: make(map[string]ActivationReport),
+		}
+		for name, ar := range c.ActivationResults() {
+			if !ar.Activated() {
+				continue
+			}
+			var activationErr error
+			if ar.IsError() || ar.IsPanic() {
+				activationErr = ar.ActivationError()
+			}
+			cycleReport.Activations[name] = ActivationReport{
+				Code:            ar.Code(),
+				Err:             activationErr,
+				Duration:        ar.Duration(),
+				SignalsConsumed: ar.SignalsConsumed(),
+				SignalsProduced: ar.SignalsProduced(),
+				Retries:         ar.Retries(),
+				Stack:           ar.Stack(),
+				Restarted:       ar.Restarted(),
+			}
+		}
+		report.Cycles = append(report.Cycles, cycleReport)
+	}
+	return report
+}
+
+// PortBuffersReport describes which input port buffers were written without locking during the run
+type PortBuffersReport struct {
+	// LockFree is the number of input ports fed by at most one component, their buffers are written without locking
+	LockFree int
+	// Contended lists input ports ("component.port") fed by multiple components, their buffers are locked on each write,
+	// fan-in hotspots can be restructured (e.g. by giving each producer its own input port) to make them lock-free
+	Contended []string
+}
+
+// RuntimeInfo returns the information about the latest run (nil if the mesh never ran)
+func (fm *FMesh) RuntimeInfo() *RuntimeInfo {
+	return fm.runtimeInfo
+}
+
+// startRuntimeInfo initializes runtime info for a new run
+func (fm *FMesh) startRuntimeInfo() {
+	fm.runtimeInfo = &RuntimeInfo{
+		StartedAt: fm.Clock().Now(),
+		Pacing: PacingReport{
+			Period: fm.config.CyclePeriod,
+		},
+	}
+}
+
+// reportPortBuffers records the buffer modes of input ports for the current run
+func (fm *FMesh) reportPortBuffers(t *topology) {
+	if fm.runtimeInfo == nil {
+		return
+	}
+	fm.runtimeInfo.PortBuffers = t.portBuffersReport()
+}
+
+// stopRuntimeInfo finalizes runtime info of the current run
+func (fm *FMesh) stopRuntimeInfo() {
+	if fm.runtimeInfo == nil {
+		return
+	}
+	fm.runtimeInfo.Cycles = fm.cycles.CyclesOrNil()
+	fm.runtimeInfo.Report = newRunReport(fm.runtimeInfo.Cycles)
+	fm.runtimeInfo.StoppedAt = fm.Clock().Now()
+	fm.runtimeInfo.Duration = fm.runtimeInfo.StoppedAt.Sub(fm.runtimeInfo.StartedAt)
+	if fm.config.StateStats {
+		fm.runtimeInfo.States = fm.StateStats()
+	}
+}
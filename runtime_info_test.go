@@ -0,0 +1,160 @@
+package fmesh
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/clock"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestFMesh_RuntimeInfo(t *testing.T) {
+	t.Run("no runtime info before first run", func(t *testing.T) {
+		assert.Nil(t, New("fm").RuntimeInfo())
+	})
+
+	t.Run("runtime info after run", func(t *testing.T) {
+		fm := New("fm").WithComponents(
+			component.New("c1").
+				WithInputs("i1").
+				WithActivationFunc(func(this *component.Component) error {
+					return nil
+				}),
+		)
+		fm.ComponentByName("c1").InputByName("i1").PutSignals(signal.New(1))
+
+		cycles, err := fm.Run()
+		assert.NoError(t, err)
+
+		runtimeInfo := fm.RuntimeInfo()
+		assert.NotNil(t, runtimeInfo)
+		assert.Equal(t, cycles, runtimeInfo.Cycles)
+		assert.False(t, runtimeInfo.StartedAt.IsZero())
+		assert.False(t, runtimeInfo.StoppedAt.Before(runtimeInfo.StartedAt))
+		assert.Equal(t, runtimeInfo.StoppedAt.Sub(runtimeInfo.StartedAt), runtimeInfo.Duration)
+		assert.Equal(t, PortBuffersReport{LockFree: 1, Contended: []string{}}, runtimeInfo.PortBuffers)
+	})
+
+	t.Run("contended ports are reported", func(t *testing.T) {
+		fm := getProducersMesh(3, true)
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"sink.in"}, fm.RuntimeInfo().PortBuffers.Contended)
+	})
+
+	t.Run("activations are reported per cycle", func(t *testing.T) {
+		clk := clock.NewVirtual(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		producer := component.New("producer").
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				this.OutputByName("out").PutSignals(signal.New(1), signal.New(2), signal.New(3))
+				return nil
+			})
+		lb := component.New("lb").
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				clk.Advance(5 * time.Millisecond)
+				return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+			})
+		producer.OutputByName("out").PipeTo(lb.InputByName("in"))
+		producer.InputByName("in").PutSignals(signal.New("start"))
+
+		fm := NewWithConfig("fm", &Config{Clock: clk}).WithComponents(producer, lb)
+		_, err := fm.Run()
+		require.NoError(t, err)
+
+		report := fm.RuntimeInfo().Report
+		require.Len(t, report.Cycles, 3)
+		assert.Equal(t, map[string]ActivationReport{
+			"producer": {
+				Code:            component.ActivationCodeOK,
+				SignalsConsumed: 1,
+				SignalsProduced: 3,
+			},
+		}, report.Cycles[0].Activations)
+		assert.Equal(t, 2, report.Cycles[1].Number)
+		assert.Equal(t, ActivationReport{
+			Code:            component.ActivationCodeOK,
+			Duration:        5 * time.Millisecond,
+			SignalsConsumed: 3,
+			SignalsProduced: 3,
+		}, report.Cycles[1].Activations["lb"])
+		assert.Empty(t, report.Cycles[2].Activations)
+	})
+
+	t.Run("activation errors are reported", func(t *testing.T) {
+		fm := NewWithConfig("fm", &Config{ErrorHandlingStrategy: IgnoreAll}).WithComponents(
+			component.New("c1").
+				WithInputs("in").
+				WithActivationFunc(func(this *component.Component) error {
+					return errors.New("boom")
+				}),
+		)
+		fm.ComponentByName("c1").InputByName("in").PutSignals(signal.New(1))
+		_, err := fm.Run()
+		require.NoError(t, err)
+
+		activation := fm.RuntimeInfo().Report.Cycles[0].Activations["c1"]
+		assert.Equal(t, component.ActivationCodeReturnedError, activation.Code)
+		assert.ErrorContains(t, activation.Err, "boom")
+		assert.Equal(t, 1, activation.SignalsConsumed)
+	})
+
+	t.Run("retries are reported", func(t *testing.T) {
+		attempts := 0
+		fm := New("fm").WithComponents(
+			component.New("c1").
+				WithInputs("in").
+				WithRetryPolicy(3, nil).
+				WithActivationFunc(func(this *component.Component) error {
+					attempts++
+					if attempts < 3 {
+						return errors.New("boom")
+					}
+					return nil
+				}),
+		)
+		fm.ComponentByName("c1").InputByName("in").PutSignals(signal.New(1))
+		_, err := fm.Run()
+		require.NoError(t, err)
+
+		activation := fm.RuntimeInfo().Report.Cycles[0].Activations["c1"]
+		assert.Equal(t, component.ActivationCodeOK, activation.Code)
+		assert.Equal(t, 2, activation.Retries)
+	})
+
+	t.Run("panics are isolated", func(t *testing.T) {
+		fm := NewWithConfig("fm", &Config{ErrorHandlingStrategy: IgnoreAll}).WithComponents(
+			component.New("panicking").
+				WithInputs("in").
+				WithActivationFunc(func(this *component.Component) error {
+					panic("boom")
+				}),
+			component.New("healthy").
+				WithInputs("in").
+				WithActivationFunc(func(this *component.Component) error {
+					return nil
+				}),
+		)
+		fm.ComponentByName("panicking").InputByName("in").PutSignals(signal.New(1))
+		fm.ComponentByName("healthy").InputByName("in").PutSignals(signal.New(2))
+		_, err := fm.Run()
+		require.NoError(t, err)
+
+		activations := fm.RuntimeInfo().Report.Cycles[0].Activations
+		assert.Equal(t, component.ActivationCodePanicked, activations["panicking"].Code)
+		assert.NotEmpty(t, activations["panicking"].Stack)
+		assert.Equal(t, component.ActivationCodeOK, activations["healthy"].Code)
+		assert.Nil(t, activations["healthy"].Stack)
+
+		letters := fm.DeadLetters().All()
+		require.Len(t, letters, 1)
+		assert.Equal(t, activations["panicking"].Stack, letters[0].Stack)
+	})
+}
@@ -0,0 +1,126 @@
+package fmesh
+
+import (
+	"cmp"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"math/rand"
+	"slices"
+)
+
+// SchedulingPolicy defines the order in which components ready to activate are dispatched within a cycle.
+// Activations of a cycle run concurrently, so the order is strictly followed only when they run one at a time
+// (WorkerPool strategy with a single worker), otherwise it is the order activations are started in
+type SchedulingPolicy int
+
+const (
+	// ScheduleByName dispatches components in the order of their names
+	ScheduleByName SchedulingPolicy = iota
+
+	// ScheduleTopological dispatches upstream components before downstream ones (components in loops are ordered by name)
+	ScheduleTopological
+
+	// ScheduleFIFO dispatches components in the order their input signals arrived in, components waiting longer go first
+	ScheduleFIFO
+
+	// ScheduleByPriority dispatches components holding higher priority input signals first (see signal.Priority)
+	ScheduleByPriority
+
+	// ScheduleRandom dispatches components in random order seeded from Config.RandSource, so races can be fuzzed reproducibly
+	ScheduleRandom
+)
+
+// scheduler orders ready components according to the policy
+type scheduler struct {
+	policy SchedulingPolicy
+	// rank holds topological ranks indexed by component ID
+	rank []int
+	// arrival holds, for each component ID, the sequence number of the drain which scheduled it, arrivals counts drains
+	arrival  []int
+	arrivals int
+	// priority holds the highest priority of input signals of ready components indexed by component ID
+	priority []signal.Priority
+	rand     *rand.Rand
+}
+
+// newScheduler creates the scheduler of the topology
+func newScheduler(policy SchedulingPolicy, t *topology, seed func() int64) *scheduler {
+	s := &scheduler{
+		policy: policy,
+	}
+
+	switch policy {
+	case ScheduleTopological:
+		s.rank = t.topologicalRanks()
+	case ScheduleFIFO:
+		s.arrival = make([]int, len(t.components))
+	case ScheduleByPriority:
+		s.priority = make([]signal.Priority, len(t.components))
+	case ScheduleRandom:
+		s.rand = rand.New(rand.NewSource(seed()))
+	}
+	return s
+}
+
+// order sorts IDs of ready components (initially sorted by name, as IDs are)
+func (s *scheduler) order(t *topology, ready []int) {
+	switch s.policy {
+	case ScheduleTopological:
+		slices.SortStableFunc(ready, func(a, b int) int {
+			return cmp.Compare(s.rank[a], s.rank[b])
+		})
+	case ScheduleFIFO:
+		slices.SortStableFunc(ready, func(a, b int) int {
+			return cmp.Compare(s.arrival[a], s.arrival[b])
+		})
+	case ScheduleByPriority:
+		for _, id := range ready {
+			s.priority[id] = highestInputPriority(t.components[id].Inputs().PortsOrNil())
+		}
+		slices.SortStableFunc(ready, func(a, b int) int {
+			return cmp.Compare(s.priority[b], s.priority[a])
+		})
+	case ScheduleRandom:
+		s.rand.Shuffle(len(ready), func(i, j int) {
+			ready[i], ready[j] = ready[j], ready[i]
+		})
+	}
+
+	// Priorities of components go before the policy
+	if t.prioritized {
+		slices.SortStableFunc(ready, func(a, b int) int {
+			return cmp.Compare(t.components[b].Priority(), t.components[a].Priority())
+		})
+	}
+}
+
+// scheduled records arrivals of the components scheduled for the next cycle (only FIFO needs it),
+// components waiting for inputs with their signals kept are still served for the old arrival
+func (s *scheduler) scheduled(activationResults []*component.ActivationResult, next []bool) {
+	if s.arrival == nil {
+		return
+	}
+
+	s.arrivals++
+	for id, isScheduled := range next {
+		if !isScheduled {
+			continue
+		}
+		if ar := activationResults[id]; ar.Activated() && component.IsWaitingForInput(ar) && component.WantsToKeepInputs(ar) {
+			continue
+		}
+		s.arrival[id] = s.arrivals
+	}
+}
+
+// highestInputPriority returns the highest priority of signals buffered in the ports (PriorityLow when there are none)
+func highestInputPriority(ports port.PortMap) signal.Priority {
+	highest := signal.PriorityLow
+	for _, p := range ports {
+		for _, sig := range p.AllSignalsOrNil() {
+			highest = max(highest, sig.Priority())
+		}
+	}
+	return highest
+}
@@ -0,0 +1,211 @@
+package fmesh
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFMesh_SchedulingPolicy(t *testing.T) {
+	// a -> c -> b, d is fed externally with a high priority signal in the second cycle
+	newMesh := func(policy SchedulingPolicy, activated *[]string) *FMesh {
+		record := func(this *component.Component) error {
+			*activated = append(*activated, this.Name())
+			return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+		}
+		newComponent := func(name string) *component.Component {
+			return component.New(name).WithInputs("in").WithOutputs("out").WithActivationFunc(record)
+		}
+		a, b, c, d := newComponent("a"), newComponent("b"), newComponent("c"), newComponent("d")
+		a.OutputByName("out").PipeTo(c.InputByName("in"))
+		c.OutputByName("out").PipeTo(b.InputByName("in"))
+
+		fm := NewWithConfig("fm", &Config{
+			CyclesLimit:       10,
+			ExecutionStrategy: WorkerPool,
+			Workers:           1,
+			SchedulingPolicy:  policy,
+			RandSource:        rand.NewSource(42),
+		}).WithComponents(a, b, c, d)
+		a.InputByName("in").PutSignals(signal.New(1))
+		b.InputByName("in").PutSignals(signal.New(2))
+		c.InputByName("in").PutSignals(signal.New(3))
+		d.InputByName("in").PutSignals(signal.New(4).WithPriority(signal.PriorityHigh))
+		return fm
+	}
+
+	tests := []struct {
+		name   string
+		policy SchedulingPolicy
+		want   []string
+	}{
+		{
+			name:   "by name",
+			policy: ScheduleByName,
+			want:   []string{"a", "b", "c", "d", "b", "c", "b"},
+		},
+		{
+			name:   "topological",
+			policy: ScheduleTopological,
+			want:   []string{"a", "d", "c", "b", "c", "b", "b"},
+		},
+		{
+			name:   "by priority",
+			policy: ScheduleByPriority,
+			want:   []string{"d", "a", "b", "c", "b", "c", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var activated []string
+			_, err := newMesh(tt.policy, &activated).Run()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, activated)
+		})
+	}
+
+	t.Run("random order is reproducible with the same source", func(t *testing.T) {
+		var first, second []string
+		_, err := newMesh(ScheduleRandom, &first).Run()
+		assert.NoError(t, err)
+		_, err = newMesh(ScheduleRandom, &second).Run()
+		assert.NoError(t, err)
+		assert.Equal(t, first, second)
+		assert.ElementsMatch(t, []string{"a", "b", "c", "d", "b", "c", "b"}, first)
+	})
+}
+
+func TestFMesh_SchedulingPolicyFIFO(t *testing.T) {
+	// w waits for both inputs from the first cycle, n is scheduled later, so w goes first despite its name
+	newMesh := func(policy SchedulingPolicy, activated *[]string) *FMesh {
+		forward := func(this *component.Component) error {
+			*activated = append(*activated, this.Name())
+			return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+		}
+		x := component.New("x").WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+		n := component.New("n").WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+		w := component.New("w").WithInputs("in1", "in2").WithActivationFunc(func(this *component.Component) error {
+			*activated = append(*activated, this.Name())
+			if !this.InputByName("in2").HasSignals() {
+				return component.NewErrWaitForInputs(true)
+			}
+			return nil
+		})
+		x.OutputByName("out").PipeTo(n.InputByName("in"))
+		n.OutputByName("out").PipeTo(w.InputByName("in2"))
+
+		fm := NewWithConfig("fm", &Config{
+			CyclesLimit:       10,
+			ExecutionStrategy: WorkerPool,
+			Workers:           1,
+			SchedulingPolicy:  policy,
+		}).WithComponents(x, n, w)
+		x.InputByName("in").PutSignals(signal.New(1))
+		w.InputByName("in1").PutSignals(signal.New(2))
+		return fm
+	}
+
+	tests := []struct {
+		name   string
+		policy SchedulingPolicy
+		want   []string
+	}{
+		{
+			name:   "waiting component goes first",
+			policy: ScheduleFIFO,
+			want:   []string{"w", "x", "w", "n", "w"},
+		},
+		{
+			name:   "by name",
+			policy: ScheduleByName,
+			want:   []string{"w", "x", "n", "w", "w"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var activated []string
+			_, err := newMesh(tt.policy, &activated).Run()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, activated)
+		})
+	}
+}
+
+func TestFMesh_ComponentPriority(t *testing.T) {
+	newComponent := func(name string, activated *[]string) *component.Component {
+		return component.New(name).WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+			*activated = append(*activated, this.Name())
+			return nil
+		})
+	}
+
+	t.Run("ready components start in priority order", func(t *testing.T) {
+		var activated []string
+		a, b, c, d := newComponent("a", &activated), newComponent("b", &activated), newComponent("c", &activated), newComponent("d", &activated)
+		c.WithPriority(5)
+		d.WithPriority(10)
+		b.WithPriority(-1)
+
+		fm := NewWithConfig("fm", &Config{
+			CyclesLimit:       10,
+			ExecutionStrategy: WorkerPool,
+			Workers:           1,
+		}).WithComponents(a, b, c, d)
+		for _, comp := range []*component.Component{a, b, c, d} {
+			comp.InputByName("in").PutSignals(signal.New(1))
+		}
+
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"d", "c", "a", "b"}, activated)
+	})
+
+	t.Run("exclusive components preempt others", func(t *testing.T) {
+		var activated []string
+		ctl, data := newComponent("ctl", &activated).WithExclusive(), newComponent("data", &activated)
+
+		fm := New("fm").WithComponents(ctl, data)
+		ctl.InputByName("in").PutSignals(signal.New(1))
+		data.InputByName("in").PutSignals(signal.New(1))
+
+		cycles, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ctl", "data"}, activated)
+		assert.False(t, cycles[0].ActivationResults().ByComponentName("data").Activated())
+		assert.True(t, cycles[1].ActivationResults().ByComponentName("data").Activated())
+	})
+
+	t.Run("exclusive activations run alone with EventEngine", func(t *testing.T) {
+		var running, overlaps atomic.Int64
+		newWorker := func(name string) *component.Component {
+			return component.New(name).WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+				running.Add(1)
+				defer running.Add(-1)
+				if this.IsExclusive() && running.Load() > 1 {
+					overlaps.Add(1)
+				}
+				time.Sleep(time.Millisecond)
+				return nil
+			})
+		}
+
+		components := []*component.Component{newWorker("ctl").WithExclusive()}
+		for i := 0; i < 5; i++ {
+			components = append(components, newWorker(fmt.Sprintf("data-%d", i)))
+		}
+		fm := NewWithConfig("fm", &Config{Engine: EventEngine}).WithComponents(components...)
+		for _, comp := range components {
+			comp.InputByName("in").PutSignals(signal.New(1))
+		}
+
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Zero(t, overlaps.Load())
+	})
+}
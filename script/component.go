@@ -0,0 +1,28 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+)
+
+// NewComponent creates a component whose activation function runs the script written in the given language,
+// the script is compiled once, ports are added as usual:
+// script.NewComponent("scale", golite.Language, src).WithInputs("in").WithOutputs("out")
+func NewComponent(name string, language string, source []byte) *component.Component {
+	c := component.New(name).WithDescription(fmt.Sprintf("runs %s script", language))
+
+	engine, err := engineFor(language)
+	if err != nil {
+		return c.WithErr(err)
+	}
+
+	program, err := engine.Compile(name, source)
+	if err != nil {
+		return c.WithErr(errors.Join(ErrFailedToCompile, err))
+	}
+
+	return c.WithActivationFunc(func(this *component.Component) error {
+		return program.Run(&Env{c: this})
+	})
+}
@@ -0,0 +1,117 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+// multiplierEngine understands scripts like "multiply <input> <output> <factor>", it also counts activations in state
+var multiplierEngine = EngineFunc(func(name string, source []byte) (Program, error) {
+	var (
+		in, out string
+		factor  int
+	)
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(source)), "multiply %s %s %d", &in, &out, &factor); err != nil {
+		return nil, err
+	}
+
+	return ProgramFunc(func(env *Env) error {
+		payloads, err := env.Input(in)
+		if err != nil {
+			return err
+		}
+		for _, payload := range payloads {
+			num, ok := payload.(int)
+			if !ok {
+				return errors.New("not a number")
+			}
+			if err := env.Emit(out, num*factor); err != nil {
+				return err
+			}
+		}
+		count, _ := env.State("activations").(int)
+		env.SetState("activations", count+1)
+		return nil
+	}), nil
+})
+
+func init() {
+	if err := Register("multiplier", multiplierEngine); err != nil {
+		panic(err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	assert.ErrorIs(t, Register("multiplier", multiplierEngine), ErrEngineRegistered)
+	assert.Contains(t, Languages(), "multiplier")
+}
+
+func TestNewComponent(t *testing.T) {
+	tests := []struct {
+		name       string
+		language   string
+		source     string
+		assertions func(t *testing.T, c *component.Component)
+	}{
+		{
+			name:     "runs script",
+			language: "multiplier",
+			source:   "multiply num res 3",
+			assertions: func(t *testing.T, c *component.Component) {
+				c.InputByName("num").PutSignals(signal.New(1), signal.New(2))
+				assert.Equal(t, component.ActivationCodeOK, c.MaybeActivate().Code())
+				payloads, err := c.OutputByName("res").AllSignalsPayloads()
+				assert.NoError(t, err)
+				assert.Equal(t, []any{3, 6}, payloads)
+				assert.Equal(t, 1, c.State().Get("activations"))
+			},
+		},
+		{
+			name:     "script error",
+			language: "multiplier",
+			source:   "multiply num res 3",
+			assertions: func(t *testing.T, c *component.Component) {
+				c.InputByName("num").PutSignals(signal.New("x"))
+				activationResult := c.MaybeActivate()
+				assert.Equal(t, component.ActivationCodeReturnedError, activationResult.Code())
+				assert.ErrorContains(t, activationResult.ActivationError(), "not a number")
+			},
+		},
+		{
+			name:     "script emits to unknown port",
+			language: "multiplier",
+			source:   "multiply num missing 3",
+			assertions: func(t *testing.T, c *component.Component) {
+				c.InputByName("num").PutSignals(signal.New(1))
+				assert.Equal(t, component.ActivationCodeReturnedError, c.MaybeActivate().Code())
+			},
+		},
+		{
+			name:     "compile error",
+			language: "multiplier",
+			source:   "divide num res",
+			assertions: func(t *testing.T, c *component.Component) {
+				assert.ErrorIs(t, c.Err(), ErrFailedToCompile)
+			},
+		},
+		{
+			name:     "unknown language",
+			language: "cobol",
+			source:   "",
+			assertions: func(t *testing.T, c *component.Component) {
+				assert.ErrorIs(t, c.Err(), ErrUnknownLanguage)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewComponent("scripted", tt.language, []byte(tt.source)).WithInputs("num").WithOutputs("res")
+			tt.assertions(t, c)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Package script allows activation logic to be provided as a script (or a WASM module) interpreted at runtime,
+// so meshes loaded from config files can define behavior without recompilation.
+// Interpreters are plugged in as engines registered under a language name (e.g. "lua" or "wasm"),
+// scripts are sandboxed: they see ports and signals only through Env.
+// Package golite ships an engine interpreting a sandboxed subset of Go, other interpreters can be adapted the same way
+package script
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrUnknownLanguage  = errors.New("no engine registered for language")
+	ErrFailedToCompile  = errors.New("failed to compile script")
+	ErrEngineRegistered = errors.New("engine is already registered for language")
+)
+
+// Engine compiles sources of a single language
+type Engine interface {
+	Compile(name string, source []byte) (Program, error)
+}
+
+// Program is a compiled script, it is run once per activation
+type Program interface {
+	Run(env *Env) error
+}
+
+// EngineFunc is an adapter to use ordinary functions as engines
+type EngineFunc func(name string, source []byte) (Program, error)
+
+// Compile calls the function
+func (f EngineFunc) Compile(name string, source []byte) (Program, error) {
+	return f(name, source)
+}
+
+// ProgramFunc is an adapter to use ordinary functions as programs
+type ProgramFunc func(env *Env) error
+
+// Run calls the function
+func (f ProgramFunc) Run(env *Env) error {
+	return f(env)
+}
+
+var (
+	enginesMu sync.RWMutex
+	engines   = make(map[string]Engine)
+)
+
+// Register makes the engine available for the language, usually called from init() of the package adapting an interpreter
+func Register(language string, engine Engine) error {
+	enginesMu.Lock()
+	defer enginesMu.Unlock()
+
+	if _, ok := engines[language]; ok {
+		return fmt.Errorf("%w: %s", ErrEngineRegistered, language)
+	}
+	engines[language] = engine
+	return nil
+}
+
+// Languages returns the languages having registered engines
+func Languages() []string {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+
+	languages := make([]string, 0, len(engines))
+	for language := range engines {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// engineFor returns the engine registered for the language
+func engineFor(language string) (Engine, error) {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+
+	engine, ok := engines[language]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownLanguage, language)
+	}
+	return engine, nil
+}
@@ -0,0 +1,57 @@
+package script
+
+import (
+	"context"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+)
+
+// Env is the sandboxed view of the component given to a running script:
+// scripts read input payloads, emit output payloads and keep state, but can not touch the mesh
+type Env struct {
+	c *component.Component
+}
+
+// Name returns the component name
+func (env *Env) Name() string {
+	return env.c.Name()
+}
+
+// Context returns the context of the current run
+func (env *Env) Context() context.Context {
+	return env.c.Context()
+}
+
+// Input returns payloads of signals on the input port
+func (env *Env) Input(port string) ([]any, error) {
+	return env.c.InputByName(port).AllSignalsPayloads()
+}
+
+// HasInput says whether the input port has signals
+func (env *Env) HasInput(port string) bool {
+	return env.c.InputByName(port).HasSignals()
+}
+
+// Emit puts signals with the given payloads on the output port
+func (env *Env) Emit(port string, payloads ...any) error {
+	out := env.c.OutputByName(port)
+	if out.HasErr() {
+		return out.Err()
+	}
+	return out.WithSignalGroups(signal.NewGroup(payloads...)).Err()
+}
+
+// State returns the value kept in the component state (nil when missing)
+func (env *Env) State(key string) any {
+	return env.c.State().Get(key)
+}
+
+// SetState keeps the value in the component state between activations
+func (env *Env) SetState(key string, value any) {
+	env.c.State().Set(key, value)
+}
+
+// Log writes the message to the component logger
+func (env *Env) Log(message string) {
+	env.c.Logger().Println(message)
+}
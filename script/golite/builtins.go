@@ -0,0 +1,186 @@
+package golite
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/script"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// maxFormatWidth is the largest width or precision sprintf verbs can have, so padding can not exhaust the memory
+const maxFormatWidth = 1000
+
+// builtin is a function scripts can call, it is the only way for a script to reach the component
+type builtin func(env *script.Env, args []any) (any, error)
+
+// builtins are the functions available to scripts by name
+var builtins = map[string]builtin{
+	"input": func(env *script.Env, args []any) (any, error) {
+		port, err := stringArg(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return env.Input(port)
+	},
+	"hasInput": func(env *script.Env, args []any) (any, error) {
+		port, err := stringArg(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return env.HasInput(port), nil
+	},
+	"emit": func(env *script.Env, args []any) (any, error) {
+		if len(args) < 1 {
+			return nil, errors.New("port is required")
+		}
+		port, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("port must be a string, got %T", args[0])
+		}
+		return nil, env.Emit(port, args[1:]...)
+	},
+	"state": func(env *script.Env, args []any) (any, error) {
+		key, err := stringArg(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return env.State(key), nil
+	},
+	"setState": func(env *script.Env, args []any) (any, error) {
+		key, err := stringArg(args, 2)
+		if err != nil {
+			return nil, err
+		}
+		env.SetState(key, args[1])
+		return nil, nil
+	},
+	"log": func(env *script.Env, args []any) (any, error) {
+		env.Log(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+		return nil, nil
+	},
+	"fail": func(env *script.Env, args []any) (any, error) {
+		return nil, errors.New(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+	},
+	"len": func(env *script.Env, args []any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("want 1 argument, got %d", len(args))
+		}
+		if args[0] == nil {
+			return 0, nil
+		}
+		v := reflect.ValueOf(args[0])
+		switch v.Kind() {
+		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+			return v.Len(), nil
+		default:
+			return nil, fmt.Errorf("invalid argument %T", args[0])
+		}
+	},
+	"append": func(env *script.Env, args []any) (any, error) {
+		if len(args) < 1 {
+			return nil, errors.New("list is required")
+		}
+		var list []any
+		if l, ok := args[0].([]any); ok {
+			// The list is copied, so appending never changes lists shared with other values (e.g. payloads)
+			list = make([]any, len(l), len(l)+len(args)-1)
+			copy(list, l)
+		} else if args[0] != nil {
+			v := reflect.ValueOf(args[0])
+			if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+				return nil, fmt.Errorf("first argument must be a list, got %T", args[0])
+			}
+			for i := 0; i < v.Len(); i++ {
+				list = append(list, v.Index(i).Interface())
+			}
+		}
+		return append(list, args[1:]...), nil
+	},
+	"sprintf": func(env *script.Env, args []any) (any, error) {
+		if len(args) < 1 {
+			return nil, errors.New("format is required")
+		}
+		format, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("format must be a string, got %T", args[0])
+		}
+		if err := checkFormat(format); err != nil {
+			return nil, err
+		}
+		return fmt.Sprintf(format, args[1:]...), nil
+	},
+	"int": func(env *script.Env, args []any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("want 1 argument, got %d", len(args))
+		}
+		if s, ok := args[0].(string); ok {
+			return strconv.Atoi(s)
+		}
+		f, isFloat, ok := toNumber(args[0])
+		if !ok {
+			return nil, fmt.Errorf("cannot convert %T to int", args[0])
+		}
+		if isFloat {
+			return int(f), nil
+		}
+		return toInteger(args[0]), nil
+	},
+	"float": func(env *script.Env, args []any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("want 1 argument, got %d", len(args))
+		}
+		if s, ok := args[0].(string); ok {
+			return strconv.ParseFloat(s, 64)
+		}
+		f, _, ok := toNumber(args[0])
+		if !ok {
+			return nil, fmt.Errorf("cannot convert %T to float", args[0])
+		}
+		return f, nil
+	},
+	"string": func(env *script.Env, args []any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("want 1 argument, got %d", len(args))
+		}
+		return fmt.Sprint(args[0]), nil
+	},
+}
+
+// stringArg checks the number of arguments and returns the first one, which must be a string
+func stringArg(args []any, want int) (string, error) {
+	if len(args) != want {
+		return "", fmt.Errorf("want %d arguments, got %d", want, len(args))
+	}
+	s, ok := args[0].(string)
+	if !ok {
+		return "", fmt.Errorf("first argument must be a string, got %T", args[0])
+	}
+	return s, nil
+}
+
+// checkFormat fails when widths or precisions of the format verbs are dynamic (*) or larger than maxFormatWidth
+func checkFormat(format string) error {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		// Flags, widths, precisions and argument indexes go before the verb letter
+		number := 0
+		for i++; i < len(format) && strings.IndexByte("+-# 0123456789.[]*", format[i]) >= 0; i++ {
+			switch c := format[i]; {
+			case c == '*':
+				return errors.New("dynamic width or precision is not supported")
+			case c >= '0' && c <= '9':
+				number = number*10 + int(c-'0')
+				if number > maxFormatWidth {
+					return fmt.Errorf("width or precision is more than %d", maxFormatWidth)
+				}
+			default:
+				number = 0
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+// Package golite is a script engine interpreting a sandboxed subset of Go, so activation logic can be shipped as text
+// without third-party interpreters. Importing the package registers the engine under Language:
+//
+//	import _ "github.com/hovsep/fmesh/script/golite"
+//
+//	c := script.NewComponent("scale", golite.Language, []byte(`
+//		for _, v := range input("in") {
+//			emit("out", v * 2)
+//		}
+//	`)).WithInputs("in").WithOutputs("out")
+//
+// A script is the body of the activation function. Supported are assignments (=, :=, +=, -=, *=, /=, ++, --),
+// if/else, for-range over lists, maps (in key order) and integers, break, continue and return.
+// Values are nil, bool, int, float64, string, lists ([]any) and maps (map[string]any), variables are scoped to the whole script.
+// Loops other than for-range, function literals, goroutines and imports are not supported, so a script can reach the component only through builtins.
+// Each activation is bounded by Limits (steps run, lengths of strings and lists built) and stops once the context of the run is done,
+// so a script can not hang or exhaust the memory of the mesh:
+//
+//	input(port) []any         payloads of signals on the input port
+//	hasInput(port) bool       whether the input port has signals
+//	emit(port, values...)     puts signals with the payloads on the output port
+//	state(key) any            value kept in the component state (nil when missing)
+//	setState(key, value)      keeps the value in the component state
+//	log(values...)            writes to the component logger
+//	fail(message)             stops the script, the activation returns the error
+//	len(x), append(list, values...), sprintf(format, values...), int(x), float(x), string(x)
+//
+// The engine registered under Language uses DefaultLimits, engines with other limits are made by NewEngine and registered under their own names.
+// Interpreters of other languages (Lua, WASM runtimes) are adapted the same way, see script.Register
+package golite
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/script"
+	"go/ast"
+	"go/parser"
+	"go/token"
+)
+
+// Language is the name the engine is registered under
+const Language = "golite"
+
+var (
+	ErrUnsupported   = errors.New("unsupported construct")
+	ErrRuntime       = errors.New("script runtime error")
+	ErrLimitExceeded = errors.New("script limit exceeded")
+)
+
+// Limits bound the work of a single activation of a script
+type Limits struct {
+	// MaxSteps is the number of statements and loop iterations run, copying long strings and lists is charged as extra steps
+	MaxSteps int
+	// MaxLen is the length of strings and lists built (by concatenation and builtins)
+	MaxLen int
+}
+
+// DefaultLimits are the limits of the engine registered under Language
+var DefaultLimits = Limits{
+	MaxSteps: 1_000_000,
+	MaxLen:   1 << 20,
+}
+
+func init() {
+	if err := script.Register(Language, NewEngine(DefaultLimits)); err != nil {
+		panic(err)
+	}
+}
+
+// NewEngine creates an engine running scripts within the limits, limits left zero are taken from DefaultLimits
+func NewEngine(limits Limits) script.Engine {
+	if limits.MaxSteps <= 0 {
+		limits.MaxSteps = DefaultLimits.MaxSteps
+	}
+	if limits.MaxLen <= 0 {
+		limits.MaxLen = DefaultLimits.MaxLen
+	}
+	return script.EngineFunc(func(name string, source []byte) (script.Program, error) {
+		return compile(name, source, limits)
+	})
+}
+
+// program is a compiled script
+type program struct {
+	fset   *token.FileSet
+	body   *ast.BlockStmt
+	limits Limits
+}
+
+// compile parses the script as the body of a function and checks that only supported constructs are used
+func compile(name string, source []byte, limits Limits) (script.Program, error) {
+	fset := token.NewFileSet()
+	// The line directive makes reported positions relative to the script
+	file, err := parser.ParseFile(fset, name, "package script\nfunc activate() {\n//line "+name+":1:1\n"+string(source)+"\n}\n", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	body := file.Decls[0].(*ast.FuncDecl).Body
+	if err := check(fset, body); err != nil {
+		return nil, err
+	}
+	return &program{
+		fset:   fset,
+		body:   body,
+		limits: limits,
+	}, nil
+}
+
+// Run runs the script once
+func (p *program) Run(env *script.Env) error {
+	in := &interpreter{
+		fset:   p.fset,
+		env:    env,
+		limits: p.limits,
+		vars:   make(map[string]any),
+	}
+	_, err := in.block(p.body)
+	return err
+}
+
+// check returns an error for the first construct the interpreter does not support
+func check(fset *token.FileSet, body *ast.BlockStmt) error {
+	var err error
+	ast.Inspect(body, func(node ast.Node) bool {
+		if err != nil || node == nil {
+			return false
+		}
+
+		switch n := node.(type) {
+		case *ast.BlockStmt, *ast.ExprStmt, *ast.IfStmt, *ast.EmptyStmt, *ast.Ident, *ast.BasicLit, *ast.ParenExpr, *ast.IndexExpr:
+		case *ast.RangeStmt:
+			for _, target := range []ast.Expr{n.Key, n.Value} {
+				if _, ok := target.(*ast.Ident); target != nil && !ok {
+					err = unsupported(fset, target, "range over anything but variables")
+				}
+			}
+		case *ast.IncDecStmt:
+			if _, ok := n.X.(*ast.Ident); !ok {
+				err = unsupported(fset, n, "update of anything but a variable")
+			}
+		case *ast.UnaryExpr:
+			if n.Op != token.SUB && n.Op != token.NOT {
+				err = unsupported(fset, n, "operator "+n.Op.String())
+			}
+		case *ast.BinaryExpr:
+			if !supportedOperators[n.Op] {
+				err = unsupported(fset, n, "operator "+n.Op.String())
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				if _, ok := lhs.(*ast.Ident); !ok {
+					err = unsupported(fset, lhs, "assignment to anything but a variable")
+				}
+			}
+		case *ast.ReturnStmt:
+			if len(n.Results) > 0 {
+				err = unsupported(fset, n, "return with values")
+			}
+		case *ast.BranchStmt:
+			if n.Tok != token.BREAK && n.Tok != token.CONTINUE || n.Label != nil {
+				err = unsupported(fset, n, n.Tok.String())
+			}
+		case *ast.CallExpr:
+			ident, ok := n.Fun.(*ast.Ident)
+			if !ok || builtins[ident.Name] == nil {
+				err = unsupported(fset, n, "call of anything but a builtin")
+			}
+			if n.Ellipsis.IsValid() {
+				err = unsupported(fset, n, "variadic call")
+			}
+		default:
+			err = unsupported(fset, node, fmt.Sprintf("%T", node))
+		}
+		return err == nil
+	})
+	return err
+}
+
+// unsupported returns the error about the construct at the node position
+func unsupported(fset *token.FileSet, node ast.Node, what string) error {
+	return fmt.Errorf("%s: %w: %s", fset.Position(node.Pos()), ErrUnsupported, what)
+}
@@ -0,0 +1,281 @@
+package golite
+
+import (
+	"context"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/script"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestGolite_Mesh(t *testing.T) {
+	// parser turns JSON-like maps into amounts, summer keeps the running total in state
+	parser := script.NewComponent("parser", Language, []byte(`
+		for _, order := range input("orders") {
+			if order["amount"] == nil {
+				continue
+			}
+			emit("amounts", int(order["amount"]) * order["qty"])
+		}
+	`)).WithInputs("orders").WithOutputs("amounts")
+	summer := script.NewComponent("summer", Language, []byte(`
+		total := state("total")
+		if total == nil {
+			total = 0
+		}
+		for _, amount := range input("amounts") {
+			total += amount
+		}
+		setState("total", total)
+		emit("total", sprintf("total=%d", total))
+	`)).WithInputs("amounts").WithOutputs("total")
+	parser.OutputByName("amounts").PipeTo(summer.InputByName("amounts"))
+
+	fm := fmesh.New("orders").WithComponents(parser, summer)
+	parser.InputByName("orders").PutSignals(
+		signal.New(map[string]any{"amount": 10.0, "qty": 2}),
+		signal.New(map[string]any{"qty": 5}),
+		signal.New(map[string]any{"amount": 3.0, "qty": 1}),
+	)
+
+	_, err := fm.Run()
+	require.NoError(t, err)
+	payloads, err := summer.OutputByName("total").AllSignalsPayloads()
+	require.NoError(t, err)
+	assert.Equal(t, []any{"total=23"}, payloads)
+	assert.Equal(t, 23, summer.State().Get("total"))
+}
+
+func TestGolite_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		inputs      []any
+		wantOutputs []any
+		wantErrMsg  string
+	}{
+		{
+			name: "arithmetic and strings",
+			source: `
+				a, b := 7, 2
+				emit("out", a/b, a%b, a/2.0, -a, "x"+"y", 'a', 1 < 2 && !(2 < 1))
+			`,
+			wantOutputs: []any{3, 1, 3.5, -7, "xy", 97, true},
+		},
+		{
+			name: "loops and branches",
+			source: `
+				sum := 0
+				for i := range 10 {
+					if i%2 == 0 {
+						continue
+					} else if i > 7 {
+						break
+					}
+					sum += i
+				}
+				keys := append(nil)
+				for key, value := range input("in")[0] {
+					keys = append(keys, sprintf("%s=%v", key, value))
+				}
+				emit("out", sum, keys)
+			`,
+			inputs:      []any{map[string]any{"b": 2, "a": 1}},
+			wantOutputs: []any{16, []any{"a=1", "b=2"}},
+		},
+		{
+			name: "lists from inputs",
+			source: `
+				values := input("in")
+				emit("out", len(values), values[1], string(values[0]), float("1.5"))
+				return
+				emit("out", "unreachable")
+			`,
+			inputs:      []any{1, 2},
+			wantOutputs: []any{2, 2, "1", 1.5},
+		},
+		{
+			name:       "integer division by zero",
+			source:     `x := 0; emit("out", 1/x)`,
+			wantErrMsg: "script:1:21: script runtime error: integer division by zero",
+		},
+		{
+			name:       "fail",
+			source:     `fail("bad input", 42)`,
+			wantErrMsg: "script:1:1: fail: bad input 42",
+		},
+		{
+			name:       "unknown port",
+			source:     `emit("missing", 1)`,
+			wantErrMsg: "port not found",
+		},
+		{
+			name:       "type mismatch",
+			source:     `emit("out", "a" * 2)`,
+			wantErrMsg: "invalid operation: string * int",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := script.NewComponent("script", Language, []byte(tt.source)).WithInputs("in").WithOutputs("out")
+			require.NoError(t, c.Err())
+			inputs := tt.inputs
+			if len(inputs) == 0 {
+				inputs = []any{"trigger"}
+			}
+			c.InputByName("in").PutSignals(signal.NewGroup(inputs...).SignalsOrNil()...)
+
+			activationResult := c.MaybeActivate()
+			if tt.wantErrMsg != "" {
+				assert.True(t, activationResult.IsError())
+				assert.ErrorContains(t, activationResult.ActivationError(), tt.wantErrMsg)
+				return
+			}
+			assert.Equal(t, component.ActivationCodeOK, activationResult.Code(), activationResult.ActivationError())
+			payloads, err := c.OutputByName("out").AllSignalsPayloads()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOutputs, payloads)
+		})
+	}
+}
+
+func TestGolite_Compile(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		wantErrMsg string
+	}{
+		{
+			name:       "syntax error",
+			source:     "x := ",
+			wantErrMsg: "script:2:1: expected operand",
+		},
+		{
+			name:       "unbounded loop",
+			source:     "for {}",
+			wantErrMsg: "script:1:1: unsupported construct: *ast.ForStmt",
+		},
+		{
+			name:       "goroutine",
+			source:     `go emit("out", 1)`,
+			wantErrMsg: "unsupported construct: *ast.GoStmt",
+		},
+		{
+			name:       "function literal",
+			source:     "f := func() {}",
+			wantErrMsg: "unsupported construct: *ast.FuncLit",
+		},
+		{
+			name:       "unknown function",
+			source:     `os.Exit(1)`,
+			wantErrMsg: "unsupported construct: call of anything but a builtin",
+		},
+		{
+			name:       "bitwise operator",
+			source:     "x := 1 << 2",
+			wantErrMsg: "unsupported construct: operator <<",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := script.NewComponent("script", Language, []byte(tt.source))
+			assert.ErrorIs(t, c.Err(), script.ErrFailedToCompile)
+			assert.ErrorContains(t, c.Err(), tt.wantErrMsg)
+		})
+	}
+}
+
+func TestGolite_Limits(t *testing.T) {
+	require.NoError(t, script.Register("golite-limits-test", NewEngine(Limits{MaxSteps: 100})))
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name       string
+		language   string
+		source     string
+		ctx        context.Context
+		wantErrIs  error
+		wantErrMsg string
+	}{
+		{
+			name:     "nested loops within limits",
+			language: Language,
+			source: `
+				n := 0
+				for range 100 {
+					for range 100 {
+						n++
+					}
+				}
+				emit("out", n)
+			`,
+		},
+		{
+			name:       "huge range",
+			language:   Language,
+			source:     `for range 68719476736 {}`,
+			wantErrIs:  ErrLimitExceeded,
+			wantErrMsg: "script:1:1: script limit exceeded: more than 1000000 steps",
+		},
+		{
+			name:      "string doubling",
+			language:  Language,
+			source:    `s := "x"; for range 64 { s += s }`,
+			wantErrIs: ErrLimitExceeded,
+		},
+		{
+			name:      "appending in a loop",
+			language:  Language,
+			source:    `l := append(nil); for range 1000000 { l = append(l, 1) }`,
+			wantErrIs: ErrLimitExceeded,
+		},
+		{
+			name:       "huge padding",
+			language:   Language,
+			source:     `emit("out", sprintf("%01000000000d", 1))`,
+			wantErrMsg: "width or precision is more than 1000",
+		},
+		{
+			name:      "custom limits",
+			language:  "golite-limits-test",
+			source:    `for range 1000 {}`,
+			wantErrIs: ErrLimitExceeded,
+		},
+		{
+			name:      "cancelled run",
+			language:  Language,
+			source:    `for range 1000 {}`,
+			ctx:       cancelled,
+			wantErrIs: context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := script.NewComponent("script", tt.language, []byte(tt.source)).WithInputs("in").WithOutputs("out")
+			require.NoError(t, c.Err())
+			if tt.ctx != nil {
+				c.WithContext(tt.ctx)
+			}
+			c.InputByName("in").PutSignals(signal.New("trigger"))
+
+			started := time.Now()
+			activationResult := c.MaybeActivate()
+			assert.Less(t, time.Since(started), 5*time.Second, "script stops early")
+			if tt.wantErrIs == nil && tt.wantErrMsg == "" {
+				assert.Equal(t, component.ActivationCodeOK, activationResult.Code(), activationResult.ActivationError())
+				return
+			}
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, activationResult.ActivationError(), tt.wantErrIs)
+			}
+			if tt.wantErrMsg != "" {
+				assert.ErrorContains(t, activationResult.ActivationError(), tt.wantErrMsg)
+			}
+		})
+	}
+}
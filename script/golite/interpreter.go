@@ -0,0 +1,608 @@
+package golite
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/hovsep/fmesh/script"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// flow tells the enclosing statements how to go on after a statement
+type flow int
+
+const (
+	flowNext flow = iota
+	flowBreak
+	flowContinue
+	flowReturn
+)
+
+// supportedOperators are the binary operators scripts may use
+var supportedOperators = map[token.Token]bool{
+	token.ADD: true, token.SUB: true, token.MUL: true, token.QUO: true, token.REM: true,
+	token.EQL: true, token.NEQ: true, token.LSS: true, token.LEQ: true, token.GTR: true, token.GEQ: true,
+	token.LAND: true, token.LOR: true,
+}
+
+// assignOperators map assignment operations to the binary operators they apply
+var assignOperators = map[token.Token]token.Token{
+	token.ADD_ASSIGN: token.ADD,
+	token.SUB_ASSIGN: token.SUB,
+	token.MUL_ASSIGN: token.MUL,
+	token.QUO_ASSIGN: token.QUO,
+	token.REM_ASSIGN: token.REM,
+}
+
+const (
+	// contextCheckInterval is the number of steps between checks of the run context
+	contextCheckInterval = 256
+	// lengthPerStep is how much copying strings and lists costs, so handling large values counts against Limits.MaxSteps
+	lengthPerStep = 16
+)
+
+// interpreter runs a single activation of the script
+type interpreter struct {
+	fset   *token.FileSet
+	env    *script.Env
+	limits Limits
+	steps  int
+	// nextContextCheck is the step the run context is checked at
+	nextContextCheck int
+	vars             map[string]any
+}
+
+// step counts a statement or a loop iteration (see charge)
+func (in *interpreter) step(node ast.Node) error {
+	return in.charge(node, 1)
+}
+
+// charge counts steps, it fails when the script runs out of steps or the run context is done
+func (in *interpreter) charge(node ast.Node, steps int) error {
+	in.steps += steps
+	if in.steps > in.limits.MaxSteps {
+		return fmt.Errorf("%s: %w: more than %d steps", in.fset.Position(node.Pos()), ErrLimitExceeded, in.limits.MaxSteps)
+	}
+	if in.steps >= in.nextContextCheck {
+		in.nextContextCheck = in.steps + contextCheckInterval
+		if err := in.env.Context().Err(); err != nil {
+			return fmt.Errorf("%s: %w", in.fset.Position(node.Pos()), err)
+		}
+	}
+	return nil
+}
+
+// checkLen fails when the value is a string or a list longer than the limit
+func (in *interpreter) checkLen(node ast.Node, value any) error {
+	if length := lengthOf(value); length > in.limits.MaxLen {
+		return fmt.Errorf("%s: %w: length %d is more than %d", in.fset.Position(node.Pos()), ErrLimitExceeded, length, in.limits.MaxLen)
+	}
+	return nil
+}
+
+// lengthOf returns the length of a string or a list (0 for other values)
+func lengthOf(value any) int {
+	switch v := value.(type) {
+	case string:
+		return len(v)
+	case []any:
+		return len(v)
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		return v.Len()
+	}
+	return 0
+}
+
+// errorf returns the runtime error at the node position
+func (in *interpreter) errorf(node ast.Node, format string, args ...any) error {
+	return fmt.Errorf("%s: %w: %s", in.fset.Position(node.Pos()), ErrRuntime, fmt.Sprintf(format, args...))
+}
+
+// block runs the statements of the block
+func (in *interpreter) block(b *ast.BlockStmt) (flow, error) {
+	for _, s := range b.List {
+		if err := in.step(s); err != nil {
+			return flowNext, err
+		}
+		f, err := in.stmt(s)
+		if err != nil || f != flowNext {
+			return f, err
+		}
+	}
+	return flowNext, nil
+}
+
+// stmt runs the statement
+func (in *interpreter) stmt(s ast.Stmt) (flow, error) {
+	switch s := s.(type) {
+	case *ast.BlockStmt:
+		return in.block(s)
+	case *ast.EmptyStmt:
+		return flowNext, nil
+	case *ast.ExprStmt:
+		_, err := in.expr(s.X)
+		return flowNext, err
+	case *ast.AssignStmt:
+		return flowNext, in.assign(s)
+	case *ast.IncDecStmt:
+		op := token.ADD
+		if s.Tok == token.DEC {
+			op = token.SUB
+		}
+		return flowNext, in.update(s.X, op, 1)
+	case *ast.IfStmt:
+		return in.ifStmt(s)
+	case *ast.RangeStmt:
+		return in.rangeStmt(s)
+	case *ast.BranchStmt:
+		if s.Tok == token.BREAK {
+			return flowBreak, nil
+		}
+		return flowContinue, nil
+	case *ast.ReturnStmt:
+		return flowReturn, nil
+	default:
+		return flowNext, in.errorf(s, "unsupported statement %T", s)
+	}
+}
+
+// assign runs the assignment
+func (in *interpreter) assign(s *ast.AssignStmt) error {
+	if op, ok := assignOperators[s.Tok]; ok {
+		value, err := in.expr(s.Rhs[0])
+		if err != nil {
+			return err
+		}
+		return in.update(s.Lhs[0], op, value)
+	}
+
+	if len(s.Lhs) != len(s.Rhs) {
+		return in.errorf(s, "assignment mismatch: %d variables but %d values", len(s.Lhs), len(s.Rhs))
+	}
+	// All values are evaluated first, so a, b = b, a swaps
+	values := make([]any, len(s.Rhs))
+	for i, rhs := range s.Rhs {
+		value, err := in.expr(rhs)
+		if err != nil {
+			return err
+		}
+		values[i] = value
+	}
+	for i, lhs := range s.Lhs {
+		in.set(lhs.(*ast.Ident).Name, values[i])
+	}
+	return nil
+}
+
+// update applies the operator to the variable and the value
+func (in *interpreter) update(target ast.Expr, op token.Token, value any) error {
+	ident, ok := target.(*ast.Ident)
+	if !ok {
+		return in.errorf(target, "only variables can be updated")
+	}
+
+	current, err := in.expr(ident)
+	if err != nil {
+		return err
+	}
+	result, err := in.binary(target, op, current, value)
+	if err != nil {
+		return err
+	}
+	in.set(ident.Name, result)
+	return nil
+}
+
+// set assigns the variable (the blank identifier discards the value)
+func (in *interpreter) set(name string, value any) {
+	if name != "_" {
+		in.vars[name] = value
+	}
+}
+
+// ifStmt runs the if statement
+func (in *interpreter) ifStmt(s *ast.IfStmt) (flow, error) {
+	if s.Init != nil {
+		if f, err := in.stmt(s.Init); err != nil || f != flowNext {
+			return f, err
+		}
+	}
+
+	cond, err := in.expr(s.Cond)
+	if err != nil {
+		return flowNext, err
+	}
+	ok, isBool := cond.(bool)
+	if !isBool {
+		return flowNext, in.errorf(s.Cond, "non-boolean condition (%T)", cond)
+	}
+
+	if ok {
+		return in.block(s.Body)
+	}
+	if s.Else != nil {
+		return in.stmt(s.Else)
+	}
+	return flowNext, nil
+}
+
+// rangeStmt runs the loop over a list, a map (in key order) or an integer, items are taken one by one as the loop goes
+func (in *interpreter) rangeStmt(s *ast.RangeStmt) (flow, error) {
+	x, err := in.expr(s.X)
+	if err != nil {
+		return flowNext, err
+	}
+
+	v := reflect.ValueOf(x)
+	switch {
+	case x == nil:
+		return flowNext, nil
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		return in.loop(s, v.Len(), func(i int) (any, any) {
+			return i, v.Index(i).Interface()
+		})
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		sorted := v.MapKeys()
+		slices.SortFunc(sorted, func(a, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		return in.loop(s, len(sorted), func(i int) (any, any) {
+			return sorted[i].String(), v.MapIndex(sorted[i]).Interface()
+		})
+	default:
+		n, ok := toInt(x)
+		if !ok {
+			return flowNext, in.errorf(s.X, "cannot range over %T", x)
+		}
+		return in.loop(s, n, func(i int) (any, any) {
+			return i, nil
+		})
+	}
+}
+
+// loop runs the body of the range statement n times with the key and the value of each item
+func (in *interpreter) loop(s *ast.RangeStmt, n int, item func(i int) (any, any)) (flow, error) {
+	for i := 0; i < n; i++ {
+		if err := in.step(s); err != nil {
+			return flowNext, err
+		}
+
+		key, value := item(i)
+		if s.Key != nil {
+			in.set(s.Key.(*ast.Ident).Name, key)
+		}
+		if s.Value != nil {
+			in.set(s.Value.(*ast.Ident).Name, value)
+		}
+
+		f, err := in.block(s.Body)
+		if err != nil || f == flowReturn {
+			return f, err
+		}
+		if f == flowBreak {
+			break
+		}
+	}
+	return flowNext, nil
+}
+
+// expr evaluates the expression
+func (in *interpreter) expr(e ast.Expr) (any, error) {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		return in.literal(e)
+	case *ast.Ident:
+		if value, ok := in.vars[e.Name]; ok {
+			return value, nil
+		}
+		switch e.Name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "nil":
+			return nil, nil
+		}
+		return nil, in.errorf(e, "undefined: %s", e.Name)
+	case *ast.ParenExpr:
+		return in.expr(e.X)
+	case *ast.UnaryExpr:
+		return in.unary(e)
+	case *ast.BinaryExpr:
+		return in.binaryExpr(e)
+	case *ast.IndexExpr:
+		return in.index(e)
+	case *ast.CallExpr:
+		return in.call(e)
+	default:
+		return nil, in.errorf(e, "unsupported expression %T", e)
+	}
+}
+
+// literal evaluates the literal
+func (in *interpreter) literal(e *ast.BasicLit) (any, error) {
+	switch e.Kind {
+	case token.INT:
+		value, err := strconv.ParseInt(e.Value, 0, 0)
+		if err != nil {
+			return nil, in.errorf(e, "%v", err)
+		}
+		return int(value), nil
+	case token.FLOAT:
+		value, err := strconv.ParseFloat(e.Value, 64)
+		if err != nil {
+			return nil, in.errorf(e, "%v", err)
+		}
+		return value, nil
+	case token.STRING:
+		value, err := strconv.Unquote(e.Value)
+		if err != nil {
+			return nil, in.errorf(e, "%v", err)
+		}
+		return value, nil
+	case token.CHAR:
+		value, _, _, err := strconv.UnquoteChar(e.Value[1:len(e.Value)-1], '\'')
+		if err != nil {
+			return nil, in.errorf(e, "%v", err)
+		}
+		return int(value), nil
+	default:
+		return nil, in.errorf(e, "unsupported literal %s", e.Value)
+	}
+}
+
+// unary evaluates negation and logical not
+func (in *interpreter) unary(e *ast.UnaryExpr) (any, error) {
+	x, err := in.expr(e.X)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Op == token.NOT {
+		b, ok := x.(bool)
+		if !ok {
+			return nil, in.errorf(e, "invalid operation: !%T", x)
+		}
+		return !b, nil
+	}
+	return in.binary(e, token.SUB, 0, x)
+}
+
+// binaryExpr evaluates the binary expression, logical operators are short-circuited
+func (in *interpreter) binaryExpr(e *ast.BinaryExpr) (any, error) {
+	x, err := in.expr(e.X)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Op == token.LAND || e.Op == token.LOR {
+		left, ok := x.(bool)
+		if !ok {
+			return nil, in.errorf(e, "invalid operation: %T %s", x, e.Op)
+		}
+		if left == (e.Op == token.LOR) {
+			return left, nil
+		}
+		y, err := in.expr(e.Y)
+		if err != nil {
+			return nil, err
+		}
+		right, ok := y.(bool)
+		if !ok {
+			return nil, in.errorf(e, "invalid operation: %s %T", e.Op, y)
+		}
+		return right, nil
+	}
+
+	y, err := in.expr(e.Y)
+	if err != nil {
+		return nil, err
+	}
+	return in.binary(e, e.Op, x, y)
+}
+
+// binary applies the operator to the operands: numbers (int unless any of them is a float), strings or equality of any values
+func (in *interpreter) binary(node ast.Node, op token.Token, x any, y any) (any, error) {
+	switch op {
+	case token.EQL:
+		return equal(x, y), nil
+	case token.NEQ:
+		return !equal(x, y), nil
+	}
+
+	if xs, ok := x.(string); ok {
+		if ys, ok := y.(string); ok {
+			switch op {
+			case token.ADD:
+				// Concatenation copies both strings, so it is charged before it is done
+				if err := in.charge(node, (len(xs)+len(ys))/lengthPerStep); err != nil {
+					return nil, err
+				}
+				if err := in.checkLen(node, xs+ys); err != nil {
+					return nil, err
+				}
+				return xs + ys, nil
+			case token.LSS, token.LEQ, token.GTR, token.GEQ:
+				return compare(op, strings.Compare(xs, ys)), nil
+			}
+		}
+		return nil, in.errorf(node, "invalid operation: %T %s %T", x, op, y)
+	}
+
+	xf, xIsFloat, xok := toNumber(x)
+	yf, yIsFloat, yok := toNumber(y)
+	if !xok || !yok {
+		return nil, in.errorf(node, "invalid operation: %T %s %T", x, op, y)
+	}
+
+	if xIsFloat || yIsFloat {
+		switch op {
+		case token.ADD:
+			return xf + yf, nil
+		case token.SUB:
+			return xf - yf, nil
+		case token.MUL:
+			return xf * yf, nil
+		case token.QUO:
+			return xf / yf, nil
+		case token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return compare(op, cmp.Compare(xf, yf)), nil
+		}
+		return nil, in.errorf(node, "invalid operation: %T %s %T", x, op, y)
+	}
+
+	xi, yi := toInteger(x), toInteger(y)
+	switch op {
+	case token.ADD:
+		return xi + yi, nil
+	case token.SUB:
+		return xi - yi, nil
+	case token.MUL:
+		return xi * yi, nil
+	case token.QUO, token.REM:
+		if yi == 0 {
+			return nil, in.errorf(node, "integer division by zero")
+		}
+		if op == token.QUO {
+			return xi / yi, nil
+		}
+		return xi % yi, nil
+	case token.LSS, token.LEQ, token.GTR, token.GEQ:
+		return compare(op, cmp.Compare(xi, yi)), nil
+	}
+	return nil, in.errorf(node, "invalid operation: %T %s %T", x, op, y)
+}
+
+// index evaluates indexing of a list, a map or a string
+func (in *interpreter) index(e *ast.IndexExpr) (any, error) {
+	x, err := in.expr(e.X)
+	if err != nil {
+		return nil, err
+	}
+	key, err := in.expr(e.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.ValueOf(x)
+	switch {
+	case x == nil:
+		return nil, in.errorf(e, "index of nil")
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		name, ok := key.(string)
+		if !ok {
+			return nil, in.errorf(e.Index, "map key must be a string, got %T", key)
+		}
+		value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !value.IsValid() {
+			return nil, nil
+		}
+		return value.Interface(), nil
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array || v.Kind() == reflect.String:
+		i, ok := toInt(key)
+		if !ok {
+			return nil, in.errorf(e.Index, "index must be an integer, got %T", key)
+		}
+		if i < 0 || i >= v.Len() {
+			return nil, in.errorf(e.Index, "index out of range [%d] with length %d", i, v.Len())
+		}
+		if v.Kind() == reflect.String {
+			return string(v.String()[i]), nil
+		}
+		return v.Index(i).Interface(), nil
+	default:
+		return nil, in.errorf(e, "cannot index %T", x)
+	}
+}
+
+// call calls the builtin
+func (in *interpreter) call(e *ast.CallExpr) (any, error) {
+	name := e.Fun.(*ast.Ident).Name
+	args := make([]any, len(e.Args))
+	for i, arg := range e.Args {
+		value, err := in.expr(arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	// Builtins copy strings and lists they get (e.g. append), so they are charged before the call
+	length := 0
+	for _, arg := range args {
+		length += lengthOf(arg)
+	}
+	if err := in.charge(e, length/lengthPerStep); err != nil {
+		return nil, err
+	}
+
+	result, err := builtins[name](in.env, args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", in.fset.Position(e.Pos()), name, err)
+	}
+	return result, in.checkLen(e, result)
+}
+
+// toNumber converts any integer or float to float64, isFloat tells which one it was
+func toNumber(x any) (value float64, isFloat bool, ok bool) {
+	v := reflect.ValueOf(x)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true, true
+	default:
+		return 0, false, false
+	}
+}
+
+// toInteger converts the integer of any type to int
+func toInteger(x any) int {
+	v := reflect.ValueOf(x)
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint())
+	default:
+		return int(v.Int())
+	}
+}
+
+// toInt converts any integer (or a float having no fraction, e.g. decoded from JSON) to int
+func toInt(x any) (int, bool) {
+	f, _, ok := toNumber(x)
+	if !ok || f != float64(int(f)) {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// equal compares numbers by value regardless of their types and other values deeply
+func equal(x any, y any) bool {
+	xf, _, xok := toNumber(x)
+	yf, _, yok := toNumber(y)
+	if xok && yok {
+		return xf == yf
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// compare turns the result of a three-way comparison into the result of the operator
+func compare(op token.Token, cmp int) bool {
+	switch op {
+	case token.LSS:
+		return cmp < 0
+	case token.LEQ:
+		return cmp <= 0
+	case token.GTR:
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
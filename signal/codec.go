@@ -0,0 +1,26 @@
+package signal
+
+import "encoding/json"
+
+// Codec encodes signal payloads to bytes (for the wire, disk, etc.)
+type Codec interface {
+	Encode(payload any) ([]byte, error)
+	Decode(data []byte) (any, error)
+}
+
+// JSONCodec encodes payloads as JSON, decoded payloads have generic JSON types (float64, string, map[string]any, etc.)
+type JSONCodec struct{}
+
+// Encode encodes the payload
+func (JSONCodec) Encode(payload any) ([]byte, error) {
+	return json.Marshal(payload)
+}
+
+// Decode decodes the payload
+func (JSONCodec) Decode(data []byte) (any, error) {
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
@@ -0,0 +1,65 @@
+package signal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/hovsep/fmesh/common"
+)
+
+// CorrelationIDLabel is the system label carrying the correlation ID, signals with the same ID belong to the same
+// request/response exchange (or scatter-gather round)
+const CorrelationIDLabel = common.SystemLabelPrefix + "signal:correlation-id"
+
+// NewCorrelationID returns a new random correlation ID
+func NewCorrelationID() string {
+	return newRandomID()
+}
+
+// newRandomID returns a random 128-bit ID in hex
+func newRandomID() string {
+	b := make([]byte, 16)
+	// Read fails only when the system random source is broken, there is nothing to recover then
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// NewCorrelated creates a signal correlated with the parent signal: it carries the correlation ID of the parent,
+// a parent without correlation ID gets a new one, so replies can always be matched to it
+func NewCorrelated(parent *Signal, payload any) *Signal {
+	id := parent.CorrelationID()
+	if id == "" {
+		id = NewCorrelationID()
+		parent.WithCorrelationID(id)
+	}
+	return New(payload).WithCorrelationID(id)
+}
+
+// WithCorrelationID sets the correlation ID and returns the signal
+func (s *Signal) WithCorrelationID(id string) *Signal {
+	if s.HasErr() {
+		return s
+	}
+
+	s.AddLabel(CorrelationIDLabel, id)
+	return s
+}
+
+// CorrelationID returns the correlation ID of the signal, empty string when it is not set
+func (s *Signal) CorrelationID() string {
+	return s.LabelOrDefault(CorrelationIDLabel, "")
+}
+
+// ByCorrelation returns a group of signals with the given correlation ID
+func (g *Group) ByCorrelation(id string) *Group {
+	if g.HasErr() {
+		return NewGroup().WithErr(g.Err())
+	}
+
+	matched := NewGroup()
+	for _, sig := range g.signals {
+		if sig.CorrelationID() == id {
+			matched.signals = append(matched.signals, sig)
+		}
+	}
+	return matched
+}
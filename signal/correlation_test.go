@@ -0,0 +1,57 @@
+package signal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewCorrelated(t *testing.T) {
+	t.Run("reply carries the correlation ID of the parent", func(t *testing.T) {
+		request := New("request").WithCorrelationID("req-1")
+		reply := NewCorrelated(request, "reply")
+		assert.Equal(t, "req-1", reply.CorrelationID())
+		assert.Equal(t, "reply", reply.PayloadOrNil())
+	})
+
+	t.Run("parent without correlation ID gets a new one", func(t *testing.T) {
+		request := New("request")
+		reply := NewCorrelated(request, "reply")
+		assert.NotEmpty(t, request.CorrelationID())
+		assert.Equal(t, request.CorrelationID(), reply.CorrelationID())
+	})
+
+	t.Run("correlation IDs are unique", func(t *testing.T) {
+		assert.NotEqual(t, NewCorrelationID(), NewCorrelationID())
+	})
+}
+
+func TestGroup_ByCorrelation(t *testing.T) {
+	r1 := New(1).WithCorrelationID("a")
+	r2 := New(2).WithCorrelationID("b")
+	r3 := New(3).WithCorrelationID("a")
+
+	tests := []struct {
+		name  string
+		group *Group
+		id    string
+		want  *Group
+	}{
+		{
+			name:  "matching signals",
+			group: NewGroup().With(r1, r2, r3, New(4)),
+			id:    "a",
+			want:  NewGroup().With(r1, r3),
+		},
+		{
+			name:  "no matches",
+			group: NewGroup().With(r1, r2),
+			id:    "c",
+			want:  NewGroup(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.group.ByCorrelation(tt.id))
+		})
+	}
+}
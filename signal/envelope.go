@@ -0,0 +1,101 @@
+package signal
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Headers are the metadata of an envelope, unlike labels they are typed
+type Headers map[string]any
+
+// Envelope is a multi-part payload: headers describing the body and the body itself,
+// so envelopes can be routed by headers without touching the body (e.g. a stream or a big payload)
+type Envelope struct {
+	Headers Headers
+	Body    any
+}
+
+// NewEnvelope creates an envelope with the given body and no headers
+func NewEnvelope(body any) *Envelope {
+	return &Envelope{
+		Headers: make(Headers),
+		Body:    body,
+	}
+}
+
+// NewEnvelopeSignal creates a signal carrying an envelope with the given headers and body
+func NewEnvelopeSignal(headers Headers, body any) *Signal {
+	envelope := NewEnvelope(body)
+	for key, value := range headers {
+		envelope.Headers[key] = value
+	}
+	return New(envelope)
+}
+
+// WithHeader sets the header and returns the envelope
+func (e *Envelope) WithHeader(key string, value any) *Envelope {
+	if e.Headers == nil {
+		e.Headers = make(Headers)
+	}
+	e.Headers[key] = value
+	return e
+}
+
+// Header returns the value of the header
+func (e *Envelope) Header(key string) (any, bool) {
+	value, ok := e.Headers[key]
+	return value, ok
+}
+
+// HeaderAs returns the value of the header as T
+func HeaderAs[T any](e *Envelope, key string) (T, error) {
+	var zero T
+	value, ok := e.Header(key)
+	if !ok {
+		return zero, fmt.Errorf("%w: %s", ErrHeaderNotFound, key)
+	}
+
+	typed, ok := value.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: header %s: want %s, got %T", ErrUnexpectedPayloadType, key, typeName[T](), value)
+	}
+	return typed, nil
+}
+
+// Envelope returns the envelope carried by the signal
+func (s *Signal) Envelope() (*Envelope, error) {
+	payload, err := s.Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, ok := payload.(*Envelope)
+	if !ok || envelope == nil {
+		return nil, fmt.Errorf("%w: payload is %T", ErrNotAnEnvelope, payload)
+	}
+	return envelope, nil
+}
+
+// Header returns the value of the envelope header, false when the signal does not carry an envelope or it has no such header
+func (s *Signal) Header(key string) (any, bool) {
+	envelope, err := s.Envelope()
+	if err != nil {
+		return nil, false
+	}
+	return envelope.Header(key)
+}
+
+// ByHeader returns a group of envelope signals having the header with the given value
+func (g *Group) ByHeader(key string, value any) *Group {
+	if g.HasErr() {
+		return NewGroup().WithErr(g.Err())
+	}
+
+	matched := NewGroup()
+	for _, sig := range g.signals {
+		if header, ok := sig.Header(key); ok && reflect.DeepEqual(header, value) {
+			matched.signals = append(matched.signals, sig)
+		}
+	}
+	return matched
+}
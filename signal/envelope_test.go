@@ -0,0 +1,100 @@
+package signal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestHeaderAs(t *testing.T) {
+	envelope := NewEnvelope("body").WithHeader("status", 200)
+
+	tests := []struct {
+		name       string
+		key        string
+		want       int
+		wantErrMsg string
+	}{
+		{
+			name: "typed header",
+			key:  "status",
+			want: 200,
+		},
+		{
+			name:       "missing header",
+			key:        "method",
+			wantErrMsg: "header not found: method",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HeaderAs[int](envelope, tt.key)
+			if tt.wantErrMsg != "" {
+				assert.EqualError(t, err, tt.wantErrMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := HeaderAs[string](envelope, "status")
+	assert.ErrorIs(t, err, ErrUnexpectedPayloadType)
+}
+
+func TestSignal_Envelope(t *testing.T) {
+	sig := NewEnvelopeSignal(Headers{"method": "GET"}, "body")
+	envelope, err := sig.Envelope()
+	require.NoError(t, err)
+	assert.Equal(t, "body", envelope.Body)
+
+	header, ok := sig.Header("method")
+	assert.True(t, ok)
+	assert.Equal(t, "GET", header)
+
+	_, err = New("body").Envelope()
+	assert.ErrorIs(t, err, ErrNotAnEnvelope)
+	_, ok = New("body").Header("method")
+	assert.False(t, ok)
+}
+
+func TestGroup_ByHeader(t *testing.T) {
+	group := NewGroup().With(
+		NewEnvelopeSignal(Headers{"method": "GET"}, 1),
+		NewEnvelopeSignal(Headers{"method": "POST"}, 2),
+		NewEnvelopeSignal(Headers{"tags": []string{"a"}}, 3),
+		New("GET"),
+	)
+
+	payloads := func(g *Group) []any {
+		var bodies []any
+		for _, sig := range g.SignalsOrNil() {
+			envelope, err := sig.Envelope()
+			require.NoError(t, err)
+			bodies = append(bodies, envelope.Body)
+		}
+		return bodies
+	}
+
+	assert.Equal(t, []any{1}, payloads(group.ByHeader("method", "GET")))
+	assert.Equal(t, []any{3}, payloads(group.ByHeader("tags", []string{"a"})), "values which are not comparable are supported")
+	assert.Empty(t, payloads(group.ByHeader("method", "PUT")))
+}
+
+func TestTeeSignals_EnvelopeWithStreamBody(t *testing.T) {
+	sig := NewEnvelopeSignal(Headers{"name": "file.txt"}, NewStream(strings.NewReader("data")))
+	teed, err := TeeSignals(Signals{sig}, 2)
+	require.NoError(t, err)
+
+	for _, signals := range teed {
+		envelope, err := signals[0].Envelope()
+		require.NoError(t, err)
+		assert.Equal(t, "file.txt", envelope.Headers["name"])
+
+		got, err := io.ReadAll(envelope.Body.(*Stream))
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(got))
+	}
+}
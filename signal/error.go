@@ -0,0 +1,107 @@
+package signal
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// maxErrorStackDepth is the max number of frames captured by NewError
+const maxErrorStackDepth = 32
+
+// Error is the standard payload of signals carrying errors, so topologies propagating errors (dead letter queues, loggers)
+// handle them uniformly. Besides the error it keeps the stack of NewError caller and the origin:
+// the component, output port and cycle the error signal was first piped from (stamped by the mesh)
+type Error struct {
+	Err       error
+	Time      time.Time
+	Component string
+	Port      string
+	Cycle     int
+	stack     []uintptr
+}
+
+// NewError creates a signal carrying the error envelope
+func NewError(err error) *Signal {
+	return New(newError(err))
+}
+
+// newError creates the envelope capturing the stack of NewError caller
+func newError(err error) *Error {
+	stack := make([]uintptr, maxErrorStackDepth)
+	// Skip runtime.Callers, newError and NewError
+	n := runtime.Callers(3, stack)
+	return &Error{
+		Err:   err,
+		Time:  time.Now(),
+		stack: stack[:n],
+	}
+}
+
+// Error returns the message of the error prefixed with its origin (when known)
+func (e *Error) Error() string {
+	if e.Component == "" {
+		return fmt.Sprint(e.Err)
+	}
+	if e.Cycle == 0 {
+		// Emitted outside of activation cycles (by the event engine)
+		return fmt.Sprintf("%s.%s: %v", e.Component, e.Port, e.Err)
+	}
+	return fmt.Sprintf("%s.%s (cycle %d): %v", e.Component, e.Port, e.Cycle, e.Err)
+}
+
+// Unwrap returns the wrapped error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Stack returns the formatted stack captured when the envelope was created
+func (e *Error) Stack() string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// SetOrigin sets the origin unless it is already set, so the envelope keeps the place where the error was first emitted
+// when it is forwarded further
+func (e *Error) SetOrigin(component, port string, cycle int) {
+	if e.Component != "" {
+		return
+	}
+	e.Component, e.Port, e.Cycle = component, port, cycle
+}
+
+// ErrorPayload returns the error envelope carried by the signal
+func (s *Signal) ErrorPayload() (*Error, bool) {
+	e, ok := s.PayloadOrNil().(*Error)
+	return e, ok && e != nil
+}
+
+// IsError says whether the signal carries an error (an envelope or a plain error payload)
+func (s *Signal) IsError() bool {
+	return s.ErrorOrNil() != nil
+}
+
+// ErrorOrNil returns the error carried by the signal: the envelope (which unwraps to the original error)
+// or a plain error payload, nil when the signal does not carry an error
+func (s *Signal) ErrorOrNil() error {
+	switch payload := s.PayloadOrNil().(type) {
+	case *Error:
+		if payload == nil {
+			return nil
+		}
+		return payload
+	case error:
+		return payload
+	default:
+		return nil
+	}
+}
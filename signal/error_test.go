@@ -0,0 +1,71 @@
+package signal
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewError(t *testing.T) {
+	cause := errors.New("boom")
+	sig := NewError(cause)
+
+	e, ok := sig.ErrorPayload()
+	assert.True(t, ok)
+	assert.ErrorIs(t, e, cause)
+	assert.False(t, e.Time.IsZero())
+	assert.Contains(t, e.Stack(), "TestNewError", "stack starts at the caller")
+	assert.Equal(t, "boom", e.Error())
+
+	e.SetOrigin("parser", "errors", 2)
+	e.SetOrigin("dlq", "out", 5)
+	assert.Equal(t, "parser.errors (cycle 2): boom", e.Error(), "origin is set once")
+
+	outsideCycles := NewError(cause)
+	e, _ = outsideCycles.ErrorPayload()
+	e.SetOrigin("parser", "errors", 0)
+	assert.Equal(t, "parser.errors: boom", e.Error())
+}
+
+func TestSignal_ErrorOrNil(t *testing.T) {
+	cause := errors.New("boom")
+	tests := []struct {
+		name    string
+		signal  *Signal
+		wantErr bool
+	}{
+		{
+			name:    "envelope",
+			signal:  NewError(cause),
+			wantErr: true,
+		},
+		{
+			name:    "plain error payload",
+			signal:  New(cause),
+			wantErr: true,
+		},
+		{
+			name:   "not an error",
+			signal: New("boom"),
+		},
+		{
+			name:   "nil envelope",
+			signal: New((*Error)(nil)),
+		},
+		{
+			name:   "signal with chain error",
+			signal: NewError(cause).WithErr(errors.New("chain error")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.signal.ErrorOrNil()
+			assert.Equal(t, tt.wantErr, tt.signal.IsError())
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, cause)
+		})
+	}
+}
@@ -3,6 +3,12 @@ package signal
 import "errors"
 
 var (
-	ErrNoSignalsInGroup = errors.New("group has no signals")
-	ErrInvalidSignal    = errors.New("signal is invalid")
+	ErrNoSignalsInGroup      = errors.New("group has no signals")
+	ErrInvalidSignal         = errors.New("signal is invalid")
+	ErrNotAStream            = errors.New("signal payload is not a stream")
+	ErrUnexpectedPayloadType = errors.New("unexpected payload type")
+	ErrNotAnEnvelope         = errors.New("signal payload is not an envelope")
+	ErrHeaderNotFound        = errors.New("header not found")
+	ErrStreamClosed          = errors.New("stream is closed")
+	ErrStreamTeed            = errors.New("stream is teed, read its branches instead")
 )
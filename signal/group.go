@@ -2,6 +2,7 @@ package signal
 
 import (
 	"github.com/hovsep/fmesh/common"
+	"slices"
 )
 
 type Signals []*Signal
@@ -72,9 +73,7 @@ func (g *Group) With(signals ...*Signal) *Group {
 		return g
 	}
 
-	newSignals := make(Signals, len(g.signals)+len(signals))
-	copy(newSignals, g.signals)
-	for i, sig := range signals {
+	for _, sig := range signals {
 		if sig == nil {
 			g.SetErr(ErrInvalidSignal)
 			return NewGroup().WithErr(g.Err())
@@ -84,11 +83,26 @@ func (g *Group) With(signals ...*Signal) *Group {
 			g.SetErr(sig.Err())
 			return NewGroup().WithErr(g.Err())
 		}
+	}
+
+	// Appending in place keeps the amortized cost of adding one signal constant
+	return g.withSignals(append(g.signals, signals...))
+}
+
+// WithGroup returns the group with all signals of other group appended as a single batch,
+// signals are validated when they are added to a group, so they are not validated again
+func (g *Group) WithGroup(other *Group) *Group {
+	if g.HasErr() {
+		return g
+	}
 
-		newSignals[len(g.signals)+i] = sig
+	if other.HasErr() {
+		g.SetErr(other.Err())
+		return NewGroup().WithErr(g.Err())
 	}
 
-	return g.withSignals(newSignals)
+	g.signals = append(g.signals, other.signals...)
+	return g
 }
 
 // WithPayloads returns a group with added signals created from provided payloads
@@ -98,10 +112,9 @@ func (g *Group) WithPayloads(payloads ...any) *Group {
 		return g
 	}
 
-	newSignals := make(Signals, len(g.signals)+len(payloads))
-	copy(newSignals, g.signals)
-	for i, p := range payloads {
-		newSignals[len(g.signals)+i] = New(p)
+	newSignals := slices.Grow(g.signals, len(payloads))
+	for _, p := range payloads {
+		newSignals = append(newSignals, New(p))
 	}
 	return g.withSignals(newSignals)
 }
@@ -144,3 +157,45 @@ func (g *Group) WithErr(err error) *Group {
 func (g *Group) Len() int {
 	return len(g.signals)
 }
+
+// ByLabelMatch returns a group of signals having a label which key and value match the patterns (see common.LabelMatcher)
+func (g *Group) ByLabelMatch(keyPattern string, valuePattern string) *Group {
+	if g.HasErr() {
+		return NewGroup().WithErr(g.Err())
+	}
+
+	matcher, err := common.NewLabelMatcher(keyPattern, valuePattern)
+	if err != nil {
+		g.SetErr(err)
+		return NewGroup().WithErr(g.Err())
+	}
+
+	matched := NewGroup()
+	for _, sig := range g.signals {
+		if sig.MatchesLabels(matcher) {
+			matched.signals = append(matched.signals, sig)
+		}
+	}
+	return matched
+}
+
+// ByLabelQuery returns a group of signals satisfying the query, e.g. "stage > 3" (see common.LabelQuery)
+func (g *Group) ByLabelQuery(query string) *Group {
+	if g.HasErr() {
+		return NewGroup().WithErr(g.Err())
+	}
+
+	q, err := common.ParseLabelQuery(query)
+	if err != nil {
+		g.SetErr(err)
+		return NewGroup().WithErr(g.Err())
+	}
+
+	matched := NewGroup()
+	for _, sig := range g.signals {
+		if sig.MatchesLabels(q) {
+			matched.signals = append(matched.signals, sig)
+		}
+	}
+	return matched
+}
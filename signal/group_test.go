@@ -2,6 +2,7 @@ package signal
 
 import (
 	"errors"
+	"github.com/hovsep/fmesh/common"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -217,6 +218,45 @@ func TestGroup_With(t *testing.T) {
 	}
 }
 
+func TestGroup_WithGroup(t *testing.T) {
+	tests := []struct {
+		name  string
+		group *Group
+		other *Group
+		want  *Group
+	}{
+		{
+			name:  "empty groups",
+			group: NewGroup(),
+			other: NewGroup(),
+			want:  NewGroup(),
+		},
+		{
+			name:  "addition to group",
+			group: NewGroup(1, 2, 3),
+			other: NewGroup(4, 5, 6),
+			want:  NewGroup(1, 2, 3, 4, 5, 6),
+		},
+		{
+			name:  "with error in other group",
+			group: NewGroup(1, 2, 3),
+			other: NewGroup(4).WithErr(errors.New("some error")),
+			want:  NewGroup().WithErr(errors.New("some error")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.group.WithGroup(tt.other)
+			if tt.want.HasErr() {
+				assert.EqualError(t, got.Err(), tt.want.Err().Error())
+				return
+			}
+			assert.NoError(t, got.Err())
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestGroup_WithPayloads(t *testing.T) {
 	type args struct {
 		payloads []any
@@ -391,3 +431,125 @@ func TestGroup_SignalsOrDefault(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkGroup_With(b *testing.B) {
+	sig := New(1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		group := NewGroup()
+		for j := 0; j < 1000; j++ {
+			group = group.With(sig)
+		}
+	}
+}
+
+func BenchmarkGroup_WithPayloads(b *testing.B) {
+	payloads := make([]any, 1000)
+	for i := range payloads {
+		payloads[i] = i
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewGroup().WithPayloads(payloads...)
+	}
+}
+
+func TestGroup_ByLabelMatch(t *testing.T) {
+	eu := New(1).WithLabels(common.LabelsCollection{"region": "region/eu/west"})
+	us := New(2).WithLabels(common.LabelsCollection{"region": "region/us/east"})
+	unlabeled := New(3)
+
+	type args struct {
+		keyPattern   string
+		valuePattern string
+	}
+	tests := []struct {
+		name  string
+		group *Group
+		args  args
+		want  *Group
+	}{
+		{
+			name:  "glob",
+			group: NewGroup().With(eu, us, unlabeled),
+			args: args{
+				keyPattern:   "region",
+				valuePattern: "region/eu/*",
+			},
+			want: NewGroup().With(eu),
+		},
+		{
+			name:  "regexp",
+			group: NewGroup().With(eu, us, unlabeled),
+			args: args{
+				keyPattern:   "region",
+				valuePattern: "re:^region/(eu|us)/",
+			},
+			want: NewGroup().With(eu, us),
+		},
+		{
+			name:  "nothing matched",
+			group: NewGroup().With(eu, us, unlabeled),
+			args: args{
+				keyPattern:   "tier",
+				valuePattern: "*",
+			},
+			want: NewGroup(),
+		},
+		{
+			name:  "with chain error",
+			group: NewGroup().WithErr(errors.New("some error")),
+			args: args{
+				keyPattern:   "region",
+				valuePattern: "*",
+			},
+			want: NewGroup().WithErr(errors.New("some error")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.group.ByLabelMatch(tt.args.keyPattern, tt.args.valuePattern))
+		})
+	}
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		group := NewGroup().With(eu).ByLabelMatch("region", "re:(")
+		assert.ErrorIs(t, group.Err(), common.ErrInvalidLabelPattern)
+	})
+}
+
+func TestGroup_ByLabelQuery(t *testing.T) {
+	s1 := New(1).WithLabels(common.LabelsCollection{"year": "2019"})
+	s2 := New(2).WithLabels(common.LabelsCollection{"year": "2021"})
+	s3 := New(3).WithLabels(common.LabelsCollection{"year": "unknown"})
+
+	tests := []struct {
+		name  string
+		group *Group
+		query string
+		want  *Group
+	}{
+		{
+			name:  "numeric comparison",
+			group: NewGroup().With(s1, s2, s3),
+			query: "year >= 2020",
+			want:  NewGroup().With(s2),
+		},
+		{
+			name:  "with chain error",
+			group: NewGroup().WithErr(errors.New("some error")),
+			query: "year >= 2020",
+			want:  NewGroup().WithErr(errors.New("some error")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.group.ByLabelQuery(tt.query))
+		})
+	}
+
+	t.Run("invalid query", func(t *testing.T) {
+		group := NewGroup().With(s1).ByLabelQuery("year")
+		assert.ErrorIs(t, group.Err(), common.ErrInvalidLabelQuery)
+	})
+}
@@ -0,0 +1,22 @@
+package signal
+
+import "github.com/hovsep/fmesh/common"
+
+// IdempotencyKeyLabel is the system label carrying the idempotency key of a signal,
+// signals with the same key are the same message (e.g. redelivered after a retry)
+const IdempotencyKeyLabel = common.SystemLabelPrefix + "signal:idempotency-key"
+
+// WithIdempotencyKey sets the idempotency key and returns the signal
+func (s *Signal) WithIdempotencyKey(key string) *Signal {
+	if s.HasErr() {
+		return s
+	}
+
+	s.AddLabel(IdempotencyKeyLabel, key)
+	return s
+}
+
+// IdempotencyKey returns the idempotency key of the signal, empty string when it is not set
+func (s *Signal) IdempotencyKey() string {
+	return s.LabelOrDefault(IdempotencyKeyLabel, "")
+}
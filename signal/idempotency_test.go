@@ -0,0 +1,11 @@
+package signal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSignal_IdempotencyKey(t *testing.T) {
+	assert.Equal(t, "", New(1).IdempotencyKey())
+	assert.Equal(t, "order-42", New(1).WithIdempotencyKey("order-42").IdempotencyKey())
+}
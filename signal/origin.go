@@ -0,0 +1,32 @@
+package signal
+
+import (
+	"github.com/hovsep/fmesh/common"
+	"strconv"
+)
+
+// System labels stamped by the mesh onto signals when they are piped, they describe the last hop of the signal
+const (
+	SourceComponentLabel = common.SystemLabelPrefix + "signal:source-component"
+	SourcePortLabel      = common.SystemLabelPrefix + "signal:source-port"
+	CycleLabel           = common.SystemLabelPrefix + "signal:cycle"
+)
+
+// SourceComponent returns the name of the component which emitted the signal, empty string when the signal was not piped yet
+func (s *Signal) SourceComponent() string {
+	return s.LabelOrDefault(SourceComponentLabel, "")
+}
+
+// SourcePort returns the name of the output port the signal was emitted from, empty string when the signal was not piped yet
+func (s *Signal) SourcePort() string {
+	return s.LabelOrDefault(SourcePortLabel, "")
+}
+
+// SourceCycle returns the number of the activation cycle in which the signal was emitted, 0 when the signal was not piped yet
+func (s *Signal) SourceCycle() int {
+	cycle, err := strconv.Atoi(s.LabelOrDefault(CycleLabel, ""))
+	if err != nil {
+		return 0
+	}
+	return cycle
+}
@@ -0,0 +1,47 @@
+package signal
+
+import (
+	"github.com/hovsep/fmesh/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSignal_Origin(t *testing.T) {
+	tests := []struct {
+		name                string
+		signal              *Signal
+		wantSourceComponent string
+		wantSourcePort      string
+		wantSourceCycle     int
+	}{
+		{
+			name:   "signal was not piped",
+			signal: New(1),
+		},
+		{
+			name: "signal was piped",
+			signal: New(1).WithLabels(common.LabelsCollection{
+				SourceComponentLabel: "c1",
+				SourcePortLabel:      "out",
+				CycleLabel:           "7",
+			}),
+			wantSourceComponent: "c1",
+			wantSourcePort:      "out",
+			wantSourceCycle:     7,
+		},
+		{
+			name: "malformed cycle label",
+			signal: New(1).WithLabels(common.LabelsCollection{
+				CycleLabel: "seven",
+			}),
+			wantSourceCycle: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantSourceComponent, tt.signal.SourceComponent())
+			assert.Equal(t, tt.wantSourcePort, tt.signal.SourcePort())
+			assert.Equal(t, tt.wantSourceCycle, tt.signal.SourceCycle())
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package signal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PayloadAs returns the payload of the signal as T, the error describes the mismatch
+// (expected and actual types and where the signal came from), so it is not a bare failed type assertion
+func PayloadAs[T any](s *Signal) (T, error) {
+	var zero T
+	if s == nil {
+		return zero, ErrInvalidSignal
+	}
+
+	payload, err := s.Payload()
+	if err != nil {
+		return zero, err
+	}
+
+	value, ok := payload.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: want %s, got %T%s", ErrUnexpectedPayloadType, typeName[T](), payload, s.origin())
+	}
+	return value, nil
+}
+
+// typeName returns the name of T (which is an interface type when T is an interface)
+func typeName[T any]() string {
+	return fmt.Sprintf("%T", (*T)(nil))[1:]
+}
+
+// origin describes where the signal came from (empty when it was not piped yet)
+func (s *Signal) origin() string {
+	component, port := s.SourceComponent(), s.SourcePort()
+	if component == "" && port == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, " (emitted by %s.%s", component, port)
+	if cycle := s.SourceCycle(); cycle > 0 {
+		fmt.Fprintf(&b, " in cycle %d", cycle)
+	}
+	b.WriteString(")")
+	return b.String()
+}
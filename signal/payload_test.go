@@ -0,0 +1,72 @@
+package signal
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPayloadAs(t *testing.T) {
+	t.Run("matching type", func(t *testing.T) {
+		value, err := PayloadAs[int](New(42))
+		assert.NoError(t, err)
+		assert.Equal(t, 42, value)
+	})
+
+	t.Run("interface type", func(t *testing.T) {
+		value, err := PayloadAs[error](New(errors.New("boom")))
+		assert.NoError(t, err)
+		assert.EqualError(t, value, "boom")
+	})
+
+	tests := []struct {
+		name       string
+		signal     *Signal
+		wantErr    error
+		wantErrMsg string
+	}{
+		{
+			name:       "mismatch",
+			signal:     New("42"),
+			wantErr:    ErrUnexpectedPayloadType,
+			wantErrMsg: "unexpected payload type: want int, got string",
+		},
+		{
+			name:       "nil payload",
+			signal:     New(nil),
+			wantErr:    ErrUnexpectedPayloadType,
+			wantErrMsg: "unexpected payload type: want int, got <nil>",
+		},
+		{
+			name: "mismatch of piped signal",
+			signal: New(4.2).WithLabels(map[string]string{
+				SourceComponentLabel: "parser",
+				SourcePortLabel:      "out",
+				CycleLabel:           "3",
+			}),
+			wantErr:    ErrUnexpectedPayloadType,
+			wantErrMsg: "unexpected payload type: want int, got float64 (emitted by parser.out in cycle 3)",
+		},
+		{
+			name:       "signal with chain error",
+			signal:     New(42).WithErr(errors.New("some error")),
+			wantErrMsg: "some error",
+		},
+		{
+			name:       "nil signal",
+			signal:     nil,
+			wantErr:    ErrInvalidSignal,
+			wantErrMsg: ErrInvalidSignal.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := PayloadAs[int](tt.signal)
+			assert.Zero(t, value)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.EqualError(t, err, tt.wantErrMsg)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package signal
+
+import (
+	"github.com/hovsep/fmesh/common"
+	"slices"
+	"strconv"
+)
+
+// Priority is the priority class of a signal, signals without priority are PriorityNormal
+type Priority int
+
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+)
+
+// PriorityLabel is the system label carrying the priority of a signal, so it travels with the signal (e.g. over the wire)
+const PriorityLabel = common.SystemLabelPrefix + "signal:priority"
+
+// WithPriority sets the priority class and returns the signal
+func (s *Signal) WithPriority(priority Priority) *Signal {
+	if s.HasErr() {
+		return s
+	}
+
+	if priority == PriorityNormal {
+		s.DeleteLabel(PriorityLabel)
+		return s
+	}
+	s.AddLabel(PriorityLabel, strconv.Itoa(int(priority)))
+	return s
+}
+
+// Priority returns the priority class of the signal
+func (s *Signal) Priority() Priority {
+	priority, err := strconv.Atoi(s.LabelOrDefault(PriorityLabel, ""))
+	if err != nil {
+		return PriorityNormal
+	}
+	return Priority(priority)
+}
+
+// ByPriority returns a group with the same signals ordered by priority (highest first),
+// signals of the same priority keep their order
+func (g *Group) ByPriority() *Group {
+	if g.HasErr() {
+		return NewGroup().WithErr(g.Err())
+	}
+
+	return NewGroup().withSignals(SortByPriority(slices.Clone(g.signals)))
+}
+
+// SortByPriority orders signals by priority (highest first) in place and returns them,
+// signals of the same priority keep their order
+func SortByPriority(signals Signals) Signals {
+	slices.SortStableFunc(signals, func(a, b *Signal) int {
+		return int(b.Priority()) - int(a.Priority())
+	})
+	return signals
+}
@@ -0,0 +1,84 @@
+package signal
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSignal_Priority(t *testing.T) {
+	tests := []struct {
+		name   string
+		signal *Signal
+		want   Priority
+	}{
+		{
+			name:   "no priority",
+			signal: New(1),
+			want:   PriorityNormal,
+		},
+		{
+			name:   "high",
+			signal: New(1).WithPriority(PriorityHigh),
+			want:   PriorityHigh,
+		},
+		{
+			name:   "back to normal",
+			signal: New(1).WithPriority(PriorityLow).WithPriority(PriorityNormal),
+			want:   PriorityNormal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.signal.Priority())
+		})
+	}
+
+	t.Run("normal priority does not need a label", func(t *testing.T) {
+		assert.False(t, New(1).WithPriority(PriorityHigh).WithPriority(PriorityNormal).HasLabel(PriorityLabel))
+	})
+}
+
+func TestGroup_ByPriority(t *testing.T) {
+	tests := []struct {
+		name  string
+		group *Group
+		want  []any
+	}{
+		{
+			name: "ordered by priority, stable within a class",
+			group: NewGroup().With(
+				New(1),
+				New(2).WithPriority(PriorityLow),
+				New(3).WithPriority(PriorityHigh),
+				New(4),
+				New(5).WithPriority(PriorityHigh),
+			),
+			want: []any{3, 5, 1, 4, 2},
+		},
+		{
+			name:  "empty group",
+			group: NewGroup(),
+			want:  []any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payloads, err := tt.group.ByPriority().AllPayloads()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, payloads)
+		})
+	}
+
+	t.Run("with chain error", func(t *testing.T) {
+		assert.Error(t, NewGroup().WithErr(errors.New("some error")).ByPriority().Err())
+	})
+
+	t.Run("original group is not reordered", func(t *testing.T) {
+		group := NewGroup().With(New(1), New(2).WithPriority(PriorityHigh))
+		group.ByPriority()
+		payloads, err := group.AllPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, 2}, payloads)
+	})
+}
@@ -4,15 +4,19 @@ import "github.com/hovsep/fmesh/common"
 
 // Signal is a wrapper around the data flowing between components
 type Signal struct {
+	common.LabeledEntity
 	*common.Chainable
-	payload []any //Slice is used in order to support nil payload
+	payload any
+	// trace is the latest hop of the signal (nil when it is not traced)
+	trace *traceNode
 }
 
 // New creates a new signal from the given payloads
 func New(payload any) *Signal {
 	return &Signal{
-		Chainable: common.NewChainable(),
-		payload:   []any{payload},
+		LabeledEntity: common.NewLabeledEntity(nil),
+		Chainable:     common.NewChainable(),
+		payload:       payload,
 	}
 }
 
@@ -21,7 +25,7 @@ func (s *Signal) Payload() (any, error) {
 	if s.HasErr() {
 		return nil, s.Err()
 	}
-	return s.payload[0], nil
+	return s.payload, nil
 }
 
 // PayloadOrNil returns payload or nil in case of error
@@ -38,6 +42,41 @@ func (s *Signal) PayloadOrDefault(defaultPayload any) any {
 	return payload
 }
 
+// WithPayload replaces the payload and returns the signal, signals may be shared by many ports, so only signals owned by the caller
+// must be changed (e.g. the copy given to a pipe transform, see Copy)
+func (s *Signal) WithPayload(payload any) *Signal {
+	if s.HasErr() {
+		return s
+	}
+
+	s.payload = payload
+	return s
+}
+
+// Copy returns a new signal with the same payload, labels (the copy copies them on first modification) and trace.
+// The original signal is only read, so signals shared by many ports can be copied concurrently, but their labels
+// must not be modified while copies are in use
+func (s *Signal) Copy() *Signal {
+	if s.HasErr() {
+		return New(nil).WithErr(s.Err())
+	}
+
+	cp := New(s.payload)
+	cp.ShareLabelsOf(&s.LabeledEntity)
+	cp.ShareTrace(s)
+	return cp
+}
+
+// WithLabels sets labels and returns the signal
+func (s *Signal) WithLabels(labels common.LabelsCollection) *Signal {
+	if s.HasErr() {
+		return s
+	}
+
+	s.LabeledEntity.SetLabels(labels)
+	return s
+}
+
 // WithErr returns signal with error
 func (s *Signal) WithErr(err error) *Signal {
 	s.SetErr(err)
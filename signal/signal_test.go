@@ -22,7 +22,7 @@ func TestNew(t *testing.T) {
 				payload: nil,
 			},
 			want: &Signal{
-				payload:   []any{nil},
+				payload:   nil,
 				Chainable: &common.Chainable{},
 			},
 		},
@@ -32,7 +32,7 @@ func TestNew(t *testing.T) {
 				payload: []any{123, "hello", []int{1, 2, 3}, map[string]int{"key": 42}, []byte{}, nil},
 			},
 			want: &Signal{
-				payload:   []any{[]any{123, "hello", []int{1, 2, 3}, map[string]int{"key": 42}, []byte{}, nil}},
+				payload:   []any{123, "hello", []int{1, 2, 3}, map[string]int{"key": 42}, []byte{}, nil},
 				Chainable: &common.Chainable{},
 			},
 		},
@@ -105,3 +105,67 @@ func TestSignal_PayloadOrNil(t *testing.T) {
 		})
 	}
 }
+
+func TestSignal_WithLabels(t *testing.T) {
+	type args struct {
+		labels common.LabelsCollection
+	}
+	tests := []struct {
+		name       string
+		signal     *Signal
+		args       args
+		assertions func(t *testing.T, signal *Signal)
+	}{
+		{
+			name:   "happy path",
+			signal: New(123),
+			args: args{
+				labels: common.LabelsCollection{
+					"l1": "v1",
+					"l2": "v2",
+				},
+			},
+			assertions: func(t *testing.T, signal *Signal) {
+				assert.Len(t, signal.Labels(), 2)
+				assert.True(t, signal.HasAllLabels("l1", "l2"))
+			},
+		},
+		{
+			name:   "with error in chain",
+			signal: New(123).WithErr(errors.New("some error in chain")),
+			args: args{
+				labels: common.LabelsCollection{
+					"l1": "v1",
+				},
+			},
+			assertions: func(t *testing.T, signal *Signal) {
+				assert.Nil(t, signal.Labels())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signalAfter := tt.signal.WithLabels(tt.args.labels)
+			if tt.assertions != nil {
+				tt.assertions(t, signalAfter)
+			}
+		})
+	}
+}
+
+func TestSignal_Copy(t *testing.T) {
+	original := New(1).WithLabels(common.LabelsCollection{"l1": "v1"})
+	original.AddHop("c", "out", 1)
+
+	cp := original.Copy()
+	assert.Equal(t, 1, cp.PayloadOrNil())
+	assert.Equal(t, original.Trace(), cp.Trace())
+
+	// The copy does not change the original
+	cp.WithPayload(2).AddLabel("l2", "v2")
+	assert.Equal(t, 1, original.PayloadOrNil())
+	assert.Equal(t, common.LabelsCollection{"l1": "v1"}, original.Labels())
+	assert.Equal(t, common.LabelsCollection{"l1": "v1", "l2": "v2"}, cp.Labels())
+
+	assert.True(t, New(1).WithErr(errors.New("boom")).Copy().HasErr())
+}
@@ -0,0 +1,318 @@
+package signal
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"sync"
+)
+
+// streamChunkSize is the size of chunks buffered for branches of a teed stream
+const streamChunkSize = 32 << 10
+
+// Stream is a payload read incrementally (e.g. a file or an HTTP body), so it does not have to be loaded into memory.
+// A stream is read once: when a signal carrying it is piped to many ports, each port gets its own branch (see Tee),
+// branches are read independently and only the part not yet read by all of them is buffered.
+// Consumers which do not need the stream must close it, otherwise the whole stream ends up buffered for them
+type Stream struct {
+	source *streamSource
+	// chunk is the absolute index of the next chunk to read, offset is the position within it
+	chunk  int
+	offset int
+	closed bool
+	teed   bool
+}
+
+// streamSource is the underlying reader shared by branches of the stream
+type streamSource struct {
+	mu sync.Mutex
+	r  io.Reader
+	// err is the error the reader finished with (io.EOF when it is exhausted)
+	err error
+	// chunks are read from the reader, but not yet read by all branches, base is the absolute index of the first one
+	chunks   [][]byte
+	base     int
+	branches []*Stream
+}
+
+// NewStream creates a stream reading from r (closed when all branches are closed, if it is an io.Closer)
+func NewStream(r io.Reader) *Stream {
+	s := &Stream{
+		source: &streamSource{r: r},
+	}
+	s.source.branches = []*Stream{s}
+	return s
+}
+
+// NewChunkedStream creates a stream of the given chunks, the sequence is stopped when all branches are closed
+func NewChunkedStream(chunks iter.Seq[[]byte]) *Stream {
+	next, stop := iter.Pull(chunks)
+	return NewStream(&chunkReader{next: next, stop: stop})
+}
+
+// NewStreamSignal creates a signal carrying a stream reading from r
+func NewStreamSignal(r io.Reader) *Signal {
+	return New(NewStream(r))
+}
+
+// Read reads the next bytes of the stream
+func (s *Stream) Read(p []byte) (int, error) {
+	src := s.source
+	src.mu.Lock()
+	defer src.mu.Unlock()
+
+	if s.closed {
+		return 0, ErrStreamClosed
+	}
+	if s.teed {
+		return 0, ErrStreamTeed
+	}
+
+	for s.chunk-src.base >= len(src.chunks) {
+		// Nothing buffered ahead, so the reader goes on
+		if src.err != nil {
+			return 0, src.err
+		}
+
+		if len(src.branches) == 1 {
+			// The only branch does not need buffering
+			n, err := src.r.Read(p)
+			src.err = err
+			return n, err
+		}
+
+		buf := make([]byte, streamChunkSize)
+		n, err := src.r.Read(buf)
+		if n > 0 {
+			src.chunks = append(src.chunks, buf[:n])
+		}
+		src.err = err
+	}
+
+	chunk := src.chunks[s.chunk-src.base]
+	n := copy(p, chunk[s.offset:])
+	s.offset += n
+	if s.offset == len(chunk) {
+		s.chunk++
+		s.offset = 0
+		src.trim()
+	}
+	return n, nil
+}
+
+// Close closes the stream (the branch), the underlying reader is closed with the last branch
+func (s *Stream) Close() error {
+	src := s.source
+	src.mu.Lock()
+	defer src.mu.Unlock()
+
+	if s.closed || s.teed {
+		return nil
+	}
+	s.closed = true
+	return src.detach(s)
+}
+
+// Tee splits the stream into n branches starting at the current position, the stream itself can not be read anymore
+func (s *Stream) Tee(n int) ([]*Stream, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("%w: can not tee a stream into %d branches", ErrInvalidSignal, n)
+	}
+
+	src := s.source
+	src.mu.Lock()
+	defer src.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrStreamClosed
+	}
+	if s.teed {
+		return nil, ErrStreamTeed
+	}
+
+	branches := make([]*Stream, n)
+	for i := range branches {
+		branches[i] = &Stream{
+			source: src,
+			chunk:  s.chunk,
+			offset: s.offset,
+		}
+	}
+	src.branches = append(src.branches, branches...)
+	s.teed = true
+	return branches, src.detach(s)
+}
+
+// Chunks returns an iterator reading the stream in chunks of up to the given size, the stream is closed when the iteration stops.
+// The chunk is only valid until the next iteration
+func (s *Stream) Chunks(size int) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		defer s.Close()
+
+		buf := make([]byte, size)
+		for {
+			n, err := s.Read(buf)
+			if n > 0 && !yield(buf[:n], nil) {
+				return
+			}
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
+
+// detach removes the branch, so buffered chunks are not kept for it
+func (src *streamSource) detach(s *Stream) error {
+	for i, branch := range src.branches {
+		if branch == s {
+			src.branches = append(src.branches[:i], src.branches[i+1:]...)
+			break
+		}
+	}
+
+	if len(src.branches) > 0 {
+		src.trim()
+		return nil
+	}
+
+	src.chunks = nil
+	if closer, ok := src.r.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// trim drops chunks read by all branches
+func (src *streamSource) trim() {
+	if len(src.branches) == 0 {
+		return
+	}
+
+	oldest := src.branches[0].chunk
+	for _, branch := range src.branches[1:] {
+		oldest = min(oldest, branch.chunk)
+	}
+
+	if drop := oldest - src.base; drop > 0 {
+		clear(src.chunks[:drop])
+		src.chunks = src.chunks[drop:]
+		src.base = oldest
+	}
+}
+
+// chunkReader adapts a pulled sequence of chunks to io.Reader
+type chunkReader struct {
+	next    func() ([]byte, bool)
+	stop    func()
+	current []byte
+}
+
+// Read reads the next bytes of the current chunk, pulling the next chunk when it is exhausted
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.current) == 0 {
+		chunk, ok := r.next()
+		if !ok {
+			return 0, io.EOF
+		}
+		r.current = chunk
+	}
+
+	n := copy(p, r.current)
+	r.current = r.current[n:]
+	return n, nil
+}
+
+// Close stops the sequence
+func (r *chunkReader) Close() error {
+	r.stop()
+	return nil
+}
+
+// Stream returns the stream carried by the signal
+func (s *Signal) Stream() (*Stream, error) {
+	payload, err := s.Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	stream, ok := payload.(*Stream)
+	if !ok {
+		return nil, fmt.Errorf("%w: payload is %T", ErrNotAStream, payload)
+	}
+	return stream, nil
+}
+
+// IsStream says whether the signal carries a stream
+func (s *Signal) IsStream() bool {
+	_, ok := s.PayloadOrNil().(*Stream)
+	return ok
+}
+
+// TeeSignals prepares signals to be delivered to n destinations: stream signals (and envelopes with stream bodies) are replaced
+// by n signals carrying branches of the stream (with the same labels), other signals are shared as they are
+func TeeSignals(signals Signals, n int) ([]Signals, error) {
+	teed := make([]Signals, n)
+	for i := range teed {
+		teed[i] = make(Signals, len(signals))
+	}
+
+	for i, sig := range signals {
+		stream, envelope := streamOf(sig)
+		if stream == nil || n == 1 {
+			for dest := range teed {
+				teed[dest][i] = sig
+			}
+			continue
+		}
+
+		branches, err := stream.Tee(n)
+		if err != nil {
+			return nil, err
+		}
+		// Labels are shared by all branches and copied on first modification
+		sig.ShareLabelsOf(&sig.LabeledEntity)
+		for dest, branch := range branches {
+			var payload any = branch
+			if envelope != nil {
+				// Each destination gets its own envelope, as the body differs
+				payload = &Envelope{Headers: maps.Clone(envelope.Headers), Body: branch}
+			}
+			teed[dest][i] = New(payload)
+			teed[dest][i].ShareLabelsOf(&sig.LabeledEntity)
+			teed[dest][i].ShareTrace(sig)
+		}
+	}
+	return teed, nil
+}
+
+// streamOf returns the stream carried by the signal (directly or as the body of an envelope)
+func streamOf(sig *Signal) (*Stream, *Envelope) {
+	switch payload := sig.PayloadOrNil().(type) {
+	case *Stream:
+		return payload, nil
+	case *Envelope:
+		if payload == nil {
+			return nil, nil
+		}
+		if stream, ok := payload.Body.(*Stream); ok {
+			return stream, payload
+		}
+	}
+	return nil, nil
+}
+
+// HasStreams says whether any signal in the group carries a stream (directly or as the body of an envelope)
+func (g *Group) HasStreams() bool {
+	for _, sig := range g.signals {
+		if stream, _ := streamOf(sig); stream != nil {
+			return true
+		}
+	}
+	return false
+}
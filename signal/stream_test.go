@@ -0,0 +1,150 @@
+package signal
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// closeTracker records whether the reader was closed
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestStream_Tee(t *testing.T) {
+	data := strings.Repeat("0123456789", 10_000)
+
+	t.Run("branches read the whole stream independently", func(t *testing.T) {
+		source := &closeTracker{Reader: strings.NewReader(data)}
+		stream := NewStream(source)
+
+		// Part read before teeing is not seen by branches
+		head := make([]byte, 10)
+		_, err := io.ReadFull(stream, head)
+		require.NoError(t, err)
+
+		branches, err := stream.Tee(3)
+		require.NoError(t, err)
+		require.Len(t, branches, 3)
+
+		_, err = stream.Read(head)
+		assert.ErrorIs(t, err, ErrStreamTeed)
+
+		// Branches are read one after another, so the first one buffers everything for the others
+		for _, branch := range branches {
+			got, err := io.ReadAll(branch)
+			require.NoError(t, err)
+			assert.Equal(t, data[10:], string(got))
+		}
+
+		assert.Empty(t, stream.source.chunks, "chunks read by all branches are dropped")
+		assert.False(t, source.closed)
+		for _, branch := range branches {
+			require.NoError(t, branch.Close())
+		}
+		assert.True(t, source.closed, "source is closed with the last branch")
+	})
+
+	t.Run("closed branch does not hold buffered chunks", func(t *testing.T) {
+		branches, err := NewStream(strings.NewReader(data)).Tee(2)
+		require.NoError(t, err)
+		require.NoError(t, branches[1].Close())
+
+		got, err := io.ReadAll(branches[0])
+		require.NoError(t, err)
+		assert.Equal(t, data, string(got))
+		assert.Empty(t, branches[0].source.chunks)
+
+		_, err = branches[1].Read(make([]byte, 1))
+		assert.ErrorIs(t, err, ErrStreamClosed)
+	})
+
+	t.Run("invalid branches count", func(t *testing.T) {
+		_, err := NewStream(strings.NewReader(data)).Tee(0)
+		assert.ErrorIs(t, err, ErrInvalidSignal)
+	})
+}
+
+func TestStream_Chunks(t *testing.T) {
+	chunks := [][]byte{[]byte("hello "), []byte("streaming "), []byte("world")}
+	stream := NewChunkedStream(slices.Values(chunks))
+
+	var got bytes.Buffer
+	for chunk, err := range stream.Chunks(4) {
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(chunk), 4)
+		got.Write(chunk)
+	}
+	assert.Equal(t, "hello streaming world", got.String())
+
+	_, err := stream.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, ErrStreamClosed, "stream is closed after iteration")
+}
+
+func TestSignal_Stream(t *testing.T) {
+	tests := []struct {
+		name       string
+		signal     *Signal
+		wantStream bool
+		wantErr    error
+	}{
+		{
+			name:       "stream",
+			signal:     NewStreamSignal(strings.NewReader("data")),
+			wantStream: true,
+		},
+		{
+			name:    "not a stream",
+			signal:  New("data"),
+			wantErr: ErrNotAStream,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantStream, tt.signal.IsStream())
+			stream, err := tt.signal.Stream()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, stream)
+		})
+	}
+}
+
+func TestTeeSignals(t *testing.T) {
+	plain := New(1)
+	streamed := NewStreamSignal(strings.NewReader("data"))
+	streamed.AddLabel("k", "v")
+
+	teed, err := TeeSignals(Signals{plain, streamed}, 2)
+	require.NoError(t, err)
+	require.Len(t, teed, 2)
+
+	for _, signals := range teed {
+		assert.Same(t, plain, signals[0], "plain signals are shared")
+		assert.NotSame(t, streamed, signals[1])
+		assert.Equal(t, "v", signals[1].LabelOrDefault("k", ""))
+
+		stream, err := signals[1].Stream()
+		require.NoError(t, err)
+		got, err := io.ReadAll(stream)
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(got))
+	}
+
+	// Labels of branches are copied on modification
+	teed[0][1].AddLabel("k", "changed")
+	assert.Equal(t, "v", teed[1][1].LabelOrDefault("k", ""))
+}
@@ -0,0 +1,89 @@
+package signal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Hop is a step of a signal through the mesh: the output port it was emitted from and the cycle it happened in
+type Hop struct {
+	Component string
+	Port      string
+	// Cycle is 0 when the signal was not emitted in an activation cycle (see fmesh.EventEngine)
+	Cycle int
+}
+
+// String returns the hop as "component.port@cycle"
+func (h Hop) String() string {
+	if h.Cycle == 0 {
+		return h.Component + "." + h.Port
+	}
+	return fmt.Sprintf("%s.%s@%d", h.Component, h.Port, h.Cycle)
+}
+
+// Trace is the route of a signal: the ID generated when the signal made the first hop and all hops in order
+type Trace struct {
+	ID   string
+	Hops []Hop
+}
+
+// String returns the route as "a.out@1 -> b.out@2"
+func (t Trace) String() string {
+	hops := make([]string, len(t.Hops))
+	for i, hop := range t.Hops {
+		hops[i] = hop.String()
+	}
+	return strings.Join(hops, " -> ")
+}
+
+// traceNode is a hop in an immutable list growing towards the latest hop, so signals may share the route they have in common
+type traceNode struct {
+	id    string
+	hop   Hop
+	prev  *traceNode
+	count int
+}
+
+// AddHop records the hop in the trace of the signal, the first hop starts the trace with a new ID
+func (s *Signal) AddHop(component string, port string, cycle int) *Signal {
+	if s.HasErr() {
+		return s
+	}
+
+	node := &traceNode{
+		hop:   Hop{Component: component, Port: port, Cycle: cycle},
+		prev:  s.trace,
+		count: 1,
+	}
+	if s.trace == nil {
+		node.id = newRandomID()
+	} else {
+		node.id = s.trace.id
+		node.count = s.trace.count + 1
+	}
+	s.trace = node
+	return s
+}
+
+// ShareTrace makes the signal continue the trace of the other one (e.g. a copy of a signal continues its route)
+func (s *Signal) ShareTrace(other *Signal) *Signal {
+	if s.HasErr() {
+		return s
+	}
+
+	s.trace = other.trace
+	return s
+}
+
+// Trace returns the route of the signal, the trace is empty when the signal made no hops with tracing enabled (see fmesh.Config.TraceSignals)
+func (s *Signal) Trace() Trace {
+	if s.trace == nil {
+		return Trace{}
+	}
+
+	hops := make([]Hop, s.trace.count)
+	for node := s.trace; node != nil; node = node.prev {
+		hops[node.count-1] = node.hop
+	}
+	return Trace{ID: s.trace.id, Hops: hops}
+}
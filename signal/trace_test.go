@@ -0,0 +1,36 @@
+package signal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSignal_Trace(t *testing.T) {
+	t.Run("untraced signal", func(t *testing.T) {
+		trace := New(1).Trace()
+		assert.Empty(t, trace.ID)
+		assert.Empty(t, trace.Hops)
+	})
+
+	t.Run("hops are recorded in order", func(t *testing.T) {
+		sig := New(1).AddHop("a", "out", 1).AddHop("b", "out", 2)
+
+		trace := sig.Trace()
+		assert.Len(t, trace.ID, 32)
+		assert.Equal(t, []Hop{
+			{Component: "a", Port: "out", Cycle: 1},
+			{Component: "b", Port: "out", Cycle: 2},
+		}, trace.Hops)
+		assert.Equal(t, "a.out@1 -> b.out@2", trace.String())
+	})
+
+	t.Run("shared trace continues independently", func(t *testing.T) {
+		original := New(1).AddHop("a", "out", 1)
+		copied := New(1).ShareTrace(original).AddHop("c", "out", 0)
+		original.AddHop("b", "out", 2)
+
+		assert.Equal(t, original.Trace().ID, copied.Trace().ID)
+		assert.Equal(t, "a.out@1 -> b.out@2", original.Trace().String())
+		assert.Equal(t, "a.out@1 -> c.out", copied.Trace().String())
+	})
+}
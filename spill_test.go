@@ -0,0 +1,46 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFMesh_Spill(t *testing.T) {
+	producer := component.New("producer").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+		for i := 0; i < 10; i++ {
+			this.OutputByName("out").PutSignals(signal.New(i))
+		}
+		return nil
+	})
+
+	var batchSizes []int
+	sum := 0.0
+	consumer := component.New("consumer").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+		signals := this.InputByName("in").AllSignalsOrNil()
+		batchSizes = append(batchSizes, len(signals))
+		for _, sig := range signals {
+			switch payload := sig.PayloadOrNil().(type) {
+			case int:
+				sum += float64(payload)
+			case float64:
+				// Spilled payloads are decoded from JSON
+				sum += payload
+			}
+		}
+		return nil
+	})
+	consumer.InputByName("in").WithSpill(port.SpillConfig{Dir: t.TempDir(), MemoryThreshold: 4})
+	producer.OutputByName("out").PipeTo(consumer.InputByName("in"))
+
+	fm := NewWithConfig("fm", &Config{CyclesLimit: 10}).WithComponents(producer, consumer)
+	producer.InputByName("in").PutSignals(signal.New("start"))
+
+	_, err := fm.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 4, 2}, batchSizes)
+	assert.Equal(t, 45.0, sum)
+	assert.Equal(t, 0, consumer.InputByName("in").SpilledLen())
+}
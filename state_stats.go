@@ -0,0 +1,64 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"sort"
+)
+
+// ComponentStateStats describes the state of a single component
+type ComponentStateStats struct {
+	Component string
+	// Keys is the number of keys in the state
+	Keys int
+	// Bytes is the estimated size of the state (keys and values)
+	Bytes int
+	// LastModifiedCycle is the number of the latest cycle in which an activation changed the state,
+	// 0 when it was not modified since state stats are enabled
+	LastModifiedCycle int
+}
+
+// StateStats returns stats of component states sorted by component name, so leaking state (e.g. ever-growing seen-sets)
+// can be detected by polling them. Modifications are tracked only when Config.StateStats is enabled.
+// Must not be called while the mesh is running
+func (fm *FMesh) StateStats() []ComponentStateStats {
+	components := fm.Components().ComponentsOrNil()
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	estimator := newSizeEstimator()
+	stats := make([]ComponentStateStats, 0, len(names))
+	for _, name := range names {
+		state := components[name].State()
+		stats = append(stats, ComponentStateStats{
+			Component:         name,
+			Keys:              len(state),
+			Bytes:             estimator.sizeOf(state),
+			LastModifiedCycle: fm.stateModifiedAt[name],
+		})
+	}
+	return stats
+}
+
+// trackStateModifications makes components track modifications of their states (when state stats are enabled)
+func (fm *FMesh) trackStateModifications() {
+	if !fm.config.StateStats {
+		return
+	}
+
+	if fm.stateModifiedAt == nil {
+		fm.stateModifiedAt = make(map[string]int)
+	}
+	for _, c := range fm.Components().ComponentsOrNil() {
+		c.TrackStateModifications()
+	}
+}
+
+// recordStateModification records the cycle in which the state of the activated component was modified
+func (fm *FMesh) recordStateModification(c *component.Component, cycleNumber int) {
+	if fm.config.StateStats && c.TakeStateModified() {
+		fm.stateModifiedAt[c.Name()] = cycleNumber
+	}
+}
@@ -0,0 +1,61 @@
+package fmesh
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFMesh_StateStats(t *testing.T) {
+	// dedup remembers every payload it has seen, reader only reads its state
+	dedup := component.New("dedup").
+		WithInputs("in").
+		WithOutputs("out").
+		WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("in").AllSignalsOrNil() {
+				this.State().Set(fmt.Sprint(sig.PayloadOrNil()), true)
+			}
+			this.OutputByName("out").PutSignals(signal.New("next"))
+			return nil
+		})
+	reader := component.New("reader").
+		WithInputs("in").
+		WithInitialState(func(state component.State) {
+			state.Set("threshold", 10)
+		}).
+		WithActivationFunc(func(this *component.Component) error {
+			_ = this.State().Get("threshold")
+			return nil
+		})
+	dedup.OutputByName("out").PipeTo(reader.InputByName("in"))
+
+	fm := NewWithConfig("fm", &Config{
+		CyclesLimit: 10,
+		StateStats:  true,
+	}).WithComponents(dedup, reader)
+	dedup.InputByName("in").PutSignals(signal.NewGroup(1, 2, 3).SignalsOrNil()...)
+
+	_, err := fm.Run()
+	assert.NoError(t, err)
+
+	stats := fm.RuntimeInfo().States
+	if assert.Len(t, stats, 2) {
+		assert.Equal(t, "dedup", stats[0].Component)
+		assert.Equal(t, 3, stats[0].Keys)
+		assert.Positive(t, stats[0].Bytes)
+		assert.Equal(t, 1, stats[0].LastModifiedCycle)
+
+		assert.Equal(t, "reader", stats[1].Component)
+		assert.Equal(t, 1, stats[1].Keys)
+		assert.Equal(t, 0, stats[1].LastModifiedCycle, "state was only read")
+	}
+
+	// Stats are kept across runs
+	dedup.InputByName("in").PutSignals(signal.New(4))
+	_, err = fm.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, fm.StateStats()[0].Keys)
+	assert.Equal(t, 4, fm.StateStats()[0].LastModifiedCycle)
+}
@@ -0,0 +1,81 @@
+package fmesh
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFMesh_StateWatchers(t *testing.T) {
+	counter := component.New("counter").
+		WithInputs("in").
+		WithInitialState(func(state component.State) {
+			state.Set("count", 0)
+		}).
+		WithActivationFunc(func(this *component.Component) error {
+			count := this.State().Get("count").(int)
+			this.State().Set("count", count+this.InputByName("in").Buffer().Len())
+			return nil
+		})
+
+	var counts []any
+	counter.WatchState("count", func(change component.StateChange) {
+		counts = append(counts, change.NewValue)
+	})
+
+	fm := New("fm").WithComponents(counter)
+	counter.InputByName("in").PutSignals(signal.NewGroup(1, 2).SignalsOrNil()...)
+	_, err := fm.Run()
+	assert.NoError(t, err)
+
+	counter.InputByName("in").PutSignals(signal.New(3))
+	_, err = fm.Run()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []any{2, 3}, counts)
+}
+
+func TestFMesh_TransactionalState(t *testing.T) {
+	tests := []struct {
+		name          string
+		transactional bool
+		wantState     component.State
+	}{
+		{
+			name:          "failed activation leaves the state as it was",
+			transactional: true,
+			wantState:     component.State{"debit": 0, "credit": 0},
+		},
+		{
+			name:      "failed activation leaves half-updated state",
+			wantState: component.State{"debit": 100, "credit": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfer := component.New("transfer").
+				WithInputs("in").
+				WithInitialState(func(state component.State) {
+					state.Set("debit", 0)
+					state.Set("credit", 0)
+				}).
+				WithActivationFunc(func(this *component.Component) error {
+					this.State().Set("debit", 100)
+					return errors.New("failed before credit")
+				})
+
+			fm := NewWithConfig("fm", &Config{
+				ErrorHandlingStrategy: IgnoreAll,
+				CyclesLimit:           10,
+				TransactionalState:    tt.transactional,
+			}).WithComponents(transfer)
+			transfer.InputByName("in").PutSignals(signal.New(100))
+
+			_, err := fm.Run()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantState, transfer.State())
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package fmesh
+
+import (
+	"os"
+	ossignal "os/signal"
+	"sync"
+)
+
+// stopRequest is a graceful stop requested from another goroutine
+type stopRequest struct {
+	mu        sync.Mutex
+	requested bool
+	ch        chan struct{}
+}
+
+// request marks the stop as requested and wakes up everybody waiting for it
+func (s *stopRequest) request() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.requested {
+		return
+	}
+	s.requested = true
+	if s.ch != nil {
+		close(s.ch)
+	}
+}
+
+// isRequested says whether the stop is requested
+func (s *stopRequest) isRequested() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requested
+}
+
+// done returns a channel which is closed when the stop is requested
+func (s *stopRequest) done() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ch == nil {
+		s.ch = make(chan struct{})
+		if s.requested {
+			close(s.ch)
+		}
+	}
+	return s.ch
+}
+
+// reset forgets the request (when the run is over)
+func (s *stopRequest) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requested = false
+	s.ch = nil
+}
+
+// Stop requests a graceful stop of the current run (or the next one if the mesh is not running):
+// sources stop producing new signals, signals already in the mesh are processed and then the run returns without error.
+// It is safe to call from other goroutines
+func (fm *FMesh) Stop() {
+	fm.stop.request()
+}
+
+// HandleOSSignals stops the mesh gracefully (see Stop) when the process receives any of the given signals,
+// so services embedding a mesh shut down correctly in containers: HandleOSSignals(mesh, os.Interrupt, syscall.SIGTERM).
+// Only the first signal is handled, a repeated one gets the default behaviour (e.g. a second Ctrl+C kills the process).
+// The returned function stops handling the signals
+func HandleOSSignals(fm *FMesh, signals ...os.Signal) (stop func()) {
+	received := make(chan os.Signal, 1)
+	ossignal.Notify(received, signals...)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-received:
+			ossignal.Stop(received)
+			fm.LogDebug("received OS signal, stopping gracefully")
+			fm.Stop()
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ossignal.Stop(received)
+			close(done)
+		})
+	}
+}
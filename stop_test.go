@@ -0,0 +1,96 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFMesh_Stop(t *testing.T) {
+	t.Run("sources stop producing and in-flight signals are processed", func(t *testing.T) {
+		var (
+			fm       *FMesh
+			emitted  int
+			received []any
+		)
+		source := component.New("source").
+			WithOutputs("out").
+			WithReadinessFunc(func(this *component.Component) bool {
+				return true
+			}).
+			WithActivationFunc(func(this *component.Component) error {
+				emitted++
+				this.OutputByName("out").PutSignals(signal.New(emitted))
+				return nil
+			})
+		delay := component.New("delay").WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+			return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+		})
+		sink := component.New("sink").WithInputs("in").WithActivationFunc(func(this *component.Component) error {
+			payloads, err := this.InputByName("in").AllSignalsPayloads()
+			received = append(received, payloads...)
+			if len(received) == 3 {
+				fm.Stop()
+			}
+			return err
+		})
+		source.OutputByName("out").PipeTo(delay.InputByName("in"))
+		delay.OutputByName("out").PipeTo(sink.InputByName("in"))
+
+		fm = NewWithConfig("fm", &Config{
+			CyclesLimit: UnlimitedCycles,
+		}).WithComponents(source, delay, sink)
+
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		// Signals emitted before the stop (still travelling through delay) reached the sink
+		assert.Equal(t, 5, emitted)
+		assert.Equal(t, []any{1, 2, 3, 4, 5}, received)
+		assert.False(t, fm.stop.isRequested())
+	})
+
+	t.Run("stops waiting for sources", func(t *testing.T) {
+		source := component.New("source").
+			WithReadinessFunc(func(this *component.Component) bool {
+				return false
+			}).
+			WithIdleWaitFunc(func(this *component.Component, cancel <-chan struct{}) bool {
+				<-cancel
+				return false
+			}).
+			WithActivationFunc(func(this *component.Component) error {
+				return nil
+			})
+		fm := New("fm").WithComponents(source)
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			fm.Stop()
+		}()
+		_, err := fm.Run()
+		assert.NoError(t, err)
+	})
+
+	t.Run("stop before run", func(t *testing.T) {
+		ticks := 0
+		source := component.New("source").
+			WithOutputs("out").
+			WithReadinessFunc(func(this *component.Component) bool {
+				return true
+			}).
+			WithActivationFunc(func(this *component.Component) error {
+				ticks++
+				return nil
+			})
+		fm := New("fm").WithComponents(source)
+
+		fm.Stop()
+		cycles, err := fm.Run()
+		assert.NoError(t, err)
+		assert.Len(t, cycles, 1)
+		assert.Zero(t, ticks)
+	})
+}
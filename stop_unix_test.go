@@ -0,0 +1,86 @@
+//go:build unix
+
+package fmesh
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/component"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// signalsHelperEnv makes the test binary act as a process handling OS signals (see TestHandleOSSignals)
+const signalsHelperEnv = "FMESH_SIGNALS_HELPER"
+
+func getIdleSourceMesh() *FMesh {
+	source := component.New("source").
+		WithReadinessFunc(func(this *component.Component) bool {
+			return false
+		}).
+		WithIdleWaitFunc(func(this *component.Component, cancel <-chan struct{}) bool {
+			<-cancel
+			return false
+		}).
+		WithActivationFunc(func(this *component.Component) error {
+			return nil
+		})
+	return New("fm").WithComponents(source)
+}
+
+func sendSignalToSelf(t *testing.T, sig os.Signal) {
+	process, err := os.FindProcess(os.Getpid())
+	assert.NoError(t, err)
+	assert.NoError(t, process.Signal(sig))
+}
+
+func TestHandleOSSignals(t *testing.T) {
+	if os.Getenv(signalsHelperEnv) != "" {
+		// Helper process: the first signal stops the run, the second one must terminate the process
+		// (SIGTERM is used as unlike SIGUSR1 its default behaviour is to exit)
+		fm := getIdleSourceMesh()
+		stop := HandleOSSignals(fm, syscall.SIGTERM)
+		defer stop()
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			sendSignalToSelf(t, syscall.SIGTERM)
+		}()
+		_, err := fm.Run()
+		assert.NoError(t, err)
+
+		sendSignalToSelf(t, syscall.SIGTERM)
+		time.Sleep(5 * time.Second)
+		os.Exit(0)
+	}
+
+	t.Run("first signal stops the mesh gracefully", func(t *testing.T) {
+		fm := getIdleSourceMesh()
+		stop := HandleOSSignals(fm, syscall.SIGUSR1)
+		defer stop()
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			sendSignalToSelf(t, syscall.SIGUSR1)
+		}()
+		_, err := fm.Run()
+		assert.NoError(t, err)
+	})
+
+	t.Run("repeated signal gets the default behaviour", func(t *testing.T) {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestHandleOSSignals$")
+		cmd.Env = append(os.Environ(), signalsHelperEnv+"=1")
+		err := cmd.Run()
+
+		var exitErr *exec.ExitError
+		require.True(t, errors.As(err, &exitErr), "helper process must be killed by the second signal, got: %v", err)
+		status, ok := exitErr.Sys().(syscall.WaitStatus)
+		require.True(t, ok)
+		assert.True(t, status.Signaled())
+		assert.Equal(t, syscall.SIGTERM, status.Signal())
+	})
+}
@@ -0,0 +1,21 @@
+package fmesh
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/cycle"
+)
+
+// escalatedErrors combines errors of failed activations of the cycle which were not handled by restarts
+// (see component.WithRestartStrategy), panics are always included, returned errors only when withErrors is set
+func escalatedErrors(c *cycle.Cycle, withErrors bool) error {
+	var errs []error
+	for _, ar := range c.ActivationResults() {
+		if ar.Restarted() {
+			continue
+		}
+		if ar.IsPanic() || (withErrors && ar.IsError()) {
+			errs = append(errs, ar.ActivationError())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,83 @@
+package fmesh
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFMesh_RestartStrategy(t *testing.T) {
+	// newMesh builds a mesh where the battery fails on the first drain, the collector receives levels of the battery
+	newMesh := func(engine Engine, strategy component.RestartStrategy, levels *[]int) *FMesh {
+		battery := component.New("battery").
+			WithInputs("drain").
+			WithOutputs("level").
+			WithRestartStrategy(strategy).
+			WithInitialState(func(state component.State) {
+				state.Set("level", 100)
+			}).
+			WithActivationFunc(func(this *component.Component) error {
+				for _, sig := range this.InputByName("drain").AllSignalsOrNil() {
+					level := this.State().Get("level").(int) - sig.PayloadOrNil().(int)
+					if level < 0 {
+						this.State().Set("level", 0)
+						return errors.New("battery is over-drained")
+					}
+					this.State().Set("level", level)
+					this.OutputByName("level").PutSignals(signal.New(level))
+				}
+				return nil
+			})
+		collector := component.New("collector").
+			WithInputs("level").
+			WithActivationFunc(func(this *component.Component) error {
+				for _, sig := range this.InputByName("level").AllSignalsOrNil() {
+					*levels = append(*levels, sig.PayloadOrNil().(int))
+				}
+				return nil
+			})
+		battery.OutputByName("level").PipeTo(collector.InputByName("level"))
+
+		fm := NewWithConfig("fm", &Config{
+			ErrorHandlingStrategy: StopOnFirstErrorOrPanic,
+			Engine:                engine,
+		}).WithComponents(battery, collector)
+		battery.InputByName("drain").PutSignals(signal.New(150))
+		return fm
+	}
+
+	for _, engine := range []Engine{CycleEngine, EventEngine} {
+		var levels []int
+		fm := newMesh(engine, component.Escalate, &levels)
+		_, err := fm.Run()
+		require.ErrorIs(t, err, ErrHitAnErrorOrPanic)
+
+		levels = nil
+		fm = newMesh(engine, component.RestartAndClearState, &levels)
+		_, err = fm.Run()
+		require.NoError(t, err)
+
+		// The restarted battery keeps serving with the initial state
+		fm.ComponentByName("battery").InputByName("drain").PutSignals(signal.New(30))
+		_, err = fm.Run()
+		require.NoError(t, err)
+		assert.Equal(t, []int{70}, levels)
+	}
+
+	t.Run("restarts are reported and not degraded", func(t *testing.T) {
+		var levels []int
+		fm := newMesh(CycleEngine, component.RestartOnError, &levels)
+		fm.config.ErrorHandlingStrategy = DegradeFailing
+		_, err := fm.Run()
+		require.NoError(t, err)
+
+		activation := fm.RuntimeInfo().Report.Cycles[0].Activations["battery"]
+		assert.True(t, activation.Restarted)
+		assert.Error(t, activation.Err)
+		assert.False(t, fm.ComponentByName("battery").IsDegraded())
+		assert.Equal(t, 0, fm.ComponentByName("battery").State().Get("level"))
+	})
+}
@@ -0,0 +1,202 @@
+package testkit
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/cycle"
+	"github.com/hovsep/fmesh/port"
+	"sort"
+	"strings"
+)
+
+// PipeCoverage describes how many signals were carried by a single pipe
+type PipeCoverage struct {
+	SourceComponent string
+	SourcePort      string
+	DestComponent   string
+	DestPort        string
+	SignalsCount    int
+}
+
+// String returns a readable pipe representation
+func (pc PipeCoverage) String() string {
+	return fmt.Sprintf("%s.%s -> %s.%s", pc.SourceComponent, pc.SourcePort, pc.DestComponent, pc.DestPort)
+}
+
+// Coverage is the topology coverage of a mesh (which components activated and which pipes carried signals)
+type Coverage struct {
+	// Activations holds the number of activations per component
+	Activations map[string]int
+	// Pipes holds all pipes of the mesh sorted by source and destination
+	Pipes []*PipeCoverage
+}
+
+// NewCoverage builds the coverage of given mesh from the cycles returned by one or more runs.
+// Runs are warm, so a run returns cycles of the previous ones too, each cycle is counted once however many times it is passed.
+// Cycles are dropped by FMesh.Reset, to track coverage across resets use CoverageTracker
+func NewCoverage(fm *fmesh.FMesh, cycles ...cycle.Cycles) *Coverage {
+	coverage := newEmptyCoverage(fm)
+
+	counted := make(map[*cycle.Cycle]bool)
+	for _, runCycles := range cycles {
+		for _, c := range runCycles {
+			if counted[c] {
+				continue
+			}
+			counted[c] = true
+			coverage.addCycle(c)
+		}
+	}
+
+	return coverage
+}
+
+// newEmptyCoverage returns the coverage of given mesh with all counters set to zero
+func newEmptyCoverage(fm *fmesh.FMesh) *Coverage {
+	coverage := &Coverage{
+		Activations: make(map[string]int),
+	}
+
+	components := fm.Components().ComponentsOrNil()
+	inputOwners := make(map[*port.Port]string)
+	for _, c := range components {
+		coverage.Activations[c.Name()] = 0
+		for _, p := range c.Inputs().PortsOrNil() {
+			inputOwners[p] = c.Name()
+		}
+	}
+
+	for _, c := range sortedComponents(components) {
+		for _, out := range sortedPorts(c.Outputs().PortsOrNil()) {
+			for _, dest := range out.Pipes().PortsOrNil() {
+				coverage.Pipes = append(coverage.Pipes, &PipeCoverage{
+					SourceComponent: c.Name(),
+					SourcePort:      out.Name(),
+					DestComponent:   inputOwners[dest],
+					DestPort:        dest.Name(),
+				})
+			}
+		}
+	}
+	return coverage
+}
+
+// addCycle accumulates activations and transfers of given cycle
+func (coverage *Coverage) addCycle(c *cycle.Cycle) {
+	for componentName, ar := range c.ActivationResults() {
+		if ar.Activated() {
+			coverage.Activations[componentName]++
+		}
+	}
+
+	for _, transfer := range c.Transfers() {
+		if pipe := coverage.pipe(transfer); pipe != nil {
+			pipe.SignalsCount += transfer.SignalsCount
+		}
+	}
+}
+
+// add accumulates counters of other coverage (components and pipes the coverage does not have are skipped)
+func (coverage *Coverage) add(other *Coverage) {
+	for name, activations := range other.Activations {
+		if _, ok := coverage.Activations[name]; ok {
+			coverage.Activations[name] += activations
+		}
+	}
+	for _, otherPipe := range other.Pipes {
+		if pipe := coverage.pipe(cycle.Transfer(*otherPipe)); pipe != nil {
+			pipe.SignalsCount += otherPipe.SignalsCount
+		}
+	}
+}
+
+// pipe returns the pipe matching the given transfer
+func (coverage *Coverage) pipe(transfer cycle.Transfer) *PipeCoverage {
+	for _, pipe := range coverage.Pipes {
+		if pipe.SourceComponent == transfer.SourceComponent &&
+			pipe.SourcePort == transfer.SourcePort &&
+			pipe.DestComponent == transfer.DestComponent &&
+			pipe.DestPort == transfer.DestPort {
+			return pipe
+		}
+	}
+	return nil
+}
+
+// NotActivatedComponents returns names of components which never activated
+func (coverage *Coverage) NotActivatedComponents() []string {
+	names := make([]string, 0)
+	for name, activations := range coverage.Activations {
+		if activations == 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UnusedPipes returns pipes which never carried a signal
+func (coverage *Coverage) UnusedPipes() []*PipeCoverage {
+	pipes := make([]*PipeCoverage, 0)
+	for _, pipe := range coverage.Pipes {
+		if pipe.SignalsCount == 0 {
+			pipes = append(pipes, pipe)
+		}
+	}
+	return pipes
+}
+
+// ComponentsRatio returns the share of activated components (1 when mesh has no components)
+func (coverage *Coverage) ComponentsRatio() float64 {
+	if len(coverage.Activations) == 0 {
+		return 1
+	}
+	return float64(len(coverage.Activations)-len(coverage.NotActivatedComponents())) / float64(len(coverage.Activations))
+}
+
+// PipesRatio returns the share of pipes which carried at least one signal (1 when mesh has no pipes)
+func (coverage *Coverage) PipesRatio() float64 {
+	if len(coverage.Pipes) == 0 {
+		return 1
+	}
+	return float64(len(coverage.Pipes)-len(coverage.UnusedPipes())) / float64(len(coverage.Pipes))
+}
+
+// String returns human-readable coverage report
+func (coverage *Coverage) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("components: %.1f%% activated\n", coverage.ComponentsRatio()*100))
+	for _, name := range coverage.NotActivatedComponents() {
+		sb.WriteString(fmt.Sprintf("  never activated: %s\n", name))
+	}
+	sb.WriteString(fmt.Sprintf("pipes: %.1f%% carried signals\n", coverage.PipesRatio()*100))
+	for _, pipe := range coverage.UnusedPipes() {
+		sb.WriteString(fmt.Sprintf("  never used: %s\n", pipe))
+	}
+	return sb.String()
+}
+
+// sortedComponents returns components sorted by name
+func sortedComponents(components component.ComponentsMap) []*component.Component {
+	sorted := make([]*component.Component, 0, len(components))
+	for _, c := range components {
+		sorted = append(sorted, c)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name() < sorted[j].Name()
+	})
+	return sorted
+}
+
+// sortedPorts returns ports sorted by name
+func sortedPorts(ports port.PortMap) []*port.Port {
+	sorted := make([]*port.Port, 0, len(ports))
+	for _, p := range ports {
+		sorted = append(sorted, p)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name() < sorted[j].Name()
+	})
+	return sorted
+}
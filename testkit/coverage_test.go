@@ -0,0 +1,82 @@
+package testkit
+
+import (
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func getRouterMesh() *fmesh.FMesh {
+	router := component.New("router").
+		WithInputs("num").
+		WithOutputs("even", "odd").
+		WithActivationFunc(func(this *component.Component) error {
+			for _, sig := range this.InputByName("num").AllSignalsOrNil() {
+				if sig.PayloadOrNil().(int)%2 == 0 {
+					this.OutputByName("even").PutSignals(sig)
+				} else {
+					this.OutputByName("odd").PutSignals(sig)
+				}
+			}
+			return nil
+		})
+
+	forward := func(this *component.Component) error {
+		return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+	}
+	evenHandler := component.New("even-handler").WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+	oddHandler := component.New("odd-handler").WithInputs("in").WithOutputs("out").WithActivationFunc(forward)
+
+	router.OutputByName("even").PipeTo(evenHandler.InputByName("in"))
+	router.OutputByName("odd").PipeTo(oddHandler.InputByName("in"))
+
+	return fmesh.New("router mesh").WithComponents(router, evenHandler, oddHandler)
+}
+
+func TestNewCoverage(t *testing.T) {
+	t.Run("partial coverage", func(t *testing.T) {
+		fm := getRouterMesh()
+		fm.ComponentByName("router").InputByName("num").PutSignals(signal.New(2), signal.New(4))
+		cycles, err := fm.Run()
+		assert.NoError(t, err)
+
+		coverage := NewCoverage(fm, cycles)
+		assert.Equal(t, map[string]int{"router": 1, "even-handler": 1, "odd-handler": 0}, coverage.Activations)
+		assert.Equal(t, []string{"odd-handler"}, coverage.NotActivatedComponents())
+		assert.InDelta(t, 2.0/3.0, coverage.ComponentsRatio(), 0.001)
+		assert.Len(t, coverage.Pipes, 2)
+		assert.Equal(t, 2, coverage.Pipes[0].SignalsCount)
+		assert.Len(t, coverage.UnusedPipes(), 1)
+		assert.Equal(t, "router.odd -> odd-handler.in", coverage.UnusedPipes()[0].String())
+		assert.InDelta(t, 0.5, coverage.PipesRatio(), 0.001)
+		assert.Contains(t, coverage.String(), "never used: router.odd -> odd-handler.in")
+	})
+
+	t.Run("full coverage accumulated over multiple runs", func(t *testing.T) {
+		fm := getRouterMesh()
+		fm.ComponentByName("router").InputByName("num").PutSignals(signal.New(2))
+		cycles1, err := fm.Run()
+		assert.NoError(t, err)
+
+		fm.ComponentByName("router").InputByName("num").PutSignals(signal.New(3))
+		cycles2, err := fm.Run()
+		assert.NoError(t, err)
+
+		coverage := NewCoverage(fm, cycles1, cycles2)
+		assert.Equal(t, map[string]int{"router": 2, "even-handler": 1, "odd-handler": 1}, coverage.Activations, "cycles of the first run are counted once")
+		assert.Equal(t, 1, coverage.Pipes[0].SignalsCount)
+		assert.Empty(t, coverage.NotActivatedComponents())
+		assert.Empty(t, coverage.UnusedPipes())
+		assert.Equal(t, 1.0, coverage.ComponentsRatio())
+		assert.Equal(t, 1.0, coverage.PipesRatio())
+	})
+
+	t.Run("empty mesh", func(t *testing.T) {
+		coverage := NewCoverage(fmesh.New("empty"))
+		assert.Equal(t, 1.0, coverage.ComponentsRatio())
+		assert.Equal(t, 1.0, coverage.PipesRatio())
+	})
+}
@@ -0,0 +1,108 @@
+package testkit
+
+import (
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/cycle"
+	"sync"
+)
+
+// CoverageTracker is a plugin tracking the coverage of the mesh it is installed on while it runs.
+// Counters are reset at the start of each run and when the mesh is reset (see fmesh.FMesh.Reset),
+// so the coverage describes the latest run, unless warm start is requested (see WithWarmStart)
+type CoverageTracker struct {
+	warmStart bool
+
+	mu       sync.Mutex
+	coverage *Coverage
+	// pending is the latest cycle, it is counted once its transfers are done (on the next cycle or at the end of the run)
+	pending *cycle.Cycle
+}
+
+// NewCoverageTracker creates a coverage tracker, install it with fmesh.FMesh.Use
+func NewCoverageTracker() *CoverageTracker {
+	return &CoverageTracker{}
+}
+
+// WithWarmStart makes runs accumulate coverage of the previous ones (until the mesh is reset),
+// like runs of the mesh themselves continue from where the previous one stopped
+func (t *CoverageTracker) WithWarmStart() *CoverageTracker {
+	t.warmStart = true
+	return t
+}
+
+// Install implements fmesh.Plugin
+func (t *CoverageTracker) Install(fm *fmesh.FMesh) error {
+	t.reset(fm)
+	return nil
+}
+
+// OnRunStart implements fmesh.RunStartListener, components and pipes are taken again as they could change since the previous run
+func (t *CoverageTracker) OnRunStart(fm *fmesh.FMesh) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending = nil
+	coverage := newEmptyCoverage(fm)
+	if t.warmStart {
+		coverage.add(t.coverage)
+	}
+	t.coverage = coverage
+}
+
+// OnCycle implements fmesh.CycleListener
+func (t *CoverageTracker) OnCycle(fm *fmesh.FMesh, c *cycle.Cycle) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.flush()
+	t.pending = c
+}
+
+// OnRunStop implements fmesh.RunStopListener
+func (t *CoverageTracker) OnRunStop(fm *fmesh.FMesh, cycles cycle.Cycles, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.flush()
+}
+
+// OnReset implements fmesh.ResetListener
+func (t *CoverageTracker) OnReset(fm *fmesh.FMesh) {
+	t.reset(fm)
+}
+
+// Coverage returns a copy of the coverage tracked so far
+func (t *CoverageTracker) Coverage() *Coverage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	coverage := &Coverage{
+		Activations: make(map[string]int, len(t.coverage.Activations)),
+		Pipes:       make([]*PipeCoverage, 0, len(t.coverage.Pipes)),
+	}
+	for name, activations := range t.coverage.Activations {
+		coverage.Activations[name] = activations
+	}
+	for _, pipe := range t.coverage.Pipes {
+		pipeCopy := *pipe
+		coverage.Pipes = append(coverage.Pipes, &pipeCopy)
+	}
+	return coverage
+}
+
+// reset sets all counters to zero
+func (t *CoverageTracker) reset(fm *fmesh.FMesh) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.coverage = newEmptyCoverage(fm)
+	t.pending = nil
+}
+
+// flush counts the pending cycle
+func (t *CoverageTracker) flush() {
+	if t.pending != nil {
+		t.coverage.addCycle(t.pending)
+		t.pending = nil
+	}
+}
@@ -0,0 +1,76 @@
+package testkit
+
+import (
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCoverageTracker(t *testing.T) {
+	t.Run("two consecutive runs are covered separately", func(t *testing.T) {
+		tracker := NewCoverageTracker()
+		fm := getRouterMesh().Use(tracker)
+
+		fm.ComponentByName("router").InputByName("num").PutSignals(signal.New(2))
+		_, err := fm.Run()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"router": 1, "even-handler": 1, "odd-handler": 0}, tracker.Coverage().Activations)
+
+		fm.ComponentByName("router").InputByName("num").PutSignals(signal.New(3))
+		_, err = fm.Run()
+		require.NoError(t, err)
+		coverage := tracker.Coverage()
+		assert.Equal(t, map[string]int{"router": 1, "even-handler": 0, "odd-handler": 1}, coverage.Activations)
+		require.Len(t, coverage.UnusedPipes(), 1)
+		assert.Equal(t, "router.even -> even-handler.in", coverage.UnusedPipes()[0].String(), "pipes used by the previous run are not covered")
+	})
+
+	t.Run("warm start accumulates two consecutive runs", func(t *testing.T) {
+		tracker := NewCoverageTracker().WithWarmStart()
+		fm := getRouterMesh().Use(tracker)
+
+		fm.ComponentByName("router").InputByName("num").PutSignals(signal.New(2))
+		_, err := fm.Run()
+		require.NoError(t, err)
+
+		fm.ComponentByName("router").InputByName("num").PutSignals(signal.New(3))
+		_, err = fm.Run()
+		require.NoError(t, err)
+
+		coverage := tracker.Coverage()
+		assert.Equal(t, map[string]int{"router": 2, "even-handler": 1, "odd-handler": 1}, coverage.Activations)
+		assert.Empty(t, coverage.UnusedPipes())
+		for _, pipe := range coverage.Pipes {
+			assert.Equal(t, 1, pipe.SignalsCount, pipe.String())
+		}
+	})
+
+	t.Run("reset clears counters", func(t *testing.T) {
+		tracker := NewCoverageTracker().WithWarmStart()
+		fm := getRouterMesh().Use(tracker)
+
+		fm.ComponentByName("router").InputByName("num").PutSignals(signal.New(2))
+		_, err := fm.Run()
+		require.NoError(t, err)
+
+		require.False(t, fm.Reset().HasErr())
+		assert.Equal(t, 0.0, tracker.Coverage().ComponentsRatio())
+		assert.Equal(t, 0.0, tracker.Coverage().PipesRatio())
+
+		fm.ComponentByName("router").InputByName("num").PutSignals(signal.New(3))
+		_, err = fm.Run()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"router": 1, "even-handler": 0, "odd-handler": 1}, tracker.Coverage().Activations)
+	})
+
+	t.Run("coverage is a copy", func(t *testing.T) {
+		tracker := NewCoverageTracker()
+		getRouterMesh().Use(tracker)
+
+		tracker.Coverage().Activations["router"] = 10
+		tracker.Coverage().Pipes[0].SignalsCount = 10
+		assert.Equal(t, 0, tracker.Coverage().Activations["router"])
+		assert.Equal(t, 0, tracker.Coverage().Pipes[0].SignalsCount)
+	})
+}
@@ -0,0 +1,92 @@
+package testkit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/common"
+	"github.com/hovsep/fmesh/signal"
+	"io"
+)
+
+var (
+	ErrPayloadColumnNotFound = errors.New("payload column not found")
+)
+
+// SignalFixture describes a single labeled signal
+type SignalFixture struct {
+	Payload any                     `json:"payload"`
+	Labels  common.LabelsCollection `json:"labels"`
+}
+
+// SignalFixtures is a table of signal fixtures
+type SignalFixtures []SignalFixture
+
+// Group builds a signal group from the fixtures table
+func (fixtures SignalFixtures) Group() *signal.Group {
+	signals := make(signal.Signals, len(fixtures))
+	for i, fixture := range fixtures {
+		signals[i] = signal.New(fixture.Payload).WithLabels(fixture.Labels)
+	}
+	return signal.NewGroup().With(signals...)
+}
+
+// SignalsFromTable builds a signal group from the given fixtures
+func SignalsFromTable(fixtures ...SignalFixture) *signal.Group {
+	return SignalFixtures(fixtures).Group()
+}
+
+// SignalsFromCSV builds a signal group from CSV data
+// the first row is a header, the value of payloadColumn becomes the (string) payload
+// and all other columns become labels of the signal
+func SignalsFromCSV(r io.Reader, payloadColumn string) (*signal.Group, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+
+	if len(records) == 0 {
+		return signal.NewGroup(), nil
+	}
+
+	header := records[0]
+	payloadIndex := -1
+	for i, column := range header {
+		if column == payloadColumn {
+			payloadIndex = i
+			break
+		}
+	}
+
+	if payloadIndex < 0 {
+		return nil, fmt.Errorf("%w, column name: %s", ErrPayloadColumnNotFound, payloadColumn)
+	}
+
+	fixtures := make(SignalFixtures, 0, len(records)-1)
+	for _, record := range records[1:] {
+		fixture := SignalFixture{
+			Payload: record[payloadIndex],
+			Labels:  make(common.LabelsCollection, len(header)-1),
+		}
+		for i, value := range record {
+			if i == payloadIndex {
+				continue
+			}
+			fixture.Labels[header[i]] = value
+		}
+		fixtures = append(fixtures, fixture)
+	}
+
+	return fixtures.Group(), nil
+}
+
+// SignalsFromJSON builds a signal group from JSON data
+// the data must be an array of objects with "payload" and (optional) "labels" keys
+func SignalsFromJSON(r io.Reader) (*signal.Group, error) {
+	var fixtures SignalFixtures
+	if err := json.NewDecoder(r).Decode(&fixtures); err != nil {
+		return nil, fmt.Errorf("failed to decode json: %w", err)
+	}
+	return fixtures.Group(), nil
+}
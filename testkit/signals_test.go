@@ -0,0 +1,99 @@
+package testkit
+
+import (
+	"github.com/hovsep/fmesh/common"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestSignalsFromTable(t *testing.T) {
+	group := SignalsFromTable(
+		SignalFixture{Payload: "Dune", Labels: common.LabelsCollection{"genre": "sci-fi"}},
+		SignalFixture{Payload: "It", Labels: common.LabelsCollection{"genre": "horror"}},
+		SignalFixture{Payload: 42},
+	)
+
+	assert.False(t, group.HasErr())
+	assert.Equal(t, 3, group.Len())
+
+	payloads, err := group.AllPayloads()
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"Dune", "It", 42}, payloads)
+	assert.Equal(t, "horror", group.SignalsOrNil()[1].LabelOrDefault("genre", ""))
+	assert.Nil(t, group.SignalsOrNil()[2].Labels())
+}
+
+func TestSignalsFromCSV(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		payloadColumn string
+		assertions    func(t *testing.T, payloads []any, labels []common.LabelsCollection, err error)
+	}{
+		{
+			name:          "empty data",
+			data:          "",
+			payloadColumn: "title",
+			assertions: func(t *testing.T, payloads []any, labels []common.LabelsCollection, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, payloads)
+			},
+		},
+		{
+			name:          "payload column not found",
+			data:          "title,genre\nDune,sci-fi",
+			payloadColumn: "name",
+			assertions: func(t *testing.T, payloads []any, labels []common.LabelsCollection, err error) {
+				assert.ErrorIs(t, err, ErrPayloadColumnNotFound)
+			},
+		},
+		{
+			name:          "happy path",
+			data:          "genre,title,year\nsci-fi,Dune,1965\nhorror,It,1986",
+			payloadColumn: "title",
+			assertions: func(t *testing.T, payloads []any, labels []common.LabelsCollection, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []any{"Dune", "It"}, payloads)
+				assert.Equal(t, []common.LabelsCollection{
+					{"genre": "sci-fi", "year": "1965"},
+					{"genre": "horror", "year": "1986"},
+				}, labels)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group, err := SignalsFromCSV(strings.NewReader(tt.data), tt.payloadColumn)
+			var payloads []any
+			var labels []common.LabelsCollection
+			if err == nil {
+				payloads, _ = group.AllPayloads()
+				for _, sig := range group.SignalsOrNil() {
+					labels = append(labels, sig.Labels())
+				}
+			}
+			tt.assertions(t, payloads, labels, err)
+		})
+	}
+}
+
+func TestSignalsFromJSON(t *testing.T) {
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := SignalsFromJSON(strings.NewReader(`{"payload":`))
+		assert.Error(t, err)
+	})
+
+	t.Run("happy path", func(t *testing.T) {
+		group, err := SignalsFromJSON(strings.NewReader(`[
+			{"payload": "Dune", "labels": {"genre": "sci-fi"}},
+			{"payload": 42}
+		]`))
+		assert.NoError(t, err)
+
+		payloads, err := group.AllPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"Dune", float64(42)}, payloads)
+		assert.Equal(t, common.LabelsCollection{"genre": "sci-fi"}, group.SignalsOrNil()[0].Labels())
+	})
+}
@@ -0,0 +1,138 @@
+package testkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/cycle"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+const (
+	// UpdateSnapshotsEnv is the environment variable which forces snapshots to be (re)written
+	UpdateSnapshotsEnv = "FMESH_UPDATE_SNAPSHOTS"
+
+	// SnapshotsDir is the directory (relative to the test package) where snapshots are stored
+	SnapshotsDir = "testdata/snapshots"
+)
+
+// ActivationSnapshot is the normalized activation result
+type ActivationSnapshot struct {
+	Component string `json:"component"`
+	Activated bool   `json:"activated"`
+	Code      string `json:"code"`
+	Error     string `json:"error,omitempty"`
+}
+
+// CycleSnapshot is the normalized activation cycle
+type CycleSnapshot struct {
+	Number      int                  `json:"number"`
+	Activations []ActivationSnapshot `json:"activations"`
+	Transfers   []cycle.Transfer     `json:"transfers,omitempty"`
+}
+
+// RunSnapshot is the normalized runtime info (stable ordering, no timestamps and durations)
+type RunSnapshot struct {
+	Cycles []CycleSnapshot `json:"cycles"`
+}
+
+// NewRunSnapshot normalizes the given runtime info
+func NewRunSnapshot(runtimeInfo *fmesh.RuntimeInfo) *RunSnapshot {
+	snapshot := &RunSnapshot{
+		Cycles: make([]CycleSnapshot, 0),
+	}
+
+	if runtimeInfo == nil {
+		return snapshot
+	}
+
+	for _, c := range runtimeInfo.Cycles {
+		cycleSnapshot := CycleSnapshot{
+			Number:      c.Number(),
+			Activations: make([]ActivationSnapshot, 0, len(c.ActivationResults())),
+		}
+
+		for _, ar := range c.ActivationResults() {
+			activationSnapshot := ActivationSnapshot{
+				Component: ar.ComponentName(),
+				Activated: ar.Activated(),
+				Code:      ar.Code().String(),
+			}
+			if ar.ActivationError() != nil {
+				activationSnapshot.Error = ar.ActivationError().Error()
+			}
+			cycleSnapshot.Activations = append(cycleSnapshot.Activations, activationSnapshot)
+		}
+		sort.Slice(cycleSnapshot.Activations, func(i, j int) bool {
+			return cycleSnapshot.Activations[i].Component < cycleSnapshot.Activations[j].Component
+		})
+
+		cycleSnapshot.Transfers = append(cycleSnapshot.Transfers, c.Transfers()...)
+		sort.Slice(cycleSnapshot.Transfers, func(i, j int) bool {
+			return transferKey(cycleSnapshot.Transfers[i]) < transferKey(cycleSnapshot.Transfers[j])
+		})
+
+		snapshot.Cycles = append(snapshot.Cycles, cycleSnapshot)
+	}
+
+	return snapshot
+}
+
+// Marshal returns the snapshot as indented JSON
+func (snapshot *RunSnapshot) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// MatchSnapshot compares the normalized runtime info with the stored snapshot, the snapshot is (re)written when UpdateSnapshotsEnv is set.
+// A missing snapshot fails the test, so deleted or renamed snapshots are caught in CI
+func MatchSnapshot(t testing.TB, name string, runtimeInfo *fmesh.RuntimeInfo) {
+	t.Helper()
+
+	got, err := NewRunSnapshot(runtimeInfo).Marshal()
+	if err != nil {
+		t.Fatalf("failed to marshal snapshot: %v", err)
+		return
+	}
+
+	path := filepath.Join(SnapshotsDir, name+".json")
+	if os.Getenv(UpdateSnapshotsEnv) != "" {
+		if err := writeSnapshot(path, got); err != nil {
+			t.Fatalf("failed to write snapshot: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("snapshot %s does not exist (set %s=1 to create it)", path, UpdateSnapshotsEnv)
+		return
+	}
+	if err != nil {
+		t.Fatalf("failed to read snapshot: %v", err)
+		return
+	}
+
+	if string(want) != string(got) {
+		t.Errorf("run does not match snapshot %s (set %s=1 to update)\nwant:\n%s\ngot:\n%s", path, UpdateSnapshotsEnv, want, got)
+	}
+}
+
+// writeSnapshot writes snapshot file creating directories when needed
+func writeSnapshot(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// transferKey returns the key used to sort transfers
+func transferKey(transfer cycle.Transfer) string {
+	return fmt.Sprintf("%s/%s/%s/%s", transfer.SourceComponent, transfer.SourcePort, transfer.DestComponent, transfer.DestPort)
+}
@@ -0,0 +1,80 @@
+package testkit
+
+import (
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewRunSnapshot(t *testing.T) {
+	t.Run("nil runtime info", func(t *testing.T) {
+		assert.Empty(t, NewRunSnapshot(nil).Cycles)
+	})
+
+	t.Run("normalized run", func(t *testing.T) {
+		fm := getRouterMesh()
+		fm.ComponentByName("router").InputByName("num").PutSignals(signal.New(1), signal.New(2))
+		_, err := fm.Run()
+		assert.NoError(t, err)
+
+		snapshot := NewRunSnapshot(fm.RuntimeInfo())
+		assert.Len(t, snapshot.Cycles, 3)
+		assert.Equal(t, []ActivationSnapshot{
+			{Component: "even-handler", Activated: false, Code: "No input"},
+			{Component: "odd-handler", Activated: false, Code: "No input"},
+			{Component: "router", Activated: true, Code: "OK"},
+		}, snapshot.Cycles[0].Activations)
+		assert.Len(t, snapshot.Cycles[0].Transfers, 2)
+		assert.Equal(t, "even-handler", snapshot.Cycles[0].Transfers[0].DestComponent)
+	})
+}
+
+func TestMatchSnapshot(t *testing.T) {
+	run := func(payloads ...any) *fmesh.RuntimeInfo {
+		fm := getRouterMesh()
+		fm.ComponentByName("router").InputByName("num").PutSignals(signal.NewGroup(payloads...).SignalsOrNil()...)
+		_, err := fm.Run()
+		assert.NoError(t, err)
+		return fm.RuntimeInfo()
+	}
+
+	t.Run("matching snapshot", func(t *testing.T) {
+		MatchSnapshot(t, "router", run(1, 2))
+	})
+
+	t.Run("structural change is detected", func(t *testing.T) {
+		rt := runRecorded(func(t testing.TB) {
+			MatchSnapshot(t, "router", run(2))
+		})
+		if assert.Len(t, rt.Failures(), 1) {
+			assert.Contains(t, rt.Failures()[0], "run does not match snapshot")
+		}
+	})
+
+	t.Run("missing snapshot fails", func(t *testing.T) {
+		rt := runRecorded(func(t testing.TB) {
+			MatchSnapshot(t, "no-such-snapshot", run(1))
+		})
+		if assert.Len(t, rt.Failures(), 1) {
+			assert.Contains(t, rt.Failures()[0], "does not exist")
+		}
+		assert.NoFileExists(t, filepath.Join(SnapshotsDir, "no-such-snapshot.json"))
+	})
+
+	t.Run("missing snapshot is created on update", func(t *testing.T) {
+		t.Setenv(UpdateSnapshotsEnv, "1")
+		path := filepath.Join(SnapshotsDir, "created-snapshot.json")
+		t.Cleanup(func() {
+			_ = os.Remove(path)
+		})
+
+		rt := runRecorded(func(t testing.TB) {
+			MatchSnapshot(t, "created-snapshot", run(1))
+		})
+		assert.Empty(t, rt.Failures())
+		assert.FileExists(t, path)
+	})
+}
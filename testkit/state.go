@@ -0,0 +1,107 @@
+package testkit
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"sync"
+	"testing"
+)
+
+const (
+	defaultStressActivations = 100
+	defaultStressInstances   = 4
+	defaultStressReaders     = 4
+)
+
+// StateStressConfig configures StressState
+type StateStressConfig struct {
+	// Activations is the total number of activations spread over all instances (default 100)
+	Activations int
+	// Instances is the number of component instances activating concurrently (default 4)
+	Instances int
+	// Readers is the number of background goroutines reading states of instances while they activate (default 4)
+	Readers int
+	// Prepare is called before each activation, typically to put signals on input ports
+	Prepare func(c *component.Component, activation int)
+	// Read is called repeatedly by each reader until all activations are done
+	Read func(state component.State)
+}
+
+// StressState activates instances of the component made by the factory concurrently (like workers of a load balancer
+// or components sharing values through their states) while background readers access their states,
+// run it with -race to make sure the state usage pattern (e.g. values guarded by mutex or atomics) is safe.
+// Each instance activates sequentially, as a component never activates concurrently with itself
+func StressState(t testing.TB, newComponent func(index int) *component.Component, config StateStressConfig) {
+	t.Helper()
+
+	if config.Activations <= 0 {
+		config.Activations = defaultStressActivations
+	}
+
+	if config.Instances <= 0 {
+		config.Instances = defaultStressInstances
+	}
+
+	if config.Readers <= 0 {
+		config.Readers = defaultStressReaders
+	}
+
+	instances := make([]*component.Component, config.Instances)
+	for i := range instances {
+		instances[i] = newComponent(i)
+	}
+
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	if config.Read != nil {
+		for i := 0; i < config.Readers; i++ {
+			readers.Add(1)
+			go func(i int) {
+				defer readers.Done()
+				for {
+					select {
+					case <-done:
+						return
+					default:
+						config.Read(instances[i%len(instances)].State())
+						i++
+					}
+				}
+			}(i)
+		}
+	}
+
+	var activations sync.WaitGroup
+	for i, c := range instances {
+		activations.Add(1)
+		go func() {
+			defer activations.Done()
+			// Activations are dealt to instances in turn
+			for activation := i; activation < config.Activations; activation += len(instances) {
+				activate(t, c, activation, config.Prepare)
+			}
+		}()
+	}
+
+	activations.Wait()
+	close(done)
+	readers.Wait()
+}
+
+// activate runs a single activation of the instance and reports its failure
+func activate(t testing.TB, c *component.Component, activation int, prepare func(c *component.Component, activation int)) {
+	if prepare != nil {
+		prepare(c, activation)
+	}
+
+	activationResult := c.MaybeActivate()
+	if activationResult.HasErr() {
+		t.Errorf("activation #%d of %s failed with chain error: %v", activation, c.Name(), activationResult.Err())
+	}
+
+	if activationResult.IsError() || activationResult.IsPanic() {
+		t.Errorf("activation #%d of %s finished with code %s: %v", activation, c.Name(), activationResult.Code(), activationResult.ActivationError())
+	}
+
+	c.ClearInputs()
+	c.Outputs().Clear()
+}
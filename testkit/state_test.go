@@ -0,0 +1,97 @@
+package testkit
+
+import (
+	"errors"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStressState(t *testing.T) {
+	t.Run("atomic counter shared by instances is safe", func(t *testing.T) {
+		counter := &atomic.Int64{}
+		newCounter := func(index int) *component.Component {
+			return component.New("counter").
+				WithInputs("in").
+				WithInitialState(func(state component.State) {
+					state.Set("count", counter)
+				}).
+				WithActivationFunc(func(this *component.Component) error {
+					this.State().Get("count").(*atomic.Int64).Add(int64(this.InputByName("in").Buffer().Len()))
+					return nil
+				})
+		}
+
+		StressState(t, newCounter, StateStressConfig{
+			Activations: 50,
+			Prepare: func(c *component.Component, activation int) {
+				c.InputByName("in").PutSignals(signal.New(activation))
+			},
+			Read: func(state component.State) {
+				_ = state.Get("count").(*atomic.Int64).Load()
+			},
+		})
+
+		assert.Equal(t, int64(50), counter.Load())
+	})
+
+	t.Run("instances activate concurrently", func(t *testing.T) {
+		const instances = 3
+		var arrived sync.WaitGroup
+		arrived.Add(instances)
+		allArrived := make(chan struct{})
+		go func() {
+			arrived.Wait()
+			close(allArrived)
+		}()
+
+		// The first activation of each instance waits for the others, which only works when they run concurrently
+		newWorker := func(index int) *component.Component {
+			return component.New("worker").
+				WithInputs("in").
+				WithActivationFunc(func(this *component.Component) error {
+					arrived.Done()
+					select {
+					case <-allArrived:
+						return nil
+					case <-time.After(5 * time.Second):
+						return errors.New("instances did not activate concurrently")
+					}
+				})
+		}
+
+		StressState(t, newWorker, StateStressConfig{
+			Activations: instances,
+			Instances:   instances,
+			Prepare: func(c *component.Component, activation int) {
+				c.InputByName("in").PutSignals(signal.New(activation))
+			},
+		})
+	})
+
+	t.Run("failing activations are reported", func(t *testing.T) {
+		newFailing := func(index int) *component.Component {
+			return component.New("failing").
+				WithInputs("in").
+				WithActivationFunc(func(this *component.Component) error {
+					panic("boom")
+				})
+		}
+
+		rt := runRecorded(func(t testing.TB) {
+			StressState(t, newFailing, StateStressConfig{
+				Activations: 1,
+				Prepare: func(c *component.Component, activation int) {
+					c.InputByName("in").PutSignals(signal.New(activation))
+				},
+			})
+		})
+		if assert.Len(t, rt.Failures(), 1) {
+			assert.Contains(t, rt.Failures()[0], "activation #0 of failing finished with code")
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package testkit
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// recordingT is a fake testing.TB recording failures instead of failing the test
+type recordingT struct {
+	testing.TB
+	mu       sync.Mutex
+	failures []string
+}
+
+// runRecorded runs f with a recording testing.TB in its own goroutine, so Fatalf can stop f like it stops a test
+func runRecorded(f func(t testing.TB)) *recordingT {
+	rt := &recordingT{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f(rt)
+	}()
+	<-done
+	return rt
+}
+
+// Helper does nothing
+func (rt *recordingT) Helper() {}
+
+// Errorf records the failure
+func (rt *recordingT) Errorf(format string, args ...any) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.failures = append(rt.failures, fmt.Sprintf(format, args...))
+}
+
+// Fatalf records the failure and stops the calling goroutine
+func (rt *recordingT) Fatalf(format string, args ...any) {
+	rt.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// Failures returns recorded failures
+func (rt *recordingT) Failures() []string {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.failures
+}
@@ -0,0 +1,34 @@
+package testkit
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/clock"
+	"time"
+)
+
+var (
+	ErrInvalidStep = errors.New("step must be positive")
+)
+
+// FastForward advances the virtual clock by step and runs the mesh until total simulated time elapses,
+// beforeRun (optional) is called after each advance, typically to put signals on input ports
+func FastForward(fm *fmesh.FMesh, clk *clock.Virtual, total time.Duration, step time.Duration, beforeRun func(now time.Time)) error {
+	if step <= 0 {
+		return ErrInvalidStep
+	}
+
+	for elapsed := time.Duration(0); elapsed < total; elapsed += step {
+		clk.Advance(step)
+
+		if beforeRun != nil {
+			beforeRun(clk.Now())
+		}
+
+		if _, err := fm.Run(); err != nil {
+			return fmt.Errorf("run failed at %s: %w", clk.Now(), err)
+		}
+	}
+	return nil
+}
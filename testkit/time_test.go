@@ -0,0 +1,47 @@
+package testkit
+
+import (
+	"github.com/hovsep/fmesh"
+	"github.com/hovsep/fmesh/clock"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFastForward(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("invalid step", func(t *testing.T) {
+		err := FastForward(fmesh.New("fm"), clock.NewVirtual(start), time.Hour, 0, nil)
+		assert.ErrorIs(t, err, ErrInvalidStep)
+	})
+
+	t.Run("run error is returned", func(t *testing.T) {
+		err := FastForward(fmesh.New("empty"), clock.NewVirtual(start), time.Hour, time.Minute, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("clock is advanced before each run", func(t *testing.T) {
+		clk := clock.NewVirtual(start)
+		fm := fmesh.NewWithConfig("fm", &fmesh.Config{Clock: clk}).WithComponents(
+			component.New("c1").
+				WithInputs("i1").
+				WithActivationFunc(func(this *component.Component) error {
+					this.State().Set("last_seen", this.Clock().Now())
+					return nil
+				}),
+		)
+
+		runs := 0
+		err := FastForward(fm, clk, 10*time.Hour, time.Hour, func(now time.Time) {
+			runs++
+			fm.ComponentByName("c1").InputByName("i1").PutSignals(signal.New(now))
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 10, runs)
+		assert.Equal(t, start.Add(10*time.Hour), clk.Now())
+		assert.Equal(t, start.Add(10*time.Hour), fm.ComponentByName("c1").State().Get("last_seen"))
+	})
+}
@@ -0,0 +1,311 @@
+package fmesh
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/cycle"
+	"github.com/hovsep/fmesh/port"
+	"slices"
+	"sort"
+)
+
+// topology is the index-based representation of the mesh used by the scheduler,
+// it is compiled at the start of each run so the inner loop does not need lookups by name
+type topology struct {
+	// components sorted by name, the index is the component ID
+	components []*component.Component
+	// ids maps component names to IDs
+	ids map[string]int
+	// inputOwners maps each input port to the ID of the component owning it
+	inputOwners map[*port.Port]int
+	// downstream holds, for each component ID, the IDs of components fed by its outputs, upstream holds the IDs of components feeding it
+	downstream [][]int
+	upstream   [][]int
+	// blocking is set when some input ports block pipes on overflow, held holds for each component ID whether signals stay
+	// on its output ports as they did not fit into such ports (see port.OverflowBlock)
+	blocking bool
+	held     []bool
+	// pressurable is set when some input ports have high watermarks, deferred holds for each component ID whether it was not
+	// scheduled as ports it pipes to are over their high watermarks (see component.Backpressure)
+	pressurable bool
+	deferred    []bool
+	// prioritized is set when some components have non-default priority, exclusive is set when some components are exclusive
+	prioritized bool
+	exclusive   bool
+	// serial is set when some components are pinned to serial execution
+	serial bool
+	// writers holds the number of components piping into each input port
+	writers map[*port.Port]int
+	// scheduled holds, for each component ID, whether the component may have got new input signals since it was last evaluated,
+	// components which are not scheduled are quiet and skipped by the scheduler
+	scheduled []bool
+	// quietResults holds, for each component ID, the activation result reported when the component is skipped
+	quietResults []*component.ActivationResult
+	// arena holds the transient per-cycle bookkeeping
+	arena *cycleArena
+	// scheduler orders ready components
+	scheduler *scheduler
+}
+
+// compileTopology builds the index-based topology from the given components
+func compileTopology(components component.ComponentsMap) *topology {
+	t := &topology{
+		components:   make([]*component.Component, 0, len(components)),
+		ids:          make(map[string]int, len(components)),
+		inputOwners:  make(map[*port.Port]int),
+		downstream:   make([][]int, len(components)),
+		upstream:     make([][]int, len(components)),
+		held:         make([]bool, len(components)),
+		deferred:     make([]bool, len(components)),
+		writers:      make(map[*port.Port]int),
+		scheduled:    make([]bool, len(components)),
+		quietResults: make([]*component.ActivationResult, len(components)),
+		arena:        newCycleArena(len(components)),
+	}
+
+	for _, c := range components {
+		t.components = append(t.components, c)
+	}
+	sort.Slice(t.components, func(i, j int) bool {
+		return t.components[i].Name() < t.components[j].Name()
+	})
+
+	for id, c := range t.components {
+		// Signals may be put on any component before the run, so everything is evaluated in the first cycle
+		t.scheduled[id] = true
+		t.quietResults[id] = c.QuietActivationResult()
+		t.ids[c.Name()] = id
+		t.prioritized = t.prioritized || c.Priority() != 0
+		t.exclusive = t.exclusive || c.IsExclusive()
+		t.serial = t.serial || c.IsSerial()
+		for _, p := range c.Inputs().PortsOrNil() {
+			t.inputOwners[p] = id
+			t.blocking = t.blocking || p.BlocksOnOverflow()
+			t.pressurable = t.pressurable || p.HighWatermark() > 0
+		}
+	}
+
+	for id, c := range t.components {
+		seen := make(map[int]bool)
+		seenPorts := make(map[*port.Port]bool)
+		for _, out := range c.Outputs().PortsOrNil() {
+			for _, dest := range out.Pipes().PortsOrNil() {
+				destID, ok := t.inputOwners[dest]
+				if !ok {
+					continue
+				}
+
+				if !seenPorts[dest] {
+					seenPorts[dest] = true
+					t.writers[dest]++
+				}
+
+				if seen[destID] {
+					continue
+				}
+				seen[destID] = true
+				t.downstream[id] = append(t.downstream[id], destID)
+				t.upstream[destID] = append(t.upstream[destID], id)
+			}
+		}
+		sort.Ints(t.downstream[id])
+	}
+
+	return t
+}
+
+// preempt returns the ready exclusive components when there are some (other ready components are deferred to the next cycle),
+// otherwise all ready components
+func (t *topology) preempt(ready []int, activationResults []*component.ActivationResult) []int {
+	if !t.exclusive || !slices.ContainsFunc(ready, func(id int) bool {
+		return t.components[id].IsExclusive()
+	}) {
+		return ready
+	}
+
+	exclusive := ready[:0]
+	for _, id := range ready {
+		if t.components[id].IsExclusive() {
+			exclusive = append(exclusive, id)
+			continue
+		}
+		t.deferred[id] = true
+		activationResults[id] = t.quietResults[id]
+	}
+	return exclusive
+}
+
+// holdsSignals says whether signals stay on output ports of the component as they did not fit into destinations
+func holdsSignals(c *component.Component) bool {
+	for _, out := range c.Outputs().PortsOrNil() {
+		if out.HasHeldSignals() {
+			return true
+		}
+	}
+	return false
+}
+
+// ownerName returns the name of the component owning the given input port (empty when the port is not in the mesh)
+func (t *topology) ownerName(p *port.Port) string {
+	id, ok := t.inputOwners[p]
+	if !ok {
+		return ""
+	}
+	return t.components[id].Name()
+}
+
+// applyBufferModes switches input ports fed by at most one component to lock-free buffers
+// (each component is flushed by a single goroutine, so such ports never have concurrent writers),
+// other input ports are switched back to locked buffers
+func (t *topology) applyBufferModes() {
+	for p := range t.inputOwners {
+		p.SetLockFree(t.writers[p] <= 1)
+	}
+}
+
+// portBuffersReport returns the report on the buffer modes of input ports
+func (t *topology) portBuffersReport() PortBuffersReport {
+	report := PortBuffersReport{
+		Contended: make([]string, 0),
+	}
+	for p, id := range t.inputOwners {
+		if t.writers[p] <= 1 {
+			report.LockFree++
+			continue
+		}
+		report.Contended = append(report.Contended, fmt.Sprintf("%s.%s", t.components[id].Name(), p.Name()))
+	}
+	sort.Strings(report.Contended)
+	return report
+}
+
+// scheduleNext schedules the components which may get input signals in the next cycle:
+// the ones fed by drained components, the ones keeping their inputs (or still having input signals after clearing) and sources, all others stay quiet
+// activationResults are the results of the latest cycle indexed by component ID
+func (t *topology) scheduleNext(activationResults []*component.ActivationResult) {
+	next := t.arena.nextScheduled
+	clear(next)
+	for id, activationResult := range activationResults {
+		if !activationResult.Activated() {
+			continue
+		}
+
+		if component.IsWaitingForInput(activationResult) {
+			next[id] = component.WantsToKeepInputs(activationResult)
+			continue
+		}
+
+		if t.components[id].Inputs().AnyHasSignals() {
+			// Failed activation kept unacknowledged signals or spilled signals were loaded, so the component goes on
+			next[id] = true
+		}
+
+		for _, downstreamID := range t.downstream[id] {
+			next[downstreamID] = true
+		}
+	}
+
+	// Sources may get data from outside the mesh at any time, so they are never quiet,
+	// deferred components are evaluated again as destinations may have caught up
+	for id, c := range t.components {
+		if c.IsSource() || t.deferred[id] {
+			next[id] = true
+		}
+	}
+	t.scheduler.scheduled(activationResults, next)
+	t.arena.nextScheduled, t.scheduled = t.scheduled, next
+}
+
+// topologicalRanks returns ranks of components indexed by ID: each component ranks after all components feeding it,
+// except for loops, which are broken at the component with the smallest name
+func (t *topology) topologicalRanks() []int {
+	incoming := make([]int, len(t.components))
+	for _, downstream := range t.downstream {
+		for _, id := range downstream {
+			incoming[id]++
+		}
+	}
+
+	rank := make([]int, len(t.components))
+	ranked := make([]bool, len(t.components))
+	queue := make([]int, 0, len(t.components))
+	next := 0
+	for len(queue) < len(t.components) {
+		if next == len(queue) {
+			// Everything reachable is ranked, start from the first unranked component (a root or a loop)
+			for id := range t.components {
+				if !ranked[id] && incoming[id] == 0 {
+					ranked[id] = true
+					queue = append(queue, id)
+				}
+			}
+			if next == len(queue) {
+				for id := range t.components {
+					if !ranked[id] {
+						ranked[id] = true
+						queue = append(queue, id)
+						break
+					}
+				}
+			}
+		}
+
+		id := queue[next]
+		next++
+		for _, downstreamID := range t.downstream[id] {
+			if ranked[downstreamID] {
+				continue
+			}
+			rank[downstreamID] = max(rank[downstreamID], rank[id]+1)
+			incoming[downstreamID]--
+			if incoming[downstreamID] == 0 {
+				ranked[downstreamID] = true
+				queue = append(queue, downstreamID)
+			}
+		}
+	}
+	return rank
+}
+
+// quietCount returns the number of components which are currently not scheduled
+func (t *topology) quietCount() int {
+	count := 0
+	for _, scheduled := range t.scheduled {
+		if !scheduled {
+			count++
+		}
+	}
+	return count
+}
+
+// activationResults returns activation results of the given cycle indexed by component ID
+// (results collected in the arena are used when they belong to the given cycle)
+func (t *topology) activationResults(activationCycle *cycle.Cycle) []*component.ActivationResult {
+	if t.arena.cycleNumber != 0 && t.arena.cycleNumber == activationCycle.Number() {
+		return t.arena.activationResults
+	}
+
+	results := make([]*component.ActivationResult, len(t.components))
+	for id, c := range t.components {
+		results[id] = activationCycle.ActivationResults().ByComponentName(c.Name())
+	}
+	return results
+}
+
+// compileTopology compiles the topology of the mesh and caches it until the next run,
+// labels are inherited down the hierarchy at this point, as all components are known
+func (fm *FMesh) compileTopology() *topology {
+	fm.inheritLabels()
+	fm.topology = compileTopology(fm.Components().ComponentsOrNil())
+	fm.topology.applyBufferModes()
+	fm.topology.scheduler = newScheduler(fm.config.SchedulingPolicy, fm.topology, fm.newRandSeed)
+	return fm.topology
+}
+
+// compiledTopology returns the cached topology (compiling it when needed)
+func (fm *FMesh) compiledTopology() *topology {
+	if fm.topology == nil {
+		return fm.compileTopology()
+	}
+	return fm.topology
+}
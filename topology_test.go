@@ -0,0 +1,163 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/cycle"
+	"github.com/hovsep/fmesh/port"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_compileTopology(t *testing.T) {
+	tests := []struct {
+		name       string
+		getFM      func() *FMesh
+		assertions func(t *testing.T, fm *FMesh, topology *topology)
+	}{
+		{
+			name: "empty mesh",
+			getFM: func() *FMesh {
+				return New("fm")
+			},
+			assertions: func(t *testing.T, fm *FMesh, topology *topology) {
+				assert.Empty(t, topology.components)
+				assert.Empty(t, topology.downstream)
+			},
+		},
+		{
+			name: "components are indexed by name and pipes become adjacency",
+			getFM: func() *FMesh {
+				c1 := component.New("c1").WithInputs("i1").WithOutputs("o1", "o2")
+				c2 := component.New("c2").WithInputs("i1", "i2").WithOutputs("o1")
+				c3 := component.New("c3").WithInputs("i1")
+				external := component.New("external").WithInputs("i1")
+
+				c1.OutputByName("o1").PipeTo(c2.InputByName("i1"), c3.InputByName("i1"))
+				c1.OutputByName("o2").PipeTo(c2.InputByName("i2"))
+				c2.OutputByName("o1").PipeTo(c1.InputByName("i1"), external.InputByName("i1"))
+
+				return New("fm").WithComponents(c3, c2, c1)
+			},
+			assertions: func(t *testing.T, fm *FMesh, topology *topology) {
+				assert.Len(t, topology.components, 3)
+				assert.Equal(t, map[string]int{"c1": 0, "c2": 1, "c3": 2}, topology.ids)
+				assert.Equal(t, [][]int{{1, 2}, {0}, nil}, topology.downstream)
+				assert.Equal(t, "c2", topology.ownerName(fm.ComponentByName("c2").InputByName("i2")))
+				assert.Equal(t, "", topology.ownerName(port.New("not in mesh")))
+			},
+		},
+		{
+			name: "input ports with single writer are lock-free",
+			getFM: func() *FMesh {
+				c1 := component.New("c1").WithOutputs("o1", "o2")
+				c2 := component.New("c2").WithOutputs("o1")
+				sink := component.New("sink").WithInputs("single", "fan-in", "unfed")
+
+				// Two pipes from the same component are still a single writer
+				c1.OutputByName("o1").PipeTo(sink.InputByName("single"))
+				c1.OutputByName("o2").PipeTo(sink.InputByName("single"), sink.InputByName("fan-in"))
+				c2.OutputByName("o1").PipeTo(sink.InputByName("fan-in"))
+
+				return New("fm").WithComponents(c1, c2, sink)
+			},
+			assertions: func(t *testing.T, fm *FMesh, topology *topology) {
+				sink := fm.ComponentByName("sink")
+				assert.True(t, sink.InputByName("single").IsLockFree())
+				assert.True(t, sink.InputByName("unfed").IsLockFree())
+				assert.False(t, sink.InputByName("fan-in").IsLockFree())
+				assert.Equal(t, PortBuffersReport{
+					LockFree:  2,
+					Contended: []string{"sink.fan-in"},
+				}, topology.portBuffersReport())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm := tt.getFM()
+			tt.assertions(t, fm, fm.compileTopology())
+		})
+	}
+}
+
+func Test_topology_applyBufferModes(t *testing.T) {
+	c1 := component.New("c1").WithOutputs("o1")
+	c2 := component.New("c2").WithOutputs("o1")
+	sink := component.New("sink").WithInputs("i1")
+	c1.OutputByName("o1").PipeTo(sink.InputByName("i1"))
+	fm := New("fm").WithComponents(c1, c2, sink)
+
+	fm.compileTopology()
+	assert.True(t, sink.InputByName("i1").IsLockFree())
+
+	// A new writer appeared, so the port must be locked again on the next compilation
+	c2.OutputByName("o1").PipeTo(sink.InputByName("i1"))
+	fm.compileTopology()
+	assert.False(t, sink.InputByName("i1").IsLockFree())
+}
+
+func TestFMesh_compiledTopology(t *testing.T) {
+	fm := New("fm").WithComponents(component.New("c1"))
+	compiled := fm.compiledTopology()
+	assert.Same(t, compiled, fm.compiledTopology())
+
+	fm.WithComponents(component.New("c2"))
+	assert.NotSame(t, compiled, fm.compiledTopology())
+	assert.Len(t, fm.compiledTopology().components, 2)
+}
+
+func Test_topology_activationResults(t *testing.T) {
+	fm := New("fm").WithComponents(component.New("c2"), component.New("c1"))
+	activationCycle := cycle.New().WithActivationResults(
+		component.NewActivationResult("c1").SetActivated(true),
+		component.NewActivationResult("c2").SetActivated(false),
+	)
+
+	results := fm.compiledTopology().activationResults(activationCycle)
+	assert.Equal(t, "c1", results[0].ComponentName())
+	assert.Equal(t, "c2", results[1].ComponentName())
+}
+
+func Test_topology_scheduleNext(t *testing.T) {
+	// c1 -> c2 -> c3, c4 is isolated
+	c1 := component.New("c1").WithOutputs("o1")
+	c2 := component.New("c2").WithInputs("i1").WithOutputs("o1")
+	c3 := component.New("c3").WithInputs("i1")
+	c4 := component.New("c4").WithInputs("i1")
+	c1.OutputByName("o1").PipeTo(c2.InputByName("i1"))
+	c2.OutputByName("o1").PipeTo(c3.InputByName("i1"))
+	topology := New("fm").WithComponents(c1, c2, c3, c4).compileTopology()
+
+	assert.Equal(t, []bool{true, true, true, true}, topology.scheduled, "everything is scheduled in the first cycle")
+	assert.Equal(t, 0, topology.quietCount())
+
+	topology.scheduleNext([]*component.ActivationResult{
+		component.NewActivationResult("c1").SetActivated(true).WithActivationCode(component.ActivationCodeOK),
+		component.NewActivationResult("c2").SetActivated(false).WithActivationCode(component.ActivationCodeNoInput),
+		component.NewActivationResult("c3").SetActivated(true).WithActivationCode(component.ActivationCodeOK),
+		component.NewActivationResult("c4").SetActivated(true).WithActivationCode(component.ActivationCodeWaitingForInputsKeep),
+	})
+	assert.Equal(t, []bool{false, true, false, true}, topology.scheduled)
+	assert.Equal(t, 2, topology.quietCount())
+
+	topology.scheduleNext([]*component.ActivationResult{
+		topology.quietResults[0],
+		component.NewActivationResult("c2").SetActivated(true).WithActivationCode(component.ActivationCodeWaitingForInputsClear),
+		topology.quietResults[2],
+		component.NewActivationResult("c4").SetActivated(true).WithActivationCode(component.ActivationCodeOK),
+	})
+	assert.Equal(t, []bool{false, false, false, false}, topology.scheduled)
+}
+
+func Test_topology_scheduleNext_sources(t *testing.T) {
+	source := component.New("source").WithReadinessFunc(func(this *component.Component) bool {
+		return false
+	})
+	topology := New("fm").WithComponents(source, component.New("c1")).compileTopology()
+
+	topology.scheduleNext([]*component.ActivationResult{
+		topology.quietResults[0],
+		topology.quietResults[1],
+	})
+	assert.Equal(t, []bool{false, true}, topology.scheduled, "sources must never be quiet")
+}
@@ -0,0 +1,183 @@
+package fmesh
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ValidationCheck is an optional check of the topology done by Validate
+type ValidationCheck int
+
+const (
+	// CheckDanglingPipes reports pipes leading to ports of components which are not in the mesh
+	CheckDanglingPipes ValidationCheck = iota + 1
+	// CheckUnfedInputs reports input ports which have no signals and no pipes leading to them
+	CheckUnfedInputs
+	// CheckUnreachableComponents reports components which can never activate: they have no signals, they are not sources
+	// and signals can not reach them through pipes from components which can activate
+	CheckUnreachableComponents
+	// CheckCycles reports looped pipes (a component feeding itself directly or through other components),
+	// loops are legit in many meshes (e.g. iterative computations), so the check is meant for meshes which must be acyclic
+	CheckCycles
+)
+
+// TopologyChecks are the checks of mistakes which are rarely intended (all checks except CheckCycles)
+var TopologyChecks = []ValidationCheck{CheckDanglingPipes, CheckUnfedInputs, CheckUnreachableComponents}
+
+var (
+	ErrDuplicateComponentName     = errors.New("another component with the same name was added, it was replaced")
+	ErrDanglingPipe               = errors.New("pipe leads to a port outside of the mesh")
+	ErrUnfedInput                 = errors.New("input port has no signals and no pipes leading to it")
+	ErrUnreachableComponent       = errors.New("component can never activate")
+	ErrCycle                      = errors.New("looped pipes")
+	ErrUnsupportedValidationCheck = errors.New("unsupported validation check")
+)
+
+// validateTopology returns problems found by the checks (ordered by components and ports)
+func (fm *FMesh) validateTopology(checks []ValidationCheck) error {
+	components := fm.Components().ComponentsOrNil()
+	names := make([]string, 0, len(components))
+	owners := make(map[*port.Port]string)
+	for name, c := range components {
+		names = append(names, name)
+		for _, p := range c.Inputs().PortsOrNil() {
+			owners[p] = name
+		}
+	}
+	sort.Strings(names)
+
+	// fed are input ports having pipes leading to them, downstream maps components to components they pipe to
+	fed := make(map[*port.Port]bool)
+	downstream := make(map[string][]string, len(components))
+	var dangling []error
+	for _, name := range names {
+		c := components[name]
+		for _, outName := range sortedPortNames(c.Outputs().PortsOrNil()) {
+			for _, dest := range c.OutputByName(outName).Pipes().PortsOrNil() {
+				owner, ok := owners[dest]
+				if !ok {
+					dangling = append(dangling, &ConstructionError{Component: name, Port: outName, Err: fmt.Errorf("%w: %s", ErrDanglingPipe, dest.Name())})
+					continue
+				}
+				fed[dest] = true
+				if !slices.Contains(downstream[name], owner) {
+					downstream[name] = append(downstream[name], owner)
+				}
+			}
+		}
+		sort.Strings(downstream[name])
+	}
+
+	var errs []error
+	for _, check := range checks {
+		switch check {
+		case CheckDanglingPipes:
+			errs = append(errs, dangling...)
+		case CheckUnfedInputs:
+			for _, name := range names {
+				inputs := components[name].Inputs().PortsOrNil()
+				for _, portName := range sortedPortNames(inputs) {
+					if p := inputs[portName]; !fed[p] && !p.HasSignals() {
+						errs = append(errs, &ConstructionError{Component: name, Port: portName, Err: ErrUnfedInput})
+					}
+				}
+			}
+		case CheckUnreachableComponents:
+			reachable := fm.reachableComponents(components, downstream)
+			for _, name := range names {
+				if !reachable[name] {
+					errs = append(errs, &ConstructionError{Component: name, Err: ErrUnreachableComponent})
+				}
+			}
+		case CheckCycles:
+			for _, loop := range findCycles(names, downstream) {
+				errs = append(errs, fmt.Errorf("%w: %s", ErrCycle, strings.Join(loop, " -> ")))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("%w: %d", ErrUnsupportedValidationCheck, check))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reachableComponents returns components which can activate: the ones having signals (or injected signals), sources
+// and components downstream of them
+func (fm *FMesh) reachableComponents(components component.ComponentsMap, downstream map[string][]string) map[string]bool {
+	injected := fm.injections.targets()
+	reachable := make(map[string]bool, len(components))
+	var queue []string
+	for name, c := range components {
+		if c.IsSource() || c.Inputs().AnyHasSignals() || injected[name] {
+			reachable[name] = true
+			queue = append(queue, name)
+		}
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, next := range downstream[name] {
+			if !reachable[next] {
+				reachable[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return reachable
+}
+
+// findCycles returns loops of components found by depth-first search (one per pipe closing a loop), each loop starts and ends with the same component
+func findCycles(names []string, downstream map[string][]string) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(names))
+	var (
+		path  []string
+		loops [][]string
+		visit func(name string)
+	)
+	visit = func(name string) {
+		state[name] = visiting
+		path = append(path, name)
+		for _, next := range downstream[name] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				start := len(path) - 1
+				for path[start] != next {
+					start--
+				}
+				loop := append(append([]string{}, path[start:]...), next)
+				loops = append(loops, loop)
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+	}
+
+	for _, name := range names {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+	return loops
+}
+
+// sortedPortNames returns names of the ports sorted
+func sortedPortNames(ports port.PortMap) []string {
+	names := make([]string, 0, len(ports))
+	for name := range ports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
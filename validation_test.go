@@ -0,0 +1,118 @@
+package fmesh
+
+import (
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFMesh_ValidateTopology(t *testing.T) {
+	newComponent := func(name string) *component.Component {
+		return component.New(name).
+			WithInputs("in").
+			WithOutputs("out").
+			WithActivationFunc(func(this *component.Component) error {
+				return nil
+			})
+	}
+
+	tests := []struct {
+		name   string
+		getFM  func() *FMesh
+		checks []ValidationCheck
+		errs   []error
+		errMsg string
+	}{
+		{
+			name: "valid chain",
+			getFM: func() *FMesh {
+				a, b := newComponent("a"), newComponent("b")
+				a.OutputByName("out").PipeTo(b.InputByName("in"))
+				a.InputByName("in").PutSignals(signal.New(1))
+				return New("fm").WithComponents(a, b)
+			},
+			checks: append(TopologyChecks, CheckCycles),
+		},
+		{
+			name: "checks are optional",
+			getFM: func() *FMesh {
+				return New("fm").WithComponents(newComponent("a"))
+			},
+		},
+		{
+			name: "all problems are reported",
+			getFM: func() *FMesh {
+				a, b, outsider := newComponent("a"), newComponent("b"), newComponent("outsider")
+				a.OutputByName("out").PipeTo(b.InputByName("in"), outsider.InputByName("in"))
+				return New("fm").WithComponents(a, b)
+			},
+			checks: TopologyChecks,
+			errs:   []error{ErrDanglingPipe, ErrUnfedInput, ErrUnreachableComponent},
+			errMsg: `component a, port out: pipe leads to a port outside of the mesh: in
+component a, port in: input port has no signals and no pipes leading to it
+component a: component can never activate
+component b: component can never activate`,
+		},
+		{
+			name: "injected signals make components reachable",
+			getFM: func() *FMesh {
+				fm := New("fm").WithComponents(newComponent("a"))
+				require.NoError(t, fm.Inject("a", "in", signal.New(1)))
+				return fm
+			},
+			checks: []ValidationCheck{CheckUnreachableComponents},
+		},
+		{
+			name: "cycles",
+			getFM: func() *FMesh {
+				a, b, c := newComponent("a"), newComponent("b"), newComponent("c")
+				a.OutputByName("out").PipeTo(b.InputByName("in"))
+				b.OutputByName("out").PipeTo(a.InputByName("in"))
+				c.OutputByName("out").PipeTo(c.InputByName("in"))
+				return New("fm").WithComponents(a, b, c)
+			},
+			checks: []ValidationCheck{CheckCycles},
+			errs:   []error{ErrCycle},
+			errMsg: "looped pipes: a -> b -> a\nlooped pipes: c -> c",
+		},
+		{
+			name: "duplicate component names",
+			getFM: func() *FMesh {
+				return New("fm").WithComponents(newComponent("a"), newComponent("a"))
+			},
+			errs:   []error{ErrDuplicateComponentName},
+			errMsg: "component a: another component with the same name was added, it was replaced",
+		},
+		{
+			name: "unsupported check",
+			getFM: func() *FMesh {
+				return New("fm").WithComponents(newComponent("a"))
+			},
+			checks: []ValidationCheck{ValidationCheck(100)},
+			errs:   []error{ErrUnsupportedValidationCheck},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.getFM().Validate(tt.checks...)
+			if len(tt.errs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			for _, expected := range tt.errs {
+				assert.ErrorIs(t, err, expected)
+			}
+			if tt.errMsg != "" {
+				assert.EqualError(t, err, tt.errMsg)
+			}
+		})
+	}
+
+	t.Run("topology problems do not break the mesh", func(t *testing.T) {
+		fm := New("fm").WithComponents(newComponent("a"))
+		require.Error(t, fm.Validate(TopologyChecks...))
+		assert.False(t, fm.HasErr())
+	})
+}
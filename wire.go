@@ -0,0 +1,140 @@
+package fmesh
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hovsep/fmesh/port"
+	"runtime"
+)
+
+var ErrInvalidWiring = errors.New("invalid wiring")
+
+// WiringError is an error of a single wiring call with the location of the call
+type WiringError struct {
+	File string
+	Line int
+	Err  error
+}
+
+// Error returns the error prefixed with the call location
+func (e *WiringError) Error() string {
+	return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *WiringError) Unwrap() error {
+	return e.Err
+}
+
+// Wiring pipes ports of the mesh addressed by names, e.g.:
+//
+//	fm, err := fmesh.Wire(fm).
+//		From("lb", "downstream0").To("worker-0", "in").
+//		From("lb", "downstream1").To("worker-1", "in").
+//		Build()
+//
+// Mistakes do not break the chain: each of them is recorded with the location of the call and all of them are reported by Build
+type Wiring struct {
+	fm *FMesh
+	// from is the output port the next To calls pipe from (nil when it is not set or invalid)
+	from *port.Port
+	// fromCalled says whether From was called at all
+	fromCalled bool
+	pipes      []pipe
+	errors     []error
+}
+
+// pipe is a pipe to be created by Build
+type pipe struct {
+	from *port.Port
+	to   *port.Port
+}
+
+// Wire starts wiring of the mesh, components must be added to the mesh before
+func Wire(fm *FMesh) *Wiring {
+	return &Wiring{
+		fm: fm,
+	}
+}
+
+// From sets the output port the next To calls pipe from
+func (w *Wiring) From(componentName, portName string) *Wiring {
+	w.from, w.fromCalled = nil, true
+	p, err := w.lookup(componentName, portName, port.DirectionOut)
+	if err != nil {
+		w.fail(fmt.Errorf("from %w", err))
+		return w
+	}
+	w.from = p
+	return w
+}
+
+// To pipes the latest From port to the given input port, it may be called many times to fan out
+func (w *Wiring) To(componentName, portName string) *Wiring {
+	p, err := w.lookup(componentName, portName, port.DirectionIn)
+	if err != nil {
+		w.fail(fmt.Errorf("to %w", err))
+		return w
+	}
+	if !w.fromCalled {
+		w.fail(fmt.Errorf("to %s.%s: no preceding From", componentName, portName))
+		return w
+	}
+	if w.from == nil {
+		// Invalid From is already reported
+		return w
+	}
+	w.pipes = append(w.pipes, pipe{from: w.from, to: p})
+	return w
+}
+
+// Build creates the pipes and returns the mesh, when any call failed no pipes are created and all errors are returned
+// (each error is a WiringError wrapping ErrInvalidWiring)
+func (w *Wiring) Build() (*FMesh, error) {
+	if w.fm.HasErr() {
+		return w.fm, w.fm.Err()
+	}
+	if len(w.errors) > 0 {
+		return w.fm, errors.Join(w.errors...)
+	}
+
+	for _, p := range w.pipes {
+		if p.from.PipeTo(p.to).HasErr() {
+			return w.fm, errors.Join(ErrFailedToConnect, p.from.Err())
+		}
+	}
+
+	// Topology must be recompiled as pipes changed
+	w.fm.topology = nil
+	return w.fm, nil
+}
+
+// lookup returns the port of the component
+func (w *Wiring) lookup(componentName, portName, direction string) (*port.Port, error) {
+	c, ok := w.fm.Components().ComponentsOrNil()[componentName]
+	if !ok {
+		return nil, fmt.Errorf("%s.%s: component not found", componentName, portName)
+	}
+
+	ports := c.Inputs()
+	if direction == port.DirectionOut {
+		ports = c.Outputs()
+	}
+	p, ok := ports.PortsOrNil()[portName]
+	if !ok {
+		return nil, fmt.Errorf("%s.%s: %s port not found", componentName, portName, direction)
+	}
+	return p, nil
+}
+
+// fail records the error with the location of the DSL call made by the user
+func (w *Wiring) fail(err error) {
+	wiringErr := &WiringError{
+		Err: fmt.Errorf("%w: %w", ErrInvalidWiring, err),
+	}
+	// Skip fail, the DSL method and report its caller
+	if _, file, line, ok := runtime.Caller(2); ok {
+		wiringErr.File, wiringErr.Line = file, line
+	}
+	w.errors = append(w.errors, wiringErr)
+}
@@ -0,0 +1,73 @@
+package fmesh
+
+import (
+	"fmt"
+	"github.com/hovsep/fmesh/component"
+	"github.com/hovsep/fmesh/port"
+	"github.com/hovsep/fmesh/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"testing"
+)
+
+func TestWire(t *testing.T) {
+	newMesh := func() *FMesh {
+		lb := component.New("lb").WithInputs("in").WithOutputs("downstream0", "downstream1").WithActivationFunc(func(this *component.Component) error {
+			for i, sig := range this.InputByName("in").AllSignalsOrNil() {
+				if i%2 == 0 {
+					this.OutputByName("downstream0").PutSignals(sig)
+					continue
+				}
+				this.OutputByName("downstream1").PutSignals(sig)
+			}
+			return nil
+		})
+		newWorker := func(name string) *component.Component {
+			return component.New(name).WithInputs("in").WithOutputs("out").WithActivationFunc(func(this *component.Component) error {
+				return port.ForwardSignals(this.InputByName("in"), this.OutputByName("out"))
+			})
+		}
+		return New("fm").WithComponents(lb, newWorker("worker-0"), newWorker("worker-1"))
+	}
+
+	t.Run("ports are piped", func(t *testing.T) {
+		fm, err := Wire(newMesh()).
+			From("lb", "downstream0").To("worker-0", "in").
+			From("lb", "downstream1").To("worker-1", "in").
+			Build()
+		require.NoError(t, err)
+
+		fm.ComponentByName("lb").InputByName("in").PutSignals(signal.New(1), signal.New(2), signal.New(3))
+		_, err = fm.Run()
+		require.NoError(t, err)
+		payloads, err := fm.ComponentByName("worker-0").OutputByName("out").AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, 3}, payloads)
+		payloads, err = fm.ComponentByName("worker-1").OutputByName("out").AllSignalsPayloads()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{2}, payloads)
+	})
+
+	t.Run("all errors are reported with locations", func(t *testing.T) {
+		_, _, line, _ := runtime.Caller(0)
+		fm, err := Wire(newMesh()).
+			From("lb", "downstream0").To("worker-0", "in").
+			From("lb", "downstream2").To("worker-1", "in").
+			From("lb", "downstream1").To("worker-2", "in").
+			Build()
+		require.ErrorIs(t, err, ErrInvalidWiring)
+		assert.ErrorContains(t, err, fmt.Sprintf("wire_test.go:%d: invalid wiring: from lb.downstream2: out port not found", line+3))
+		assert.ErrorContains(t, err, fmt.Sprintf("wire_test.go:%d: invalid wiring: to worker-2.in: component not found", line+4))
+		assert.False(t, fm.ComponentByName("lb").OutputByName("downstream0").HasPipes(), "nothing is piped")
+
+		var wiringErr *WiringError
+		require.ErrorAs(t, err, &wiringErr)
+		assert.Equal(t, line+3, wiringErr.Line)
+	})
+
+	t.Run("to without from", func(t *testing.T) {
+		_, err := Wire(newMesh()).To("worker-0", "in").Build()
+		assert.ErrorContains(t, err, "no preceding From")
+	})
+}